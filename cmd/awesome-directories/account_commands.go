@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/auth"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// accountCommand creates the account command
+func accountCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "account",
+		Usage: "Export or delete your server-side account data",
+		Commands: []*cli.Command{
+			{
+				Name:  "export",
+				Usage: "Download all your favorites, submissions and votes as JSON",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output file path (default: stdout)",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					if cfg.AuthToken == "" {
+						return fmt.Errorf("authentication required: use 'auth login' or 'auth token' first")
+					}
+
+					user, err := auth.GetUserInfo(cfg)
+					if err != nil {
+						return fmt.Errorf("failed to get user info: %w", err)
+					}
+
+					apiClient := api.NewClient(cfg)
+
+					favorites, err := apiClient.GetFavorites(ctx)
+					if err != nil {
+						return fmt.Errorf("failed to get favorites: %w", err)
+					}
+
+					submissions, err := apiClient.GetSubmissions(ctx)
+					if err != nil {
+						return err
+					}
+
+					votes, err := apiClient.GetVotes(ctx)
+					if err != nil {
+						return err
+					}
+
+					data := models.AccountData{
+						ExportedAt:  time.Now().UTC(),
+						User:        models.User{ID: user.ID, Email: user.Email},
+						Favorites:   favorites,
+						Submissions: submissions,
+						Votes:       votes,
+					}
+
+					output, err := json.MarshalIndent(data, "", "  ")
+					if err != nil {
+						return fmt.Errorf("failed to marshal account data: %w", err)
+					}
+
+					outputPath := cmd.String("output")
+					if outputPath == "" {
+						u.Println(string(output))
+						return nil
+					}
+
+					if err := os.WriteFile(outputPath, append(output, '\n'), 0600); err != nil {
+						return fmt.Errorf("failed to write account data: %w", err)
+					}
+
+					u.Success("Exported %d favorites, %d submissions and %d votes to %s",
+						len(favorites), len(submissions), len(votes), outputPath)
+
+					return nil
+				},
+			},
+			{
+				Name:  "delete",
+				Usage: "Permanently delete your account and all associated data",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "confirm",
+						Usage: "Skip the prompt by passing your account email",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					if cfg.AuthToken == "" {
+						return fmt.Errorf("authentication required: use 'auth login' or 'auth token' first")
+					}
+
+					user, err := auth.GetUserInfo(cfg)
+					if err != nil {
+						return fmt.Errorf("failed to get user info: %w", err)
+					}
+
+					confirmation := cmd.String("confirm")
+					if confirmation == "" {
+						u.Warning("This permanently deletes your account, favorites, submissions and votes.")
+						u.Muted("Consider running 'account export' first.")
+
+						confirmation, err = u.Prompt(fmt.Sprintf("Type your email (%s) to confirm: ", user.Email))
+						if err != nil {
+							return err
+						}
+					}
+
+					if confirmation != user.Email {
+						return fmt.Errorf("confirmation does not match account email, aborting")
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would delete account %s and log out", user.Email)
+						return nil
+					}
+
+					if err := api.NewClient(cfg).DeleteAccount(ctx); err != nil {
+						return err
+					}
+
+					if err := auth.Logout(cfg); err != nil {
+						return fmt.Errorf("failed to logout: %w", err)
+					}
+
+					u.Success("Account %s deleted", user.Email)
+
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/assert"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// assertFields are the numeric directory fields avg, min, max and sum
+// aggregate, with their short names
+var assertFields = map[string]func(models.Directory) float64{
+	"domain_rating":    func(d models.Directory) float64 { return float64(d.DomainRating) },
+	"dr":               func(d models.Directory) float64 { return float64(d.DomainRating) },
+	"organic_traffic":  func(d models.Directory) float64 { return float64(d.OrganicTraffic) },
+	"traffic":          func(d models.Directory) float64 { return float64(d.OrganicTraffic) },
+	"organic_keywords": func(d models.Directory) float64 { return float64(d.OrganicKeywords) },
+	"helpful_count":    func(d models.Directory) float64 { return float64(d.HelpfulCount) },
+	"votes":            func(d models.Directory) float64 { return float64(d.HelpfulCount) },
+	"view_count":       func(d models.Directory) float64 { return float64(d.ViewCount) },
+	"price_amount":     func(d models.Directory) float64 { return d.PriceAmount },
+	"price":            func(d models.Directory) float64 { return d.PriceAmount },
+	"review_days":      func(d models.Directory) float64 { return float64(d.ReviewDays) },
+}
+
+// assertionResult is the JSON output of a checked assertion
+type assertionResult struct {
+	Assertion string  `json:"assertion"`
+	Value     float64 `json:"value"`
+	Expected  string  `json:"expected"`
+	Passed    bool    `json:"passed"`
+}
+
+// assertCommand creates the assert command
+func assertCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "assert",
+		Usage:     "Check counts and averages of the catalog or your submissions, failing when one doesn't hold",
+		ArgsUsage: "<assertion>...",
+		Description: `Each assertion compares two expressions, such as
+"count(--dr-min 70 --pricing free) >= 25". The command exits with status 1
+when any assertion fails, for data-quality or coverage gates in CI.
+
+Expressions combine numbers and these calls with + - * / and parentheses:
+
+   count(flags...)          directories matching the filter flags
+   avg(field flags...)      average of a field over matching directories
+   min, max, sum            likewise
+
+Flags are the filter flags of 'filter' (--category, --pricing, --link-type,
+--dr-min, --dr-max, --query, --country, --language, --audience, --max-price,
+--currency). With --submissions, calls look at tracked submissions
+instead of the catalog, narrowed with --status, --project and --product;
+filter flags then apply to the submitted directories.
+
+Fields: domain_rating (dr), organic_traffic (traffic), organic_keywords,
+helpful_count (votes), view_count, price_amount (price), review_days.`,
+		Metadata: examples(
+			`awesome-directories assert "count(--dr-min 70 --pricing free) >= 25"`,
+			`awesome-directories assert "avg(dr --category ai) > 40" "count(--link-type dofollow) / count() >= 0.5"`,
+			`awesome-directories assert "count(--submissions --status approved --project acme) >= 10"`,
+			`awesome-directories assert --snapshot 2024-q1 "count() >= 1000" --json`,
+		),
+		Flags: []cli.Flag{
+			snapshotFlag(),
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output the results as JSON",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() == 0 {
+				return fmt.Errorf("at least one assertion is required")
+			}
+
+			var assertions []*assert.Assertion
+			for _, expression := range cmd.Args().Slice() {
+				assertion, err := assert.Parse(expression)
+				if err != nil {
+					return err
+				}
+				assertions = append(assertions, assertion)
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			directories, err := loadDirectories(ctx, cmd, cache.NewCache(cfg, api.NewClient(cfg)))
+			if err != nil {
+				return err
+			}
+
+			evaluator := &assertEvaluator{ctx: ctx, cfg: cfg, directories: directories, dataStore: store.New(cfg)}
+
+			var results []assertionResult
+			failed := 0
+			for i, assertion := range assertions {
+				result, err := assertion.Check(evaluator.eval)
+				if err != nil {
+					return fmt.Errorf("%s: %w", cmd.Args().Get(i), err)
+				}
+				results = append(results, assertionResult{
+					Assertion: cmd.Args().Get(i),
+					Value:     result.Left,
+					Expected:  result.Op + " " + formatAssertValue(result.Right),
+					Passed:    result.Passed,
+				})
+				if !result.Passed {
+					failed++
+				}
+			}
+			recordResults(ctx, len(results))
+
+			if cmd.Bool("json") {
+				// Keep comparison operators readable instead of \u003e
+				encoder := json.NewEncoder(u.Out)
+				encoder.SetEscapeHTML(false)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(results); err != nil {
+					return fmt.Errorf("failed to marshal JSON: %w", err)
+				}
+			} else {
+				for _, result := range results {
+					if result.Passed {
+						u.Success("%s (%s)", result.Assertion, formatAssertValue(result.Value))
+					} else {
+						u.Error("%s: got %s, expected %s", result.Assertion, formatAssertValue(result.Value), result.Expected)
+					}
+				}
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d of %d assertions failed", failed, len(results))
+			}
+			return nil
+		},
+	}
+}
+
+// assertEvaluator computes the calls of assertions
+type assertEvaluator struct {
+	ctx         context.Context
+	cfg         *config.Config
+	directories []models.Directory
+	dataStore   *store.Store
+}
+
+// eval computes a call: count, or an aggregate of a field
+func (e *assertEvaluator) eval(call assert.Call) (float64, error) {
+	args := call.Args
+	var field func(models.Directory) float64
+	switch call.Name {
+	case "count":
+	case "avg", "min", "max", "sum":
+		if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+			return 0, fmt.Errorf("%s needs a field first, such as %s(dr)", call.Name, call.Name)
+		}
+		var ok bool
+		if field, ok = assertFields[strings.ToLower(args[0])]; !ok {
+			return 0, fmt.Errorf("unknown field %q in %s", args[0], call)
+		}
+		args = args[1:]
+	default:
+		return 0, fmt.Errorf("unknown function %q: use count, avg, min, max or sum", call.Name)
+	}
+
+	selected, err := e.selectDirectories(args)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", call, err)
+	}
+
+	if field == nil {
+		return float64(len(selected)), nil
+	}
+	if len(selected) == 0 {
+		return 0, nil
+	}
+
+	values := make([]float64, len(selected))
+	for i, dir := range selected {
+		values[i] = field(dir)
+	}
+	switch call.Name {
+	case "min":
+		result := math.Inf(1)
+		for _, value := range values {
+			result = math.Min(result, value)
+		}
+		return result, nil
+	case "max":
+		result := math.Inf(-1)
+		for _, value := range values {
+			result = math.Max(result, value)
+		}
+		return result, nil
+	}
+
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+	if call.Name == "avg" {
+		return sum / float64(len(values)), nil
+	}
+	return sum, nil
+}
+
+// selectDirectories returns the directories the flags of a call select: the
+// matching catalog directories, or with --submissions the directory of each
+// matching submission
+func (e *assertEvaluator) selectDirectories(args []string) ([]models.Directory, error) {
+	var selected []models.Directory
+	parser := &cli.Command{
+		Name:     "assert",
+		HideHelp: true,
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{Name: "filter"}, // catalog directories, the default
+			&cli.BoolFlag{Name: "submissions"},
+			&cli.StringSliceFlag{Name: "status"},
+			&cli.StringFlag{Name: "project"},
+			&cli.StringFlag{Name: "product"},
+			&cli.StringSliceFlag{Name: "category", Aliases: []string{"c"}},
+			&cli.StringSliceFlag{Name: "pricing", Aliases: []string{"p"}},
+			&cli.StringSliceFlag{Name: "link-type"},
+			&cli.IntFlag{Name: "dr-min"},
+			&cli.IntFlag{Name: "dr-max"},
+			&cli.StringFlag{Name: "query"},
+		}, filterMetadataFlags()...),
+		OnUsageError: func(ctx context.Context, cmd *cli.Command, err error, isSubcommand bool) error {
+			return err
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			if cmd.Args().Len() > 0 {
+				return fmt.Errorf("unexpected %q", cmd.Args().First())
+			}
+
+			options := &models.FilterOptions{
+				Query:      cmd.String("query"),
+				Categories: cmd.StringSlice("category"),
+				Pricing:    cmd.StringSlice("pricing"),
+				LinkType:   cmd.StringSlice("link-type"),
+				DRMin:      cmd.Int("dr-min"),
+				DRMax:      cmd.Int("dr-max"),
+			}
+			if err := applyFilterMetadata(ctx, cmd, e.cfg, options); err != nil {
+				return err
+			}
+
+			if !cmd.Bool("submissions") {
+				for _, dir := range e.directories {
+					if cache.Matches(dir, options) {
+						selected = append(selected, dir)
+					}
+				}
+				return nil
+			}
+
+			submissions, err := e.dataStore.Submissions()
+			if err != nil {
+				return err
+			}
+			// Without directory filters, submissions to directories gone
+			// from the catalog count too
+			filtered := false
+			for _, name := range cmd.FlagNames() {
+				if name != "submissions" && name != "status" && name != "project" && name != "product" && cmd.IsSet(name) {
+					filtered = true
+				}
+			}
+			for _, submission := range submissions {
+				if statuses := cmd.StringSlice("status"); len(statuses) > 0 && !containsFold(statuses, submission.Status) {
+					continue
+				}
+				if project := cmd.String("project"); project != "" && submission.Project != project {
+					continue
+				}
+				if product := cmd.String("product"); product != "" && submission.Product != product {
+					continue
+				}
+
+				dir := findDirectory(e.directories, submission.Directory)
+				switch {
+				case dir != nil && cache.Matches(*dir, options):
+					selected = append(selected, *dir)
+				case dir == nil && !filtered:
+					selected = append(selected, models.Directory{Slug: submission.Directory})
+				}
+			}
+			return nil
+		},
+	}
+
+	if err := parser.Run(e.ctx, append([]string{"assert"}, args...)); err != nil {
+		return nil, err
+	}
+	return selected, nil
+}
+
+// formatAssertValue formats a value without needless decimals
+func formatAssertValue(value float64) string {
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/clipboard"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// assistPollInterval is how often the clipboard is checked for directory URLs
+const assistPollInterval = time.Second
+
+// assistField is a product field that can be copied with a one-letter command
+type assistField struct {
+	key   string
+	name  string
+	value func(models.Product) string
+}
+
+var assistFields = []assistField{
+	{"n", "name", func(p models.Product) string { return p.Name }},
+	{"u", "url", func(p models.Product) string { return p.URL }},
+	{"t", "tagline", func(p models.Product) string { return p.Tagline }},
+	{"d", "description", func(p models.Product) string { return p.Description }},
+	{"c", "categories", func(p models.Product) string { return strings.Join(p.Categories, ", ") }},
+	{"e", "email", func(p models.Product) string { return p.ContactEmail }},
+	{"l", "logo", func(p models.Product) string { return p.Logo }},
+}
+
+// assistCommand creates the assist command
+func assistCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "assist",
+		Usage:     "Copy product fields to the clipboard while filling directory submission forms",
+		ArgsUsage: "[directory-slug]",
+		Metadata: examples(
+			"awesome-directories assist --product acme",
+			"awesome-directories assist producthunt --product acme --variant b",
+		),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "product",
+				Usage: "Product profile to copy fields from (default: the only profile)",
+			},
+			&cli.StringFlag{
+				Name:  "variant",
+				Usage: "Copy variant to use",
+				Value: models.DefaultVariant,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			if !clipboard.Available() {
+				return clipboard.ErrUnavailable
+			}
+
+			dataStore := store.New(cfg)
+			product, err := assistProduct(dataStore, cmd.String("product"), cmd.String("variant"))
+			if err != nil {
+				return err
+			}
+
+			cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+			directories, err := cacheClient.GetDirectories(ctx, false)
+			if err != nil {
+				return fmt.Errorf("failed to get directories: %w", err)
+			}
+
+			var current *models.Directory
+			if slug := cmd.Args().First(); slug != "" {
+				if current = findDirectory(directories, slug); current == nil {
+					return fmt.Errorf("directory not found: %s", slug)
+				}
+			}
+
+			logActivity := func(action, detail string) {
+				if current == nil {
+					return
+				}
+				err := dataStore.LogActivity(store.Activity{
+					Directory: current.Slug,
+					Product:   product.Slug,
+					Action:    action,
+					Detail:    detail,
+				})
+				if err != nil {
+					log.Warn().Err(err).Msg("Failed to log activity")
+				}
+			}
+
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			u.Info("Assisting with %s (variant %s). Copy a directory's URL to switch to it.", product.Name, cmd.String("variant"))
+			printAssistHelp(u)
+			if current != nil {
+				u.Info("Working on %s", current.Name)
+				logActivity("started", "")
+			}
+
+			lines := make(chan string)
+			go func() {
+				defer close(lines)
+				scanner := bufio.NewScanner(u.In)
+				for scanner.Scan() {
+					lines <- strings.TrimSpace(scanner.Text())
+				}
+			}()
+
+			lastClipboard, _ := clipboard.Read()
+			ticker := time.NewTicker(assistPollInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+
+				case <-ticker.C:
+					text, err := clipboard.Read()
+					if err != nil || text == lastClipboard {
+						continue
+					}
+					lastClipboard = text
+
+					if dir := directoryForURL(directories, text); dir != nil && (current == nil || dir.ID != current.ID) {
+						current = dir
+						u.Info("Working on %s", current.Name)
+						logActivity("started", text)
+					}
+
+				case line, ok := <-lines:
+					if !ok || line == "q" || line == "quit" {
+						return nil
+					}
+
+					switch {
+					case line == "":
+					case line == "?" || line == "help":
+						printAssistHelp(u)
+					case line == "done":
+						if current == nil {
+							u.Warning("No directory selected. Use 'dir <slug>' or copy its URL.")
+							continue
+						}
+						logActivity("filled", "")
+						u.Success("Logged %s as filled in", current.Name)
+					case strings.HasPrefix(line, "dir "):
+						dir := findDirectory(directories, strings.TrimSpace(strings.TrimPrefix(line, "dir ")))
+						if dir == nil {
+							u.Warning("Unknown directory: %s", strings.TrimPrefix(line, "dir "))
+							continue
+						}
+						current = dir
+						u.Info("Working on %s", current.Name)
+						logActivity("started", "")
+					default:
+						field := assistFieldFor(line)
+						if field == nil {
+							u.Warning("Unknown command: %s (type ? for help)", line)
+							continue
+						}
+
+						value := field.value(*product)
+						if value == "" {
+							u.Warning("%s is empty in the product profile", field.name)
+							continue
+						}
+
+						if err := clipboard.Write(value); err != nil {
+							return err
+						}
+						lastClipboard = value
+						u.Success("Copied %s: %s", field.name, ui.TruncateString(value, 60))
+						logActivity("copied", field.name)
+					}
+				}
+			}
+		},
+	}
+}
+
+// assistProduct loads the product to assist with, defaulting to the only
+// stored profile
+func assistProduct(dataStore *store.Store, slug, variant string) (*models.Product, error) {
+	if slug == "" {
+		products, err := dataStore.Products()
+		if err != nil {
+			return nil, err
+		}
+		if len(products) != 1 {
+			return nil, fmt.Errorf("choose a product with --product (%d profiles stored)", len(products))
+		}
+		slug = products[0].Slug
+	}
+
+	stored, err := dataStore.Product(slug)
+	if err != nil {
+		return nil, err
+	}
+
+	product, ok := stored.WithVariant(strings.ToLower(variant))
+	if !ok {
+		return nil, fmt.Errorf("unknown variant %q of %s (available: %s)", variant, stored.Slug, strings.Join(stored.VariantNames(), ", "))
+	}
+	return &product, nil
+}
+
+// printAssistHelp lists the assist commands
+func printAssistHelp(u *ui.UI) {
+	var keys []string
+	for _, field := range assistFields {
+		keys = append(keys, fmt.Sprintf("%s=%s", field.key, field.name))
+	}
+	u.Muted("Copy a field: %s", strings.Join(keys, "  "))
+	u.Muted("dir <slug> switch directory · done log as filled in · ? help · q quit")
+}
+
+// assistFieldFor returns the field copied by a command, by key or name
+func assistFieldFor(command string) *assistField {
+	for i, field := range assistFields {
+		if command == field.key || command == field.name {
+			return &assistFields[i]
+		}
+	}
+	return nil
+}
+
+// directoryForURL returns the directory whose website or submission form is
+// on the host of text, if text is a URL
+func directoryForURL(directories []models.Directory, text string) *models.Directory {
+	parsed, err := url.Parse(strings.TrimSpace(text))
+	if err != nil || parsed.Hostname() == "" {
+		return nil
+	}
+	host := strings.TrimPrefix(parsed.Hostname(), "www.")
+
+	for i, dir := range directories {
+		for _, candidate := range []string{dir.URL, dir.SubmissionURL} {
+			if candidateURL, err := url.Parse(candidate); err == nil && candidateURL.Hostname() != "" &&
+				strings.TrimPrefix(candidateURL.Hostname(), "www.") == host {
+				return &directories[i]
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// auditCommand creates the audit command
+func auditCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "audit",
+		Usage: "Review changes made to local data",
+		Commands: []*cli.Command{
+			{
+				Name:  "log",
+				Usage: "Show the audit log of favorites, submission, product and config changes",
+				Metadata: examples(
+					"awesome-directories audit log",
+					"awesome-directories audit log --since 7d",
+					"awesome-directories audit log --since 2024-01-01 --action submissions --json",
+				),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "since",
+						Usage: "Only show entries newer than a duration (30m, 12h, 7d, 2w) or a date (YYYY-MM-DD)",
+					},
+					&cli.StringFlag{
+						Name:  "action",
+						Usage: "Only show actions starting with this prefix, such as submissions or favorites.add",
+					},
+					&cli.StringFlag{
+						Name:  "user",
+						Usage: "Only show entries of this OS user",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output as JSON Lines",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					var since time.Time
+					if value := cmd.String("since"); value != "" {
+						var err error
+						if since, err = parseSince(value, time.Now()); err != nil {
+							return err
+						}
+					}
+
+					dataStore, err := openStore()
+					if err != nil {
+						return err
+					}
+
+					entries, err := dataStore.AuditLog(since)
+					if err != nil {
+						return err
+					}
+
+					var shown []store.AuditEntry
+					for _, entry := range entries {
+						if action := cmd.String("action"); action != "" && !strings.HasPrefix(entry.Action, action) {
+							continue
+						}
+						if user := cmd.String("user"); user != "" && entry.User != user {
+							continue
+						}
+						shown = append(shown, entry)
+					}
+					recordResults(ctx, len(shown))
+
+					if cmd.Bool("json") {
+						for _, entry := range shown {
+							data, err := json.Marshal(entry)
+							if err != nil {
+								return fmt.Errorf("failed to marshal audit entry: %w", err)
+							}
+							u.Println(string(data))
+						}
+						return nil
+					}
+
+					if len(shown) == 0 {
+						u.Warning("No audit entries found")
+						return nil
+					}
+
+					table := u.CreateTable([]string{"Time", "User", "Action", "Target", "Detail"})
+					for _, entry := range shown {
+						table.Row(
+							entry.Time.Local().Format("2006-01-02 15:04:05"),
+							entry.User,
+							entry.Action,
+							entry.Target,
+							ui.TruncateString(entry.Detail, 50),
+						)
+					}
+					u.Println(table)
+					u.Info("%d entries", len(shown))
+
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// recordAudit appends an entry to the audit log. Failing to record an entry
+// does not fail the change itself.
+func recordAudit(dataStore *store.Store, action, target, detail string) {
+	if err := dataStore.Audit(action, target, detail); err != nil {
+		log.Warn().Err(err).Str("action", action).Msg("Failed to record audit entry")
+	}
+}
+
+// recordChange records a change to local data that undo can revert, along
+// with the record as it was; before is nil for a record the change created
+func recordChange(dataStore *store.Store, action, target, detail string, before interface{}) {
+	if err := dataStore.AuditChange(action, target, detail, before); err != nil {
+		log.Warn().Err(err).Str("action", action).Msg("Failed to record audit entry")
+	}
+}
+
+// recordConfigAudit records a change to the config file
+func recordConfigAudit(cfg *config.Config, detail string) {
+	recordAudit(store.New(cfg), "config.edit", "config.yaml", detail)
+}
+
+// parseSince parses a --since value: a duration such as 12h, 7d or 2w
+// before now, or a date
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, err := strconv.Atoi(strings.TrimSuffix(value, suffix)); err == nil && strings.HasSuffix(value, suffix) && n >= 0 {
+			return now.Add(-time.Duration(n) * unit), nil
+		}
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid --since value: %s (use a duration such as 12h, 7d or 2w, or a date YYYY-MM-DD)", value)
+	}
+	return now.Add(-d), nil
+}
+
+// changedFlags lists the flags set on a command, to describe an update
+func changedFlags(cmd *cli.Command) string {
+	return strings.Join(cmd.LocalFlagNames(), ", ")
+}
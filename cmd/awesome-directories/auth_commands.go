@@ -3,13 +3,17 @@ package main
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v3"
 
 	"github.com/awesome-directories/cli/internal/api"
 	"github.com/awesome-directories/cli/internal/auth"
 	"github.com/awesome-directories/cli/internal/cache"
 	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/store"
 	"github.com/awesome-directories/cli/internal/ui"
 	"github.com/awesome-directories/cli/pkg/models"
 )
@@ -22,26 +26,104 @@ func authCommand() *cli.Command {
 		Commands: []*cli.Command{
 			{
 				Name:  "login",
-				Usage: "Login via browser OAuth",
+				Usage: "Login via browser OAuth or with email and password",
+				Metadata: examples(
+					"awesome-directories auth login",
+					"awesome-directories auth login --email you@example.com",
+				),
 				Flags: []cli.Flag{
 					&cli.StringFlag{
 						Name:  "provider",
 						Usage: "OAuth provider: google or github",
 						Value: "google",
 					},
+					&cli.StringFlag{
+						Name:  "email",
+						Usage: "Login with email and password instead of OAuth",
+					},
 				},
 				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if email := cmd.String("email"); email != "" {
+						cfg, err := config.Load()
+						if err != nil {
+							return fmt.Errorf("failed to load config: %w", err)
+						}
+
+						if dryRun(cmd) {
+							u.Info("Dry run: would log in as %s and save the session to the config", email)
+							return nil
+						}
+
+						password, err := u.ReadPassword("Password: ")
+						if err != nil {
+							return err
+						}
+
+						if err := auth.LoginWithPassword(ctx, cfg, email, password); err != nil {
+							return err
+						}
+						recordConfigAudit(cfg, "logged in as "+email)
+						return nil
+					}
+
 					provider := cmd.String("provider")
 					if provider != "google" && provider != "github" {
 						return fmt.Errorf("invalid provider: %s (use google or github)", provider)
 					}
 
-					ui.Warning("Browser-based OAuth is not fully implemented yet.")
-					ui.Info("Please use 'auth token' command with a token from awesome-directories.com")
+					u.Warning("Browser-based OAuth is not fully implemented yet.")
+					u.Info("Please use 'auth token' command with a token from awesome-directories.com")
 
 					return nil
 				},
 			},
+			{
+				Name:  "signup",
+				Usage: "Create a new account with email and password",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "email",
+						Usage:    "Email address for the new account",
+						Required: true,
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					email := cmd.String("email")
+					if !strings.Contains(email, "@") {
+						return fmt.Errorf("invalid email address: %s", email)
+					}
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would create an account for %s", email)
+						return nil
+					}
+
+					password, err := u.ReadPassword("Choose a password: ")
+					if err != nil {
+						return err
+					}
+
+					confirm, err := u.ReadPassword("Confirm password: ")
+					if err != nil {
+						return err
+					}
+
+					if password != confirm {
+						return fmt.Errorf("passwords do not match")
+					}
+
+					return auth.SignUp(ctx, cfg, email, password)
+				},
+			},
 			{
 				Name:      "token",
 				Usage:     "Login with an auth token",
@@ -58,9 +140,15 @@ func authCommand() *cli.Command {
 						return fmt.Errorf("failed to load config: %w", err)
 					}
 
+					if dryRun(cmd) {
+						ui.FromContext(ctx).Info("Dry run: would save the token to the config")
+						return nil
+					}
+
 					if err := auth.LoginWithToken(cfg, token); err != nil {
 						return fmt.Errorf("failed to login: %w", err)
 					}
+					recordConfigAudit(cfg, "logged in with a token")
 
 					return nil
 				},
@@ -74,24 +162,42 @@ func authCommand() *cli.Command {
 						return fmt.Errorf("failed to load config: %w", err)
 					}
 
+					if dryRun(cmd) {
+						ui.FromContext(ctx).Info("Dry run: would end the session and clear the token from the config")
+						return nil
+					}
+
 					if err := auth.Logout(cfg); err != nil {
 						return fmt.Errorf("failed to logout: %w", err)
 					}
+					invalidateUserData(cfg)
+					recordConfigAudit(cfg, "logged out")
 
 					return nil
 				},
 			},
 			{
 				Name:  "whoami",
-				Usage: "Show current authenticated user",
+				Usage: "Show current authenticated user, plan and quota",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output as JSON",
+					},
+				},
 				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
 					cfg, err := config.Load()
 					if err != nil {
 						return fmt.Errorf("failed to load config: %w", err)
 					}
 
 					if cfg.AuthToken == "" {
-						ui.Warning("Not authenticated. Use 'auth token' or 'auth login' to authenticate.")
+						if cmd.Bool("json") {
+							return printJSON(u, whoamiOutput{Authenticated: false})
+						}
+						u.Warning("Not authenticated. Use 'auth token' or 'auth login' to authenticate.")
 						return nil
 					}
 
@@ -100,9 +206,61 @@ func authCommand() *cli.Command {
 						return fmt.Errorf("failed to get user info: %w", err)
 					}
 
-					ui.Bold("Authenticated as:")
-					fmt.Printf("  Email: %s\n", user.Email)
-					fmt.Printf("  ID: %s\n", user.ID)
+					output := whoamiOutput{
+						Authenticated: true,
+						ID:            user.ID,
+						Email:         user.Email,
+						Provider:      user.AppMetadata.Provider,
+						Plan:          user.AppMetadata.Plan,
+					}
+					if !user.CreatedAt.IsZero() {
+						output.CreatedAt = &user.CreatedAt
+					}
+					if !cfg.TokenExpiresAt.IsZero() {
+						output.ExpiresAt = &cfg.TokenExpiresAt
+					}
+					stats, err := cache.NewCache(cfg, api.NewClient(cfg)).GetAccountStats(ctx, false)
+					if err != nil {
+						log.Debug().Err(err).Msg("Failed to get account stats")
+					} else {
+						output.FavoritesCount = stats.FavoritesCount
+						output.RateLimit = stats.RateLimit
+					}
+
+					if cmd.Bool("json") {
+						return printJSON(u, output)
+					}
+
+					u.Bold("Authenticated as:")
+					u.Printf("  Email: %s\n", output.Email)
+					u.Printf("  ID: %s\n", output.ID)
+					if output.Provider != "" {
+						u.Printf("  Provider: %s\n", output.Provider)
+					}
+					if output.CreatedAt != nil {
+						u.Printf("  Member since: %s\n", output.CreatedAt.Format("2006-01-02"))
+					}
+					if output.ExpiresAt != nil {
+						u.Printf("  Session expires: %s\n", output.ExpiresAt.Local().Format("2006-01-02 15:04"))
+					}
+
+					u.Println()
+					u.Bold("Plan:")
+					// The API doesn't always tell the tier; JSON leaves it out then
+					tier := output.Plan
+					if tier == "" {
+						tier = "unknown"
+					}
+					u.Printf("  Tier: %s\n", tier)
+					if stats != nil {
+						u.Printf("  Favorites: %d\n", output.FavoritesCount)
+					}
+					if output.RateLimit != nil {
+						u.Printf("  API quota: %d of %d requests remaining\n", output.RateLimit.Remaining, output.RateLimit.Limit)
+						if !output.RateLimit.Reset.IsZero() {
+							u.Printf("  Quota resets: %s\n", output.RateLimit.Reset.Local().Format("2006-01-02 15:04"))
+						}
+					}
 
 					return nil
 				},
@@ -111,6 +269,19 @@ func authCommand() *cli.Command {
 	}
 }
 
+// whoamiOutput is the machine-readable output of auth whoami
+type whoamiOutput struct {
+	Authenticated  bool              `json:"authenticated"`
+	ID             string            `json:"id,omitempty"`
+	Email          string            `json:"email,omitempty"`
+	Provider       string            `json:"provider,omitempty"`
+	Plan           string            `json:"plan,omitempty"`
+	CreatedAt      *time.Time        `json:"created_at,omitempty"`
+	ExpiresAt      *time.Time        `json:"session_expires_at,omitempty"`
+	FavoritesCount int               `json:"favorites_count"`
+	RateLimit      *models.RateLimit `json:"rate_limit,omitempty"`
+}
+
 // favoritesCommand creates the favorites command
 func favoritesCommand() *cli.Command {
 	return &cli.Command{
@@ -121,7 +292,15 @@ func favoritesCommand() *cli.Command {
 			{
 				Name:  "list",
 				Usage: "List favorite directories",
+				Flags: append(formatFlags(),
+					&cli.BoolFlag{
+						Name:  "refresh",
+						Usage: "Fetch the favorites from the API even when they are cached",
+					},
+				),
 				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
 					cfg, err := config.Load()
 					if err != nil {
 						return fmt.Errorf("failed to load config: %w", err)
@@ -135,13 +314,16 @@ func favoritesCommand() *cli.Command {
 					cacheClient := cache.NewCache(cfg, apiClient)
 
 					// Get favorites
-					favorites, err := apiClient.GetFavorites(ctx)
+					favorites, err := cacheClient.GetFavorites(ctx, cmd.Bool("refresh"))
 					if err != nil {
 						return fmt.Errorf("failed to get favorites: %w", err)
 					}
 
 					if len(favorites) == 0 {
-						ui.Warning("No favorites yet. Use 'favorites add <slug>' to add directories.")
+						if !isTableFormat(cmd) {
+							return renderDirectories(ctx, u, cmd, nil)
+						}
+						u.Warning("No favorites yet. Use 'favorites add <slug>' to add directories.")
 						return nil
 					}
 
@@ -150,22 +332,25 @@ func favoritesCommand() *cli.Command {
 					if err != nil {
 						return fmt.Errorf("failed to get directories: %w", err)
 					}
+					recordProvenance(ctx, cacheClient.Provenance())
 
-					// Filter to favorite directories
-					favoriteMap := make(map[string]bool)
-					for _, fav := range favorites {
-						favoriteMap[fav.DirectoryID] = true
+					// Favorites missing from the cached catalog, such as
+					// directories added since the last sync, are fetched
+					ids := make([]string, len(favorites))
+					for i, fav := range favorites {
+						ids[i] = fav.DirectoryID
 					}
+					found, missing := cache.Resolve(directories, ids, cache.ByID, cacheClient.Lineage())
 
-					var favoriteDirectories []models.Directory
-					for _, dir := range directories {
-						if favoriteMap[dir.ID] {
-							favoriteDirectories = append(favoriteDirectories, dir)
-						}
+					favoriteDirectories, err := renderHydrated(ctx, u, cmd, found, missing, cache.ByID)
+					if err != nil {
+						return err
 					}
+					recordResults(ctx, len(favoriteDirectories))
 
-					displayDirectoriesTable(favoriteDirectories)
-					ui.Info("You have %d favorite directories", len(favoriteDirectories))
+					if isTableFormat(cmd) {
+						u.Info("You have %d favorite directories", len(favoriteDirectories))
+					}
 
 					return nil
 				},
@@ -175,6 +360,8 @@ func favoritesCommand() *cli.Command {
 				Usage:     "Add a directory to favorites",
 				ArgsUsage: "<slug>",
 				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
 					if cmd.Args().Len() == 0 {
 						return fmt.Errorf("directory slug is required")
 					}
@@ -198,12 +385,19 @@ func favoritesCommand() *cli.Command {
 						return fmt.Errorf("failed to get directory: %w", err)
 					}
 
+					if dryRun(cmd) {
+						u.Info("Dry run: would add '%s' to favorites", directory.Name)
+						return nil
+					}
+
 					// Add to favorites
 					if err := apiClient.AddFavorite(ctx, directory.ID); err != nil {
 						return fmt.Errorf("failed to add favorite: %w", err)
 					}
 
-					ui.Success("Added '%s' to favorites", directory.Name)
+					invalidateUserData(cfg)
+					recordChange(store.New(cfg), "favorites.add", directory.Slug, "", nil)
+					u.Success("Added '%s' to favorites", directory.Name)
 
 					return nil
 				},
@@ -214,6 +408,8 @@ func favoritesCommand() *cli.Command {
 				Usage:     "Remove a directory from favorites",
 				ArgsUsage: "<slug>",
 				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
 					if cmd.Args().Len() == 0 {
 						return fmt.Errorf("directory slug is required")
 					}
@@ -237,65 +433,20 @@ func favoritesCommand() *cli.Command {
 						return fmt.Errorf("failed to get directory: %w", err)
 					}
 
+					if dryRun(cmd) {
+						u.Info("Dry run: would remove '%s' from favorites", directory.Name)
+						return nil
+					}
+
 					// Remove from favorites
 					if err := apiClient.RemoveFavorite(ctx, directory.ID); err != nil {
 						return fmt.Errorf("failed to remove favorite: %w", err)
 					}
 
-					ui.Success("Removed '%s' from favorites", directory.Name)
-
-					return nil
-				},
-			},
-		},
-	}
-}
+					invalidateUserData(cfg)
+					recordChange(store.New(cfg), "favorites.remove", directory.Slug, "", directory.Slug)
+					u.Success("Removed '%s' from favorites", directory.Name)
 
-// submissionsCommand creates the submissions command (stubbed for future)
-func submissionsCommand() *cli.Command {
-	return &cli.Command{
-		Name:    "submissions",
-		Aliases: []string{"sub"},
-		Usage:   "Track directory submissions (coming soon)",
-		Commands: []*cli.Command{
-			{
-				Name:  "list",
-				Usage: "List your directory submissions",
-				Action: func(ctx context.Context, cmd *cli.Command) error {
-					ui.Warning("Submissions tracking is not yet implemented.")
-					ui.Info("This feature will be available once the website implements it.")
-					ui.Info("Stay tuned for updates!")
-					return nil
-				},
-			},
-			{
-				Name:      "track",
-				Usage:     "Track a directory submission",
-				ArgsUsage: "<slug> --status <status>",
-				Flags: []cli.Flag{
-					&cli.StringFlag{
-						Name:     "status",
-						Usage:    "Submission status: pending, submitted, approved, rejected",
-						Required: true,
-					},
-					&cli.StringFlag{
-						Name:  "notes",
-						Usage: "Add notes about this submission",
-					},
-				},
-				Action: func(ctx context.Context, cmd *cli.Command) error {
-					ui.Warning("Submissions tracking is not yet implemented.")
-					ui.Info("This feature will be available once the website implements it.")
-					return nil
-				},
-			},
-			{
-				Name:      "notes",
-				Usage:     "Add notes to a submission",
-				ArgsUsage: "<slug> <notes>",
-				Action: func(ctx context.Context, cmd *cli.Command) error {
-					ui.Warning("Submissions tracking is not yet implemented.")
-					ui.Info("This feature will be available once the website implements it.")
 					return nil
 				},
 			},
@@ -0,0 +1,544 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/github"
+	"github.com/awesome-directories/cli/internal/sample"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/tracker"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// defaultBoardColumns maps submission statuses to the columns of GitHub's
+// default board template, used when no column is named after the status
+var defaultBoardColumns = map[string]string{
+	"pending":   "Todo",
+	"submitted": "In Progress",
+	"approved":  "Done",
+	"rejected":  "Done",
+}
+
+// boardScopeFlags returns the flags selecting the submissions on a board
+func boardScopeFlags() []cli.Flag {
+	return []cli.Flag{
+		projectFlag(),
+		&cli.BoolFlag{
+			Name:  "all-projects",
+			Usage: "Include submissions of every project",
+		},
+	}
+}
+
+// submissionBoardCommand creates the submissions board command
+func submissionBoardCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "board",
+		Usage: "Show tracked submissions as a kanban board, one column per status",
+		Metadata: examples(
+			"awesome-directories submissions board",
+			"awesome-directories submissions board --format markdown > BOARD.md",
+			"awesome-directories submissions board --all-projects --format json",
+		),
+		Flags: append(boardScopeFlags(), &cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "Output format: table, markdown, json",
+			Value:   "table",
+		}),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			submissions, err := scopedSubmissions(cfg, cmd)
+			if err != nil {
+				return err
+			}
+			recordResults(ctx, len(submissions))
+
+			directories := boardDirectories(ctx, cfg)
+			columns := make(map[string][]models.TrackedSubmission)
+			for _, submission := range submissions {
+				columns[submission.Status] = append(columns[submission.Status], submission)
+			}
+
+			switch strings.ToLower(cmd.String("format")) {
+			case "", "table":
+				if len(submissions) == 0 {
+					u.Warning("No submissions tracked yet. Use 'submissions track <slug> --status <status>' to add one.")
+					return nil
+				}
+
+				headers := make([]string, len(models.SubmissionStatuses))
+				rows := 0
+				for i, status := range models.SubmissionStatuses {
+					headers[i] = fmt.Sprintf("%s (%d)", strings.ToUpper(status[:1])+status[1:], len(columns[status]))
+					rows = max(rows, len(columns[status]))
+				}
+
+				table := u.CreateTable(headers)
+				for row := 0; row < rows; row++ {
+					cells := make([]string, len(models.SubmissionStatuses))
+					for i, status := range models.SubmissionStatuses {
+						if row < len(columns[status]) {
+							cells[i] = ui.TruncateString(boardCardTitle(columns[status][row], directories, cmd.Bool("all-projects")), 30)
+						}
+					}
+					table.Row(cells...)
+				}
+				u.Println(table)
+				return nil
+
+			case "markdown", "md":
+				u.Println("# Submission board")
+				for _, status := range models.SubmissionStatuses {
+					u.Printf("\n## %s (%d)\n\n", strings.ToUpper(status[:1])+status[1:], len(columns[status]))
+					for _, submission := range columns[status] {
+						u.Printf("%s\n", boardMarkdownCard(submission, directories))
+					}
+				}
+				return nil
+
+			case "json":
+				board := make(map[string][]models.TrackedSubmission, len(models.SubmissionStatuses))
+				for _, status := range models.SubmissionStatuses {
+					board[status] = append([]models.TrackedSubmission{}, columns[status]...)
+				}
+
+				data, err := json.MarshalIndent(board, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal board: %w", err)
+				}
+				u.Println(string(data))
+				return nil
+
+			default:
+				return fmt.Errorf("unsupported format: %s (use table, markdown, json)", cmd.String("format"))
+			}
+		},
+	}
+}
+
+// submissionGitHubSyncCommand creates the submissions github-sync command
+func submissionGitHubSyncCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "github-sync",
+		Usage:     "Push tracked submissions to a GitHub Projects board",
+		ArgsUsage: "<project-url>",
+		Metadata: examples(
+			"awesome-directories submissions github-sync https://github.com/orgs/acme/projects/3",
+			"awesome-directories submissions github-sync https://github.com/users/me/projects/1 --column approved=Live --dry-run",
+		),
+		Flags: append(boardScopeFlags(),
+			&cli.StringSliceFlag{
+				Name:  "column",
+				Usage: "Map a status to a board column, as status=Column (can be specified multiple times)",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be pushed without changing the board",
+			},
+		),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() == 0 {
+				return fmt.Errorf("GitHub project URL is required")
+			}
+
+			owner, number, err := github.ParseProjectURL(cmd.Args().First())
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			if cfg.GitHubToken == "" {
+				return fmt.Errorf("a GitHub token with the project scope is required: set GITHUB_TOKEN")
+			}
+
+			mapping := make(map[string]string)
+			for _, entry := range cmd.StringSlice("column") {
+				status, column, ok := strings.Cut(entry, "=")
+				status = strings.ToLower(strings.TrimSpace(status))
+				if !ok || !models.ValidSubmissionStatus(status) || strings.TrimSpace(column) == "" {
+					return fmt.Errorf("invalid column mapping: %s (use status=Column)", entry)
+				}
+				mapping[status] = strings.TrimSpace(column)
+			}
+
+			submissions, err := scopedSubmissions(cfg, cmd)
+			if err != nil {
+				return err
+			}
+			if len(submissions) == 0 {
+				u.Warning("No submissions to sync")
+				return nil
+			}
+
+			client := github.NewClient(cfg.GitHubToken)
+			project, err := client.Project(ctx, owner, number)
+			if err != nil {
+				return err
+			}
+
+			directories := boardDirectories(ctx, cfg)
+			added, moved := 0, 0
+			for _, submission := range submissions {
+				title := boardCardTitle(submission, directories, submission.Project != models.DefaultProject)
+
+				optionID, column, ok := boardColumn(project, submission.Status, mapping)
+				if !ok {
+					return fmt.Errorf("project %q has no column for status %s: add one or use --column %s=<Column>",
+						project.Title, submission.Status, submission.Status)
+				}
+
+				if dryRun(cmd) {
+					action := "update"
+					if _, exists := project.Items[title]; !exists {
+						action = "add"
+					}
+					u.Printf("  %s %s → %s\n", action, title, column)
+					continue
+				}
+
+				itemID, exists := project.Items[title]
+				if !exists {
+					itemID, err = client.AddDraftIssue(ctx, project.ID, title, boardIssueBody(submission, directories))
+					if err != nil {
+						return err
+					}
+					added++
+				}
+
+				if err := client.SetStatus(ctx, project, itemID, optionID); err != nil {
+					return err
+				}
+				if exists {
+					moved++
+				}
+			}
+			recordResults(ctx, len(submissions))
+
+			if dryRun(cmd) {
+				u.Info("Dry run: %s was not changed", project.Title)
+				return nil
+			}
+
+			u.Success("Synced %d submissions to %s (%d added, %d updated)", len(submissions), project.Title, added, moved)
+			return nil
+		},
+	}
+}
+
+// submissionPushCommand creates the submissions push command
+func submissionPushCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "push",
+		Usage: "Create or update an issue per submission in Linear or Jira",
+		Metadata: examples(
+			"awesome-directories submissions push --linear-team GROW",
+			"awesome-directories submissions push --jira-project MKT --status pending --dry-run",
+		),
+		Flags: append(boardScopeFlags(),
+			&cli.StringFlag{
+				Name:  "linear-team",
+				Usage: "Key of the Linear team to push to (requires LINEAR_API_KEY)",
+			},
+			&cli.StringFlag{
+				Name:  "jira-project",
+				Usage: "Key of the Jira project to push to (requires JIRA_URL, JIRA_EMAIL and JIRA_API_TOKEN)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "status",
+				Usage: "Only push submissions with this status",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show the issues that would be pushed without pushing them",
+			},
+		),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			issueTracker, err := submissionTracker(cfg, cmd)
+			if err != nil {
+				return err
+			}
+
+			submissions, err := scopedSubmissions(cfg, cmd)
+			if err != nil {
+				return err
+			}
+
+			var pushed []models.TrackedSubmission
+			for _, submission := range submissions {
+				if statuses := cmd.StringSlice("status"); len(statuses) == 0 || containsFold(statuses, submission.Status) {
+					pushed = append(pushed, submission)
+				}
+			}
+			if len(pushed) == 0 {
+				u.Warning("No submissions to push")
+				return nil
+			}
+
+			dataStore := store.New(cfg)
+			directories := boardDirectories(ctx, cfg)
+			created, updated := 0, 0
+			for _, submission := range pushed {
+				issue := submissionIssue(submission, directories)
+				key := submission.Issues[issueTracker.Name()]
+
+				if dryRun(cmd) {
+					action := "create"
+					if key != "" {
+						action = "update " + key
+					}
+					u.Printf("  %s: %s [%s]\n", action, issue.Title, strings.Join(issue.Labels, ", "))
+					continue
+				}
+
+				key, url, err := issueTracker.Push(ctx, key, issue)
+				if err != nil {
+					return err
+				}
+
+				if submission.Issues[issueTracker.Name()] == "" {
+					created++
+				} else {
+					updated++
+				}
+
+				// Reload the submission so edits made while pushing are kept
+				err = dataStore.Transaction(func() error {
+					current, err := dataStore.Submission(submission.Project, submission.Directory)
+					if err != nil {
+						return err
+					}
+					if current == nil {
+						current = &submission
+					}
+					if current.Issues == nil {
+						current.Issues = make(map[string]string)
+					}
+					current.Issues[issueTracker.Name()] = key
+					return dataStore.SaveSubmission(current)
+				})
+				if err != nil {
+					return err
+				}
+				recordAudit(dataStore, "submissions.push", submission.Project+"/"+submission.Directory, issueTracker.Name()+" "+key)
+				u.Printf("  %s %s\n", key, url)
+			}
+			recordResults(ctx, len(pushed))
+
+			if dryRun(cmd) {
+				u.Info("Dry run: nothing was pushed")
+				return nil
+			}
+
+			u.Success("Pushed %d submissions to %s (%d created, %d updated)", len(pushed), issueTracker.Name(), created, updated)
+			return nil
+		},
+	}
+}
+
+// submissionTracker returns the issue tracker selected by --linear-team or
+// --jira-project
+func submissionTracker(cfg *config.Config, cmd *cli.Command) (tracker.Tracker, error) {
+	switch {
+	case cmd.String("linear-team") != "" && cmd.String("jira-project") != "":
+		return nil, fmt.Errorf("use either --linear-team or --jira-project")
+
+	case cmd.String("linear-team") != "":
+		if cfg.LinearAPIKey == "" {
+			return nil, fmt.Errorf("a Linear API key is required: set LINEAR_API_KEY")
+		}
+		return tracker.NewLinear(cfg.LinearAPIKey, cmd.String("linear-team")), nil
+
+	case cmd.String("jira-project") != "":
+		if cfg.JiraURL == "" || cfg.JiraEmail == "" || cfg.JiraAPIToken == "" {
+			return nil, fmt.Errorf("jira credentials are required: set JIRA_URL, JIRA_EMAIL and JIRA_API_TOKEN")
+		}
+		return tracker.NewJira(cfg.JiraURL, cfg.JiraEmail, cfg.JiraAPIToken, cmd.String("jira-project")), nil
+
+	default:
+		return nil, fmt.Errorf("choose a tracker with --linear-team or --jira-project")
+	}
+}
+
+// submissionIssue returns the issue describing a submission, labelled with
+// the directory's categories and domain rating band
+func submissionIssue(submission models.TrackedSubmission, directories map[string]models.Directory) tracker.Issue {
+	issue := tracker.Issue{
+		Title:       "Submit to " + boardCardTitle(submission, directories, submission.Project != models.DefaultProject),
+		Description: fmt.Sprintf("Status: %s\n%s", submission.Status, boardIssueBody(submission, directories)),
+	}
+
+	if dir, ok := directories[submission.Directory]; ok {
+		issue.Labels = append(issue.Labels, dir.Categories...)
+		issue.Labels = append(issue.Labels, sample.DRBand(dir))
+	}
+	return issue
+}
+
+// scopedSubmissions returns the tracked submissions selected by --project
+// and --all-projects
+func scopedSubmissions(cfg *config.Config, cmd *cli.Command) ([]models.TrackedSubmission, error) {
+	submissions, err := store.New(cfg).Submissions()
+	if err != nil {
+		return nil, err
+	}
+
+	if cmd.Bool("all-projects") {
+		return submissions, nil
+	}
+
+	var scoped []models.TrackedSubmission
+	for _, submission := range submissions {
+		if submission.Project == cmd.String("project") {
+			scoped = append(scoped, submission)
+		}
+	}
+	return scoped, nil
+}
+
+// boardDirectories returns the cached directories keyed by slug, to show
+// names and links on cards. The board still works from slugs without them.
+func boardDirectories(ctx context.Context, cfg *config.Config) map[string]models.Directory {
+	bySlug := make(map[string]models.Directory)
+
+	cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+	directories, err := cacheClient.GetDirectories(ctx, false)
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to get directories for the board")
+		return bySlug
+	}
+
+	for _, dir := range directories {
+		bySlug[dir.Slug] = dir
+	}
+	// Submissions tracked before a directory was renamed keep its old slug
+	cacheClient.Lineage().Alias(bySlug, cache.BySlug)
+	return bySlug
+}
+
+// boardColumn returns the option ID and name of the column for a status:
+// an explicit mapping, a column named after the status, or the default
+// board column
+func boardColumn(project *github.Project, status string, mapping map[string]string) (string, string, bool) {
+	candidates := []string{status, defaultBoardColumns[status]}
+	if column, ok := mapping[status]; ok {
+		candidates = []string{column}
+	}
+
+	for _, column := range candidates {
+		if id, ok := project.Column(column); ok {
+			return id, column, true
+		}
+	}
+	return "", "", false
+}
+
+// boardCardTitle returns the title of a submission's card
+func boardCardTitle(submission models.TrackedSubmission, directories map[string]models.Directory, withProject bool) string {
+	title := submission.Directory
+	if dir, ok := directories[submission.Directory]; ok {
+		title = dir.Name
+	}
+	if withProject {
+		title += " (" + submission.Project + ")"
+	}
+	return title
+}
+
+// boardMarkdownCard renders a submission as a Markdown list item
+func boardMarkdownCard(submission models.TrackedSubmission, directories map[string]models.Directory) string {
+	name := submission.Directory
+	if dir, ok := directories[submission.Directory]; ok {
+		name = fmt.Sprintf("[%s](%s)", dir.Name, dir.URL)
+	}
+
+	var card string
+	switch submission.Status {
+	case "approved":
+		card = "- [x] " + name
+	case "rejected":
+		card = "- ~~" + name + "~~"
+	default:
+		card = "- [ ] " + name
+	}
+
+	details := []string{"project `" + submission.Project + "`"}
+	if submission.Product != "" {
+		product := "product " + submission.Product
+		if submission.Variant != "" {
+			product += " (" + submission.Variant + ")"
+		}
+		details = append(details, product)
+	}
+	if len(submission.Todos) > 0 {
+		details = append(details, "todos "+formatTodoProgress(submission.Todos))
+	}
+
+	return card + " — " + strings.Join(details, ", ")
+}
+
+// boardIssueBody renders the body of a submission's draft issue
+func boardIssueBody(submission models.TrackedSubmission, directories map[string]models.Directory) string {
+	var b strings.Builder
+
+	if dir, ok := directories[submission.Directory]; ok {
+		fmt.Fprintf(&b, "Directory: %s\n", dir.URL)
+		if dir.SubmissionURL != "" {
+			fmt.Fprintf(&b, "Submit at: %s\n", dir.SubmissionURL)
+		}
+	}
+	fmt.Fprintf(&b, "Project: %s\n", submission.Project)
+	if submission.Product != "" {
+		fmt.Fprintf(&b, "Product: %s", submission.Product)
+		if submission.Variant != "" {
+			fmt.Fprintf(&b, " (variant %s)", submission.Variant)
+		}
+		b.WriteString("\n")
+	}
+
+	if submission.Notes != "" {
+		fmt.Fprintf(&b, "\n%s\n", submission.Notes)
+	}
+
+	if len(submission.Todos) > 0 {
+		b.WriteString("\n")
+		for _, todo := range submission.Todos {
+			mark := " "
+			if todo.Done {
+				mark = "x"
+			}
+			fmt.Fprintf(&b, "- [%s] %s\n", mark, todo.Text)
+		}
+	}
+
+	return b.String()
+}
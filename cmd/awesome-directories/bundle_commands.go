@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/export"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// exportBundleCommand creates the sync export-bundle command
+func exportBundleCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "export-bundle",
+		Usage:     "Write the catalog, or its changes since a date, to a bundle for a machine without API access",
+		ArgsUsage: "FILE",
+		Metadata: examples(
+			"awesome-directories sync export-bundle bundle.bin",
+			"awesome-directories sync export-bundle bundle.bin --since 2025-06-01",
+		),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only bundle directories updated since a date (YYYY-MM-DD) or a duration (12h, 7d, 2w), as printed by import-bundle",
+			},
+			&cli.BoolFlag{
+				Name:  "cached",
+				Usage: "Bundle the cached catalog without syncing it first",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite the bundle file if it exists",
+			},
+			&cli.BoolFlag{
+				Name:  "backup",
+				Usage: "Keep a timestamped copy of an existing bundle file",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() == 0 {
+				return fmt.Errorf("bundle file is required")
+			}
+			path := cmd.Args().First()
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			var since time.Time
+			if value := cmd.String("since"); value != "" {
+				if since, err = parseSince(value, time.Now()); err != nil {
+					return err
+				}
+			}
+
+			cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+			if !cmd.Bool("cached") {
+				if _, err := cacheClient.Refresh(ctx); err != nil {
+					return fmt.Errorf("failed to sync cache: %w", err)
+				}
+			}
+
+			bundle, err := cacheClient.ExportBundle(since)
+			if err != nil {
+				return err
+			}
+
+			backupPath, err := export.PrepareOutput(path, cmd.Bool("force"), cmd.Bool("backup"))
+			if err != nil {
+				return err
+			}
+			if backupPath != "" {
+				u.Info("Backed up existing %s to %s", path, backupPath)
+			}
+			file, err := os.Create(path)
+			if err != nil {
+				return fmt.Errorf("failed to create bundle file: %w", err)
+			}
+			if err := cache.WriteBundle(file, bundle); err != nil {
+				_ = file.Close()
+				return err
+			}
+			if err := file.Close(); err != nil {
+				return fmt.Errorf("failed to write bundle file: %w", err)
+			}
+
+			if since.IsZero() {
+				u.Success("Bundled the catalog of %d directories to %s", len(bundle.IDs), path)
+			} else {
+				u.Success("Bundled %d of %d directories updated since %s to %s",
+					len(bundle.Directories), len(bundle.IDs), since.Local().Format("2006-01-02 15:04"), path)
+			}
+			return nil
+		},
+	}
+}
+
+// importBundleCommand creates the sync import-bundle command
+func importBundleCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "import-bundle",
+		Usage:     "Update the cached catalog from a bundle written by export-bundle",
+		ArgsUsage: "FILE",
+		Metadata: examples(
+			"awesome-directories sync import-bundle bundle.bin",
+			"awesome-directories sync import-bundle bundle.bin --dry-run",
+		),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() == 0 {
+				return fmt.Errorf("bundle file is required")
+			}
+			path := cmd.Args().First()
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			file, err := os.Open(path)
+			if err != nil {
+				return fmt.Errorf("failed to open bundle file: %w", err)
+			}
+			defer func() {
+				if err := file.Close(); err != nil {
+					log.Warn().Err(err).Msg("Failed to close bundle file")
+				}
+			}()
+
+			bundle, err := cache.ReadBundle(file)
+			if err != nil {
+				return err
+			}
+
+			// Importing never needs the API
+			cacheClient := cache.NewCache(cfg, nil)
+			imp, err := cacheClient.PlanBundle(bundle)
+			if err != nil {
+				return err
+			}
+
+			if dryRun(cmd) {
+				u.Info("Dry run: would add %d, update %d and remove %d directories", imp.Added, imp.Updated, imp.Removed)
+				return nil
+			}
+
+			if err := cacheClient.ImportBundle(imp); err != nil {
+				return fmt.Errorf("failed to import bundle: %w", err)
+			}
+
+			u.Success("Imported bundle from %s: %d added, %d updated, %d removed",
+				bundle.CreatedAt.Local().Format("2006-01-02 15:04"), imp.Added, imp.Updated, imp.Removed)
+			if current := cache.CatalogUpdatedAt(imp.Directories); !current.IsZero() {
+				u.Info("Next time, export a bundle with --since %s", current.Local().Format("2006-01-02"))
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// inspectPartitionMax is how many values of a partition cache inspect lists
+const inspectPartitionMax = 10
+
+// cacheCommand creates the cache command
+func cacheCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "cache",
+		Usage: "Maintain the local cache",
+		Commands: []*cli.Command{
+			{
+				Name:  "inspect",
+				Usage: "Show what the cache holds: sync state, sizes and catalog breakdown",
+				Metadata: examples(
+					"awesome-directories cache inspect",
+					"awesome-directories cache inspect --json",
+				),
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output as JSON",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					inspection, err := cache.NewCache(cfg, api.NewClient(cfg)).Inspect()
+					if err != nil {
+						return fmt.Errorf("failed to inspect cache: %w", err)
+					}
+
+					if cmd.Bool("json") {
+						return printJSON(u, inspection)
+					}
+
+					u.Bold("Cache")
+					u.Printf("  Directory: %s\n", inspection.Dir)
+					u.Printf("  Source: %s\n", inspection.Source)
+					if inspection.LastSync.IsZero() {
+						u.Printf("  Last sync: never\n")
+					} else {
+						state := "valid"
+						if !inspection.Valid {
+							state = "expired"
+						}
+						u.Printf("  Last sync: %s (%s ago, %s)\n", inspection.LastSync.Local().Format("2006-01-02 15:04"),
+							time.Since(inspection.LastSync).Round(time.Second), state)
+						u.Printf("  Schema version: %s\n", inspection.SchemaVersion)
+					}
+					u.Printf("  Directories: %d\n", inspection.Directories)
+					u.Println()
+
+					table := u.CreateTable([]string{"Part", "Entries", "Size"})
+					for _, file := range inspection.Files {
+						table.Row(file.Name, fmt.Sprint(file.Entries), ui.FormatBytes(file.Size))
+					}
+					u.Println(table)
+
+					limit := "no limit"
+					if inspection.MaxSize > 0 {
+						limit = "limit " + ui.FormatBytes(inspection.MaxSize)
+					}
+					u.Info("%s used (%s)", ui.FormatBytes(inspection.TotalSize), limit)
+
+					partitions := []struct{ key, title string }{
+						{"pricing", "Pricing"},
+						{"link_type", "Link Type"},
+						{"category", "Category"},
+					}
+					for _, partition := range partitions {
+						counts := inspection.Partitions[partition.key]
+						if len(counts) == 0 {
+							continue
+						}
+
+						keys := make([]string, 0, len(counts))
+						for key := range counts {
+							keys = append(keys, key)
+						}
+						sort.Slice(keys, func(i, j int) bool {
+							if counts[keys[i]] != counts[keys[j]] {
+								return counts[keys[i]] > counts[keys[j]]
+							}
+							return keys[i] < keys[j]
+						})
+
+						u.Println()
+						table := u.CreateTable([]string{partition.title, "Directories"})
+						for i, key := range keys {
+							if i == inspectPartitionMax {
+								table.Row(fmt.Sprintf("(%d more)", len(keys)-i), "")
+								break
+							}
+							table.Row(key, fmt.Sprint(counts[key]))
+						}
+						u.Println(table)
+					}
+
+					if histogram := inspection.DRHistogram; histogram != nil && inspection.Directories > 0 {
+						u.Println()
+						table := u.CreateTable([]string{"Domain Rating", "Directories"})
+						for band, count := range histogram {
+							label := fmt.Sprintf("%d-%d", band*10, band*10+9)
+							if band == len(histogram)-1 {
+								label = fmt.Sprintf("%d-100", band*10)
+							}
+							table.Row(label, fmt.Sprint(count))
+						}
+						u.Println(table)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:  "gc",
+				Usage: "Prune old snapshots, alerts and crash reports",
+				Metadata: examples(
+					"awesome-directories cache gc",
+					"awesome-directories cache gc --max-size 100 --dry-run",
+					"awesome-directories cache gc --older-than 90d",
+				),
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "max-size",
+						Usage: "Prune the cache down to this many MB (default: cache_max_size_mb from config.yaml)",
+					},
+					&cli.StringFlag{
+						Name:  "older-than",
+						Usage: "Also prune alerts and crash reports older than a duration (30d, 2w) or a date (YYYY-MM-DD)",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Show what would be pruned without removing anything",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					options := cache.GCOptions{
+						MaxSize: int64(cfg.CacheMaxSizeMB) << 20,
+						DryRun:  dryRun(cmd),
+					}
+					if cmd.IsSet("max-size") {
+						options.MaxSize = int64(cmd.Int("max-size")) << 20
+					}
+					if value := cmd.String("older-than"); value != "" {
+						if options.Before, err = parseSince(value, time.Now()); err != nil {
+							return err
+						}
+					}
+
+					cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+					result, err := cacheClient.GC(options)
+					if err != nil {
+						return fmt.Errorf("failed to garbage collect cache: %w", err)
+					}
+
+					verb := "Removed"
+					if options.DryRun {
+						verb = "Would remove"
+					}
+					if len(result.Snapshots) > 0 {
+						u.Info("%s %d snapshot(s): %s", verb, len(result.Snapshots), strings.Join(result.Snapshots, ", "))
+					}
+					if result.Alerts > 0 {
+						u.Info("%s %d alert(s)", verb, result.Alerts)
+					}
+					if len(result.CrashReports) > 0 {
+						u.Info("%s %d crash report(s)", verb, len(result.CrashReports))
+					}
+
+					if result.Reclaimed() == 0 {
+						u.Success("Nothing to prune (%s used)", ui.FormatBytes(result.SizeBefore))
+						return nil
+					}
+
+					if options.DryRun {
+						u.Info("Dry run: would reclaim %s (%s → %s)", ui.FormatBytes(result.Reclaimed()),
+							ui.FormatBytes(result.SizeBefore), ui.FormatBytes(result.SizeAfter))
+						return nil
+					}
+					u.Success("Reclaimed %s (%s → %s)", ui.FormatBytes(result.Reclaimed()),
+						ui.FormatBytes(result.SizeBefore), ui.FormatBytes(result.SizeAfter))
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,512 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/dates"
+	"github.com/awesome-directories/cli/internal/plan"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// defaultFollowUpDays is how long after a submission a follow-up is due,
+// for directories without a known review time
+const defaultFollowUpDays = 14
+
+// Kinds of calendar entries, in the order they are listed within a day
+const (
+	calendarEvent    = "event"
+	calendarPlanned  = "planned"
+	calendarFollowUp = "follow-up"
+)
+
+// calendarKinds lists the kinds of calendar entries with the letters
+// marking them in the month grid
+var calendarKinds = []struct{ kind, mark string }{
+	{calendarEvent, "E"},
+	{calendarPlanned, "P"},
+	{calendarFollowUp, "F"},
+}
+
+// calendarEntry is a day of the calendar
+type calendarEntry struct {
+	Date      string `json:"date"`
+	Kind      string `json:"kind"`
+	Directory string `json:"directory"`
+	Name      string `json:"name"`
+	Project   string `json:"project,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+}
+
+// calendarOutput is the JSON representation of the calendar command
+type calendarOutput struct {
+	Month   string          `json:"month"`
+	Entries []calendarEntry `json:"entries"`
+}
+
+// calendarCommand creates the calendar command
+func calendarCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "calendar",
+		Usage:     "Show a month of planned submissions, follow-ups and directory events",
+		ArgsUsage: "[YYYY-MM]",
+		Description: `Pending submissions are laid out from today following the pacing rules of
+their project, as plan --schedule would. A follow-up is due once a submitted
+directory's review time has passed, or two weeks without one. Events are the
+launch days and other dates of directories recorded with add-event.`,
+		Metadata: examples(
+			"awesome-directories calendar",
+			"awesome-directories calendar 2025-07 --all-projects",
+			"awesome-directories calendar add-event product-hunt --title \"Launch day\" --weekly tuesday",
+		),
+		Flags: append(boardScopeFlags(), &cli.BoolFlag{
+			Name:  "json",
+			Usage: "Output as JSON",
+		}),
+		Commands: []*cli.Command{
+			calendarEventsCommand(),
+			calendarAddEventCommand(),
+			calendarRemoveEventCommand(),
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			now := time.Now()
+			month := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+			if arg := cmd.Args().First(); arg != "" {
+				if month, err = time.ParseInLocation("2006-01", arg, time.Local); err != nil {
+					return fmt.Errorf("invalid month: %s (use YYYY-MM)", arg)
+				}
+			}
+
+			submissions, err := scopedSubmissions(cfg, cmd)
+			if err != nil {
+				return err
+			}
+			dataStore := store.New(cfg)
+			events, err := dataStore.Events()
+			if err != nil {
+				return err
+			}
+			audit, err := dataStore.AuditLog(time.Time{})
+			if err != nil {
+				return err
+			}
+
+			entries := calendarEntries(cfg, month, now, submissions, events, audit, boardDirectories(ctx, cfg))
+			recordResults(ctx, len(entries))
+
+			if cmd.Bool("json") {
+				return printJSON(u, calendarOutput{Month: month.Format("2006-01"), Entries: entries})
+			}
+
+			printMonth(u, month, now, entries)
+			u.Println()
+			if len(entries) == 0 {
+				u.Muted("Nothing planned this month")
+				return nil
+			}
+			for _, entry := range entries {
+				day, _ := time.ParseInLocation(dates.Layout, entry.Date, time.Local)
+				text := entry.Name
+				if cmd.Bool("all-projects") && entry.Project != "" {
+					text += " [" + entry.Project + "]"
+				}
+				if entry.Detail != "" {
+					text += ": " + entry.Detail
+				}
+				u.Printf("  %s  %-9s  %s\n", day.Format("Mon Jan 02"), entry.Kind, text)
+			}
+			return nil
+		},
+	}
+}
+
+// calendarEntries returns the entries falling in a month, by day
+func calendarEntries(cfg *config.Config, month, now time.Time, submissions []models.TrackedSubmission, events []models.DirectoryEvent,
+	audit []store.AuditEntry, directories map[string]models.Directory) []calendarEntry {
+	end := month.AddDate(0, 1, 0)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	inMonth := func(day time.Time) bool { return !day.Before(month) && day.Before(end) }
+	name := func(slug string) string {
+		if dir, ok := directories[slug]; ok {
+			return dir.Name
+		}
+		return slug
+	}
+
+	entries := []calendarEntry{}
+
+	// Pending submissions, first tracked first, are scheduled per project
+	sort.SliceStable(submissions, func(i, j int) bool { return submissions[i].CreatedAt.Before(submissions[j].CreatedAt) })
+	pending := make(map[string][]plan.Item)
+	for _, submission := range submissions {
+		if submission.Status != "pending" {
+			continue
+		}
+		dir, ok := directories[submission.Directory]
+		if !ok {
+			dir = models.Directory{Name: submission.Directory}
+		}
+		dir.Slug = submission.Directory
+		pending[submission.Project] = append(pending[submission.Project], plan.Item{Directory: dir})
+	}
+	for project, items := range pending {
+		pacing := plan.DefaultPacing
+		if configured, ok := cfg.ProjectPacing(project); ok {
+			pacing = configured.PlanPacing()
+		}
+		for _, scheduled := range plan.Schedule(items, pacing, today) {
+			if inMonth(scheduled.Date) {
+				entries = append(entries, calendarEntry{
+					Date:      scheduled.Date.Format(dates.Layout),
+					Kind:      calendarPlanned,
+					Directory: scheduled.Directory.Slug,
+					Name:      scheduled.Directory.Name,
+					Project:   project,
+					Detail:    "submit",
+				})
+			}
+		}
+	}
+
+	// Overdue follow-ups are due today
+	for _, f := range followUps(submissions, audit, directories) {
+		due := f.Due
+		detail := "submitted " + f.SubmittedAt.Format("Jan 2")
+		if due.Before(today) {
+			detail += ", overdue since " + due.Format("Jan 2")
+			due = today
+		}
+		if inMonth(due) {
+			entries = append(entries, calendarEntry{
+				Date:      due.Format(dates.Layout),
+				Kind:      calendarFollowUp,
+				Directory: f.Submission.Directory,
+				Name:      name(f.Submission.Directory),
+				Project:   f.Submission.Project,
+				Detail:    detail,
+			})
+		}
+	}
+
+	for day := month; day.Before(end); day = day.AddDate(0, 0, 1) {
+		for _, event := range events {
+			if event.On(day) {
+				entries = append(entries, calendarEntry{
+					Date:      day.Format(dates.Layout),
+					Kind:      calendarEvent,
+					Directory: event.Directory,
+					Name:      name(event.Directory),
+					Detail:    event.Title,
+				})
+			}
+		}
+	}
+
+	rank := make(map[string]int, len(calendarKinds))
+	for i, k := range calendarKinds {
+		rank[k.kind] = i
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].Date != entries[j].Date {
+			return entries[i].Date < entries[j].Date
+		}
+		if entries[i].Kind != entries[j].Kind {
+			return rank[entries[i].Kind] < rank[entries[j].Kind]
+		}
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// followUp is a submitted directory to follow up with once its review time
+// has passed
+type followUp struct {
+	Submission  models.TrackedSubmission
+	SubmittedAt time.Time
+	Due         time.Time
+}
+
+// followUps returns when the submitted submissions are due a follow-up,
+// soonest first: the review time of their directory, or two weeks without
+// one, after the audit log saw them move to submitted, or else after their
+// last change
+func followUps(submissions []models.TrackedSubmission, audit []store.AuditEntry, directories map[string]models.Directory) []followUp {
+	submittedAt := make(map[string]time.Time)
+	for _, entry := range audit {
+		if strings.HasPrefix(entry.Action, "submissions.") && auditStatus(entry.Detail) == "submitted" {
+			submittedAt[entry.Target] = entry.Time
+		}
+	}
+
+	var due []followUp
+	for _, submission := range submissions {
+		if submission.Status != "submitted" {
+			continue
+		}
+		since, ok := submittedAt[submission.Project+"/"+submission.Directory]
+		if !ok {
+			since = submission.UpdatedAt
+		}
+		since = since.In(time.Local)
+
+		days := defaultFollowUpDays
+		if dir, ok := directories[submission.Directory]; ok && dir.ReviewDays > 0 {
+			days = dir.ReviewDays
+		}
+		due = append(due, followUp{
+			Submission:  submission,
+			SubmittedAt: since,
+			Due:         time.Date(since.Year(), since.Month(), since.Day()+days, 0, 0, 0, 0, time.Local),
+		})
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].Due.Before(due[j].Due) })
+	return due
+}
+
+// printMonth prints a month as a grid of weeks from Monday, each day marked
+// with the kinds of its entries and today with a star
+func printMonth(u *ui.UI, month, today time.Time, entries []calendarEntry) {
+	kinds := make(map[string]map[string]bool)
+	for _, entry := range entries {
+		if kinds[entry.Date] == nil {
+			kinds[entry.Date] = make(map[string]bool)
+		}
+		kinds[entry.Date][entry.Kind] = true
+	}
+
+	u.Bold("%s", month.Format("January 2006"))
+	u.Printf(" Mon    Tue    Wed    Thu    Fri    Sat    Sun\n")
+
+	line := strings.Repeat(" ", 7*((int(month.Weekday())+6)%7))
+	for day := month; day.Month() == month.Month(); day = day.AddDate(0, 0, 1) {
+		marks := ""
+		for _, k := range calendarKinds {
+			if kinds[day.Format(dates.Layout)][k.kind] {
+				marks += k.mark
+			}
+		}
+		prefix := " "
+		if day.Format(dates.Layout) == today.Format(dates.Layout) {
+			prefix = "*"
+		}
+		line += fmt.Sprintf("%s%2d %-3s ", prefix, day.Day(), marks)
+
+		if day.Weekday() == time.Sunday {
+			u.Printf("%s\n", strings.TrimRight(line, " "))
+			line = ""
+		}
+	}
+	if line != "" {
+		u.Printf("%s\n", strings.TrimRight(line, " "))
+	}
+
+	u.Muted("* today  E directory event  P planned submission  F follow-up")
+}
+
+// calendarEventsCommand creates the calendar events command
+func calendarEventsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "events",
+		Usage: "List the known launch days and other dates of directories",
+		Metadata: examples(
+			"awesome-directories calendar events",
+		),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			dataStore, err := openStore()
+			if err != nil {
+				return err
+			}
+			events, err := dataStore.Events()
+			if err != nil {
+				return err
+			}
+			recordResults(ctx, len(events))
+
+			if cmd.Bool("json") {
+				return printJSON(u, append([]models.DirectoryEvent{}, events...))
+			}
+			if len(events) == 0 {
+				u.Warning("No events recorded yet. Use 'calendar add-event <slug> --title <title> --weekly <day>' to add one.")
+				return nil
+			}
+
+			table := u.CreateTable([]string{"Directory", "Event", "When"})
+			for _, event := range events {
+				table.Row(event.Directory, event.Title, event.When())
+			}
+			u.Println(table)
+			return nil
+		},
+	}
+}
+
+// calendarAddEventCommand creates the calendar add-event command
+func calendarAddEventCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "add-event",
+		Usage:     "Record a launch day or other date of a directory",
+		ArgsUsage: "<slug>",
+		Metadata: examples(
+			"awesome-directories calendar add-event product-hunt --title \"Launch day\" --weekly tuesday",
+			"awesome-directories calendar add-event betalist --title \"Summer showcase\" --on 2025-07-15",
+		),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "title",
+				Usage: "What happens on that day",
+				Value: "Launch day",
+			},
+			&cli.StringFlag{
+				Name:  "weekly",
+				Usage: "Day of the week the event repeats on, such as tuesday",
+			},
+			&cli.StringFlag{
+				Name:  "on",
+				Usage: "Day of a one-off event: YYYY-MM-DD, or relative such as \"next friday\"",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() == 0 {
+				return fmt.Errorf("directory slug is required")
+			}
+			slug := cmd.Args().First()
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			event := models.DirectoryEvent{Directory: slug, Title: strings.TrimSpace(cmd.String("title"))}
+			if event.Title == "" {
+				return fmt.Errorf("--title can't be empty")
+			}
+			switch {
+			case cmd.IsSet("weekly") == cmd.IsSet("on"):
+				return fmt.Errorf("give either --weekly or --on")
+			case cmd.IsSet("weekly"):
+				weekday, ok := dates.ParseWeekday(cmd.String("weekly"))
+				if !ok {
+					return fmt.Errorf("invalid --weekly: %s (use a day of the week such as tuesday)", cmd.String("weekly"))
+				}
+				event.Weekday = strings.ToLower(weekday.String())
+			default:
+				day, err := dates.Parse(cmd.String("on"), time.Now())
+				if err != nil {
+					return fmt.Errorf("invalid --on: %w", err)
+				}
+				event.Date = day.Format(dates.Layout)
+			}
+
+			// Events are kept under the current slug of renamed directories
+			name := slug
+			if directories := boardDirectories(ctx, cfg); len(directories) > 0 {
+				dir, ok := directories[slug]
+				if !ok {
+					return fmt.Errorf("directory not found: %s", slug)
+				}
+				event.Directory, name = dir.Slug, dir.Name
+			}
+
+			if dryRun(cmd) {
+				u.Info("Dry run: would add %s of %s, %s", event.Title, name, event.When())
+				return nil
+			}
+
+			dataStore := store.New(cfg)
+			if err := dataStore.SaveEvent(&event); err != nil {
+				return fmt.Errorf("failed to save event: %w", err)
+			}
+			recordAudit(dataStore, "event.add", event.Directory, event.Title+", "+event.When())
+
+			u.Success("Added %s of %s, %s", event.Title, name, event.When())
+			return nil
+		},
+	}
+}
+
+// calendarRemoveEventCommand creates the calendar remove-event command
+func calendarRemoveEventCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "remove-event",
+		Usage:     "Forget the events of a directory",
+		ArgsUsage: "<slug>",
+		Metadata: examples(
+			"awesome-directories calendar remove-event product-hunt",
+			"awesome-directories calendar remove-event betalist --title \"Summer showcase\"",
+		),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "title",
+				Usage: "Only remove the event with this title",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() == 0 {
+				return fmt.Errorf("directory slug is required")
+			}
+			slug, title := cmd.Args().First(), cmd.String("title")
+
+			dataStore, err := openStore()
+			if err != nil {
+				return err
+			}
+
+			if dryRun(cmd) {
+				events, err := dataStore.Events()
+				if err != nil {
+					return err
+				}
+				n := 0
+				for _, event := range events {
+					if event.Directory == slug && (title == "" || event.Title == title) {
+						n++
+					}
+				}
+				u.Info("Dry run: would remove %d event(s) of %s", n, slug)
+				return nil
+			}
+
+			removed, err := dataStore.DeleteEvents(slug, title)
+			if err != nil {
+				return fmt.Errorf("failed to remove events: %w", err)
+			}
+			if removed == 0 {
+				if title != "" {
+					return fmt.Errorf("no event %q of %s", title, slug)
+				}
+				return fmt.Errorf("no event of %s", slug)
+			}
+			recordAudit(dataStore, "event.remove", slug, title)
+
+			u.Success("Removed %d event(s) of %s", removed, slug)
+			return nil
+		},
+	}
+}
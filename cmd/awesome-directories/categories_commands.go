@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// categoriesCommand creates the categories command
+func categoriesCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "categories",
+		Usage: "List categories with the number of directories in each",
+		Description: `Categories named with a slash, such as Marketing/SEO, are subcategories.
+A category counts the directories of its subcategories too, each once, so
+broad and narrow views add up. Filter a category with its subcategories
+with --category "Marketing/*".`,
+		Metadata: examples(
+			"awesome-directories categories",
+			"awesome-directories categories --tree",
+			"awesome-directories categories --tree --pricing free --json",
+		),
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "tree",
+				Usage: "Show subcategories under their parent category",
+			},
+			&cli.StringSliceFlag{
+				Name:    "pricing",
+				Aliases: []string{"p"},
+				Usage:   "Only count directories with this pricing: free, paid, freemium",
+			},
+			&cli.StringSliceFlag{
+				Name:  "link-type",
+				Usage: "Only count directories with this link type: dofollow, nofollow",
+			},
+			&cli.IntFlag{
+				Name:  "dr-min",
+				Usage: "Only count directories with at least this domain rating",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output the categories as JSON",
+			},
+			snapshotFlag(),
+		}, filterMetadataFlags()...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+
+			options := &models.FilterOptions{
+				Pricing:  cmd.StringSlice("pricing"),
+				LinkType: cmd.StringSlice("link-type"),
+				DRMin:    cmd.Int("dr-min"),
+			}
+			if err := applyFilterMetadata(ctx, cmd, cfg, options); err != nil {
+				return err
+			}
+
+			tree := cache.CategoryTree(cacheClient.FilterDirectories(directories, options))
+
+			var categories []*cache.CategoryNode
+			if cmd.Bool("tree") {
+				categories = tree
+			} else {
+				categories = cache.FlattenCategories(tree)
+			}
+			recordResults(ctx, len(categories))
+
+			if cmd.Bool("json") {
+				if categories == nil {
+					categories = []*cache.CategoryNode{}
+				}
+				return printJSON(u, categories)
+			}
+
+			if len(categories) == 0 {
+				u.Warning("No categories found")
+				return nil
+			}
+
+			if cmd.Bool("tree") {
+				printCategoryTree(u, tree)
+				return nil
+			}
+
+			table := u.CreateTable([]string{"Category", "Directories"})
+			for _, category := range categories {
+				table.Row(category.Path, strconv.Itoa(category.Count))
+			}
+			u.Println(table.String())
+			u.Info("%d categories", len(categories))
+
+			return nil
+		},
+	}
+}
+
+// printCategoryTree prints categories with their subcategories below them
+func printCategoryTree(u *ui.UI, roots []*cache.CategoryNode) {
+	for _, root := range roots {
+		u.Printf("%s %s\n", root.Name, categoryCount(root))
+		printSubcategories(u, root.Children, "")
+	}
+}
+
+// printSubcategories prints the branches of the category tree below a
+// category
+func printSubcategories(u *ui.UI, nodes []*cache.CategoryNode, indent string) {
+	for i, node := range nodes {
+		branch, next := "├── ", indent+"│   "
+		if i == len(nodes)-1 {
+			branch, next = "└── ", indent+"    "
+		}
+		u.Printf("%s%s%s %s\n", indent, branch, node.Name, categoryCount(node))
+		printSubcategories(u, node.Children, next)
+	}
+}
+
+// categoryCount formats the number of directories of a category, and how
+// many are in the category itself when others are only in subcategories
+func categoryCount(node *cache.CategoryNode) string {
+	if node.Direct != node.Count {
+		return fmt.Sprintf("(%d, %d directly)", node.Count, node.Direct)
+	}
+	return fmt.Sprintf("(%d)", node.Count)
+}
@@ -0,0 +1,625 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/export"
+	"github.com/awesome-directories/cli/internal/render"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// collectionCommand creates the collection command
+func collectionCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "collection",
+		Aliases: []string{"collections"},
+		Usage:   "Group directories into named lists, such as the picks for a launch week",
+		Commands: []*cli.Command{
+			{
+				Name:      "create",
+				Usage:     "Create an empty collection",
+				ArgsUsage: "<name>",
+				Metadata: examples(
+					`awesome-directories collection create launch-week --description "Directories for the v2 launch"`,
+					"awesome-directories collection create eu-launch --preset eu-b2b",
+				),
+				Flags: collectionFieldFlags(),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("collection name is required")
+					}
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+					dataStore := store.New(cfg)
+
+					name := cmd.Args().First()
+					collection := &models.Collection{Name: name, Directories: []string{}}
+					if err := applyCollectionFields(cmd, cfg, collection); err != nil {
+						return err
+					}
+					err = dataStore.Transaction(func() error {
+						if _, err := dataStore.Collection(name); err == nil {
+							return fmt.Errorf("collection already exists: %s", name)
+						}
+						if dryRun(cmd) {
+							return nil
+						}
+						return dataStore.SaveCollection(collection)
+					})
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would create collection %s", name)
+						return nil
+					}
+
+					pushCollection(ctx, cfg, collection)
+					recordChange(dataStore, "collection.create", name, "", nil)
+					u.Success("Created collection %s", name)
+					return nil
+				},
+			},
+			{
+				Name:      "set",
+				Usage:     "Change the description or default preset of a collection",
+				ArgsUsage: "<name>",
+				Metadata: examples(
+					"awesome-directories collection set launch-week --preset high-dr",
+					`awesome-directories collection set launch-week --preset ""`,
+				),
+				Flags: collectionFieldFlags(),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("collection name is required")
+					}
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+					dataStore := store.New(cfg)
+
+					var collection *models.Collection
+					var before models.Collection
+					err = dataStore.Transaction(func() error {
+						collection, err = dataStore.Collection(cmd.Args().First())
+						if err != nil {
+							return err
+						}
+						before = *collection
+						if err := applyCollectionFields(cmd, cfg, collection); err != nil {
+							return err
+						}
+						if dryRun(cmd) {
+							return nil
+						}
+						return dataStore.SaveCollection(collection)
+					})
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would update collection %s", collection.Name)
+						return nil
+					}
+
+					pushCollection(ctx, cfg, collection)
+					recordChange(dataStore, "collection.set", collection.Name, changedFlags(cmd), before)
+					u.Success("Updated collection %s", collection.Name)
+					return nil
+				},
+			},
+			{
+				Name:      "add",
+				Usage:     "Add directories to a collection",
+				ArgsUsage: "<name> <slug>...",
+				Metadata: examples(
+					"awesome-directories collection add launch-week product-hunt betalist",
+				),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() < 2 {
+						return fmt.Errorf("collection name and at least one directory slug are required")
+					}
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+					dataStore := store.New(cfg)
+
+					directories, err := loadDirectories(ctx, cmd, cache.NewCache(cfg, api.NewClient(cfg)))
+					if err != nil {
+						return err
+					}
+
+					slugs := cmd.Args().Tail()
+					for _, slug := range slugs {
+						if findDirectory(directories, slug) == nil {
+							return fmt.Errorf("directory not found: %s", slug)
+						}
+					}
+
+					var collection *models.Collection
+					var before models.Collection
+					added := 0
+					err = dataStore.Transaction(func() error {
+						collection, err = dataStore.Collection(cmd.Args().First())
+						if err != nil {
+							return err
+						}
+						before = *collection
+						for _, slug := range slugs {
+							if !collection.Contains(slug) {
+								collection.Directories = append(collection.Directories, slug)
+								added++
+							}
+						}
+						if added == 0 || dryRun(cmd) {
+							return nil
+						}
+						return dataStore.SaveCollection(collection)
+					})
+					if err != nil {
+						return err
+					}
+
+					if added == 0 {
+						u.Info("%s already holds these directories", collection.Name)
+						return nil
+					}
+					if dryRun(cmd) {
+						u.Info("Dry run: would add %d directories to %s", added, collection.Name)
+						return nil
+					}
+
+					pushCollection(ctx, cfg, collection)
+					recordChange(dataStore, "collection.add", collection.Name, strings.Join(slugs, ","), before)
+					u.Success("Added %d directories to %s (%d in total)", added, collection.Name, len(collection.Directories))
+					return nil
+				},
+			},
+			{
+				Name:      "remove",
+				Aliases:   []string{"rm"},
+				Usage:     "Remove directories from a collection",
+				ArgsUsage: "<name> <slug>...",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() < 2 {
+						return fmt.Errorf("collection name and at least one directory slug are required")
+					}
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+					dataStore := store.New(cfg)
+
+					slugs := cmd.Args().Tail()
+					var collection *models.Collection
+					var before models.Collection
+					removed := 0
+					err = dataStore.Transaction(func() error {
+						collection, err = dataStore.Collection(cmd.Args().First())
+						if err != nil {
+							return err
+						}
+						before = *collection
+						kept := make([]string, 0, len(collection.Directories))
+						for _, slug := range collection.Directories {
+							if slices.Contains(slugs, slug) {
+								removed++
+							} else {
+								kept = append(kept, slug)
+							}
+						}
+						if removed == 0 {
+							return fmt.Errorf("%s holds none of these directories", collection.Name)
+						}
+						collection.Directories = kept
+						if dryRun(cmd) {
+							return nil
+						}
+						return dataStore.SaveCollection(collection)
+					})
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would remove %d directories from %s", removed, collection.Name)
+						return nil
+					}
+
+					pushCollection(ctx, cfg, collection)
+					recordChange(dataStore, "collection.remove", collection.Name, strings.Join(slugs, ","), before)
+					u.Success("Removed %d directories from %s", removed, collection.Name)
+					return nil
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "List collections",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					dataStore, err := openStore()
+					if err != nil {
+						return err
+					}
+
+					collections, err := dataStore.Collections()
+					if err != nil {
+						return err
+					}
+
+					if len(collections) == 0 {
+						u.Warning("No collections yet. Use 'collection create <name>' to start one.")
+						return nil
+					}
+
+					table := u.CreateTable([]string{"Name", "Directories", "Updated", "Description"})
+					for _, collection := range collections {
+						table.Row(
+							collection.Name,
+							fmt.Sprintf("%d", len(collection.Directories)),
+							collection.UpdatedAt.Local().Format("2006-01-02"),
+							ui.TruncateString(collection.Description, 50),
+						)
+					}
+					u.Println(table)
+
+					return nil
+				},
+			},
+			{
+				Name:      "show",
+				Usage:     "Show the directories of a collection",
+				ArgsUsage: "<name>",
+				Flags:     formatFlags(),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					collection, found, missing, err := loadCollection(ctx, cmd)
+					if err != nil {
+						return err
+					}
+
+					if isTableFormat(cmd) {
+						u.Bold("=== %s ===", collection.Name)
+						if collection.Description != "" {
+							u.Println(collection.Description)
+						}
+						if collection.Preset != "" {
+							u.Muted("Default filters: preset %s", collection.Preset)
+						}
+						u.Println()
+					}
+					directories, err := renderHydrated(ctx, u, cmd, found, missing, cache.BySlug)
+					if err != nil {
+						return err
+					}
+					recordResults(ctx, len(directories))
+
+					if isTableFormat(cmd) {
+						u.Info("%d directories in %s", len(directories), collection.Name)
+					}
+					return nil
+				},
+			},
+			{
+				Name:      "export",
+				Usage:     "Export the directories of a collection to a file or stdout",
+				ArgsUsage: "<name>",
+				Metadata: examples(
+					"awesome-directories collection export launch-week -f csv -o launch-week.csv",
+					"awesome-directories collection export launch-week -f awesome-md -o README.md",
+				),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "Export format: " + strings.Join(render.Names(), ", "),
+						Value:   "markdown",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Output file (default: stdout)",
+					},
+					&cli.StringFlag{
+						Name:  "template",
+						Usage: "Go template used with --format template",
+					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Overwrite the output file if it exists",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					collection, directories, missing, err := loadCollection(ctx, cmd)
+					if err != nil {
+						return err
+					}
+					fetched, err := hydrate(ctx, u, missing, cache.BySlug)
+					if err != nil {
+						return err
+					}
+					directories = append(directories, fetched...)
+
+					recordResults(ctx, len(directories))
+
+					opts := render.Options{
+						Template:    cmd.String("template"),
+						Title:       collectionTitle(collection.Name),
+						Description: collection.Description,
+						Missing:     missingColumns(),
+					}
+
+					outputPath := cmd.String("output")
+					if outputPath == "" {
+						renderer, err := render.Get(cmd.String("format"))
+						if err != nil {
+							return err
+						}
+						return renderer.Render(u.Out, directories, opts)
+					}
+
+					if _, err := export.PrepareOutput(outputPath, cmd.Bool("force"), false); err != nil {
+						return err
+					}
+					if err := export.ToFile(directories, cmd.String("format"), outputPath, opts); err != nil {
+						return fmt.Errorf("failed to export: %w", err)
+					}
+
+					u.Success("Exported %d directories of %s to %s", len(directories), collection.Name, outputPath)
+					return nil
+				},
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete a collection",
+				ArgsUsage: "<name>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("collection name is required")
+					}
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+					dataStore := store.New(cfg)
+
+					name := cmd.Args().First()
+					var before *models.Collection
+					err = dataStore.Transaction(func() error {
+						if before, err = dataStore.Collection(name); err != nil || dryRun(cmd) {
+							return err
+						}
+						return dataStore.DeleteCollection(name)
+					})
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would delete collection %s", name)
+						return nil
+					}
+
+					if cfg.AuthToken != "" {
+						err := api.NewClient(cfg).DeleteCollection(ctx, name)
+						if err != nil && !errors.Is(err, api.ErrCollectionsUnsupported) {
+							u.Warning("Deleted %s locally but not on the server: %v", name, err)
+						}
+					}
+
+					recordChange(dataStore, "collection.delete", name, "", *before)
+					u.Success("Deleted collection %s", name)
+					return nil
+				},
+			},
+			{
+				Name:  "sync",
+				Usage: "Merge local collections with the ones saved to your account",
+				Description: `Collections live on this computer and are saved to your account when the
+backend supports it. sync downloads collections changed elsewhere and
+uploads the ones changed here; for a collection changed in both places the
+latest change wins.`,
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					if cfg.AuthToken == "" {
+						return fmt.Errorf("authentication required: use 'auth login' or 'auth token' first")
+					}
+
+					apiClient := api.NewClient(cfg)
+					dataStore := store.New(cfg)
+
+					remote, err := apiClient.GetCollections(ctx)
+					if errors.Is(err, api.ErrCollectionsUnsupported) {
+						return fmt.Errorf("this backend doesn't support collections yet; they are kept on this computer only")
+					}
+					if err != nil {
+						return err
+					}
+
+					local, err := dataStore.Collections()
+					if err != nil {
+						return err
+					}
+
+					localByName := make(map[string]models.Collection, len(local))
+					for _, collection := range local {
+						localByName[collection.Name] = collection
+					}
+					remoteByName := make(map[string]models.Collection, len(remote))
+					for _, collection := range remote {
+						remoteByName[collection.Name] = collection
+					}
+
+					pulled, pushed := 0, 0
+					for _, collection := range remote {
+						mine, ok := localByName[collection.Name]
+						if ok && !collection.UpdatedAt.After(mine.UpdatedAt) {
+							continue
+						}
+						collection.Preset = mine.Preset
+						if !dryRun(cmd) {
+							if err := dataStore.PutCollection(&collection); err != nil {
+								return err
+							}
+						}
+						pulled++
+					}
+					for _, collection := range local {
+						theirs, ok := remoteByName[collection.Name]
+						if ok && !collection.UpdatedAt.After(theirs.UpdatedAt) {
+							continue
+						}
+						if !dryRun(cmd) {
+							if err := apiClient.PutCollection(ctx, collection); err != nil {
+								return err
+							}
+						}
+						pushed++
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would download %d and upload %d collections", pulled, pushed)
+						return nil
+					}
+
+					u.Success("Collections synced: %d downloaded, %d uploaded", pulled, pushed)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// collectionFieldFlags returns the flags setting collection fields
+func collectionFieldFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "What the collection is for",
+		},
+		&cli.StringFlag{
+			Name:  "preset",
+			Usage: "Preset from config.yaml whose filters and sort list, search and plan apply with --collection",
+		},
+	}
+}
+
+// applyCollectionFields copies the collection field flags that are set to
+// collection
+func applyCollectionFields(cmd *cli.Command, cfg *config.Config, collection *models.Collection) error {
+	if cmd.IsSet("description") {
+		collection.Description = cmd.String("description")
+	}
+	if cmd.IsSet("preset") {
+		if name := cmd.String("preset"); name != "" {
+			if _, err := cfg.Preset(name); err != nil {
+				return err
+			}
+		}
+		collection.Preset = cmd.String("preset")
+	}
+	return nil
+}
+
+// loadCollection returns the collection named by the first argument with the
+// directories of the catalog it lists, and the slugs missing from the cached
+// catalog to fetch. With --snapshot, directories missing from the snapshot
+// are left out.
+func loadCollection(ctx context.Context, cmd *cli.Command) (*models.Collection, []models.Directory, []string, error) {
+	if cmd.Args().Len() == 0 {
+		return nil, nil, nil, fmt.Errorf("collection name is required")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	collection, err := store.New(cfg).Collection(cmd.Args().First())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+	all, err := loadDirectories(ctx, cmd, cacheClient)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	directories, missing := cache.Resolve(all, collection.Directories, cache.BySlug, cacheClient.Lineage())
+	if cmd.String("snapshot") != "" && len(missing) > 0 {
+		ui.FromContext(ctx).Warning("Not in the snapshot: %s", strings.Join(missing, ", "))
+		missing = nil
+	}
+
+	return collection, directories, missing, nil
+}
+
+// collectionTitle returns the title of an awesome list made from a
+// collection: "launch-week" becomes "Awesome Launch Week"
+func collectionTitle(name string) string {
+	words := strings.Fields(strings.ReplaceAll(name, "-", " "))
+	for i, word := range words {
+		words[i] = strings.ToUpper(word[:1]) + word[1:]
+	}
+	return "Awesome " + strings.Join(words, " ")
+}
+
+// pushCollection saves a changed collection to the user's account when
+// logged in. Collections stay local when the backend doesn't support them.
+func pushCollection(ctx context.Context, cfg *config.Config, collection *models.Collection) {
+	if cfg.AuthToken == "" {
+		return
+	}
+
+	err := api.NewClient(cfg).PutCollection(ctx, *collection)
+	switch {
+	case errors.Is(err, api.ErrCollectionsUnsupported):
+		log.Debug().Msg("Backend doesn't support collections, keeping them local")
+	case err != nil:
+		ui.FromContext(ctx).Warning("Saved %s locally but not to your account: %v", collection.Name, err)
+	}
+}
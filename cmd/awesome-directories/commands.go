@@ -1,17 +1,28 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"fmt"
-	"strconv"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v3"
 
 	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/badges"
 	"github.com/awesome-directories/cli/internal/cache"
 	"github.com/awesome-directories/cli/internal/config"
 	"github.com/awesome-directories/cli/internal/export"
+	"github.com/awesome-directories/cli/internal/feeds"
+	"github.com/awesome-directories/cli/internal/mail"
+	"github.com/awesome-directories/cli/internal/render"
+	"github.com/awesome-directories/cli/internal/store"
 	"github.com/awesome-directories/cli/internal/ui"
 	"github.com/awesome-directories/cli/pkg/models"
 )
@@ -22,7 +33,12 @@ func searchCommand() *cli.Command {
 		Name:      "search",
 		Usage:     "Search directories by name or description",
 		ArgsUsage: "<query>",
-		Flags: []cli.Flag{
+		Metadata: examples(
+			"awesome-directories search notion",
+			"awesome-directories search \"ai tools\" --limit 10 --sort dr",
+			"awesome-directories search saas --format json",
+		),
+		Flags: append([]cli.Flag{
 			&cli.IntFlag{
 				Name:    "limit",
 				Aliases: []string{"l"},
@@ -30,13 +46,18 @@ func searchCommand() *cli.Command {
 				Value:   50,
 			},
 			&cli.StringFlag{
-				Name:    "sort",
-				Aliases: []string{"s"},
-				Usage:   "Sort by: helpful, dr, newest, alpha",
-				Value:   "helpful",
+				Name:      "sort",
+				Aliases:   []string{"s"},
+				Usage:     "Sort by: helpful, dr, newest, alpha",
+				Value:     "helpful",
+				Validator: validateSort,
 			},
-		},
+			snapshotFlag(),
+			explainFlag(),
+		}, append(scopeFlags("Project whose default filters apply"), formatFlags()...)...),
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
 			if cmd.Args().Len() == 0 {
 				return fmt.Errorf("search query is required")
 			}
@@ -51,26 +72,53 @@ func searchCommand() *cli.Command {
 			apiClient := api.NewClient(cfg)
 			cacheClient := cache.NewCache(cfg, apiClient)
 
-			directories, err := cacheClient.GetDirectories(ctx, false)
-			if err != nil {
-				return fmt.Errorf("failed to get directories: %w", err)
-			}
-
 			options := &models.FilterOptions{
 				Query:  query,
 				SortBy: cmd.String("sort"),
 				Limit:  cmd.Int("limit"),
 			}
 
+			if cmd.Bool("explain") {
+				if _, err := applyScope(ctx, cmd, cfg, nil, options); err != nil {
+					return err
+				}
+				return explainQuery(ctx, cmd, cfg, apiClient, cacheClient, options)
+			}
+
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+
+			// The index holds the lowercase text of the catalog, sparing
+			// lowercasing every directory
+			if cmd.String("snapshot") == "" {
+				if index, err := cacheClient.Index(); err == nil {
+					directories = index.Search(directories, query)
+				}
+			}
+
+			if directories, err = applyScope(ctx, cmd, cfg, directories, options); err != nil {
+				return err
+			}
+
+			pageSize := interactivePageSize(u, cmd, options)
 			filtered := cacheClient.FilterDirectories(directories, options)
+			recordResults(ctx, len(filtered))
+
+			if !isTableFormat(cmd) {
+				return renderDirectories(ctx, u, cmd, filtered)
+			}
 
 			if len(filtered) == 0 {
-				ui.Warning("No directories found matching query: %s", query)
+				u.Warning("No directories found matching query: %s", query)
 				return nil
 			}
 
-			displayDirectoriesTable(filtered)
-			ui.Info("Found %d directories", len(filtered))
+			if _, err := renderTable(ctx, u, cmd, filtered, pageSize); err != nil {
+				return err
+			}
+			u.Info("Found %d directories", len(filtered))
 
 			return nil
 		},
@@ -82,7 +130,12 @@ func listCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "list",
 		Usage: "List all directories",
-		Flags: []cli.Flag{
+		Metadata: examples(
+			"awesome-directories list",
+			"awesome-directories list --category saas --sort newest",
+			"awesome-directories list --limit 20 --offset 20",
+		),
+		Flags: append([]cli.Flag{
 			&cli.StringSliceFlag{
 				Name:    "category",
 				Aliases: []string{"c"},
@@ -100,13 +153,18 @@ func listCommand() *cli.Command {
 				Value: 0,
 			},
 			&cli.StringFlag{
-				Name:    "sort",
-				Aliases: []string{"s"},
-				Usage:   "Sort by: helpful, dr, newest, alpha",
-				Value:   "helpful",
+				Name:      "sort",
+				Aliases:   []string{"s"},
+				Usage:     "Sort by: helpful, dr, newest, alpha",
+				Value:     "helpful",
+				Validator: validateSort,
 			},
-		},
+			snapshotFlag(),
+			explainFlag(),
+		}, append(append(filterMetadataFlags(), scopeFlags("Project whose default filters apply")...), formatFlags()...)...),
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
 			cfg, err := config.Load()
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
@@ -115,11 +173,6 @@ func listCommand() *cli.Command {
 			apiClient := api.NewClient(cfg)
 			cacheClient := cache.NewCache(cfg, apiClient)
 
-			directories, err := cacheClient.GetDirectories(ctx, false)
-			if err != nil {
-				return fmt.Errorf("failed to get directories: %w", err)
-			}
-
 			options := &models.FilterOptions{
 				Categories: cmd.StringSlice("category"),
 				SortBy:     cmd.String("sort"),
@@ -127,15 +180,43 @@ func listCommand() *cli.Command {
 				Offset:     cmd.Int("offset"),
 			}
 
+			if err := applyFilterMetadata(ctx, cmd, cfg, options); err != nil {
+				return err
+			}
+
+			if cmd.Bool("explain") {
+				if _, err := applyScope(ctx, cmd, cfg, nil, options); err != nil {
+					return err
+				}
+				return explainQuery(ctx, cmd, cfg, apiClient, cacheClient, options)
+			}
+
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+			if directories, err = applyScope(ctx, cmd, cfg, directories, options); err != nil {
+				return err
+			}
+
+			pageSize := interactivePageSize(u, cmd, options)
 			filtered := cacheClient.FilterDirectories(directories, options)
+			recordResults(ctx, len(filtered))
+
+			if !isTableFormat(cmd) {
+				return renderDirectories(ctx, u, cmd, filtered)
+			}
 
 			if len(filtered) == 0 {
-				ui.Warning("No directories found")
+				u.Warning("No directories found")
 				return nil
 			}
 
-			displayDirectoriesTable(filtered)
-			ui.Info("Showing %d of %d directories", len(filtered), len(directories))
+			shown, err := renderTable(ctx, u, cmd, filtered, pageSize)
+			if err != nil {
+				return err
+			}
+			u.Info("Showing %d of %d directories", shown, len(directories))
 
 			return nil
 		},
@@ -147,7 +228,12 @@ func filterCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "filter",
 		Usage: "Filter directories with advanced criteria",
-		Flags: []cli.Flag{
+		Metadata: examples(
+			"awesome-directories filter --pricing free --link-type dofollow",
+			"awesome-directories filter --dr-min 50 --dr-max 80 --sort dr",
+			"awesome-directories filter --category ai --format csv > ai.csv",
+		),
+		Flags: append([]cli.Flag{
 			&cli.StringSliceFlag{
 				Name:    "category",
 				Aliases: []string{"c"},
@@ -181,13 +267,18 @@ func filterCommand() *cli.Command {
 				Value:   50,
 			},
 			&cli.StringFlag{
-				Name:    "sort",
-				Aliases: []string{"s"},
-				Usage:   "Sort by: helpful, dr, newest, alpha",
-				Value:   "helpful",
+				Name:      "sort",
+				Aliases:   []string{"s"},
+				Usage:     "Sort by: helpful, dr, newest, alpha",
+				Value:     "helpful",
+				Validator: validateSort,
 			},
-		},
+			snapshotFlag(),
+			explainFlag(),
+		}, append(filterMetadataFlags(), formatFlags()...)...),
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
 			cfg, err := config.Load()
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
@@ -196,11 +287,6 @@ func filterCommand() *cli.Command {
 			apiClient := api.NewClient(cfg)
 			cacheClient := cache.NewCache(cfg, apiClient)
 
-			directories, err := cacheClient.GetDirectories(ctx, false)
-			if err != nil {
-				return fmt.Errorf("failed to get directories: %w", err)
-			}
-
 			options := &models.FilterOptions{
 				Query:      cmd.String("query"),
 				Categories: cmd.StringSlice("category"),
@@ -220,15 +306,36 @@ func filterCommand() *cli.Command {
 				options.DRMax = drMax
 			}
 
+			if err := applyFilterMetadata(ctx, cmd, cfg, options); err != nil {
+				return err
+			}
+
+			if cmd.Bool("explain") {
+				return explainQuery(ctx, cmd, cfg, apiClient, cacheClient, options)
+			}
+
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+
+			pageSize := interactivePageSize(u, cmd, options)
 			filtered := cacheClient.FilterDirectories(directories, options)
+			recordResults(ctx, len(filtered))
+
+			if !isTableFormat(cmd) {
+				return renderDirectories(ctx, u, cmd, filtered)
+			}
 
 			if len(filtered) == 0 {
-				ui.Warning("No directories found matching filters")
+				u.Warning("No directories found matching filters")
 				return nil
 			}
 
-			displayDirectoriesTable(filtered)
-			ui.Info("Found %d of %d directories", len(filtered), len(directories))
+			if _, err := renderTable(ctx, u, cmd, filtered, pageSize); err != nil {
+				return err
+			}
+			u.Info("Found %d of %d directories", len(filtered), len(directories))
 
 			return nil
 		},
@@ -241,7 +348,30 @@ func showCommand() *cli.Command {
 		Name:      "show",
 		Usage:     "Show detailed information about a directory",
 		ArgsUsage: "<slug>",
+		Metadata: examples(
+			"awesome-directories show producthunt",
+			"awesome-directories show producthunt --format json",
+			"awesome-directories show producthunt --logo",
+			"awesome-directories show producthunt --refresh",
+		),
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "refresh",
+				Usage: "Fetch the directory from the API even when the cache is fresh",
+			},
+			&cli.BoolFlag{
+				Name:  "logo",
+				Usage: "Show the directory's logo in terminals supporting inline images",
+			},
+			&cli.StringFlag{
+				Name:  "image-protocol",
+				Usage: "Inline image protocol: auto, kitty, iterm2, sixel or none",
+				Value: "auto",
+			},
+		}, formatFlags()...),
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
 			if cmd.Args().Len() == 0 {
 				return fmt.Errorf("directory slug is required")
 			}
@@ -254,13 +384,59 @@ func showCommand() *cli.Command {
 			}
 
 			apiClient := api.NewClient(cfg)
+			cacheClient := cache.NewCache(cfg, apiClient)
 
-			directory, err := apiClient.GetDirectory(ctx, slug)
+			getDirectory := cacheClient.GetDirectory
+			if cmd.Bool("refresh") {
+				getDirectory = cacheClient.RefreshDirectory
+			}
+			directory, err := getDirectory(ctx, slug)
 			if err != nil {
 				return fmt.Errorf("failed to get directory: %w", err)
 			}
+			directories := []models.Directory{*directory}
+			if dataStore, err := openStore(); err == nil {
+				overrides, err := dataStore.Overrides()
+				if err != nil {
+					return err
+				}
+				store.ApplyOverrides(directories, overrides)
+			}
+			directory = &directories[0]
+
+			if !isTableFormat(cmd) {
+				return renderDirectories(ctx, u, cmd, []models.Directory{*directory})
+			}
+
+			if cmd.Bool("logo") && cfg.LowBandwidth {
+				log.Warn().Msg("Not fetching the logo in low-bandwidth mode")
+			} else if cmd.Bool("logo") {
+				if err := showLogo(ctx, u, apiClient, directory, cmd.String("image-protocol")); err != nil {
+					return err
+				}
+			}
 
-			displayDirectoryDetails(directory)
+			displayDirectoryDetails(u, directory)
+
+			submissions, err := store.New(cfg).Submissions()
+			if err != nil {
+				log.Warn().Err(err).Msg("Failed to load tracked submissions")
+			}
+			lineage := cacheClient.Lineage()
+			for _, submission := range submissions {
+				if lineage.Current(submission.Directory, cache.BySlug) != directory.Slug {
+					continue
+				}
+				u.Println()
+				u.Bold("Submission (%s): %s", submission.Project, submission.Status)
+				if submission.ListingURL != "" {
+					u.Printf("  Listing: %s\n", submission.ListingURL)
+				}
+				if len(submission.Evidence) > 0 {
+					u.Printf("  Evidence: %d item(s)\n", len(submission.Evidence))
+				}
+				printTodos(u, submission.Todos)
+			}
 
 			return nil
 		},
@@ -272,18 +448,40 @@ func exportCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "export",
 		Usage: "Export directories to file",
-		Flags: []cli.Flag{
+		Metadata: examples(
+			"awesome-directories export -f csv -o directories.csv",
+			"awesome-directories export -f json -o free.json --pricing free --dr-min 40",
+			"awesome-directories export -f bundle -o catalog.tar.gz --checksum",
+			"awesome-directories export -f bookmarks -o queue.html --category ai",
+			"awesome-directories export -f opml -o directories.opml --discover-feeds",
+			"awesome-directories export -f csv,json,markdown --output-dir ./out",
+			"awesome-directories export -f csv -o directories.csv --dry-run",
+			"awesome-directories export -f json -o directories.json --canonical --force",
+			"awesome-directories export -f csv -o - --pricing free | head",
+		),
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:     "format",
 				Aliases:  []string{"f"},
-				Usage:    "Export format: csv, json, markdown",
+				Usage:    "Export format, or several separated by commas with --output-dir: " + strings.Join(append(render.Names(), "bundle"), ", "),
 				Required: true,
 			},
 			&cli.StringFlag{
-				Name:     "output",
-				Aliases:  []string{"o"},
-				Usage:    "Output file path",
-				Required: true,
+				Name:  "template",
+				Usage: "Go template used with --format template",
+			},
+			&cli.BoolFlag{
+				Name:  "canonical",
+				Usage: "Write diff-friendly JSON: directories by slug, sorted keys and lists, UTC timestamps",
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Output file path, or - for standard output",
+			},
+			&cli.StringFlag{
+				Name:  "output-dir",
+				Usage: "Directory to write a file per format into, such as directories.csv",
 			},
 			&cli.StringSliceFlag{
 				Name:  "category",
@@ -297,8 +495,56 @@ func exportCommand() *cli.Command {
 				Name:  "dr-min",
 				Usage: "Minimum domain rating",
 			},
-		},
+			&cli.IntFlag{
+				Name:  "limit",
+				Usage: "Maximum number of directories to export (0 for all)",
+			},
+			&cli.IntFlag{
+				Name:  "offset",
+				Usage: "Number of directories to skip, for chunked exports",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be exported without writing anything",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite the output file if it exists",
+			},
+			&cli.BoolFlag{
+				Name:  "backup",
+				Usage: "Keep a timestamped copy of an existing output file",
+			},
+			&cli.BoolFlag{
+				Name:  "checksum",
+				Usage: "Write a .sha256 checksum file next to the export",
+			},
+			&cli.BoolFlag{
+				Name:  "sign",
+				Usage: "Sign the export with the configured minisign or cosign key",
+			},
+			&cli.BoolFlag{
+				Name:  "discover-feeds",
+				Usage: "Look up the blog or changelog feed on the homepage of directories without one",
+			},
+			snapshotFlag(),
+			explainFlag(),
+		}, filterMetadataFlags()...),
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			targets, err := exportTargets(cmd)
+			if err != nil {
+				return err
+			}
+			if cmd.Bool("canonical") && !slices.ContainsFunc(targets, hasJSON) {
+				return fmt.Errorf("--canonical only applies to the json and bundle formats")
+			}
+			toStdout := targets[0].Path == "-"
+			if toStdout && (targets[0].Format == "bundle" || cmd.Bool("checksum") || cmd.Bool("sign")) {
+				return fmt.Errorf("--output - writes to standard output, which bundles, --checksum and --sign can't use")
+			}
+
 			cfg, err := config.Load()
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
@@ -307,15 +553,12 @@ func exportCommand() *cli.Command {
 			apiClient := api.NewClient(cfg)
 			cacheClient := cache.NewCache(cfg, apiClient)
 
-			directories, err := cacheClient.GetDirectories(ctx, false)
-			if err != nil {
-				return fmt.Errorf("failed to get directories: %w", err)
-			}
-
 			// Apply filters
 			options := &models.FilterOptions{
 				Categories: cmd.StringSlice("category"),
 				Pricing:    cmd.StringSlice("pricing"),
+				Limit:      cmd.Int("limit"),
+				Offset:     cmd.Int("offset"),
 			}
 
 			if cmd.IsSet("dr-min") {
@@ -323,40 +566,194 @@ func exportCommand() *cli.Command {
 				options.DRMin = drMin
 			}
 
+			if err := applyFilterMetadata(ctx, cmd, cfg, options); err != nil {
+				return err
+			}
+
+			if cmd.Bool("explain") {
+				return explainQuery(ctx, cmd, cfg, apiClient, cacheClient, options)
+			}
+
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+
 			filtered := cacheClient.FilterDirectories(directories, options)
+			recordResults(ctx, len(filtered))
+
+			if cmd.Bool("discover-feeds") && cfg.LowBandwidth {
+				u.Warning("Not discovering feeds in low-bandwidth mode")
+			} else if cmd.Bool("discover-feeds") && !dryRun(cmd) {
+				progress := u.NewProgress("Discovering feeds", len(filtered))
+				found := feeds.Discover(ctx, filtered, progress.Increment)
+				progress.Done()
+				u.Info("Found %d new feed(s)", found)
+			}
 
-			// Export
-			outputPath := cmd.String("output")
-			format := cmd.String("format")
+			if dryRun(cmd) {
+				return previewExport(u, filtered, targets)
+			}
 
-			switch format {
-			case "csv":
-				err = export.ExportToCSV(filtered, outputPath)
-			case "json":
-				err = export.ExportToJSON(filtered, outputPath)
-			case "markdown", "md":
-				err = export.ExportToMarkdown(filtered, outputPath)
-			default:
-				return fmt.Errorf("unsupported format: %s (use csv, json, or markdown)", format)
+			if toStdout {
+				renderer, err := render.Get(targets[0].Format)
+				if err != nil {
+					return err
+				}
+				return renderer.Render(u.Out, filtered, render.Options{
+					Template:      cmd.String("template"),
+					Missing:       missingColumns(),
+					CanonicalJSON: cmd.Bool("canonical"),
+				})
 			}
 
-			if err != nil {
+			if dir := cmd.String("output-dir"); dir != "" {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					return fmt.Errorf("failed to create output directory: %w", err)
+				}
+			}
+
+			for _, target := range targets {
+				backupPath, err := export.PrepareOutput(target.Path, cmd.Bool("force"), cmd.Bool("backup"))
+				if err != nil {
+					return err
+				}
+				if backupPath != "" {
+					u.Info("Backed up existing %s to %s", target.Path, backupPath)
+				}
+
+				if strings.EqualFold(target.Format, "opml") && !slices.ContainsFunc(filtered, func(d models.Directory) bool { return d.FeedURL != "" }) {
+					u.Warning("None of the exported directories has a known feed; try --discover-feeds")
+				}
+			}
+
+			start := time.Now()
+			progress := u.NewProgress("Exporting", len(filtered))
+
+			// Formats are rendered concurrently from the one filtered list
+			opts := render.Options{
+				Template: cmd.String("template"),
+				OnRow:    progress.Increment,
+				Missing:  missingColumns(),
+
+				CanonicalJSON: cmd.Bool("canonical"),
+			}
+			if err := export.ToFiles(filtered, targets, opts); err != nil {
 				return fmt.Errorf("failed to export: %w", err)
 			}
+			progress.Done()
+			elapsed := time.Since(start).Round(time.Millisecond)
+
+			for _, target := range targets {
+				size := "unknown size"
+				if info, err := os.Stat(target.Path); err == nil {
+					size = ui.FormatBytes(info.Size())
+				}
+
+				if len(targets) == 1 {
+					u.Success("Exported %d directories to %s (%s in %s)", len(filtered), target.Path, size, elapsed)
+				} else {
+					u.Success("Wrote %s (%s)", target.Path, size)
+				}
+
+				if cmd.Bool("checksum") {
+					checksumPath, err := export.WriteChecksum(target.Path)
+					if err != nil {
+						return err
+					}
+					u.Success("Wrote checksum to %s", checksumPath)
+				}
 
-			ui.Success("Exported %d directories to %s", len(filtered), outputPath)
+				if cmd.Bool("sign") {
+					tool := cfg.SigningTool
+					if tool == "" {
+						tool = "minisign"
+					}
+
+					signaturePath, err := export.Sign(ctx, tool, cfg.SigningKey, target.Path)
+					if err != nil {
+						return fmt.Errorf("failed to sign export: %w", err)
+					}
+					u.Success("Wrote %s signature to %s", tool, signaturePath)
+				}
+			}
+
+			if len(targets) > 1 {
+				u.Success("Exported %d directories in %d formats to %s in %s", len(filtered), len(targets), cmd.String("output-dir"), elapsed)
+			}
 
 			return nil
 		},
 	}
 }
 
+// exportTargets returns the files export writes: --output in the --format,
+// or with --output-dir a file per format listed in --format
+func exportTargets(cmd *cli.Command) ([]export.Target, error) {
+	var formats []string
+	for _, format := range strings.Split(cmd.String("format"), ",") {
+		if format = strings.TrimSpace(format); format != "" {
+			formats = append(formats, format)
+		}
+	}
+
+	output, outputDir := cmd.String("output"), cmd.String("output-dir")
+	switch {
+	case len(formats) == 0:
+		return nil, fmt.Errorf("--format is required")
+	case output != "" && outputDir != "":
+		return nil, fmt.Errorf("--output and --output-dir cannot be used together")
+	case output == "" && outputDir == "":
+		return nil, fmt.Errorf("--output or --output-dir is required")
+	case output != "" && len(formats) > 1:
+		return nil, fmt.Errorf("exporting several formats needs --output-dir instead of --output")
+	case output != "":
+		return []export.Target{{Format: formats[0], Path: output}}, nil
+	}
+
+	var targets []export.Target
+	seen := make(map[string]bool)
+	for _, format := range formats {
+		name, err := export.FileName(format)
+		if err != nil {
+			return nil, err
+		}
+		// Aliases of one format, such as md and markdown, are written once
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		targets = append(targets, export.Target{Format: format, Path: filepath.Join(outputDir, name)})
+	}
+	return targets, nil
+}
+
+// hasJSON reports whether an export target writes JSON, on its own or in a
+// bundle
+func hasJSON(target export.Target) bool {
+	if target.Format == "bundle" {
+		return true
+	}
+	format, err := render.Canonical(target.Format)
+	return err == nil && format == "json"
+}
+
 // syncCommand creates the sync command
 func syncCommand() *cli.Command {
 	return &cli.Command{
 		Name:  "sync",
 		Usage: "Sync local cache with API",
+		Metadata: examples(
+			"awesome-directories sync",
+			"awesome-directories sync export-bundle bundle.bin --since 2025-06-01",
+		),
+		Commands: []*cli.Command{
+			exportBundleCommand(),
+			importBundleCommand(),
+		},
 		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
 			cfg, err := config.Load()
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
@@ -369,7 +766,7 @@ func syncCommand() *cli.Command {
 				return fmt.Errorf("failed to sync cache: %w", err)
 			}
 
-			ui.Success("Cache synced successfully")
+			u.Success("Cache synced successfully")
 
 			return nil
 		},
@@ -386,27 +783,37 @@ func configCommand() *cli.Command {
 				Name:  "show",
 				Usage: "Show current configuration",
 				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
 					cfg, err := config.Load()
 					if err != nil {
 						return fmt.Errorf("failed to load config: %w", err)
 					}
 
-					ui.Bold("Configuration:")
-					fmt.Printf("  Supabase URL: %s\n", cfg.SupabaseURL)
-					fmt.Printf("  Cache Directory: %s\n", cfg.CacheDir)
-					fmt.Printf("  Cache TTL: %s\n", cfg.CacheTTL)
-					fmt.Printf("  Authenticated: %t\n", cfg.AuthToken != "")
+					u.Bold("Configuration:")
+					u.Printf("  Supabase URL: %s\n", cfg.SupabaseURL)
+					u.Printf("  Cache Directory: %s\n", cfg.CacheDir)
+					u.Printf("  Data Directory: %s\n", cfg.DataDir)
+					u.Printf("  Cache TTL: %s\n", cfg.CacheTTL)
+					u.Printf("  Cache TTLs: directories %s, favorites %s, account %s\n",
+						cfg.DirectoriesTTL(), userDataTTL(cfg.FavoritesTTL()), userDataTTL(cfg.AccountTTL()))
+					u.Printf("  Cache Max Size: %d MB\n", cfg.CacheMaxSizeMB)
+					u.Printf("  Provenance: %t\n", cfg.Provenance)
+					u.Printf("  Usage Log: %t\n", cfg.UsageLog)
+					u.Printf("  Low Bandwidth: %t\n", cfg.LowBandwidth)
+					u.Printf("  Read Only: %t\n", cfg.ReadOnly)
+					u.Printf("  Authenticated: %t\n", cfg.AuthToken != "")
 
 					cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
 					info, err := cacheClient.GetCacheInfo()
 					if err == nil {
-						fmt.Printf("\nCache Info:\n")
+						u.Printf("\nCache Info:\n")
 						for k, v := range info {
 							displayKey := strings.ReplaceAll(k, "_", " ")
 							if len(displayKey) > 0 {
 								displayKey = strings.ToUpper(displayKey[:1]) + displayKey[1:]
 							}
-							fmt.Printf("  %s: %v\n", displayKey, v)
+							u.Printf("  %s: %v\n", displayKey, v)
 						}
 					}
 
@@ -417,18 +824,25 @@ func configCommand() *cli.Command {
 				Name:  "clear-cache",
 				Usage: "Clear local cache",
 				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
 					cfg, err := config.Load()
 					if err != nil {
 						return fmt.Errorf("failed to load config: %w", err)
 					}
 
+					if dryRun(cmd) {
+						u.Info("Dry run: would clear the cache in %s", cfg.CacheDir)
+						return nil
+					}
+
 					cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
 
 					if err := cacheClient.Clear(); err != nil {
 						return fmt.Errorf("failed to clear cache: %w", err)
 					}
 
-					ui.Success("Cache cleared successfully")
+					u.Success("Cache cleared successfully")
 
 					return nil
 				},
@@ -437,63 +851,639 @@ func configCommand() *cli.Command {
 	}
 }
 
-// displayDirectoriesTable displays directories in a table format
-func displayDirectoriesTable(directories []models.Directory) {
-	table := ui.CreateTable([]string{"Name", "DR", "Category", "Pricing", "Link", "Votes"})
+// printJSON prints a value as indented JSON to stdout
+func printJSON(u *ui.UI, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	u.Println(string(data))
+	return nil
+}
+
+// exportPreviewRows is the number of rows shown by export --dry-run
+const exportPreviewRows = 5
 
-	for _, dir := range directories {
-		category := strings.Join(dir.Categories, ", ")
-		if len(category) > 30 {
-			category = ui.TruncateString(category, 30)
+// previewExport describes an export without writing it
+func previewExport(u *ui.UI, directories []models.Directory, targets []export.Target) error {
+	for _, target := range targets {
+		if target.Format != "bundle" {
+			if _, err := render.Get(target.Format); err != nil {
+				return err
+			}
 		}
+	}
 
-		table.Row(
-			ui.TruncateString(dir.Name, 40),
-			ui.FormatDR(&dir.DomainRating),
-			category,
-			ui.FormatPricing(dir.Pricing),
-			ui.FormatLinkType(dir.LinkType),
-			strconv.Itoa(dir.HelpfulCount),
-		)
+	u.Bold("Export preview (dry run):")
+	u.Printf("  Rows: %d\n", len(directories))
+	for _, target := range targets {
+		u.Printf("  Format: %s\n", target.Format)
+		if target.Format == "bundle" {
+			var files []string
+			for _, bf := range export.BundleFormats {
+				files = append(files, bf.Name)
+			}
+			u.Printf("  Bundle contents: %s, metadata.json\n", strings.Join(files, ", "))
+		} else if columns := render.Columns(target.Format); columns != nil {
+			u.Printf("  Columns: %s\n", strings.Join(columns, ", "))
+		}
+		u.Printf("  Destination: %s\n", target.Path)
+		if info, err := os.Stat(target.Path); err == nil {
+			u.Warning("%s already exists (%s, modified %s); use --force or --backup to replace it", target.Path,
+				ui.FormatBytes(info.Size()), info.ModTime().Format("2006-01-02 15:04"))
+		}
+	}
+	u.Println()
+
+	if len(directories) == 0 {
+		u.Warning("No directories match the filters")
+		return nil
+	}
+
+	preview := directories[:min(exportPreviewRows, len(directories))]
+	if err := render.Table(u.Out, preview, render.Options{}); err != nil {
+		return err
+	}
+
+	if len(directories) > len(preview) {
+		u.Muted("... and %d more", len(directories)-len(preview))
+	}
+	u.Info("Dry run: nothing was written")
+
+	return nil
+}
+
+// scopeFlags returns the flags choosing the project or collection whose
+// default filters apply. projectUsage describes what else --project sets.
+func scopeFlags(projectUsage string) []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "project",
+			Usage: projectUsage,
+			Value: models.DefaultProject,
+		},
+		&cli.StringFlag{
+			Name:  "collection",
+			Usage: "Only consider the directories of a collection, with its default filters",
+		},
+		&cli.BoolFlag{
+			Name:  "no-defaults",
+			Usage: "Ignore the default filters of the project or collection",
+		},
 	}
+}
 
-	fmt.Println(table)
+// validateSort rejects sort orders neither the API nor the cache knows, so
+// a mistyped one, such as a config default, isn't silently ignored
+func validateSort(sortBy string) error {
+	switch models.SortOption(sortBy) {
+	case models.SortMostHelpful, models.SortHighestDR, models.SortNewest, models.SortAlpha:
+		return nil
+	}
+	return fmt.Errorf("invalid sort %q (use helpful, dr, newest or alpha)", sortBy)
+}
+
+// applyScope narrows directories to the --collection, if any, and fills the
+// filters and sort left unset on the command line from the default preset
+// of the collection or, without one, of the --project
+func applyScope(ctx context.Context, cmd *cli.Command, cfg *config.Config, directories []models.Directory, options *models.FilterOptions) ([]models.Directory, error) {
+	var presetName, scope string
+	if name := cmd.String("collection"); name != "" {
+		collection, err := store.New(cfg).Collection(name)
+		if err != nil {
+			return nil, err
+		}
+		// Directories renamed since they were added are still in it
+		lineage := cache.NewCache(cfg, nil).Lineage()
+		slugs := make(map[string]bool, len(collection.Directories))
+		for _, slug := range collection.Directories {
+			slugs[lineage.Current(slug, cache.BySlug)] = true
+		}
+		scoped := make([]models.Directory, 0, len(collection.Directories))
+		for _, dir := range directories {
+			if slugs[dir.Slug] {
+				scoped = append(scoped, dir)
+			}
+		}
+		directories = scoped
+		presetName, scope = collection.Preset, "collection "+name
+	} else if name, ok := cfg.ProjectPreset(cmd.String("project")); ok {
+		presetName, scope = name, "project "+cmd.String("project")
+	}
+
+	// An explicit --preset replaces the defaults
+	if presetName == "" || cmd.Bool("no-defaults") || cmd.String("preset") != "" {
+		return directories, nil
+	}
+
+	preset, err := cfg.Preset(presetName)
+	if err != nil {
+		return nil, fmt.Errorf("default filters of %s: %w", scope, err)
+	}
+	defaults := preset.FilterOptions()
+
+	if options.Query == "" {
+		options.Query = defaults.Query
+	}
+	if !cmd.IsSet("category") && len(defaults.Categories) > 0 {
+		options.Categories = defaults.Categories
+	}
+	if !cmd.IsSet("pricing") && len(defaults.Pricing) > 0 {
+		options.Pricing = defaults.Pricing
+	}
+	if !cmd.IsSet("link-type") && len(defaults.LinkType) > 0 {
+		options.LinkType = defaults.LinkType
+	}
+	if !cmd.IsSet("dr-min") && defaults.DRMin > 0 {
+		options.DRMin = defaults.DRMin
+	}
+	if !cmd.IsSet("dr-max") && defaults.DRMax > 0 {
+		options.DRMax = defaults.DRMax
+	}
+	if !cmd.IsSet("country") && len(defaults.Countries) > 0 {
+		options.Countries = defaults.Countries
+	}
+	if !cmd.IsSet("language") && len(defaults.Languages) > 0 {
+		options.Languages = defaults.Languages
+	}
+	if !cmd.IsSet("audience") && defaults.Audience != "" {
+		options.Audience = defaults.Audience
+	}
+	if !cmd.IsSet("sort") && defaults.SortBy != "" {
+		options.SortBy = defaults.SortBy
+	}
+
+	if isTableFormat(cmd) {
+		ui.FromContext(ctx).Muted("Using the default filters of %s (preset %s); --no-defaults ignores them", scope, presetName)
+	}
+	return directories, nil
+}
+
+// filterMetadataFlags returns the locale and price filter flags shared by
+// list, filter and export
+func filterMetadataFlags() []cli.Flag {
+	return append(localeFlags(), priceFlags()...)
+}
+
+// applyFilterMetadata copies the locale and price flags to options and
+// corrects misspelled categories, pricing and link types
+func applyFilterMetadata(ctx context.Context, cmd *cli.Command, cfg *config.Config, options *models.FilterOptions) error {
+	if err := applyLocaleFilters(cmd, options); err != nil {
+		return err
+	}
+	if err := correctFilterValues(ctx, cmd, cfg, options); err != nil {
+		return err
+	}
+
+	if cmd.Float("max-price") < 0 {
+		return fmt.Errorf("max-price must not be negative")
+	}
+	options.MaxPrice = cmd.Float("max-price")
+	options.Currency = strings.ToUpper(cmd.String("currency"))
+
+	return nil
+}
+
+// correctFilterValues replaces category, pricing and link type filters that
+// match nothing with the value they most likely meant. Pricing and link types
+// nothing is close to are rejected; such categories only warn, since the
+// cached catalog may lag behind the API.
+func correctFilterValues(ctx context.Context, cmd *cli.Command, cfg *config.Config, options *models.FilterOptions) error {
+	u := ui.FromContext(ctx)
+	notice := func(flag, value, corrected string) {
+		if isTableFormat(cmd) {
+			u.Muted("Using --%s %q for %q", flag, corrected, value)
+		}
+	}
+
+	enums := []struct {
+		flag   string
+		values []string
+		known  []string
+	}{
+		{"pricing", options.Pricing, cache.PricingValues},
+		{"link-type", options.LinkType, cache.LinkTypeValues},
+	}
+	for _, enum := range enums {
+		flag, values, known := enum.flag, enum.values, enum.known
+		for i, value := range values {
+			if slices.ContainsFunc(known, func(k string) bool { return strings.EqualFold(k, value) }) {
+				continue
+			}
+			corrected, ok := cache.Correct(value, known)
+			if !ok {
+				return fmt.Errorf("invalid %s: %s (use %s)", flag, value, strings.Join(known, ", "))
+			}
+			notice(flag, value, corrected)
+			values[i] = corrected
+		}
+	}
+
+	if len(options.Categories) == 0 {
+		return nil
+	}
+	index, err := cache.NewCache(cfg, nil).Index()
+	if err != nil {
+		// Without a cached catalog there is nothing to compare against
+		log.Debug().Err(err).Msg("Skipping category correction")
+		return nil
+	}
+	known := index.CategoryNames()
+
+	for i, value := range options.Categories {
+		name, subcategories := strings.CutSuffix(strings.TrimSpace(value), cache.CategorySeparator+"*")
+		if slices.ContainsFunc(known, func(k string) bool { return cache.SameCategory(k, name) }) {
+			continue
+		}
+		if corrected, ok := cache.Correct(name, known); ok {
+			if subcategories {
+				corrected += cache.CategorySeparator + "*"
+			}
+			notice("category", value, corrected)
+			options.Categories[i] = corrected
+			continue
+		}
+		if !isTableFormat(cmd) {
+			continue
+		}
+		if suggestions := cache.Suggest(name, known); len(suggestions) > 0 {
+			u.Warning("No category matches %q; did you mean %s?", value, strings.Join(suggestions, ", "))
+		} else {
+			u.Warning("No category matches %q; run 'awesome-directories categories' to list them", value)
+		}
+	}
+	return nil
+}
+
+// priceFlags returns the listing fee filter flags
+func priceFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.FloatFlag{
+			Name:  "max-price",
+			Usage: "Maximum listing fee; paid directories without a known price are excluded",
+		},
+		&cli.StringFlag{
+			Name:  "currency",
+			Usage: "Only include paid directories priced in this currency, e.g. USD",
+		},
+	}
+}
+
+// localeFlags returns the country, language and audience filter flags
+func localeFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "country",
+			Usage: "Filter by country served, e.g. US, DE (can be specified multiple times)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "language",
+			Usage: "Filter by listing language, e.g. en, fr (can be specified multiple times)",
+		},
+		&cli.StringFlag{
+			Name:  "audience",
+			Usage: "Filter by audience: b2b, b2c",
+		},
+	}
+}
+
+// applyLocaleFilters copies the locale flags to options
+func applyLocaleFilters(cmd *cli.Command, options *models.FilterOptions) error {
+	options.Countries = cmd.StringSlice("country")
+	options.Languages = cmd.StringSlice("language")
+
+	audience := strings.ToLower(cmd.String("audience"))
+	if audience != "" && audience != "b2b" && audience != "b2c" {
+		return fmt.Errorf("invalid audience: %s (use b2b or b2c)", cmd.String("audience"))
+	}
+	options.Audience = audience
+
+	return nil
+}
+
+// formatFlags returns the output format flags shared by listing commands
+func formatFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "format",
+			Aliases: []string{"f"},
+			Usage:   "Output format: " + strings.Join(render.Names(), ", "),
+			Value:   "table",
+		},
+		&cli.StringFlag{
+			Name:  "template",
+			Usage: "Go template used with --format template",
+		},
+	}
+}
+
+// isTableFormat reports whether the human-readable table format is selected
+func isTableFormat(cmd *cli.Command) bool {
+	return cmd.String("format") == "" || strings.EqualFold(cmd.String("format"), "table")
+}
+
+// listingJSON is the JSON output of a listing with provenance enabled
+type listingJSON struct {
+	Meta        listingMeta        `json:"meta"`
+	Directories []models.Directory `json:"directories"`
+}
+
+// listingMeta tells where the directories of a listing came from
+type listingMeta struct {
+	*cache.Provenance
+	AgeSeconds int64 `json:"age_seconds"`
+}
+
+// renderDirectories renders directories in the format selected by --format
+func renderDirectories(ctx context.Context, u *ui.UI, cmd *cli.Command, directories []models.Directory) error {
+	format := cmd.String("format")
+	if format == "" {
+		format = "table"
+	}
+
+	renderer, err := render.Get(format)
+	if err != nil {
+		return err
+	}
+
+	if directories == nil {
+		directories = []models.Directory{}
+	}
+
+	// Tables get the provenance as a footer, JSON in its meta
+	switch canonical, _ := render.Canonical(format); canonical {
+	case "table":
+		recordListing(ctx)
+	case "json":
+		if provenance := recordedProvenance(ctx); provenance != nil {
+			meta := listingMeta{Provenance: provenance, AgeSeconds: int64(time.Since(provenance.SyncedAt).Seconds())}
+			return printJSON(u, listingJSON{Meta: meta, Directories: directories})
+		}
+	}
+
+	opts := render.Options{Template: cmd.String("template"), Missing: missingColumns()}
+	if canonical, _ := render.Canonical(format); canonical == "table" {
+		opts.Badges = directoryBadges()
+	}
+	return renderer.Render(u.Out, directories, opts)
+}
+
+// directoryBadges returns the badges of directories as configured, or nil
+// when all of them are disabled. The top DR badge ranks directories within
+// the cached catalog.
+func directoryBadges() func(models.Directory) []string {
+	cfg, err := config.Load()
+	if err != nil || !badges.Enabled(cfg.Badges) {
+		return nil
+	}
+
+	minDR := 0
+	if index, err := cache.NewCache(cfg, nil).Index(); err == nil {
+		minDR, _ = index.TopDR(badges.TopN(cfg.Badges))
+	}
+	return badges.NewAwarder(cfg.Badges, minDR, !ui.ColorsEnabled(), time.Now()).Labels
+}
+
+// interactivePageSize returns the page size of a table shown on an
+// interactive terminal without an explicit --limit, lifting the limit so
+// every match can be paged to. It returns 0 when output isn't paged.
+func interactivePageSize(u *ui.UI, cmd *cli.Command, options *models.FilterOptions) int {
+	if !isTableFormat(cmd) || cmd.IsSet("limit") || options.Limit <= 0 || !u.Interactive() {
+		return 0
+	}
+	size := options.Limit
+	options.Limit = 0
+	return size
+}
+
+// renderTable renders directories as a table, a page at a time when they
+// don't fit in pageSize, and returns how many were shown
+func renderTable(ctx context.Context, u *ui.UI, cmd *cli.Command, directories []models.Directory, pageSize int) (int, error) {
+	if pageSize <= 0 || len(directories) <= pageSize {
+		return len(directories), renderDirectories(ctx, u, cmd, directories)
+	}
+
+	var buf bytes.Buffer
+	if err := render.Table(&buf, directories, render.Options{Missing: missingColumns(), Badges: directoryBadges()}); err != nil {
+		return 0, err
+	}
+	recordListing(ctx)
+
+	// The first two lines are the headers and their underline
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	shown := u.PageRows(lines[:2], lines[2:], pageSize)
+	u.Println()
+	return shown, nil
+}
+
+// renderHydrated renders the directories found in the cached catalog, then
+// fetches those missing from it and renders them too. Tables show the found
+// ones right away instead of waiting on the slowest request; other formats
+// are written whole. It returns every directory rendered.
+func renderHydrated(ctx context.Context, u *ui.UI, cmd *cli.Command, found []models.Directory, missing []string, by cache.DirectoryKey) ([]models.Directory, error) {
+	if !isTableFormat(cmd) {
+		fetched, err := hydrate(ctx, u, missing, by)
+		if err != nil {
+			return nil, err
+		}
+		all := append(found, fetched...)
+		return all, renderDirectories(ctx, u, cmd, all)
+	}
+
+	if len(found) > 0 || len(missing) == 0 {
+		if err := renderDirectories(ctx, u, cmd, found); err != nil {
+			return nil, err
+		}
+	}
+
+	fetched, err := hydrate(ctx, u, missing, by)
+	if err != nil {
+		return nil, err
+	}
+	if len(fetched) > 0 {
+		if len(found) > 0 {
+			u.Muted("Not in the cached catalog yet:")
+		}
+		if err := renderDirectories(ctx, u, cmd, fetched); err != nil {
+			return nil, err
+		}
+	}
+	return append(found, fetched...), nil
+}
+
+// hydrate fetches directories missing from the cached catalog from the API,
+// warning about those that no longer exist
+func hydrate(ctx context.Context, u *ui.UI, missing []string, by cache.DirectoryKey) ([]models.Directory, error) {
+	if len(missing) == 0 {
+		return nil, nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	progress := u.NewProgress("Fetching directories missing from the cache", len(missing))
+	fetched, gone, err := cache.NewCache(cfg, api.NewClient(cfg)).FetchDirectories(ctx, missing, by, progress.Increment)
+	progress.Done()
+
+	if len(gone) > 0 {
+		u.Warning("No longer in the catalog: %s", strings.Join(gone, ", "))
+	}
+	if err != nil {
+		u.Warning("Failed to fetch %d directories missing from the cache: %v", len(missing)-len(gone)-len(fetched), err)
+	}
+	return fetched, nil
+}
+
+// missingColumns returns the directory fields the backend didn't provide
+// when the catalog was cached, so outputs can leave them out
+func missingColumns() []string {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	return cache.NewCache(cfg, nil).MissingColumns()
+}
+
+// logoCells is the width of logos shown by show --logo, in terminal cells
+const logoCells = 8
+
+// showLogo displays the logo of a directory when the terminal supports inline
+// images. Failing to fetch or draw the logo is not fatal.
+func showLogo(ctx context.Context, u *ui.UI, apiClient *api.Client, dir *models.Directory, protocolName string) error {
+	protocol, err := ui.ParseImageProtocol(protocolName)
+	if err != nil {
+		return err
+	}
+
+	if protocol == ui.ImageNone || !u.CanShowImages() {
+		log.Debug().Msg("Inline images not supported, skipping logo")
+		return nil
+	}
+
+	logo, err := apiClient.GetLogo(ctx, dir.URL)
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to fetch logo")
+		return nil
+	}
+
+	if err := u.Image(logo, protocol, logoCells); err != nil {
+		log.Debug().Err(err).Msg("Failed to display logo")
+	}
+
+	return nil
 }
 
 // displayDirectoryDetails displays detailed information about a directory
-func displayDirectoryDetails(dir *models.Directory) {
-	ui.Bold("=== %s ===\n", dir.Name)
-	fmt.Printf("URL: %s\n", dir.URL)
-	fmt.Printf("Slug: %s\n\n", dir.Slug)
+func displayDirectoryDetails(u *ui.UI, dir *models.Directory) {
+	u.Bold("=== %s ===\n", dir.Name)
+	u.Printf("URL: %s\n", dir.URL)
+	u.Printf("Slug: %s\n\n", dir.Slug)
 
-	ui.Bold("Description:")
-	fmt.Printf("%s\n\n", dir.Description)
+	u.Bold("Description:")
+	u.Printf("%s\n\n", dir.Description)
 
-	ui.Bold("Metrics:")
-	fmt.Printf("  Domain Rating: %s\n", ui.FormatDR(&dir.DomainRating))
+	u.Bold("Metrics:")
+	u.Printf("  Domain Rating: %s\n", ui.FormatDR(&dir.DomainRating))
 	if dir.OrganicTraffic > 0 {
-		fmt.Printf("  Organic Traffic: %d\n", dir.OrganicTraffic)
+		u.Printf("  Organic Traffic: %d\n", dir.OrganicTraffic)
 	}
 	if dir.OrganicKeywords > 0 {
-		fmt.Printf("  Organic Keywords: %d\n", dir.OrganicKeywords)
+		u.Printf("  Organic Keywords: %d\n", dir.OrganicKeywords)
+	}
+	u.Printf("  Helpful Votes: %d\n", dir.HelpfulCount)
+	u.Printf("  Views: %d\n\n", dir.ViewCount)
+
+	u.Bold("Details:")
+	u.Printf("  Categories: %s\n", strings.Join(dir.Categories, ", "))
+	u.Printf("  Pricing: %s\n", ui.FormatPricing(dir.Pricing))
+	if dir.PriceAmount > 0 {
+		u.Printf("  Listing Fee: %s\n", ui.FormatPrice(dir.PriceAmount, dir.PriceCurrency))
+	}
+	u.Printf("  Link Type: %s\n", ui.FormatLinkType(dir.LinkType))
+	if labelsOf := directoryBadges(); labelsOf != nil {
+		if labels := labelsOf(*dir); len(labels) > 0 {
+			u.Printf("  Badges: %s\n", strings.Join(labels, " "))
+		}
 	}
-	fmt.Printf("  Helpful Votes: %d\n", dir.HelpfulCount)
-	fmt.Printf("  Views: %d\n\n", dir.ViewCount)
-
-	ui.Bold("Details:")
-	fmt.Printf("  Categories: %s\n", strings.Join(dir.Categories, ", "))
-	fmt.Printf("  Pricing: %s\n", ui.FormatPricing(dir.Pricing))
-	fmt.Printf("  Link Type: %s\n", ui.FormatLinkType(dir.LinkType))
 
 	if dir.SubmissionURL != "" {
-		fmt.Printf("  Submission URL: %s\n", dir.SubmissionURL)
+		u.Printf("  Submission URL: %s\n", dir.SubmissionURL)
+	}
+	if dir.ReviewDays > 0 {
+		u.Printf("  Review Time: %s\n", formatReviewTime(dir.ReviewDays))
+	}
+	if dir.FeedURL != "" {
+		u.Printf("  Feed: %s\n", dir.FeedURL)
 	}
 
 	if dir.IsAffiliate && dir.AffiliateURL != "" {
-		fmt.Printf("  Affiliate URL: %s\n", dir.AffiliateURL)
+		u.Printf("  Affiliate URL: %s\n", dir.AffiliateURL)
 	}
 
-	fmt.Printf("\n")
-	ui.Muted("Created: %s", dir.CreatedAt.Format("2006-01-02"))
-	ui.Muted("Updated: %s", dir.UpdatedAt.Format("2006-01-02"))
+	u.Printf("\n")
+	u.Muted("Created: %s", dir.CreatedAt.Format("2006-01-02"))
+	u.Muted("Updated: %s", dir.UpdatedAt.Format("2006-01-02"))
+}
+
+// yesFlag returns the flag applying bulk changes without confirmation
+func yesFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:    "yes",
+		Aliases: []string{"y"},
+		Usage:   "Apply the changes without asking for confirmation",
+	}
+}
+
+// confirmBulk asks whether to apply the bulk changes previewed above. It
+// doesn't ask with --yes, or when input is not a terminal, such as in
+// scripts and pipes.
+func confirmBulk(u *ui.UI, cmd *cli.Command, question string) (bool, error) {
+	if cmd.Bool("yes") || !isInteractive(u) {
+		return true, nil
+	}
+
+	answer, err := u.Prompt(question + " [y/N] ")
+	if err != nil {
+		return false, err
+	}
+	if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+		u.Info("Nothing changed")
+		return false, nil
+	}
+	return true, nil
+}
+
+// dryRun reports whether the command should only show what it would change,
+// with the global --dry-run or a command's own
+func dryRun(cmd *cli.Command) bool {
+	return cmd.Bool("dry-run") || cmd.Root().Bool("dry-run")
+}
+
+// mailConfig returns the SMTP server email is sent through
+func mailConfig(cfg *config.Config) mail.Config {
+	return mail.Config{
+		Server:   cfg.SMTP.Server,
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.Password,
+		From:     cfg.SMTP.From,
+	}
+}
+
+// invalidateUserData drops cached favorites and account data after a command
+// changed them
+func invalidateUserData(cfg *config.Config) {
+	if err := cache.NewCache(cfg, nil).InvalidateUserData(); err != nil {
+		log.Warn().Err(err).Msg("Failed to invalidate cached user data")
+	}
+}
+
+// userDataTTL describes how long personal data stays cached
+func userDataTTL(ttl time.Duration) string {
+	if ttl <= 0 {
+		return "always fresh"
+	}
+	return ttl.String()
 }
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-json"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// compareSideBySideMax is the largest number of directories shown side by
+// side in the table format; larger comparisons get a row per directory
+const compareSideBySideMax = 4
+
+// compareAttribute is a column of the comparison matrix
+type compareAttribute struct {
+	name  string
+	value func(models.Directory) string
+}
+
+var compareAttributes = []compareAttribute{
+	{"Name", func(d models.Directory) string { return d.Name }},
+	{"Slug", func(d models.Directory) string { return d.Slug }},
+	{"DR", func(d models.Directory) string { return strconv.Itoa(d.DomainRating) }},
+	{"Traffic", func(d models.Directory) string { return strconv.Itoa(d.OrganicTraffic) }},
+	{"Pricing", func(d models.Directory) string { return d.Pricing }},
+	{"Price", func(d models.Directory) string { return ui.FormatPrice(d.PriceAmount, d.PriceCurrency) }},
+	{"Link Type", func(d models.Directory) string { return d.LinkType }},
+	{"Review Time", func(d models.Directory) string { return formatReviewTime(d.ReviewDays) }},
+	{"Helpful Votes", func(d models.Directory) string { return strconv.Itoa(d.HelpfulCount) }},
+	{"Categories", func(d models.Directory) string { return strings.Join(d.Categories, ", ") }},
+	{"Submission URL", func(d models.Directory) string { return d.SubmissionURL }},
+}
+
+// compareCommand creates the compare command
+func compareCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "compare",
+		Usage:     "Compare directories side by side",
+		ArgsUsage: "<slug> <slug> [slug...]",
+		Metadata: examples(
+			"awesome-directories compare producthunt betalist",
+			"awesome-directories compare --all --category \"Startup Directories\" --format csv > matrix.csv",
+		),
+		Flags: append([]cli.Flag{
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Compare every directory matching the filters",
+			},
+			&cli.StringSliceFlag{
+				Name:    "category",
+				Aliases: []string{"c"},
+				Usage:   "Filter by category (with --all)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "pricing",
+				Usage: "Filter by pricing (with --all)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "link-type",
+				Usage: "Filter by link type (with --all)",
+			},
+			&cli.IntFlag{
+				Name:  "dr-min",
+				Usage: "Minimum domain rating (with --all)",
+			},
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Usage:   "Output format: table, csv, json, markdown",
+				Value:   "table",
+			},
+			snapshotFlag(),
+		}, filterMetadataFlags()...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			apiClient := api.NewClient(cfg)
+			cacheClient := cache.NewCache(cfg, apiClient)
+
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+
+			var compared []models.Directory
+			if cmd.Bool("all") {
+				if cmd.Args().Present() {
+					return fmt.Errorf("--all cannot be combined with directory slugs")
+				}
+
+				options := &models.FilterOptions{
+					Categories: cmd.StringSlice("category"),
+					Pricing:    cmd.StringSlice("pricing"),
+					LinkType:   cmd.StringSlice("link-type"),
+					DRMin:      cmd.Int("dr-min"),
+				}
+				if err := applyFilterMetadata(ctx, cmd, cfg, options); err != nil {
+					return err
+				}
+				compared = cacheClient.FilterDirectories(directories, options)
+			} else {
+				if cmd.Args().Len() < 2 {
+					return fmt.Errorf("at least two directory slugs are required (or use --all)")
+				}
+
+				for _, slug := range cmd.Args().Slice() {
+					dir := findDirectory(directories, slug)
+					if dir == nil {
+						return fmt.Errorf("directory not found: %s", slug)
+					}
+					compared = append(compared, *dir)
+				}
+			}
+			recordResults(ctx, len(compared))
+
+			if len(compared) == 0 {
+				u.Warning("No directories found")
+				return nil
+			}
+
+			return renderComparison(u, compared, cmd.String("format"))
+		},
+	}
+}
+
+// renderComparison writes the comparison matrix of directories × attributes
+func renderComparison(u *ui.UI, directories []models.Directory, format string) error {
+	switch strings.ToLower(format) {
+	case "", "table":
+		if len(directories) <= compareSideBySideMax {
+			headers := []string{""}
+			for _, dir := range directories {
+				headers = append(headers, dir.Name)
+			}
+			table := u.CreateTable(headers)
+			for _, attr := range compareAttributes[1:] {
+				row := []string{attr.name}
+				for _, dir := range directories {
+					row = append(row, ui.TruncateString(attr.value(dir), 40))
+				}
+				table.Row(row...)
+			}
+			u.Println(table)
+			return nil
+		}
+
+		table := u.CreateTable(compareHeaders())
+		for _, row := range compareRows(directories) {
+			for i := range row {
+				row[i] = ui.TruncateString(row[i], 30)
+			}
+			table.Row(row...)
+		}
+		u.Println(table)
+		return nil
+
+	case "csv":
+		writer := csv.NewWriter(u.Out)
+		if err := writer.Write(compareHeaders()); err != nil {
+			return fmt.Errorf("failed to write CSV header: %w", err)
+		}
+		if err := writer.WriteAll(compareRows(directories)); err != nil {
+			return fmt.Errorf("failed to write CSV rows: %w", err)
+		}
+		return nil
+
+	case "json":
+		headers := compareHeaders()
+		matrix := make([]map[string]string, 0, len(directories))
+		for _, row := range compareRows(directories) {
+			entry := make(map[string]string, len(headers))
+			for i, header := range headers {
+				entry[header] = row[i]
+			}
+			matrix = append(matrix, entry)
+		}
+
+		data, err := json.MarshalIndent(matrix, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal comparison: %w", err)
+		}
+		u.Println(string(data))
+		return nil
+
+	case "markdown", "md":
+		headers := compareHeaders()
+		u.Printf("| %s |\n", strings.Join(headers, " | "))
+		u.Printf("|%s\n", strings.Repeat("---|", len(headers)))
+		for _, row := range compareRows(directories) {
+			for i := range row {
+				row[i] = strings.ReplaceAll(row[i], "|", "\\|")
+			}
+			u.Printf("| %s |\n", strings.Join(row, " | "))
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported format: %s (use table, csv, json, markdown)", format)
+	}
+}
+
+// compareHeaders returns the attribute names of the comparison matrix
+func compareHeaders() []string {
+	headers := make([]string, len(compareAttributes))
+	for i, attr := range compareAttributes {
+		headers[i] = attr.name
+	}
+	return headers
+}
+
+// compareRows returns a row of attribute values per directory
+func compareRows(directories []models.Directory) [][]string {
+	rows := make([][]string, len(directories))
+	for i, dir := range directories {
+		row := make([]string, len(compareAttributes))
+		for j, attr := range compareAttributes {
+			row[j] = attr.value(dir)
+		}
+		rows[i] = row
+	}
+	return rows
+}
+
+// formatReviewTime formats a typical review time in days, "-" when unknown
+func formatReviewTime(days int) string {
+	switch {
+	case days <= 0:
+		return "-"
+	case days == 1:
+		return "1 day"
+	default:
+		return fmt.Sprintf("%d days", days)
+	}
+}
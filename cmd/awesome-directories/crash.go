@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"time"
+
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/crash"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+const (
+	// crashLogLines is the number of log lines kept for crash reports
+	crashLogLines = 200
+
+	issuesURL = "https://github.com/awesome-directories/cli/issues"
+)
+
+// logBuffer keeps recent log lines for crash reports
+var logBuffer = crash.NewLogBuffer(crashLogLines)
+
+// recoverPanic turns a panic into a crash report and a friendly message. It
+// must be deferred at the top of main.
+func recoverPanic() {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	report := &crash.Report{
+		Time:    time.Now(),
+		Version: fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date),
+		Args:    redactArgs(os.Args),
+		Panic:   r,
+		Stack:   debug.Stack(),
+		Logs:    logBuffer.Lines(),
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		cfg = &config.Config{}
+		cfg.DataDir, _ = config.GetDataDir()
+	}
+
+	u := ui.Default()
+	u.Error("awesome-directories crashed unexpectedly: %v", r)
+
+	path, err := report.Write(cfg)
+	if err != nil {
+		fmt.Fprintf(u.Err, "Failed to write crash report (%v):\n\n%s\n", err, report.Stack)
+	} else {
+		fmt.Fprintf(u.Err, "A crash report was written to %s\n", path)
+		fmt.Fprintf(u.Err, "Please attach it when reporting the issue at %s\n", issuesURL)
+	}
+
+	os.Exit(2)
+}
+
+// redactArgs hides secrets passed on the command line, such as the token of
+// 'auth token <token>'
+func redactArgs(args []string) []string {
+	redacted := append([]string(nil), args...)
+	for i := 1; i < len(redacted); i++ {
+		if redacted[i-1] == "token" {
+			redacted[i] = "[redacted]"
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/render"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+const (
+	dashboardNewRows      = 5
+	dashboardFollowUpRows = 5
+	dashboardAlertRows    = 8
+
+	// ANSI sequences for the full-screen view
+	enterAltScreen = "\033[?1049h\033[?25l"
+	exitAltScreen  = "\033[?25h\033[?1049l"
+	clearScreen    = "\033[H\033[2J"
+)
+
+// dashboardCommand creates the dashboard command
+func dashboardCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "dashboard",
+		Usage: "Show a live-updating overview of the catalog, submissions and alerts",
+		Metadata: examples(
+			"awesome-directories dashboard",
+			"awesome-directories dashboard --interval 1m",
+			"awesome-directories dashboard --once",
+		),
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:    "interval",
+				Aliases: []string{"i"},
+				Usage:   "Refresh interval",
+				Value:   30 * time.Second,
+			},
+			&cli.BoolFlag{
+				Name:  "once",
+				Usage: "Render the dashboard once and exit",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			interval := cmd.Duration("interval")
+			if interval <= 0 {
+				return fmt.Errorf("interval must be positive")
+			}
+
+			cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+
+			fullScreen := !cmd.Bool("once")
+			if file, ok := u.Out.(*os.File); !ok || !term.IsTerminal(int(file.Fd())) {
+				fullScreen = false
+			}
+
+			if !fullScreen {
+				return renderDashboard(ctx, u, cfg, cacheClient, interval)
+			}
+
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			u.Printf("%s", enterAltScreen)
+			defer u.Printf("%s", exitAltScreen)
+
+			for {
+				// Render off-screen first so the view doesn't flicker
+				var buf bytes.Buffer
+				frame := ui.New(u.In, &buf, u.Err)
+				if err := renderDashboard(ctx, frame, cfg, cacheClient, interval); err != nil {
+					frame.Error("%v", err)
+				}
+				u.Printf("%s%s", clearScreen, buf.String())
+
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-time.After(interval):
+				}
+			}
+		},
+	}
+}
+
+// renderDashboard renders a single frame of the dashboard
+func renderDashboard(ctx context.Context, u *ui.UI, cfg *config.Config, cacheClient *cache.Cache, interval time.Duration) error {
+	now := time.Now()
+
+	u.Bold("Awesome Directories — %s", now.Format("2006-01-02 15:04:05"))
+	u.Muted("Refreshing every %s, press Ctrl+C to quit", interval)
+	u.Println()
+
+	directories, err := cacheClient.GetDirectories(ctx, false)
+	if err != nil {
+		return fmt.Errorf("failed to get directories: %w", err)
+	}
+
+	// Catalog
+	u.Bold("Catalog")
+	if meta, err := cacheClient.Metadata(); err == nil {
+		age := now.Sub(meta.LastUpdated)
+		status := "fresh"
+		if age > cfg.DirectoriesTTL() {
+			status = "stale"
+		}
+		u.Printf("  Directories: %d   Cache age: %s (%s)\n", len(directories), age.Round(time.Minute), status)
+	} else {
+		u.Printf("  Directories: %d\n", len(directories))
+	}
+
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	var newToday []models.Directory
+	newThisWeek := 0
+	for _, dir := range directories {
+		if dir.CreatedAt.After(startOfDay) {
+			newToday = append(newToday, dir)
+		}
+		if dir.CreatedAt.After(startOfDay.AddDate(0, 0, -6)) {
+			newThisWeek++
+		}
+	}
+	u.Printf("  New today: %d   New this week: %d\n", len(newToday), newThisWeek)
+
+	if len(newToday) > 0 {
+		u.Println()
+		sort.Slice(newToday, func(i, j int) bool { return newToday[i].DomainRating > newToday[j].DomainRating })
+		if err := render.Table(u.Out, newToday[:min(dashboardNewRows, len(newToday))], render.Options{}); err != nil {
+			return err
+		}
+	}
+	u.Println()
+
+	// Submission pipeline, as tracked locally or in the state dir
+	u.Bold("Submission pipeline")
+	dataStore := store.New(cfg)
+	submissions, err := dataStore.Submissions()
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to load submissions")
+		u.Muted("  Unavailable")
+	} else if len(submissions) == 0 {
+		u.Muted("  No submissions yet. Track one with 'submissions track'.")
+	} else {
+		counts := make(map[string]int)
+		for _, submission := range submissions {
+			counts[submission.Status]++
+		}
+		var parts []string
+		for _, status := range models.SubmissionStatuses {
+			parts = append(parts, fmt.Sprintf("%s %d", status, counts[status]))
+		}
+		u.Printf("  %s\n", strings.Join(parts, " · "))
+	}
+	u.Println()
+
+	// Follow-ups due next, overdue ones first
+	u.Bold("Follow-ups")
+	audit, err := dataStore.AuditLog(time.Time{})
+	if err != nil {
+		log.Debug().Err(err).Msg("Failed to read the audit log")
+	}
+	bySlug := make(map[string]models.Directory, len(directories))
+	for _, dir := range directories {
+		bySlug[dir.Slug] = dir
+	}
+	cacheClient.Lineage().Alias(bySlug, cache.BySlug)
+	due := followUps(submissions, audit, bySlug)
+	if len(due) == 0 {
+		u.Muted("  Nothing submitted awaiting review")
+	}
+	overdue := 0
+	for _, f := range due {
+		if f.Due.Before(startOfDay) {
+			overdue++
+		}
+	}
+	if overdue > 0 {
+		u.Printf("  Overdue: %d\n", overdue)
+	}
+	for _, f := range due[:min(dashboardFollowUpRows, len(due))] {
+		name := f.Submission.Directory
+		if dir, ok := bySlug[name]; ok {
+			name = dir.Name
+		}
+		when := f.Due.Format("Mon Jan 02")
+		if f.Due.Before(startOfDay) {
+			when = "overdue"
+		}
+		line := fmt.Sprintf("  %-10s  %s  submitted %s", when, name, f.SubmittedAt.Format("Jan 2"))
+		if f.Submission.Project != models.DefaultProject {
+			line += " [" + f.Submission.Project + "]"
+		}
+		u.Println(line)
+	}
+	u.Println()
+
+	// Recent alerts
+	u.Bold("Recent alerts")
+	alerts, err := cacheClient.RecentAlerts(dashboardAlertRows)
+	if err != nil {
+		return err
+	}
+	if len(alerts) == 0 {
+		u.Muted("  No alerts yet. Run 'watch' to record catalog changes.")
+	}
+	for _, alert := range alerts {
+		line := fmt.Sprintf("  %s  %-8s %s", alert.Time.Local().Format("01-02 15:04"), alert.Kind, alert.Name)
+		if alert.Details != "" {
+			line += "  " + ui.TruncateString(alert.Details, 60)
+		}
+		u.Println(line)
+	}
+
+	return nil
+}
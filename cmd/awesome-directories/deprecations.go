@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// renamedCommands maps old top-level command names to their replacements.
+// Old names keep working as hidden aliases that print a deprecation warning.
+var renamedCommands = map[string]string{}
+
+// renamedFlags maps old flag names to their replacements. The old name must
+// stay in the new flag's Aliases so it still parses; using it prints a
+// deprecation warning.
+var renamedFlags = map[string]string{}
+
+// addRenamedCommands registers a hidden, warning alias for each renamed
+// command of root
+func addRenamedCommands(root *cli.Command) {
+	olds := make([]string, 0, len(renamedCommands))
+	for old := range renamedCommands {
+		olds = append(olds, old)
+	}
+	sort.Strings(olds)
+
+	for _, old := range olds {
+		target := root.Command(renamedCommands[old])
+		if target == nil {
+			panic(fmt.Sprintf("renamed command %q points to unknown command %q", old, renamedCommands[old]))
+		}
+		root.Commands = append(root.Commands, renamedCommand(old, target))
+	}
+}
+
+// renamedCommand returns a hidden command named old that warns and runs target
+func renamedCommand(old string, target *cli.Command) *cli.Command {
+	action := target.Action
+
+	return &cli.Command{
+		Name:      old,
+		Usage:     target.Usage,
+		ArgsUsage: target.ArgsUsage,
+		Hidden:    true,
+		Flags:     target.Flags,
+		Commands:  target.Commands,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			ui.FromContext(ctx).Warning("'%s' is deprecated and will be removed, use '%s' instead", old, target.Name)
+			if action == nil {
+				return cli.ShowSubcommandHelp(cmd)
+			}
+			return action(ctx, cmd)
+		},
+	}
+}
+
+// warnDeprecatedFlags prints a warning for each renamed flag used in args
+func warnDeprecatedFlags(u *ui.UI, args []string) {
+	for _, arg := range args {
+		if arg == "--" {
+			return
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		if name == arg {
+			continue
+		}
+		name, _, _ = strings.Cut(name, "=")
+
+		if replacement, ok := renamedFlags[name]; ok {
+			u.Warning("--%s is deprecated and will be removed, use --%s instead", name, replacement)
+		}
+	}
+}
+
+// warnPendingMigrations reminds the user to migrate an outdated config file
+func warnPendingMigrations(u *ui.UI) {
+	pending, err := config.PendingMigrations()
+	if err != nil || len(pending) == 0 {
+		return
+	}
+
+	u.Warning("Your config file uses an older format. Run 'awesome-directories migrate' to update it.")
+}
+
+// migrateCommand creates the migrate command
+func migrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "Update the config file after upgrading the CLI",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "List pending migrations without applying them",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			pending, err := config.PendingMigrations()
+			if err != nil {
+				return err
+			}
+
+			if len(pending) == 0 {
+				u.Success("Config is up to date")
+				return nil
+			}
+
+			if dryRun(cmd) {
+				u.Info("%d pending migration(s):", len(pending))
+				for _, m := range pending {
+					u.Printf("  %d. %s\n", m.Version, m.Description)
+				}
+				return nil
+			}
+
+			applied, err := config.Migrate()
+			if err != nil {
+				return err
+			}
+
+			for _, m := range applied {
+				u.Success("%d. %s", m.Version, m.Description)
+			}
+			if cfg, err := config.Load(); err == nil {
+				recordConfigAudit(cfg, fmt.Sprintf("migrated to version %d", config.CurrentVersion))
+			}
+
+			configDir, err := config.GetConfigDir()
+			if err == nil {
+				u.Muted("Previous config saved to %s", filepath.Join(configDir, "config.yaml.bak"))
+			}
+
+			return nil
+		},
+	}
+}
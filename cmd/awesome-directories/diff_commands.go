@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// currentRef names the live cached catalog in diff
+const currentRef = "current"
+
+// diffOutput is the machine-readable output of diff
+type diffOutput struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	*cache.ChangeSet
+}
+
+// diffCommand creates the diff command
+func diffCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "diff",
+		Usage: "Show catalog changes between two snapshots or dates",
+		Metadata: examples(
+			"awesome-directories diff --from before-launch",
+			"awesome-directories diff --from 2024-01-01 --to before-launch",
+			"awesome-directories diff --from before-launch --json",
+		),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "from",
+				Usage:    "Snapshot name or date (YYYY-MM-DD) to compare from",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "to",
+				Usage: "Snapshot name, date (YYYY-MM-DD), or 'current' for the cached catalog",
+				Value: currentRef,
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+
+			fromLabel, before, err := resolveDiffRef(ctx, cacheClient, cmd.String("from"))
+			if err != nil {
+				return err
+			}
+
+			toLabel, after, err := resolveDiffRef(ctx, cacheClient, cmd.String("to"))
+			if err != nil {
+				return err
+			}
+
+			changes := cache.DiffDirectories(before, after)
+
+			if cmd.Bool("json") {
+				return printJSON(u, diffOutput{From: fromLabel, To: toLabel, ChangeSet: changes})
+			}
+
+			u.Bold("Catalog changes from %s to %s:", fromLabel, toLabel)
+			u.Printf("  %d added, %d removed, %d changed\n\n", len(changes.Added), len(changes.Removed), len(changes.Changed))
+
+			if changes.Empty() {
+				u.Info("No changes")
+				return nil
+			}
+
+			displayChanges(u, changes)
+
+			return nil
+		},
+	}
+}
+
+// resolveDiffRef loads the directories for a diff reference and returns a
+// label describing it
+func resolveDiffRef(ctx context.Context, cacheClient *cache.Cache, ref string) (string, []models.Directory, error) {
+	if ref == "" || ref == currentRef {
+		directories, err := cacheClient.GetDirectories(ctx, false)
+		if err != nil {
+			return "", nil, fmt.Errorf("failed to get directories: %w", err)
+		}
+		return currentRef, directories, nil
+	}
+
+	snapshot, err := cacheClient.ResolveSnapshot(ref)
+	if err != nil {
+		return "", nil, err
+	}
+
+	label := fmt.Sprintf("%s (%s)", snapshot.Name, snapshot.CreatedAt.Local().Format(time.DateOnly))
+	return label, snapshot.Directories, nil
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/discover"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// valueOrDash returns value, or "-" when it is empty
+func valueOrDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// discoverSubmitMax is how many candidate pages discover-submit lists
+const discoverSubmitMax = 5
+
+// discoverSubmitCommand creates the discover-submit command
+func discoverSubmitCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "discover-submit",
+		Usage:     "Find the submission page of a directory missing a submission URL",
+		ArgsUsage: "<slug>",
+		Metadata: examples(
+			"awesome-directories discover-submit betalist",
+			"awesome-directories discover-submit betalist --save",
+			"awesome-directories discover-submit betalist --save --propose",
+		),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "save",
+				Usage: "Store the best candidate as the directory's submission URL without asking",
+			},
+			&cli.BoolFlag{
+				Name:  "propose",
+				Usage: "Print a link proposing the submission URL for the catalog",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Search even if the directory already has a submission URL",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() == 0 {
+				return fmt.Errorf("directory slug is required")
+			}
+			slug := cmd.Args().First()
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+			directory := findDirectory(directories, slug)
+			if directory == nil {
+				return fmt.Errorf("directory not found: %s", slug)
+			}
+
+			if directory.SubmissionURL != "" && !cmd.Bool("force") {
+				u.Info("%s already has a submission URL: %s", directory.Name, directory.SubmissionURL)
+				u.Muted("Use --force to search anyway")
+				return nil
+			}
+
+			u.Muted("Searching the sitemap and homepage of %s...", directory.URL)
+			candidates, err := discover.SubmitPages(ctx, directory.URL)
+			if err != nil {
+				return fmt.Errorf("failed to search %s: %w", directory.Name, err)
+			}
+			recordResults(ctx, len(candidates))
+
+			if len(candidates) == 0 {
+				u.Warning("No page of %s looks like a submission form", directory.Name)
+				return nil
+			}
+
+			table := u.CreateTable([]string{"#", "Score", "Found In", "URL", "Link Text"})
+			for i, candidate := range candidates {
+				if i == discoverSubmitMax {
+					break
+				}
+				table.Row(fmt.Sprint(i+1), fmt.Sprint(candidate.Score), candidate.Source,
+					candidate.URL, ui.TruncateString(candidate.Text, 30))
+			}
+			u.Println(table)
+
+			best := candidates[0].URL
+			save := cmd.Bool("save")
+			if dryRun(cmd) {
+				if save {
+					u.Info("Dry run: would save %s as the submission URL of %s", best, directory.Name)
+				}
+				save = false
+			} else if !save && isInteractive(u) && !cfg.ReadOnly {
+				answer, err := u.Prompt(fmt.Sprintf("Save %s as the submission URL of %s? [y/N] ", best, directory.Name))
+				if err != nil {
+					return err
+				}
+				save = strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes")
+			}
+
+			if save {
+				dataStore := store.New(cfg)
+				err := dataStore.UpdateOverride(slug, func(override *store.Override) {
+					override.SubmissionURL = best
+				})
+				if err != nil {
+					return err
+				}
+				recordAudit(dataStore, "overrides.submission_url", slug, best)
+				u.Success("Saved %s as the submission URL of %s", best, directory.Name)
+			}
+
+			if cmd.Bool("propose") {
+				query := url.Values{
+					"title": {fmt.Sprintf("Submission URL for %s", directory.Name)},
+					"body": {fmt.Sprintf("Directory: %s (%s)\nSubmission URL: %s\n\nFound with `awesome-directories discover-submit %s`.",
+						directory.Name, directory.URL, best, slug)},
+				}
+				u.Info("Propose it for the catalog: %s/new?%s", issuesURL, query.Encode())
+			}
+
+			return nil
+		},
+	}
+}
+
+// analyzeCommand creates the analyze command
+func analyzeCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "analyze",
+		Usage:     "Rate how hard the submission forms of directories are to fill in",
+		ArgsUsage: "[slug...]",
+		Metadata: examples(
+			"awesome-directories analyze betalist",
+			"awesome-directories analyze --all",
+			"awesome-directories plan --max-count 20 --max-difficulty medium",
+		),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "all",
+				Usage: "Analyze every directory with a submission URL that wasn't analyzed yet",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "With --all, analyze directories again",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() == 0 && !cmd.Bool("all") {
+				return fmt.Errorf("directory slug or --all is required")
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+
+			dataStore := store.New(cfg)
+			analyzed, err := dataStore.Analyses()
+			if err != nil {
+				return err
+			}
+
+			// Submission pages to analyze, by slug
+			pages := make(map[string]string)
+			names := make(map[string]string)
+			if cmd.Bool("all") {
+				for _, dir := range directories {
+					if _, ok := analyzed[dir.Slug]; ok && !cmd.Bool("force") {
+						continue
+					}
+					if dir.SubmissionURL != "" {
+						pages[dir.Slug], names[dir.Slug] = dir.SubmissionURL, dir.Name
+					}
+				}
+			}
+			for _, slug := range cmd.Args().Slice() {
+				dir := findDirectory(directories, slug)
+				switch {
+				case dir == nil:
+					return fmt.Errorf("directory not found: %s", slug)
+				case dir.SubmissionURL == "":
+					u.Warning("%s has no submission URL; try 'awesome-directories discover-submit %s'", dir.Name, slug)
+				default:
+					pages[slug], names[slug] = dir.SubmissionURL, dir.Name
+				}
+			}
+
+			if len(pages) == 0 {
+				u.Info("No submission pages to analyze")
+				return nil
+			}
+
+			progress := u.NewProgress("Analyzing submission pages", len(pages))
+			analyses, errs := discover.AnalyzeForms(ctx, pages, progress.Increment)
+			progress.Done()
+			recordResults(ctx, len(analyses))
+
+			now := time.Now().UTC()
+			stored := make(map[string]store.Analysis, len(analyses))
+			for slug, analysis := range analyses {
+				stored[slug] = store.Analysis{
+					URL:              analysis.URL,
+					Difficulty:       analysis.Difficulty,
+					RobotsDisallowed: analysis.RobotsDisallowed,
+					LoginWall:        analysis.LoginWall,
+					Captcha:          analysis.Captcha,
+					ExternalForm:     analysis.ExternalForm,
+					Fields:           analysis.Fields,
+					RequiredFields:   analysis.RequiredFields,
+					AnalyzedAt:       now,
+				}
+			}
+			if err := dataStore.SaveAnalyses(stored); err != nil {
+				return err
+			}
+
+			slugs := make([]string, 0, len(pages))
+			for slug := range pages {
+				slugs = append(slugs, slug)
+			}
+			sort.Strings(slugs)
+
+			table := u.CreateTable([]string{"Name", "Difficulty", "Fields", "Required", "Signals"})
+			for _, slug := range slugs {
+				analysis, ok := analyses[slug]
+				if !ok {
+					table.Row(ui.TruncateString(names[slug], 30), "-", "-", "-", ui.TruncateString(errs[slug].Error(), 50))
+					continue
+				}
+				table.Row(
+					ui.TruncateString(names[slug], 30),
+					analysis.Difficulty,
+					fmt.Sprint(analysis.Fields),
+					fmt.Sprint(analysis.RequiredFields),
+					valueOrDash(strings.Join(analysis.Signals(), ", ")),
+				)
+			}
+			u.Println(table)
+
+			if len(errs) > 0 {
+				u.Warning("%d submission page(s) could not be fetched", len(errs))
+			}
+			u.Success("Analyzed %d submission page(s)", len(analyses))
+			if len(analyses) > 0 {
+				u.Muted("plan now prefers directories with easier submission forms")
+			}
+			return nil
+		},
+	}
+}
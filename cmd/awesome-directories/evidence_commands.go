@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// submissionAttachCommand creates the submissions attach command
+func submissionAttachCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "attach",
+		Usage:     "Attach proof of submission: screenshots, confirmation emails (.eml) or URLs",
+		ArgsUsage: "<slug> <file|url>...",
+		Metadata: examples(
+			"awesome-directories submissions attach betalist shots/betalist-listing-20250115.png",
+			"awesome-directories submissions attach betalist ~/Downloads/confirmation.eml --project acme",
+			"awesome-directories submissions attach betalist https://betalist.com/startups/acme",
+		),
+		Flags: []cli.Flag{projectFlag()},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() < 2 {
+				return fmt.Errorf("directory slug and at least one file or URL are required")
+			}
+			slug := cmd.Args().First()
+			items := cmd.Args().Tail()
+
+			// Check the files before touching the submission
+			for _, item := range items {
+				if isURL(item) {
+					continue
+				}
+				info, err := os.Stat(item)
+				if err != nil {
+					return fmt.Errorf("cannot attach %s: %w", item, err)
+				}
+				if info.IsDir() {
+					return fmt.Errorf("cannot attach %s: it is a directory", item)
+				}
+			}
+
+			evidenceStore, err := openStore()
+			if err != nil {
+				return err
+			}
+
+			if dryRun(cmd) {
+				if _, err := trackedSubmission(evidenceStore, cmd.String("project"), slug); err != nil {
+					return err
+				}
+				u.Info("Dry run: would attach %s to %s", strings.Join(items, ", "), slug)
+				return nil
+			}
+
+			var attached []string
+			dataStore, submission, err := updateSubmission(cmd.String("project"), slug, false, func(submission *models.TrackedSubmission) error {
+				now := time.Now().UTC()
+				for _, item := range items {
+					evidence := models.Evidence{URL: item, AddedAt: now}
+					if !isURL(item) {
+						name, err := evidenceStore.StoreEvidence(submission.Project, submission.Directory, item)
+						if err != nil {
+							return err
+						}
+						evidence = models.Evidence{File: name, AddedAt: now}
+					}
+					submission.Evidence = append(submission.Evidence, evidence)
+					attached = append(attached, evidence.Name())
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			recordAudit(dataStore, "submissions.attach", submission.Project+"/"+slug, strings.Join(attached, ", "))
+			u.Success("Attached %d item(s) to %s", len(attached), slug)
+			u.Muted("Evidence is kept in %s", dataStore.EvidenceDir(submission.Project, slug))
+			return nil
+		},
+	}
+}
+
+// submissionEvidenceCommand creates the submissions evidence command
+func submissionEvidenceCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "evidence",
+		Usage:     "List the proof of submission attached to a submission",
+		ArgsUsage: "<slug>",
+		Flags:     []cli.Flag{projectFlag()},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() == 0 {
+				return fmt.Errorf("directory slug is required")
+			}
+
+			dataStore, err := openStore()
+			if err != nil {
+				return err
+			}
+			submission, err := trackedSubmission(dataStore, cmd.String("project"), cmd.Args().First())
+			if err != nil {
+				return err
+			}
+			recordResults(ctx, len(submission.Evidence))
+
+			if len(submission.Evidence) == 0 {
+				u.Warning("No evidence attached to %s yet. Use 'submissions attach %s <file|url>'.", submission.Directory, submission.Directory)
+				return nil
+			}
+
+			table := u.CreateTable([]string{"Added", "Kind", "Location"})
+			for _, evidence := range submission.Evidence {
+				kind, location := "url", evidence.URL
+				if evidence.File != "" {
+					kind, location = "file", dataStore.EvidencePath(submission, evidence)
+				}
+				table.Row(evidence.AddedAt.Local().Format("2006-01-02"), kind, location)
+			}
+			u.Println(table)
+
+			return nil
+		},
+	}
+}
+
+// isURL reports whether value is an http or https URL rather than a file
+func isURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
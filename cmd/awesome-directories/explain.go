@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// queryPlan describes how a listing command reads and narrows the catalog
+type queryPlan struct {
+	// Source is where the catalog is read from: cache, api or snapshot
+	Source   string     `json:"source"`
+	Snapshot string     `json:"snapshot,omitempty"`
+	SyncedAt *time.Time `json:"synced_at,omitempty"`
+	TTL      string     `json:"ttl,omitempty"`
+
+	// Request is the PostgREST request fetching the catalog, sent unless
+	// the cache is fresh or a snapshot is read
+	Request string `json:"request"`
+
+	ServerFilters []string `json:"server_filters"`
+	ClientFilters []string `json:"client_filters"`
+
+	// Sort is the order results come in; RequestedSort is the --sort given
+	Sort          string `json:"sort"`
+	RequestedSort string `json:"requested_sort,omitempty"`
+
+	Limit  int `json:"limit,omitempty"`
+	Offset int `json:"offset,omitempty"`
+}
+
+// explainFlag returns the flag printing the query plan instead of running
+// the command
+func explainFlag() cli.Flag {
+	return &cli.BoolFlag{
+		Name:  "explain",
+		Usage: "Print the query, where each filter runs, the cache status and the sort, without running it",
+	}
+}
+
+// explainQuery prints the plan of a listing command with its final options
+func explainQuery(ctx context.Context, cmd *cli.Command, cfg *config.Config, apiClient *api.Client, cacheClient *cache.Cache, options *models.FilterOptions) error {
+	u := ui.FromContext(ctx)
+
+	// The catalog is always fetched whole; filters apply to the cached copy
+	request := apiClient.DirectoriesURL(nil)
+	if decoded, err := url.QueryUnescape(request); err == nil {
+		request = decoded
+	}
+	plan := queryPlan{
+		Request:       request,
+		ClientFilters: clientFilters(cmd, options),
+		RequestedSort: options.SortBy,
+		Limit:         options.Limit,
+		Offset:        options.Offset,
+	}
+	if parsed, err := url.Parse(request); err == nil {
+		query := parsed.Query()
+		plan.Sort = query.Get("order")
+		for key, values := range query {
+			if key != "select" && key != "order" {
+				plan.ServerFilters = append(plan.ServerFilters, key+"="+strings.Join(values, ","))
+			}
+		}
+		sort.Strings(plan.ServerFilters)
+	}
+
+	if name := cmd.String("snapshot"); name != "" {
+		snapshot, err := cacheClient.LoadSnapshot(name)
+		if err != nil {
+			return err
+		}
+		plan.Source, plan.Snapshot, plan.SyncedAt = "snapshot", snapshot.Name, &snapshot.CreatedAt
+	} else {
+		plan.Source, plan.TTL = "api", cfg.DirectoriesTTL().String()
+		if meta, err := cacheClient.Metadata(); err == nil {
+			plan.SyncedAt = &meta.LastUpdated
+		}
+		if cacheClient.Fresh() {
+			plan.Source = "cache"
+		}
+	}
+
+	if strings.EqualFold(cmd.String("format"), "json") {
+		return printJSON(u, plan)
+	}
+
+	u.Bold("Query plan")
+	switch {
+	case plan.Source == "snapshot":
+		u.Printf("  Source:      snapshot %s, taken %s ago\n", plan.Snapshot, time.Since(*plan.SyncedAt).Round(time.Second))
+	case plan.Source == "cache":
+		u.Printf("  Source:      cache, synced %s ago (TTL %s)\n", time.Since(*plan.SyncedAt).Round(time.Second), plan.TTL)
+	case plan.SyncedAt != nil:
+		u.Printf("  Source:      API; the cache synced %s ago is past its TTL of %s and only used if the request fails\n",
+			time.Since(*plan.SyncedAt).Round(time.Second), plan.TTL)
+	default:
+		u.Printf("  Source:      API; nothing is cached yet\n")
+	}
+
+	u.Printf("  Request:     GET %s\n", plan.Request)
+	if plan.Source != "api" {
+		u.Printf("               (not sent: the %s is read instead)\n", plan.Source)
+	}
+	printPlanList(u, "Server-side:", plan.ServerFilters)
+	printPlanList(u, "Client-side:", plan.ClientFilters)
+
+	order := plan.Sort + ", the order the catalog was fetched in"
+	if plan.RequestedSort != "" && plan.RequestedSort != string(models.SortMostHelpful) {
+		order += fmt.Sprintf("; --sort %s is not applied to cached results", plan.RequestedSort)
+	}
+	u.Printf("  Sort:        %s\n", order)
+
+	if plan.Limit > 0 || plan.Offset > 0 {
+		u.Printf("  Page:        limit %d, offset %d (client-side)\n", plan.Limit, plan.Offset)
+	}
+	return nil
+}
+
+// printPlanList prints a labelled list of the query plan, one item a line
+func printPlanList(u *ui.UI, label string, items []string) {
+	if len(items) == 0 {
+		items = []string{"none"}
+	}
+	for i, item := range items {
+		if i > 0 {
+			label = ""
+		}
+		u.Printf("  %-12s %s\n", label, item)
+	}
+}
+
+// clientFilters describes the filters applied to the cached catalog, in the
+// order cache.Matches checks them
+func clientFilters(cmd *cli.Command, options *models.FilterOptions) []string {
+	filters := make([]string, 0)
+	if name := cmd.String("collection"); name != "" {
+		filters = append(filters, "in collection "+name)
+	}
+	if options.Query != "" {
+		filters = append(filters, fmt.Sprintf("name or description contains %q", options.Query))
+	}
+	if len(options.Categories) > 0 {
+		filters = append(filters, "category in ("+strings.Join(options.Categories, ", ")+")")
+	}
+	if len(options.Pricing) > 0 {
+		filters = append(filters, "pricing in ("+strings.Join(options.Pricing, ", ")+")")
+	}
+	if len(options.LinkType) > 0 {
+		filters = append(filters, "link type in ("+strings.Join(options.LinkType, ", ")+")")
+	}
+	if len(options.Countries) > 0 {
+		filters = append(filters, "country in ("+strings.Join(options.Countries, ", ")+") or unknown")
+	}
+	if len(options.Languages) > 0 {
+		filters = append(filters, "language in ("+strings.Join(options.Languages, ", ")+") or unknown")
+	}
+	if options.Audience != "" {
+		filters = append(filters, "audience "+options.Audience+", both or unknown")
+	}
+	if options.Currency != "" {
+		filters = append(filters, "listing fee in "+options.Currency)
+	}
+	if options.MaxPrice > 0 {
+		filters = append(filters, fmt.Sprintf("listing fee <= %g, excluding paid directories without a price", options.MaxPrice))
+	}
+	if options.DRMin > 0 {
+		filters = append(filters, fmt.Sprintf("domain rating >= %d or unknown", options.DRMin))
+	}
+	if options.DRMax > 0 {
+		filters = append(filters, fmt.Sprintf("domain rating <= %d or unknown", options.DRMax))
+	}
+	return filters
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/config"
+)
+
+// withFlagDefaults makes cmd and its subcommands start from the default flag
+// values of the config file before running
+func withFlagDefaults(cmd *cli.Command) {
+	if cmd.Action != nil {
+		before := cmd.Before
+		cmd.Before = func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			if err := applyFlagDefaults(c); err != nil {
+				return nil, err
+			}
+			if before != nil {
+				return before(ctx, c)
+			}
+			return ctx, nil
+		}
+	}
+	for _, sub := range cmd.Commands {
+		withFlagDefaults(sub)
+	}
+}
+
+// applyFlagDefaults sets the flags of cmd left unset on the command line to
+// their defaults in the config file
+func applyFlagDefaults(cmd *cli.Command) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	// Commands are named without the program name, such as "favorites list"
+	name := strings.TrimPrefix(cmd.FullName(), cmd.Root().Name+" ")
+	defaults, ok := cfg.Defaults[name]
+	if !ok {
+		return nil
+	}
+
+	for flag, value := range defaults {
+		if !slices.ContainsFunc(cmd.Flags, func(f cli.Flag) bool { return slices.Contains(f.Names(), flag) }) {
+			log.Warn().Str("command", name).Str("flag", flag).Msg("Ignoring config default of an unknown flag")
+			continue
+		}
+		if cmd.IsSet(flag) {
+			continue
+		}
+
+		// A list sets a repeatable flag once per item
+		values, ok := value.([]interface{})
+		if !ok {
+			values = []interface{}{value}
+		}
+		for _, v := range values {
+			if err := cmd.Set(flag, fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("invalid config default %s.%s: %w", name, flag, err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// examplesTemplate renders the examples stored in a command's metadata
+const examplesTemplate = `{{with index .Metadata "examples"}}
+EXAMPLES:{{range .}}
+   {{.}}{{end}}
+{{end}}`
+
+func init() {
+	cli.CommandHelpTemplate += examplesTemplate
+	cli.SubcommandHelpTemplate += examplesTemplate
+}
+
+// examples returns command metadata holding usage examples shown in --help
+func examples(lines ...string) map[string]interface{} {
+	return map[string]interface{}{"examples": lines}
+}
+
+// helpTemplatesDir, in the config directory, holds help templates replacing
+// the built-in ones, so a team distribution can add its own links and
+// conventions to --help:
+//
+//	root.tmpl          help of the CLI itself
+//	command.tmpl       help of commands
+//	subcommand.tmpl    help of commands with subcommands, such as submissions
+//	<command>.tmpl     help of one command, e.g. submissions-track.tmpl
+//	footer.tmpl        appended to every help page
+const helpTemplatesDir = "help"
+
+// helpTemplateFuncs are the functions available to help templates. Only
+// their names matter to validate a template; urfave/cli provides them.
+var helpTemplateFuncs = func() template.FuncMap {
+	funcs := template.FuncMap{}
+	for _, name := range []string{"join", "subtract", "indent", "nindent", "trim", "wrap", "offset", "offsetCommands"} {
+		funcs[name] = func(...interface{}) string { return "" }
+	}
+	return funcs
+}()
+
+// loadHelpTemplates applies the help templates found in the config
+// directory. A template that doesn't parse is skipped with a warning, since
+// a broken template would otherwise crash --help.
+func loadHelpTemplates(app *cli.Command) {
+	configDir, err := config.GetConfigDir()
+	if err != nil {
+		return
+	}
+	dir := filepath.Join(configDir, helpTemplatesDir)
+	if _, err := os.Stat(dir); err != nil {
+		return
+	}
+
+	read := func(name string) (string, bool) {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				ui.Warning("Failed to read help template %s: %v", name, err)
+			}
+			return "", false
+		}
+		if _, err := template.New(name).Funcs(helpTemplateFuncs).Parse(string(data)); err != nil {
+			ui.Warning("Ignoring help template %s: %v", name, err)
+			return "", false
+		}
+		return string(data), true
+	}
+
+	footer, _ := read("footer.tmpl")
+	override := func(target *string, name string) {
+		if tmpl, ok := read(name); ok {
+			*target = tmpl
+		}
+		*target += footer
+	}
+	override(&cli.RootCommandHelpTemplate, "root.tmpl")
+	override(&cli.CommandHelpTemplate, "command.tmpl")
+	override(&cli.SubcommandHelpTemplate, "subcommand.tmpl")
+
+	var walk func(commands []*cli.Command, prefix string)
+	walk = func(commands []*cli.Command, prefix string) {
+		for _, command := range commands {
+			name := prefix + command.Name
+			if tmpl, ok := read(name + ".tmpl"); ok {
+				command.CustomHelpTemplate = tmpl + footer
+			}
+			walk(command.Commands, name+"-")
+		}
+	}
+	walk(app.Commands, "")
+}
+
+// helpTopic is an extended help page
+type helpTopic struct {
+	Summary string
+	Body    string
+}
+
+// helpTopics are the pages available through help <topic>. Lines starting
+// with "# " are headings, lines starting with "$ " are example commands.
+var helpTopics = map[string]helpTopic{
+	"filters": {
+		Summary: "Narrowing down directories by category, pricing, link type and DR",
+		Body: `# Filter flags
+The filter and export commands accept the same filters. Flags that take
+several values can be repeated; a directory matches when it has any of them.
+
+  --category     Directory category (repeatable); Marketing/* also
+                 matches subcategories such as Marketing/SEO
+  --pricing      free, freemium or paid (repeatable)
+  --link-type    dofollow or nofollow (repeatable)
+  --dr-min       Minimum domain rating
+  --dr-max       Maximum domain rating
+  --query        Free text matched against name and description
+  --country      Country served, e.g. US, DE (repeatable)
+  --language     Listing language, e.g. en, fr (repeatable)
+  --audience     b2b or b2c
+  --max-price    Maximum listing fee
+  --currency     Currency of the listing fee, e.g. USD
+
+A misspelled category, pricing or link type is replaced by the value it
+most likely meant, with a notice, or the closest values are suggested.
+
+# Examples
+$ awesome-directories filter --category saas --pricing free
+$ awesome-directories filter --category "Marketing/*"
+$ awesome-directories filter --link-type dofollow --dr-min 50 --dr-max 80
+$ awesome-directories export -f csv -o free.csv --pricing free --dr-min 40
+
+# Presets
+Frequently used filters can be saved as presets in config.yaml:
+
+  presets:
+    high-dr:
+      dr_min: 70
+      link_type: [dofollow]
+
+$ awesome-directories watch --preset high-dr
+
+A preset can also be the default filters of a project (project_presets in
+config.yaml) or a collection (collection set <name> --preset), applied by
+list, search and plan with --project or --collection. --no-defaults skips
+them.
+`,
+	},
+	"sorting": {
+		Summary: "Ordering search, list and filter results",
+		Body: `# Sort orders
+search, list and filter accept --sort (-s):
+
+  helpful    Most helpful votes first (default)
+  dr         Highest domain rating first
+  newest     Most recently added first
+  alpha      Alphabetical by name
+
+# Examples
+$ awesome-directories list --sort dr --limit 20
+$ awesome-directories search "ai tools" --sort newest
+$ awesome-directories filter --pricing free --sort dr --format csv
+`,
+	},
+	"caching": {
+		Summary: "How the local directory cache works and how to refresh it",
+		Body: `# Local cache
+Directories are cached on disk so most commands work offline and respond
+instantly. The cache is refreshed automatically once it is older than the
+cache TTL (24h by default).
+
+  CACHE_DIR    Where the cache is stored (default: <config dir>/cache)
+  CACHE_TTL    How long the cache stays fresh, e.g. 6h
+
+Each endpoint can have its own TTL, in cache_ttls in config.yaml or with
+CACHE_TTL_DIRECTORIES, CACHE_TTL_FAVORITES and CACHE_TTL_ACCOUNT. The
+catalog follows CACHE_TTL unless given one; favorites and account data
+are fetched every time unless given one, and dropped when the CLI
+changes them.
+
+# Examples
+$ awesome-directories sync
+$ awesome-directories config show
+$ awesome-directories config clear-cache
+$ awesome-directories cache gc --dry-run
+
+# Snapshots
+Snapshots freeze the cached catalog so you can query or diff it later:
+
+$ awesome-directories snapshot create before-launch
+$ awesome-directories diff --from before-launch
+$ awesome-directories list --snapshot before-launch
+`,
+	},
+	"auth": {
+		Summary: "Logging in, tokens and session refresh",
+		Body: `# Logging in
+Favorites, submissions and account commands need an account.
+
+$ awesome-directories auth login
+$ awesome-directories auth login --email you@example.com
+$ awesome-directories auth signup --email you@example.com
+$ awesome-directories auth token <token>
+
+# Sessions
+Browser and password logins store a refresh token, so long-running commands
+such as watch renew the session before it expires. Tokens set with
+auth token or AUTH_TOKEN cannot be refreshed.
+
+$ awesome-directories auth whoami
+$ awesome-directories auth logout
+`,
+	},
+}
+
+// helpCommand creates the help command, extending the built-in command help
+// with topics
+func helpCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "help",
+		Aliases:   []string{"h"},
+		Usage:     "Show help for a command or topic (" + strings.Join(helpTopicNames(), ", ") + ")",
+		ArgsUsage: "[command|topic]",
+		HideHelp:  true,
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+			root := cmd.Root()
+
+			name := cmd.Args().First()
+			if name == "" {
+				if err := cli.ShowRootCommandHelp(root); err != nil {
+					return err
+				}
+				u.Println()
+				u.Bold("HELP TOPICS:")
+				for _, topic := range helpTopicNames() {
+					u.Printf("   %-10s %s\n", topic, helpTopics[topic].Summary)
+				}
+				return nil
+			}
+
+			if topic, ok := helpTopics[name]; ok {
+				u.Page(renderHelpTopic(name, topic))
+				return nil
+			}
+
+			if root.Command(name) == nil {
+				return fmt.Errorf("unknown command or help topic: %s (topics: %s)", name, strings.Join(helpTopicNames(), ", "))
+			}
+
+			return cli.ShowCommandHelp(ctx, root, name)
+		},
+	}
+}
+
+// helpTopicNames returns the sorted help topic names
+func helpTopicNames() []string {
+	names := make([]string, 0, len(helpTopics))
+	for name := range helpTopics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// renderHelpTopic formats a help topic with colored headings and examples
+func renderHelpTopic(name string, topic helpTopic) string {
+	var b strings.Builder
+
+	b.WriteString(ui.BoldColor.Sprint(strings.ToUpper(name)) + " - " + topic.Summary + "\n")
+
+	for _, line := range strings.Split(topic.Body, "\n") {
+		switch {
+		case strings.HasPrefix(line, "# "):
+			b.WriteString("\n" + ui.BoldColor.Sprint(strings.TrimPrefix(line, "# ")) + "\n")
+		case strings.HasPrefix(line, "$ "):
+			b.WriteString("  " + ui.InfoColor.Sprint(line) + "\n")
+		default:
+			b.WriteString(line + "\n")
+		}
+	}
+
+	return b.String()
+}
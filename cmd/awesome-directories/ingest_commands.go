@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/inbox"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// errNotConfirmation is returned for emails that don't announce a
+// submission status
+var errNotConfirmation = errors.New("not a recognizable submission confirmation")
+
+// ingestOptions controls how a confirmation email updates submissions
+type ingestOptions struct {
+	// project limits matching to one project; empty means the only
+	// project the directory is tracked in
+	project string
+
+	// attach keeps the email as evidence of the submission
+	attach bool
+
+	dryRun bool
+}
+
+// ingestResult is the submission a confirmation email was matched to
+type ingestResult struct {
+	directory  *models.Directory
+	submission *models.TrackedSubmission
+	oldStatus  string
+	status     string
+	updated    bool
+}
+
+// submissionIngestEmailCommand creates the submissions ingest-email command
+func submissionIngestEmailCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "ingest-email",
+		Usage:     "Update a submission from a confirmation email (.eml)",
+		ArgsUsage: "[file.eml]",
+		Metadata: examples(
+			"awesome-directories submissions ingest-email < confirmation.eml",
+			"awesome-directories submissions ingest-email ~/Downloads/approved.eml --attach",
+			"awesome-directories submissions ingest-email approved.eml --project acme --dry-run",
+		),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "project",
+				Usage: "Project of the submission (default: the only project tracking the directory)",
+			},
+			&cli.BoolFlag{
+				Name:  "attach",
+				Usage: "Keep the email as evidence of the submission",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what the email would change without saving",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			var input io.Reader = u.In
+			if cmd.Args().Len() > 0 {
+				file, err := os.Open(cmd.Args().First())
+				if err != nil {
+					return fmt.Errorf("failed to open email: %w", err)
+				}
+				defer func() {
+					if err := file.Close(); err != nil {
+						u.Warning("Failed to close email: %v", err)
+					}
+				}()
+				input = file
+			}
+
+			msg, err := inbox.Parse(input)
+			if err != nil {
+				return err
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+
+			result, err := ingestConfirmation(ctx, u, cfg, directories, msg, ingestOptions{
+				project: cmd.String("project"),
+				attach:  cmd.Bool("attach"),
+				dryRun:  dryRun(cmd),
+			})
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case !result.updated:
+				u.Info("%s is already %s in project %s; the email announces %s",
+					result.directory.Name, result.oldStatus, result.submission.Project, result.status)
+			case dryRun(cmd):
+				u.Info("Would mark %s as %s in project %s (was %s)",
+					result.directory.Name, result.status, result.submission.Project, result.oldStatus)
+			default:
+				u.Success("Marked %s as %s in project %s (was %s)",
+					result.directory.Name, result.status, result.submission.Project, result.oldStatus)
+				if result.submission.ListingURL != "" && result.status == "approved" {
+					u.Muted("Listing: %s", result.submission.ListingURL)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// ingestConfirmation matches a confirmation email to a tracked submission
+// and moves the submission to the status the email announces. Statuses only
+// move forward, so a late "we received your submission" doesn't undo an
+// approval.
+func ingestConfirmation(ctx context.Context, u *ui.UI, cfg *config.Config, directories []models.Directory, msg *inbox.Message, options ingestOptions) (*ingestResult, error) {
+	status := inbox.Status(msg)
+	if status == "" {
+		return nil, fmt.Errorf("%w: %q", errNotConfirmation, msg.Subject)
+	}
+
+	directory := inbox.MatchDirectory(msg, directories)
+	if directory == nil {
+		return nil, fmt.Errorf("no directory matches the email from %s: %q", msg.From, msg.Subject)
+	}
+
+	dataStore := store.New(cfg)
+	submission, err := confirmedSubmission(dataStore, directory.Slug, options.project)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ingestResult{
+		directory:  directory,
+		submission: submission,
+		oldStatus:  submission.Status,
+		status:     status,
+		updated:    models.Advances(submission.Status, status),
+	}
+	if !result.updated || options.dryRun {
+		return result, nil
+	}
+
+	date := msg.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	note := fmt.Sprintf("%s: confirmation email %q", date.Local().Format("2006-01-02"), msg.Subject)
+
+	var attached string
+	_, submission, err = updateSubmission(submission.Project, directory.Slug, false, func(submission *models.TrackedSubmission) error {
+		submission.Status = status
+		if submission.Notes != "" {
+			submission.Notes += "\n"
+		}
+		submission.Notes += note
+
+		if status == "approved" && submission.ListingURL == "" {
+			submission.ListingURL = inbox.ListingURL(msg, directory)
+		}
+
+		if options.attach {
+			name, err := dataStore.SaveEvidence(submission.Project, submission.Directory, emailFileName(msg), msg.Raw)
+			if err != nil {
+				return err
+			}
+			submission.Evidence = append(submission.Evidence, models.Evidence{File: name, AddedAt: time.Now().UTC()})
+			attached = name
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.submission = submission
+
+	detail := result.oldStatus + " → " + status
+	if attached != "" {
+		detail += " (attached " + attached + ")"
+	}
+	recordAudit(dataStore, "submissions.ingest-email", submission.Project+"/"+directory.Slug, detail)
+	notifyStatusChange(ctx, u, cfg, submission, result.oldStatus)
+
+	return result, nil
+}
+
+// confirmedSubmission returns the tracked submission to a directory that a
+// confirmation email is about: the one in project, or when project is
+// empty, the only one
+func confirmedSubmission(dataStore *store.Store, slug, project string) (*models.TrackedSubmission, error) {
+	if project != "" {
+		return trackedSubmission(dataStore, project, slug)
+	}
+
+	submissions, err := dataStore.Submissions()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []models.TrackedSubmission
+	var projects []string
+	for _, submission := range submissions {
+		if submission.Directory == slug {
+			matches = append(matches, submission)
+			projects = append(projects, submission.Project)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("%s is not tracked; use 'submissions track %s' first", slug, slug)
+	case 1:
+		return &matches[0], nil
+	default:
+		return nil, fmt.Errorf("%s is tracked in projects %s; choose one with --project", slug, strings.Join(projects, ", "))
+	}
+}
+
+// emailFileName names the evidence file of a confirmation email
+func emailFileName(msg *inbox.Message) string {
+	date := msg.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	return "confirmation-" + date.Format("20060102") + ".eml"
+}
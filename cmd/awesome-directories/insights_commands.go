@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// insightsOutput is the JSON representation of the insights command
+type insightsOutput struct {
+	Since      *time.Time   `json:"since,omitempty"`
+	Commands   []usageCount `json:"commands"`
+	Filters    []usageCount `json:"filters"`
+	Categories []usageCount `json:"categories"`
+
+	// PlanToSubmitted is how long submissions took from being tracked as
+	// pending to being submitted
+	PlanToSubmitted *leadTime `json:"plan_to_submitted,omitempty"`
+}
+
+// usageCount is how often something was used
+type usageCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// leadTime summarizes how long a number of submissions took
+type leadTime struct {
+	Submissions  int     `json:"submissions"`
+	AverageHours float64 `json:"average_hours"`
+	MedianHours  float64 `json:"median_hours"`
+}
+
+// insightsCommand creates the insights command
+func insightsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "insights",
+		Usage: "Summarize your own use of the CLI from the local audit and usage logs",
+		Metadata: examples(
+			"awesome-directories insights",
+			"awesome-directories insights --since 30d --top 10",
+			"awesome-directories insights --json",
+		),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "since",
+				Usage: "Only count activity newer than a duration (30m, 12h, 7d, 2w) or a date (YYYY-MM-DD)",
+			},
+			&cli.IntFlag{
+				Name:  "top",
+				Usage: "How many of the most used filters, commands and categories to show",
+				Value: 5,
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			var out insightsOutput
+			var since time.Time
+			if value := cmd.String("since"); value != "" {
+				if since, err = parseSince(value, time.Now()); err != nil {
+					return err
+				}
+				out.Since = &since
+			}
+
+			dataStore := store.New(cfg)
+			usage, err := dataStore.UsageLog(since)
+			if err != nil {
+				return err
+			}
+			audit, err := dataStore.AuditLog(time.Time{})
+			if err != nil {
+				return err
+			}
+
+			commands, filters := make(map[string]int), make(map[string]int)
+			for _, entry := range usage {
+				commands[entry.Command]++
+				for _, filter := range entry.Filters {
+					filters[filter]++
+				}
+			}
+			top := int(cmd.Int("top"))
+			out.Commands = topCounts(commands, top)
+			out.Filters = topCounts(filters, top)
+
+			submitted, durations := submissionTimeline(audit, since)
+			out.PlanToSubmitted = summarizeLeadTimes(durations)
+
+			if len(submitted) > 0 {
+				cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+				directories, err := loadDirectories(ctx, cmd, cacheClient)
+				if err != nil {
+					return err
+				}
+				categories := make(map[string]int)
+				for _, dir := range directories {
+					for _, category := range dir.Categories {
+						categories[category] += submitted[dir.Slug]
+					}
+				}
+				for category, n := range categories {
+					if n == 0 {
+						delete(categories, category)
+					}
+				}
+				out.Categories = topCounts(categories, top)
+			}
+
+			if cmd.Bool("json") {
+				return printJSON(u, out)
+			}
+
+			if out.Since != nil {
+				u.Bold("Insights since %s", out.Since.Local().Format("2006-01-02 15:04"))
+			} else {
+				u.Bold("Insights")
+			}
+
+			u.Println()
+			switch {
+			case len(usage) == 0 && !cfg.UsageLog:
+				u.Muted("Most used filters and commands need the usage log: set usage_log: true in the config file")
+			case len(usage) == 0:
+				u.Muted("No commands recorded yet")
+			default:
+				printUsageCounts(u, "Most used filters", out.Filters)
+				u.Println()
+				printUsageCounts(u, "Most run commands", out.Commands)
+			}
+
+			u.Println()
+			printUsageCounts(u, "Categories you submit to most", out.Categories)
+
+			u.Println()
+			u.Bold("From plan to submitted")
+			if lead := out.PlanToSubmitted; lead != nil {
+				u.Printf("  %s on average, %s median, over %d submissions\n",
+					formatLeadTime(lead.AverageHours), formatLeadTime(lead.MedianHours), lead.Submissions)
+			} else {
+				u.Printf("  No submission was tracked as pending and then submitted\n")
+			}
+
+			return nil
+		},
+	}
+}
+
+// submissionTimeline reads the submission changes of the audit log. It
+// returns how many submissions to each directory were submitted, or
+// approved straight away, since the given time, and how long those first
+// tracked as pending took to get there.
+func submissionTimeline(entries []store.AuditEntry, since time.Time) (map[string]int, []time.Duration) {
+	planned := make(map[string]time.Time)
+	done := make(map[string]bool)
+	submitted := make(map[string]int)
+	var durations []time.Duration
+
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Action, "submissions.") || entry.Target == "" {
+			continue
+		}
+		switch auditStatus(entry.Detail) {
+		case "pending":
+			if _, ok := planned[entry.Target]; !ok {
+				planned[entry.Target] = entry.Time
+			}
+		case "submitted", "approved":
+			if done[entry.Target] || entry.Time.Before(since) {
+				done[entry.Target] = true
+				continue
+			}
+			done[entry.Target] = true
+			_, slug, _ := strings.Cut(entry.Target, "/")
+			submitted[slug]++
+			if start, ok := planned[entry.Target]; ok {
+				durations = append(durations, entry.Time.Sub(start))
+			}
+		}
+	}
+	return submitted, durations
+}
+
+// auditStatus returns the status a submission change in the audit log moved
+// to, from details such as "pending", "pending → submitted" or
+// " → approved (attached confirmation.eml)"
+func auditStatus(detail string) string {
+	if _, after, ok := strings.Cut(detail, "→"); ok {
+		detail = after
+	}
+	fields := strings.Fields(detail)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.ToLower(fields[0])
+}
+
+// summarizeLeadTimes returns the average and median of durations, or nil
+// when there are none
+func summarizeLeadTimes(durations []time.Duration) *leadTime {
+	if len(durations) == 0 {
+		return nil
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	median := durations[len(durations)/2]
+	if len(durations)%2 == 0 {
+		median = (durations[len(durations)/2-1] + median) / 2
+	}
+	return &leadTime{
+		Submissions:  len(durations),
+		AverageHours: (total / time.Duration(len(durations))).Hours(),
+		MedianHours:  median.Hours(),
+	}
+}
+
+// topCounts returns the n most frequent names, most frequent first and ties
+// by name
+func topCounts(counts map[string]int, n int) []usageCount {
+	sorted := make([]usageCount, 0, len(counts))
+	for name, count := range counts {
+		sorted = append(sorted, usageCount{Name: name, Count: count})
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// printUsageCounts prints a titled list of counts
+func printUsageCounts(u *ui.UI, title string, counts []usageCount) {
+	u.Bold("%s", title)
+	if len(counts) == 0 {
+		u.Printf("  none yet\n")
+		return
+	}
+
+	width := 0
+	for _, c := range counts {
+		width = max(width, len(c.Name))
+	}
+	for _, c := range counts {
+		u.Printf("  %-*s  %d\n", width, c.Name, c.Count)
+	}
+}
+
+// formatLeadTime formats a number of hours as hours under two days, or days
+func formatLeadTime(hours float64) string {
+	if hours < 1 {
+		return "under an hour"
+	}
+	if hours < 48 {
+		return fmt.Sprintf("%.0fh", hours)
+	}
+	return fmt.Sprintf("%.1f days", hours/24)
+}
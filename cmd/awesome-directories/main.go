@@ -5,7 +5,10 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
 	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/ui"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/urfave/cli/v3"
@@ -20,12 +23,24 @@ var (
 )
 
 func main() {
+	defer recoverPanic()
+
+	// Run the app
+	if err := newApp().Run(context.Background(), os.Args); err != nil {
+		log.Error().Err(err).Msg("Command failed")
+		os.Exit(1)
+	}
+}
+
+// newApp creates the root command
+func newApp() *cli.Command {
 	app := &cli.Command{
 		Name:                  "awesome-directories",
 		Usage:                 "CLI tool for awesome-directories.com - Discover directories for your SaaS",
 		Version:               fmt.Sprintf("%s (commit: %s, built: %s by %s)", version, commit, date, builtBy),
 		EnableShellCompletion: true,
 		Suggest:               true,
+		HideHelpCommand:       true,
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:    "debug",
@@ -36,36 +51,156 @@ func main() {
 				Name:  "no-color",
 				Usage: "Disable colored output",
 			},
+			&cli.BoolFlag{
+				Name:  "timings",
+				Usage: "Print how long the command took and how many results it produced",
+			},
+			&cli.StringFlag{
+				Name:  "cache-dir",
+				Usage: "Directory holding the directory cache and snapshots",
+			},
+			&cli.StringFlag{
+				Name:  "data-dir",
+				Usage: "Directory holding submissions, audit log and other user data",
+			},
+			&cli.StringFlag{
+				Name:  "state-dir",
+				Usage: "Shared state directory holding submissions and product profiles",
+			},
+			&cli.BoolFlag{
+				Name:  "pure",
+				Usage: "Ignore environment variables and the home directory; config and cache live in --data-dir",
+			},
+			&cli.BoolFlag{
+				Name:  "strict",
+				Usage: "Fail when API responses have unknown fields, values of the wrong type or missing fields",
+			},
+			&cli.StringFlag{
+				Name:  "record",
+				Usage: "Save API requests and responses, without credentials, to a HAR `FILE` for a bug report",
+			},
+			&cli.StringFlag{
+				Name:  "replay",
+				Usage: "Answer API requests from a HAR `FILE` saved with --record instead of the network",
+			},
+			&cli.BoolFlag{
+				Name:  "low-bandwidth",
+				Usage: "Sync only what changed, skip unused columns, logos and feeds, and keep caches longer, for metered or slow connections",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what commands changing favorites, submissions, collections, products or config would do without doing it",
+			},
 		},
 		Commands: []*cli.Command{
 			searchCommand(),
 			listCommand(),
 			filterCommand(),
+			categoriesCommand(),
 			showCommand(),
+			compareCommand(),
 			exportCommand(),
+			planCommand(),
+			calendarCommand(),
+			discoverSubmitCommand(),
+			analyzeCommand(),
+			screenshotCommand(),
+			sampleCommand(),
 			syncCommand(),
+			watchCommand(),
+			subscribeCommand(),
+			dashboardCommand(),
+			snapshotCommand(),
+			diffCommand(),
 			authCommand(),
+			accountCommand(),
 			favoritesCommand(),
+			collectionCommand(),
 			submissionsCommand(),
+			reportCommand(),
+			productCommand(),
+			stateCommand(),
+			assistCommand(),
+			tourCommand(),
+			widgetCommand(),
+			cacheCommand(),
 			configCommand(),
+			assertCommand(),
+			auditCommand(),
+			insightsCommand(),
+			undoCommand(),
+			migrateCommand(),
+			selftestCommand(),
+			versionCommand(),
+			helpCommand(),
 		},
 		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			err := config.SetOverrides(config.Overrides{
+				CacheDir: c.String("cache-dir"),
+				DataDir:  c.String("data-dir"),
+				StateDir: c.String("state-dir"),
+				Pure:     c.Bool("pure"),
+
+				LowBandwidth: c.Bool("low-bandwidth"),
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if c.Bool("strict") {
+				api.SetStrict(true)
+			}
+			if err := setupSession(c.String("record"), c.String("replay")); err != nil {
+				return nil, err
+			}
+
 			cfg, err := config.Load()
 			if err != nil {
 				return nil, fmt.Errorf("failed to load configuration: %w", err)
 			}
 
 			setupLogging(cfg)
+			cache.SetCategoryAliases(cfg.CategoryAliases)
+
+			// Commands run by selftest bring a UI capturing their output
+			u := ui.FromContext(ctx)
+			if u == ui.Default() {
+				u = ui.New(os.Stdin, os.Stdout, os.Stderr)
+				ui.SetDefault(u)
+			}
+
+			warnDeprecatedFlags(u, os.Args[1:])
+			if c.Args().First() != "migrate" {
+				warnPendingMigrations(u)
+			}
 
-			return ctx, nil
+			return ui.WithContext(ctx, u), nil
 		},
 	}
 
-	// Run the app
-	if err := app.Run(context.Background(), os.Args); err != nil {
-		log.Error().Err(err).Msg("Command failed")
-		os.Exit(1)
+	addRenamedCommands(app)
+	// The last wrapper runs first: read-only mode checks flags set by the
+	// config defaults too
+	withReadOnly(app)
+	withFlagDefaults(app)
+	wrapActions(app)
+	loadHelpTemplates(app)
+
+	return app
+}
+
+// setupSession records or replays the API session. Without either flag the
+// current session is kept, so commands run by the tour keep recording.
+func setupSession(record, replay string) error {
+	switch {
+	case record != "" && replay != "":
+		return fmt.Errorf("--record and --replay cannot be used together")
+	case record != "":
+		return api.Record(record, version)
+	case replay != "":
+		return api.Replay(replay)
 	}
+	return nil
 }
 
 func setupLogging(cfg *config.Config) {
@@ -83,7 +218,12 @@ func setupLogging(cfg *config.Config) {
 		},
 	}
 
-	log.Logger = zerolog.New(output).With().Timestamp().Logger()
+	// Keep a plain copy of recent log lines for crash reports
+	buffered := output
+	buffered.Out = logBuffer
+	buffered.NoColor = true
+
+	log.Logger = zerolog.New(zerolog.MultiLevelWriter(output, buffered)).With().Timestamp().Logger()
 
 	// Set log level
 	level := zerolog.InfoLevel
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/export"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// telemetryTimeout bounds how long reporting may delay the exit
+const telemetryTimeout = 2 * time.Second
+
+type statsKey struct{}
+
+// commandStats collects facts about a command run for the middleware
+type commandStats struct {
+	results    int
+	hasResults bool
+
+	// provenance is where the catalog the command read came from; listed
+	// is set once it rendered directories as a table
+	provenance *cache.Provenance
+	listed     bool
+}
+
+// recordResults records the number of results produced by a command
+func recordResults(ctx context.Context, n int) {
+	if stats, ok := ctx.Value(statsKey{}).(*commandStats); ok {
+		stats.results = n
+		stats.hasResults = true
+	}
+}
+
+// recordProvenance records where the catalog a command read came from
+func recordProvenance(ctx context.Context, provenance *cache.Provenance) {
+	if stats, ok := ctx.Value(statsKey{}).(*commandStats); ok {
+		stats.provenance = provenance
+	}
+}
+
+// recordedProvenance returns where the catalog a command read came from
+// when provenance is enabled, or nil
+func recordedProvenance(ctx context.Context) *cache.Provenance {
+	stats, ok := ctx.Value(statsKey{}).(*commandStats)
+	if !ok || stats.provenance == nil {
+		return nil
+	}
+	if cfg, err := config.Load(); err != nil || !cfg.Provenance {
+		return nil
+	}
+	return stats.provenance
+}
+
+// recordListing records that a command listed directories as a table
+func recordListing(ctx context.Context) {
+	if stats, ok := ctx.Value(statsKey{}).(*commandStats); ok {
+		stats.listed = true
+	}
+}
+
+// provenanceBanner describes where listed data came from and how old it is
+func provenanceBanner(provenance *cache.Provenance) string {
+	age := ui.FormatAge(time.Since(provenance.SyncedAt))
+	switch provenance.Source {
+	case cache.SourceAPI:
+		return "Data from the API, fetched " + age
+	case cache.SourceStaleCache:
+		return "Data from a stale cache, synced " + age + "; the API could not be reached"
+	case cache.SourceSnapshot:
+		return "Data from snapshot " + provenance.Snapshot + ", taken " + age
+	default:
+		return "Data from cache, synced " + age
+	}
+}
+
+// wrapActions wraps the action of cmd and all its subcommands with
+// instrumentAction
+func wrapActions(cmd *cli.Command) {
+	if cmd.Action != nil {
+		cmd.Action = instrumentAction(cmd.Action)
+	}
+	for _, sub := range cmd.Commands {
+		wrapActions(sub)
+	}
+}
+
+// instrumentAction records the duration, exit status and error class of an
+// action, logs them, reports them when telemetry is enabled and prints a
+// footer with --timings
+func instrumentAction(action cli.ActionFunc) cli.ActionFunc {
+	return func(ctx context.Context, cmd *cli.Command) error {
+		stats := &commandStats{}
+		start := time.Now()
+
+		actionCtx := context.WithValue(ctx, statsKey{}, stats)
+		err := action(actionCtx, cmd)
+
+		if err == nil && stats.listed {
+			if provenance := recordedProvenance(actionCtx); provenance != nil {
+				ui.FromContext(ctx).Muted("%s", provenanceBanner(provenance))
+			}
+		}
+
+		duration := time.Since(start)
+		exitStatus := 0
+		if err != nil {
+			exitStatus = 1
+		}
+		errorClass := classifyError(err)
+
+		log.Debug().
+			Str("event", "command_completed").
+			Str("command", cmd.FullName()).
+			Dur("duration", duration).
+			Int("exit_status", exitStatus).
+			Str("error_class", errorClass).
+			Msg("Command completed")
+
+		if cmd.Root().Bool("timings") {
+			precision := 100 * time.Millisecond
+			if duration < time.Second {
+				precision = time.Millisecond
+			}
+			footer := fmt.Sprintf("completed in %s", duration.Round(precision))
+			if stats.hasResults {
+				footer += fmt.Sprintf(", %d results", stats.results)
+			}
+			fmt.Fprintln(ui.FromContext(ctx).Err, footer)
+		}
+
+		logUsage(cmd)
+
+		sendCommandEvent(cmd, models.CommandEvent{
+			Command:    cmd.FullName(),
+			DurationMS: duration.Milliseconds(),
+			ExitStatus: exitStatus,
+			ErrorClass: errorClass,
+			Version:    version,
+			OS:         runtime.GOOS,
+			Arch:       runtime.GOARCH,
+		})
+
+		return err
+	}
+}
+
+// usageFilterFlags are the flags the usage log records the values of.
+// Free-text flags such as queries are left out.
+var usageFilterFlags = []string{
+	"category", "pricing", "link-type", "dr-min", "dr-max", "sort", "collection",
+	"country", "language", "audience", "currency", "max-price",
+}
+
+// logUsage appends the command and the filters it used to the local usage
+// log when it is enabled
+func logUsage(cmd *cli.Command) {
+	cfg, err := config.Load()
+	if err != nil || !cfg.UsageLog {
+		return
+	}
+
+	entry := store.UsageEntry{Command: strings.TrimPrefix(cmd.FullName(), cmd.Root().Name+" ")}
+	for _, name := range usageFilterFlags {
+		if !cmd.IsSet(name) {
+			continue
+		}
+		value := fmt.Sprint(cmd.Value(name))
+		if values, ok := cmd.Value(name).([]string); ok {
+			value = strings.Join(values, ",")
+		}
+		entry.Filters = append(entry.Filters, name+"="+value)
+	}
+
+	if err := store.New(cfg).LogUsage(entry); err != nil {
+		log.Debug().Err(err).Msg("Failed to record usage")
+	}
+}
+
+// sendCommandEvent sends a command event when telemetry is enabled
+func sendCommandEvent(cmd *cli.Command, event models.CommandEvent) {
+	cfg, err := config.Load()
+	if err != nil || !cfg.Telemetry {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), telemetryTimeout)
+	defer cancel()
+
+	if err := api.NewClient(cfg).ReportEvent(ctx, event); err != nil {
+		log.Debug().Err(err).Msg("Failed to report telemetry")
+	}
+}
+
+// classifyError returns a coarse, anonymous class for an error
+func classifyError(err error) string {
+	var netErr net.Error
+	var pathErr *os.PathError
+
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "canceled"
+	case errors.As(err, &netErr):
+		return "network"
+	case errors.Is(err, export.ErrOutputExists):
+		return "usage"
+	case errors.As(err, &pathErr):
+		return "filesystem"
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "unauthorized"), strings.Contains(msg, "authentication required"):
+		return "auth"
+	case strings.Contains(msg, "api error"):
+		return "api"
+	case strings.Contains(msg, "config"):
+		return "config"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,423 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/dates"
+	"github.com/awesome-directories/cli/internal/plan"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// planCommand creates the plan command
+func planCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "plan",
+		Usage: "Pick the directories to submit to, optimizing score within a budget",
+		Metadata: examples(
+			"awesome-directories plan --max-count 30",
+			"awesome-directories plan --budget 200 --currency USD --max-count 30",
+			"awesome-directories plan --budget 100 --score dr=1,helpful=0.5 --category saas",
+			"awesome-directories plan --preset high-dr --format csv > plan.csv",
+			"awesome-directories plan --max-count 20 --max-difficulty medium",
+			"awesome-directories plan --max-count 40 --schedule --per-day 3 --start \"next monday\"",
+			"awesome-directories plan --max-count 40 --project acme --format json > schedule.json",
+		),
+		Flags: append([]cli.Flag{
+			&cli.FloatFlag{
+				Name:  "budget",
+				Usage: "Maximum total listing fees",
+			},
+			&cli.IntFlag{
+				Name:  "max-count",
+				Usage: "Maximum number of directories in the plan",
+			},
+			&cli.StringFlag{
+				Name:  "score",
+				Usage: "Metric to maximize: " + strings.Join(plan.ScoreMetrics(), ", ") + ", or a weighted sum such as dr=1,helpful=0.5",
+				Value: "dr",
+			},
+			&cli.StringFlag{
+				Name:  "max-difficulty",
+				Usage: "Skip analyzed directories with a harder submission form: " + strings.Join(plan.Difficulties, ", "),
+			},
+			&cli.BoolFlag{
+				Name:  "ignore-difficulty",
+				Usage: "Don't prefer directories with an easier submission form",
+			},
+			&cli.BoolFlag{
+				Name:  "schedule",
+				Usage: "Spread the plan over dated days following the pacing rules (default when the project has pacing rules in config.yaml)",
+			},
+			&cli.StringFlag{
+				Name:  "start",
+				Usage: "First day of the schedule: YYYY-MM-DD, or relative such as \"next monday\" or \"in 3 business days\" (default: today)",
+			},
+			&cli.IntFlag{
+				Name:  "per-day",
+				Usage: "Schedule at most this many submissions a day",
+			},
+			&cli.IntFlag{
+				Name:  "per-week",
+				Usage: "Schedule at most this many submissions a week",
+			},
+			&cli.IntFlag{
+				Name:  "category-per-week",
+				Usage: "Schedule at most this many submissions a week to directories sharing a category",
+			},
+			&cli.BoolFlag{
+				Name:  "skip-weekends",
+				Usage: "Schedule nothing on Saturdays and Sundays",
+			},
+			&cli.StringFlag{
+				Name:  "preset",
+				Usage: "Start from a preset from config.yaml",
+			},
+			&cli.StringSliceFlag{
+				Name:    "category",
+				Aliases: []string{"c"},
+				Usage:   "Filter by category (can be specified multiple times)",
+			},
+			&cli.StringSliceFlag{
+				Name:    "pricing",
+				Aliases: []string{"p"},
+				Usage:   "Filter by pricing: free, paid, freemium",
+			},
+			&cli.StringSliceFlag{
+				Name:  "link-type",
+				Usage: "Filter by link type: dofollow, nofollow",
+			},
+			&cli.IntFlag{
+				Name:  "dr-min",
+				Usage: "Minimum domain rating",
+			},
+			snapshotFlag(),
+		}, append(append(filterMetadataFlags(), scopeFlags("Project whose pacing rules and default filters apply")...), formatFlags()...)...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			if cmd.Float("budget") < 0 || cmd.Int("max-count") < 0 {
+				return fmt.Errorf("budget and max-count must not be negative")
+			}
+
+			score, err := plan.ParseScore(cmd.String("score"))
+			if err != nil {
+				return err
+			}
+
+			pacing, scheduled, err := planPacing(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			start := time.Now()
+			if value := cmd.String("start"); value != "" {
+				if start, err = dates.Parse(value, start); err != nil {
+					return fmt.Errorf("invalid --start: %w", err)
+				}
+			}
+
+			options := &models.FilterOptions{}
+			if name := cmd.String("preset"); name != "" {
+				preset, err := cfg.Preset(name)
+				if err != nil {
+					return err
+				}
+				options = preset.FilterOptions()
+			}
+			if cmd.IsSet("category") {
+				options.Categories = cmd.StringSlice("category")
+			}
+			if cmd.IsSet("pricing") {
+				options.Pricing = cmd.StringSlice("pricing")
+			}
+			if cmd.IsSet("link-type") {
+				options.LinkType = cmd.StringSlice("link-type")
+			}
+			if cmd.IsSet("dr-min") {
+				options.DRMin = cmd.Int("dr-min")
+			}
+			if err := applyFilterMetadata(ctx, cmd, cfg, options); err != nil {
+				return err
+			}
+
+			apiClient := api.NewClient(cfg)
+			cacheClient := cache.NewCache(cfg, apiClient)
+
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+
+			if directories, err = applyScope(ctx, cmd, cfg, directories, options); err != nil {
+				return err
+			}
+
+			candidates := cacheClient.FilterDirectories(directories, options)
+
+			// Ratings from 'awesome-directories analyze'
+			dataStore := store.New(cfg)
+			difficulties, err := dataStore.Difficulties()
+			if err != nil {
+				return err
+			}
+			if maxDifficulty := cmd.String("max-difficulty"); maxDifficulty != "" {
+				if candidates, err = filterDifficulty(candidates, difficulties, maxDifficulty); err != nil {
+					return err
+				}
+			}
+			if !cmd.Bool("ignore-difficulty") {
+				score = plan.WithDifficulty(score, difficulties)
+			}
+
+			currency := options.Currency
+			if cmd.Float("budget") > 0 && currency == "" {
+				if currency, err = budgetCurrency(candidates); err != nil {
+					return err
+				}
+			}
+
+			p := plan.Build(candidates, score, cmd.Float("budget"), cmd.Int("max-count"), currency)
+			recordResults(ctx, len(p.Items))
+
+			if scheduled {
+				return displaySchedule(ctx, u, cmd, p, plan.Schedule(p.Items, pacing, start), difficulties, len(candidates))
+			}
+
+			selected := make([]models.Directory, len(p.Items))
+			for i, item := range p.Items {
+				selected[i] = item.Directory
+			}
+
+			if !isTableFormat(cmd) {
+				return renderDirectories(ctx, u, cmd, selected)
+			}
+
+			if len(p.Items) == 0 {
+				u.Warning("No directories fit the plan")
+				return nil
+			}
+
+			table := u.CreateTable([]string{"#", "Name", "DR", "Pricing", "Price", "Difficulty", "Score"})
+			for i, item := range p.Items {
+				table.Row(
+					strconv.Itoa(i+1),
+					ui.TruncateString(item.Directory.Name, 40),
+					ui.FormatDR(&item.Directory.DomainRating),
+					ui.FormatPricing(item.Directory.Pricing),
+					ui.FormatPrice(item.Directory.PriceAmount, item.Directory.PriceCurrency),
+					valueOrDash(difficulties[item.Directory.Slug]),
+					strconv.FormatFloat(item.Score, 'f', -1, 64),
+				)
+			}
+			u.Println(table)
+
+			displayPlanSummary(u, p, len(candidates), cmd.Float("budget"))
+
+			return nil
+		},
+	}
+}
+
+// planPacing returns the pacing rules of the plan: those of the project in
+// config.yaml, overridden by flags, and whether the plan is scheduled at all
+func planPacing(cmd *cli.Command, cfg *config.Config) (plan.Pacing, bool, error) {
+	configured, ruled := cfg.ProjectPacing(cmd.String("project"))
+	pacing := configured.PlanPacing()
+
+	for name, limit := range map[string]*int{
+		"per-day":           &pacing.PerDay,
+		"per-week":          &pacing.PerWeek,
+		"category-per-week": &pacing.CategoryPerWeek,
+	} {
+		if !cmd.IsSet(name) {
+			continue
+		}
+		if cmd.Int(name) < 0 {
+			return pacing, false, fmt.Errorf("--%s must not be negative", name)
+		}
+		*limit = cmd.Int(name)
+		ruled = true
+	}
+	if cmd.IsSet("skip-weekends") {
+		pacing.SkipWeekends = cmd.Bool("skip-weekends")
+		ruled = true
+	}
+
+	scheduled := ruled
+	if cmd.IsSet("schedule") {
+		scheduled = cmd.Bool("schedule")
+	}
+	if !ruled {
+		pacing = plan.DefaultPacing
+	}
+	return pacing, scheduled, nil
+}
+
+// scheduleEntry is a scheduled submission in JSON output
+type scheduleEntry struct {
+	Date       string   `json:"date"`
+	Slug       string   `json:"slug"`
+	Name       string   `json:"name"`
+	Categories []string `json:"categories"`
+	Score      float64  `json:"score"`
+	Cost       float64  `json:"cost"`
+}
+
+// displaySchedule prints a plan as a dated schedule. JSON output carries the
+// dates; other formats list the directories in schedule order.
+func displaySchedule(ctx context.Context, u *ui.UI, cmd *cli.Command, p *plan.Plan, schedule []plan.Scheduled, difficulties map[string]string, candidates int) error {
+	if strings.EqualFold(cmd.String("format"), "json") {
+		entries := make([]scheduleEntry, len(schedule))
+		for i, item := range schedule {
+			entries[i] = scheduleEntry{
+				Date:       item.Date.Format("2006-01-02"),
+				Slug:       item.Directory.Slug,
+				Name:       item.Directory.Name,
+				Categories: item.Directory.Categories,
+				Score:      item.Score,
+				Cost:       item.Cost,
+			}
+		}
+		return printJSON(u, entries)
+	}
+
+	if !isTableFormat(cmd) {
+		selected := make([]models.Directory, len(schedule))
+		for i, item := range schedule {
+			selected[i] = item.Directory
+		}
+		return renderDirectories(ctx, u, cmd, selected)
+	}
+
+	if len(schedule) == 0 {
+		u.Warning("No directories fit the plan")
+		return nil
+	}
+
+	table := u.CreateTable([]string{"Date", "Name", "Categories", "DR", "Price", "Difficulty", "Score"})
+	for _, item := range schedule {
+		table.Row(
+			item.Date.Format("Mon 2006-01-02"),
+			ui.TruncateString(item.Directory.Name, 40),
+			ui.TruncateString(strings.Join(item.Directory.Categories, ", "), 30),
+			ui.FormatDR(&item.Directory.DomainRating),
+			ui.FormatPrice(item.Directory.PriceAmount, item.Directory.PriceCurrency),
+			valueOrDash(difficulties[item.Directory.Slug]),
+			strconv.FormatFloat(item.Score, 'f', -1, 64),
+		)
+	}
+	u.Println(table)
+
+	displayPlanSummary(u, p, candidates, cmd.Float("budget"))
+
+	first, last := schedule[0].Date, schedule[len(schedule)-1].Date
+	u.Info("Scheduled from %s to %s (%d days)",
+		first.Format("2006-01-02"), last.Format("2006-01-02"), int(math.Round(last.Sub(first).Hours()/24))+1)
+	return nil
+}
+
+// displayPlanSummary prints the size, score and cost of a plan
+func displayPlanSummary(u *ui.UI, p *plan.Plan, candidates int, budget float64) {
+	u.Info("Selected %d of %d directories, total score %s",
+		len(p.Items), candidates, strconv.FormatFloat(p.Score, 'f', -1, 64))
+
+	currencies := make(map[string]float64)
+	unknown := 0
+	for _, item := range p.Items {
+		cost, known := plan.Cost(item.Directory)
+		switch {
+		case !known:
+			unknown++
+		case cost > 0:
+			currencies[strings.ToUpper(item.Directory.PriceCurrency)] += cost
+		}
+	}
+
+	if len(currencies) == 0 && unknown == 0 {
+		u.Success("This plan has no listing fees")
+		return
+	}
+
+	var totals []string
+	for currency, total := range currencies {
+		totals = append(totals, ui.FormatPrice(total, currency))
+	}
+	sort.Strings(totals)
+	line := fmt.Sprintf("This plan costs ~%s in listing fees", strings.Join(totals, " + "))
+	if budget > 0 {
+		line += fmt.Sprintf(" (budget %s)", ui.FormatPrice(budget, p.Currency))
+	}
+	u.Info("%s", line)
+
+	if unknown > 0 {
+		u.Warning("%d paid directories have no known price and are not included in the total", unknown)
+	}
+}
+
+// filterDifficulty drops the directories rated harder than maxDifficulty.
+// Directories that were not analyzed, or could not be rated, are kept.
+func filterDifficulty(directories []models.Directory, difficulties map[string]string, maxDifficulty string) ([]models.Directory, error) {
+	rank := func(difficulty string) int {
+		for i, d := range plan.Difficulties {
+			if d == difficulty {
+				return i
+			}
+		}
+		return -1
+	}
+
+	limit := rank(strings.ToLower(maxDifficulty))
+	if limit < 0 {
+		return nil, fmt.Errorf("invalid difficulty: %s (use %s)", maxDifficulty, strings.Join(plan.Difficulties, ", "))
+	}
+
+	var kept []models.Directory
+	for _, dir := range directories {
+		if rank(difficulties[dir.Slug]) <= limit {
+			kept = append(kept, dir)
+		}
+	}
+	return kept, nil
+}
+
+// budgetCurrency returns the currency the candidates are priced in, so a
+// budget without --currency is unambiguous
+func budgetCurrency(candidates []models.Directory) (string, error) {
+	seen := make(map[string]bool)
+	for _, dir := range candidates {
+		if dir.PriceAmount > 0 {
+			seen[strings.ToUpper(dir.PriceCurrency)] = true
+		}
+	}
+
+	if len(seen) > 1 {
+		currencies := make([]string, 0, len(seen))
+		for currency := range seen {
+			currencies = append(currencies, currency)
+		}
+		sort.Strings(currencies)
+		return "", fmt.Errorf("directories are priced in %s; set the budget currency with --currency", strings.Join(currencies, ", "))
+	}
+
+	for currency := range seen {
+		return currency, nil
+	}
+	return "", nil
+}
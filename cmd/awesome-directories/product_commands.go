@@ -0,0 +1,403 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// productFieldFlags returns the flags setting product profile fields
+func productFieldFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:  "name",
+			Usage: "Product name",
+		},
+		&cli.StringFlag{
+			Name:  "url",
+			Usage: "Product website",
+		},
+		&cli.StringFlag{
+			Name:  "tagline",
+			Usage: "Short tagline",
+		},
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "Long description",
+		},
+		&cli.StringSliceFlag{
+			Name:  "category",
+			Usage: "Product category (can be specified multiple times)",
+		},
+		&cli.StringFlag{
+			Name:  "email",
+			Usage: "Contact email used in submissions",
+		},
+		&cli.StringFlag{
+			Name:  "logo",
+			Usage: "Path or URL of the product logo",
+		},
+	}
+}
+
+// productCommand creates the product command
+func productCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "product",
+		Usage: "Manage the product profiles you submit to directories",
+		Commands: []*cli.Command{
+			{
+				Name:      "create",
+				Usage:     "Create a product profile",
+				ArgsUsage: "<slug>",
+				Metadata: examples(
+					`awesome-directories product create acme --name "Acme" --url https://acme.dev --tagline "Ship faster"`,
+				),
+				Flags: productFieldFlags(),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("product slug is required")
+					}
+					if cmd.String("name") == "" || cmd.String("url") == "" {
+						return fmt.Errorf("--name and --url are required")
+					}
+
+					productStore, err := openStore()
+					if err != nil {
+						return err
+					}
+
+					slug := cmd.Args().First()
+					err = productStore.Transaction(func() error {
+						if _, err := productStore.Product(slug); err == nil {
+							return fmt.Errorf("product already exists: %s (use 'product set' to update it)", slug)
+						}
+
+						product := &models.Product{Slug: slug}
+						applyProductFields(cmd, product)
+						if dryRun(cmd) {
+							return nil
+						}
+						return productStore.SaveProduct(product)
+					})
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would create product %s", slug)
+						return nil
+					}
+
+					recordAudit(productStore, "product.create", slug, "")
+					u.Success("Created product %s", slug)
+					return nil
+				},
+			},
+			{
+				Name:      "set",
+				Usage:     "Update a product profile or one of its copy variants",
+				ArgsUsage: "<slug>",
+				Metadata: examples(
+					`awesome-directories product set acme --tagline "Ship twice as fast"`,
+					`awesome-directories product set acme --variant b --tagline "The deploy button for teams"`,
+				),
+				Flags: append([]cli.Flag{
+					&cli.StringFlag{
+						Name:  "variant",
+						Usage: "Copy variant to update, e.g. b (only --tagline and --description apply)",
+					},
+				}, productFieldFlags()...),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("product slug is required")
+					}
+
+					productStore, err := openStore()
+					if err != nil {
+						return err
+					}
+
+					variant := strings.ToLower(cmd.String("variant"))
+					var product *models.Product
+					err = productStore.Transaction(func() error {
+						product, err = productStore.Product(cmd.Args().First())
+						if err != nil {
+							return err
+						}
+
+						if variant == "" || variant == models.DefaultVariant {
+							applyProductFields(cmd, product)
+						} else {
+							for _, name := range []string{"name", "url", "category", "email", "logo"} {
+								if cmd.IsSet(name) {
+									return fmt.Errorf("--%s cannot be set per variant", name)
+								}
+							}
+
+							if product.Variants == nil {
+								product.Variants = make(map[string]models.ProductCopy)
+							}
+							variantCopy := product.Variants[variant]
+							if cmd.IsSet("tagline") {
+								variantCopy.Tagline = cmd.String("tagline")
+							}
+							if cmd.IsSet("description") {
+								variantCopy.Description = cmd.String("description")
+							}
+							product.Variants[variant] = variantCopy
+						}
+
+						if dryRun(cmd) {
+							return nil
+						}
+						return productStore.SaveProduct(product)
+					})
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would update product %s (%s)", product.Slug, changedFlags(cmd))
+						return nil
+					}
+
+					recordAudit(productStore, "product.set", product.Slug, changedFlags(cmd))
+					if variant != "" && variant != models.DefaultVariant {
+						u.Success("Updated variant %s of %s", variant, product.Slug)
+					} else {
+						u.Success("Updated product %s", product.Slug)
+					}
+					return nil
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "List product profiles",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					productStore, err := openStore()
+					if err != nil {
+						return err
+					}
+
+					products, err := productStore.Products()
+					if err != nil {
+						return err
+					}
+
+					if len(products) == 0 {
+						u.Warning("No products yet. Use 'product create <slug>' to add one.")
+						return nil
+					}
+
+					table := u.CreateTable([]string{"Slug", "Name", "URL", "Variants"})
+					for _, product := range products {
+						table.Row(product.Slug, product.Name, product.URL, strings.Join(product.VariantNames(), ", "))
+					}
+					u.Println(table)
+
+					return nil
+				},
+			},
+			{
+				Name:      "show",
+				Usage:     "Show a product profile and its copy variants",
+				ArgsUsage: "<slug>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("product slug is required")
+					}
+
+					productStore, err := openStore()
+					if err != nil {
+						return err
+					}
+
+					product, err := productStore.Product(cmd.Args().First())
+					if err != nil {
+						return err
+					}
+
+					u.Bold("=== %s ===\n", product.Name)
+					u.Printf("URL: %s\n", product.URL)
+					u.Printf("Slug: %s\n", product.Slug)
+					if len(product.Categories) > 0 {
+						u.Printf("Categories: %s\n", strings.Join(product.Categories, ", "))
+					}
+					if product.ContactEmail != "" {
+						u.Printf("Contact: %s\n", product.ContactEmail)
+					}
+					if product.Logo != "" {
+						u.Printf("Logo: %s\n", product.Logo)
+					}
+
+					for _, name := range product.VariantNames() {
+						variant, _ := product.WithVariant(name)
+						u.Println()
+						u.Bold("Variant %s:", name)
+						u.Printf("  Tagline: %s\n", variant.Tagline)
+						u.Printf("  Description: %s\n", variant.Description)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:      "export",
+				Usage:     "Print a product profile ready to paste into submission forms",
+				ArgsUsage: "<slug>",
+				Metadata: examples(
+					"awesome-directories product export acme",
+					"awesome-directories product export acme --variant b --format json",
+				),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "variant",
+						Usage: "Copy variant to use",
+						Value: models.DefaultVariant,
+					},
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "Output format: text, json, yaml",
+						Value:   "text",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("product slug is required")
+					}
+
+					productStore, err := openStore()
+					if err != nil {
+						return err
+					}
+
+					stored, err := productStore.Product(cmd.Args().First())
+					if err != nil {
+						return err
+					}
+
+					variant := strings.ToLower(cmd.String("variant"))
+					product, ok := stored.WithVariant(variant)
+					if !ok {
+						return fmt.Errorf("unknown variant %q of %s (available: %s)", variant, stored.Slug, strings.Join(stored.VariantNames(), ", "))
+					}
+					product.Variants = nil
+
+					switch cmd.String("format") {
+					case "json":
+						return printJSON(u, product)
+					case "yaml", "yml":
+						data, err := yaml.Marshal(product)
+						if err != nil {
+							return fmt.Errorf("failed to marshal YAML: %w", err)
+						}
+						u.Printf("%s", data)
+					case "text":
+						u.Printf("Name: %s\n", product.Name)
+						u.Printf("URL: %s\n", product.URL)
+						u.Printf("Tagline: %s\n", product.Tagline)
+						u.Printf("Description: %s\n", product.Description)
+						if len(product.Categories) > 0 {
+							u.Printf("Categories: %s\n", strings.Join(product.Categories, ", "))
+						}
+						if product.ContactEmail != "" {
+							u.Printf("Email: %s\n", product.ContactEmail)
+						}
+					default:
+						return fmt.Errorf("unsupported format: %s (use text, json or yaml)", cmd.String("format"))
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:      "delete",
+				Aliases:   []string{"rm"},
+				Usage:     "Delete a product profile",
+				ArgsUsage: "<slug>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("product slug is required")
+					}
+
+					productStore, err := openStore()
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						if _, err := productStore.Product(cmd.Args().First()); err != nil {
+							return err
+						}
+						u.Info("Dry run: would delete product %s", cmd.Args().First())
+						return nil
+					}
+
+					if err := productStore.DeleteProduct(cmd.Args().First()); err != nil {
+						return err
+					}
+
+					recordAudit(productStore, "product.delete", cmd.Args().First(), "")
+					u.Success("Deleted product %s", cmd.Args().First())
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// applyProductFields copies the product field flags that are set to product
+func applyProductFields(cmd *cli.Command, product *models.Product) {
+	if cmd.IsSet("name") {
+		product.Name = cmd.String("name")
+	}
+	if cmd.IsSet("url") {
+		product.URL = cmd.String("url")
+	}
+	if cmd.IsSet("tagline") {
+		product.Tagline = cmd.String("tagline")
+	}
+	if cmd.IsSet("description") {
+		product.Description = cmd.String("description")
+	}
+	if cmd.IsSet("category") {
+		product.Categories = cmd.StringSlice("category")
+	}
+	if cmd.IsSet("email") {
+		product.ContactEmail = cmd.String("email")
+	}
+	if cmd.IsSet("logo") {
+		product.Logo = cmd.String("logo")
+	}
+}
+
+// openStore opens the local data store
+func openStore() (*store.Store, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return store.New(cfg), nil
+}
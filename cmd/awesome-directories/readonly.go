@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/config"
+)
+
+// readOnlyBlocked lists the commands refused in read-only mode, those
+// changing the account, personal data, shared state or the config file.
+// Commands go by their full name and an entry covers the subcommands of the
+// command too; a flag after the name only blocks the command when given.
+var readOnlyBlocked = []string{
+	"auth login", "auth signup", "auth token", "auth logout",
+	"account delete",
+	"favorites add", "favorites remove",
+	"collection create", "collection set", "collection add", "collection remove", "collection delete", "collection sync",
+	"submissions track", "submissions notes", "submissions todo", "submissions set-status", "submissions attach",
+	"submissions ingest-email", "submissions github-sync", "submissions push",
+	"product create", "product set", "product delete",
+	"state init", "state pull", "state push",
+	"subscribe add", "subscribe remove", "subscribe digest",
+	"snapshot delete",
+	"calendar add-event", "calendar remove-event",
+	"discover-submit --save",
+	"analyze",
+	"assist",
+	"undo",
+	"migrate",
+}
+
+// readOnlyAllowed lists the commands read-only mode lets run, those only
+// reading or writing the cache, by their exact name. Every command is to
+// be in one of the lists, which selftest checks, so a new one isn't left
+// unblocked by oversight.
+var readOnlyAllowed = []string{
+	"search", "list", "filter", "categories", "show", "compare", "export", "plan", "sample", "dashboard", "diff",
+	"calendar", "calendar events",
+	"discover-submit", "screenshot",
+	"sync", "sync export-bundle", "sync import-bundle",
+	"watch",
+	"subscribe list",
+	"snapshot create", "snapshot list",
+	"auth whoami",
+	"account export",
+	"favorites list",
+	"collection list", "collection show", "collection export",
+	"submissions list", "submissions evidence", "submissions board", "submissions dupes",
+	"report generate",
+	"product list", "product show", "product export",
+	"state status",
+	"cache inspect", "cache gc", "config show", "config clear-cache",
+	"assert", "audit log", "insights",
+	"tour", "widget", "selftest", "version", "completion",
+}
+
+// withReadOnly makes cmd and its subcommands refuse to run when read-only
+// mode blocks them
+func withReadOnly(cmd *cli.Command) {
+	if cmd.Action != nil {
+		before := cmd.Before
+		cmd.Before = func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			if err := checkReadOnly(c); err != nil {
+				return nil, err
+			}
+			if before != nil {
+				return before(ctx, c)
+			}
+			return ctx, nil
+		}
+	}
+	for _, sub := range cmd.Commands {
+		withReadOnly(sub)
+	}
+}
+
+// checkReadOnly fails when read-only mode is on and blocks cmd
+func checkReadOnly(cmd *cli.Command) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if !cfg.ReadOnly {
+		return nil
+	}
+
+	name := commandName(cmd)
+	blocked := slices.ContainsFunc(readOnlyBlocked, func(entry string) bool {
+		command, flag, hasFlag := strings.Cut(entry, " --")
+		return coversCommand(command, name) && (!hasFlag || cmd.IsSet(flag))
+	})
+	if blocked {
+		return fmt.Errorf("%s: %w", name, config.ErrReadOnly)
+	}
+	return nil
+}
+
+// coversCommand reports whether an entry of the read-only lists names
+// the command, or one of its parents
+func coversCommand(entry, name string) bool {
+	return name == entry || strings.HasPrefix(name, entry+" ")
+}
+
+// commandName returns the full name of cmd without the name of the CLI
+func commandName(cmd *cli.Command) string {
+	return strings.TrimPrefix(cmd.FullName(), cmd.Root().Name+" ")
+}
+
+// readOnlyProblems walks the commands of root for those read-only mode
+// doesn't classify, and for entries of its lists naming no command or flag
+func readOnlyProblems(root *cli.Command) []string {
+	var problems []string
+	names := make(map[string]*cli.Command)
+
+	var walk func(cmd *cli.Command, name string)
+	walk = func(cmd *cli.Command, name string) {
+		if name != "" {
+			names[name] = cmd
+		}
+		// Help commands are added to every command with subcommands
+		if name != "" && cmd.Action != nil && cmd.Name != "help" {
+			blocked := slices.ContainsFunc(readOnlyBlocked, func(entry string) bool {
+				command, _, hasFlag := strings.Cut(entry, " --")
+				return !hasFlag && coversCommand(command, name)
+			})
+			if !blocked && !slices.Contains(readOnlyAllowed, name) {
+				problems = append(problems, fmt.Sprintf("%s is neither blocked nor allowed in read-only mode", name))
+			}
+		}
+		for _, sub := range cmd.Commands {
+			walk(sub, strings.TrimSpace(name+" "+sub.Name))
+		}
+	}
+	walk(root, "")
+
+	for _, entry := range append(slices.Clone(readOnlyBlocked), readOnlyAllowed...) {
+		command, flag, hasFlag := strings.Cut(entry, " --")
+		cmd, ok := names[command]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("read-only mode lists %q, which is no command", command))
+		case hasFlag && !slices.ContainsFunc(cmd.Flags, func(f cli.Flag) bool { return slices.Contains(f.Names(), flag) }):
+			problems = append(problems, fmt.Sprintf("read-only mode lists %q, which has no --%s flag", command, flag))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/export"
+	"github.com/awesome-directories/cli/internal/mail"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// reportCommand creates the report command
+func reportCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "report",
+		Usage: "Generate client reports of the submission pipeline",
+		Commands: []*cli.Command{
+			{
+				Name:  "generate",
+				Usage: "Bundle the submissions of a project and their evidence into an archive, or a PDF report",
+				Metadata: examples(
+					"awesome-directories report generate -o acme-report.tar.gz --project acme",
+					"awesome-directories report generate -o report.tar.gz --all-projects --force",
+					"awesome-directories report generate -o acme-report.tar.gz --project acme --email me@acme.dev",
+					"awesome-directories report generate -f pdf -o acme-report.pdf --project acme --product acme",
+				),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "output",
+						Aliases:  []string{"o"},
+						Usage:    "Output file path",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "format",
+						Aliases: []string{"f"},
+						Usage:   "Report format: bundle (a .tar.gz with a Markdown report and the evidence files) or pdf (a printable report for clients)",
+						Value:   "bundle",
+					},
+					projectFlag(),
+					&cli.StringFlag{
+						Name:  "product",
+						Usage: "Product profile whose name and logo brand a PDF report (default: the only profile)",
+					},
+					&cli.BoolFlag{
+						Name:  "all-projects",
+						Usage: "Report the submissions of every project",
+					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Overwrite the output file if it exists",
+					},
+					&cli.BoolFlag{
+						Name:  "backup",
+						Usage: "Keep a timestamped copy of an existing output file",
+					},
+					&cli.StringSliceFlag{
+						Name:  "email",
+						Usage: "Also email the report to this address through the configured SMTP server (can be specified multiple times)",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					format := cmd.String("format")
+					if format != "bundle" && format != "pdf" {
+						return fmt.Errorf("unsupported report format: %s (use bundle or pdf)", format)
+					}
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					var recipients []string
+					for _, address := range cmd.StringSlice("email") {
+						recipient, err := mail.ParseAddress(address)
+						if err != nil {
+							return err
+						}
+						recipients = append(recipients, recipient)
+					}
+					if len(recipients) > 0 && !mailConfig(cfg).Configured() {
+						return fmt.Errorf("--email needs an SMTP server: set smtp.server in config.yaml or SMTP_SERVER")
+					}
+
+					dataStore := store.New(cfg)
+					submissions, err := dataStore.Submissions()
+					if err != nil {
+						return err
+					}
+
+					report := export.Report{EvidencePath: dataStore.EvidencePath}
+					if !cmd.Bool("all-projects") {
+						report.Project = cmd.String("project")
+					}
+					for _, submission := range submissions {
+						if report.Project == "" || submission.Project == report.Project {
+							report.Submissions = append(report.Submissions, submission)
+						}
+					}
+					if len(report.Submissions) == 0 {
+						return fmt.Errorf("no submissions to report; use 'submissions track' first")
+					}
+
+					// Names and metrics of the directories, when the catalog is available
+					report.Directories = make(map[string]models.Directory)
+					apiClient := api.NewClient(cfg)
+					cacheClient := cache.NewCache(cfg, apiClient)
+					if directories, err := loadDirectories(ctx, cmd, cacheClient); err != nil {
+						u.Warning("Reporting without directory details: %v", err)
+					} else {
+						for _, dir := range directories {
+							report.Directories[dir.Slug] = dir
+						}
+						cacheClient.Lineage().Alias(report.Directories, cache.BySlug)
+					}
+
+					outputPath := cmd.String("output")
+					backupPath, err := export.PrepareOutput(outputPath, cmd.Bool("force"), cmd.Bool("backup"))
+					if err != nil {
+						return err
+					}
+					if backupPath != "" {
+						u.Info("Backed up existing %s to %s", outputPath, backupPath)
+					}
+
+					contentType := "application/gzip"
+					if format == "pdf" {
+						brand, err := reportBrand(ctx, u, dataStore, apiClient, cmd.String("product"))
+						if err != nil {
+							return err
+						}
+						report.Brand = brand
+						if err := export.ToReportPDF(report, outputPath); err != nil {
+							return err
+						}
+						contentType = "application/pdf"
+					} else if err := export.ToReport(report, outputPath); err != nil {
+						return err
+					}
+					recordResults(ctx, len(report.Submissions))
+
+					evidence := 0
+					for _, submission := range report.Submissions {
+						evidence += len(submission.Evidence)
+					}
+					size := int64(0)
+					if info, err := os.Stat(outputPath); err == nil {
+						size = info.Size()
+					}
+					u.Success("Reported %d submission(s) with %d evidence item(s) to %s (%s)",
+						len(report.Submissions), evidence, outputPath, ui.FormatBytes(size))
+
+					if len(recipients) > 0 {
+						data, err := os.ReadFile(outputPath)
+						if err != nil {
+							return fmt.Errorf("failed to read report: %w", err)
+						}
+
+						scope := "all projects"
+						if report.Project != "" {
+							scope = "project " + report.Project
+						}
+						err = mail.Send(ctx, mailConfig(cfg), mail.Message{
+							To:      recipients,
+							Subject: "Submission report: " + scope,
+							Text: fmt.Sprintf("Attached is the submission report of %s: %d submission(s) with %d evidence item(s).\n",
+								scope, len(report.Submissions), evidence),
+							Attachments: []mail.Attachment{{Name: filepath.Base(outputPath), ContentType: contentType, Data: data}},
+						})
+						if err != nil {
+							return fmt.Errorf("failed to email report: %w", err)
+						}
+						u.Success("Emailed the report to %s", strings.Join(recipients, ", "))
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// reportBrand returns the product profile a PDF report is prepared for: the
+// one named, or else the only one stored. A logo that can't be loaded is left
+// out with a warning.
+func reportBrand(ctx context.Context, u *ui.UI, dataStore *store.Store, apiClient *api.Client, slug string) (*export.ReportBrand, error) {
+	if slug == "" {
+		products, err := dataStore.Products()
+		if err != nil {
+			return nil, err
+		}
+		if len(products) != 1 {
+			return nil, nil
+		}
+		slug = products[0].Slug
+	}
+
+	product, err := dataStore.Product(slug)
+	if err != nil {
+		return nil, err
+	}
+	brand := &export.ReportBrand{Name: product.Name, Tagline: product.Tagline}
+	if product.Logo == "" {
+		return brand, nil
+	}
+
+	var logo []byte
+	if strings.HasPrefix(product.Logo, "http://") || strings.HasPrefix(product.Logo, "https://") {
+		logo, err = apiClient.GetImage(ctx, product.Logo)
+	} else {
+		logo, err = os.ReadFile(product.Logo)
+	}
+	if err == nil {
+		err = export.CheckImage(logo)
+	}
+	if err != nil {
+		u.Warning("Leaving out the logo of %s: %v", product.Name, err)
+		return brand, nil
+	}
+	brand.Logo = logo
+	return brand, nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/sample"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// sampleCommand creates the sample command
+func sampleCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "sample",
+		Usage: "Pick a stratified random sample of directories for outreach experiments",
+		Metadata: examples(
+			"awesome-directories sample --count 20 --stratify-by dr-band",
+			"awesome-directories sample --count 10 --stratify-by pricing --category saas --seed 42",
+			"awesome-directories sample --count 20 --preset high-dr --format csv > sample.csv",
+		),
+		Flags: append([]cli.Flag{
+			&cli.IntFlag{
+				Name:    "count",
+				Aliases: []string{"n"},
+				Usage:   "Number of directories to sample",
+				Value:   20,
+			},
+			&cli.StringFlag{
+				Name:  "stratify-by",
+				Usage: "Sample each group in proportion to its size: " + strings.Join(sample.StrataNames(), ", "),
+			},
+			&cli.Uint64Flag{
+				Name:        "seed",
+				Usage:       "Random seed, to reproduce a sample",
+				DefaultText: "random",
+			},
+			&cli.StringFlag{
+				Name:  "preset",
+				Usage: "Start from a preset from config.yaml",
+			},
+			&cli.StringSliceFlag{
+				Name:    "category",
+				Aliases: []string{"c"},
+				Usage:   "Filter by category (can be specified multiple times)",
+			},
+			&cli.StringSliceFlag{
+				Name:    "pricing",
+				Aliases: []string{"p"},
+				Usage:   "Filter by pricing: free, paid, freemium",
+			},
+			&cli.StringSliceFlag{
+				Name:  "link-type",
+				Usage: "Filter by link type: dofollow, nofollow",
+			},
+			&cli.IntFlag{
+				Name:  "dr-min",
+				Usage: "Minimum domain rating",
+			},
+			snapshotFlag(),
+		}, append(filterMetadataFlags(), formatFlags()...)...),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			if cmd.Int("count") <= 0 {
+				return fmt.Errorf("count must be positive")
+			}
+
+			strata, err := sample.ParseStrata(cmd.String("stratify-by"))
+			if err != nil {
+				return err
+			}
+
+			options := &models.FilterOptions{}
+			if name := cmd.String("preset"); name != "" {
+				preset, err := cfg.Preset(name)
+				if err != nil {
+					return err
+				}
+				options = preset.FilterOptions()
+			}
+			if cmd.IsSet("category") {
+				options.Categories = cmd.StringSlice("category")
+			}
+			if cmd.IsSet("pricing") {
+				options.Pricing = cmd.StringSlice("pricing")
+			}
+			if cmd.IsSet("link-type") {
+				options.LinkType = cmd.StringSlice("link-type")
+			}
+			if cmd.IsSet("dr-min") {
+				options.DRMin = cmd.Int("dr-min")
+			}
+			if err := applyFilterMetadata(ctx, cmd, cfg, options); err != nil {
+				return err
+			}
+
+			apiClient := api.NewClient(cfg)
+			cacheClient := cache.NewCache(cfg, apiClient)
+
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+
+			// Sample from the full matching set, not a page of it
+			options.Limit, options.Offset = 0, 0
+			candidates := cacheClient.FilterDirectories(directories, options)
+
+			seed := cmd.Uint64("seed")
+			if !cmd.IsSet("seed") {
+				seed = rand.Uint64() % 1_000_000
+			}
+
+			sampled, groups := sample.Stratified(candidates, cmd.Int("count"), strata, seed)
+			recordResults(ctx, len(sampled))
+
+			if !isTableFormat(cmd) {
+				return renderDirectories(ctx, u, cmd, sampled)
+			}
+
+			if len(sampled) == 0 {
+				u.Warning("No directories match the filters")
+				return nil
+			}
+
+			if err := renderDirectories(ctx, u, cmd, sampled); err != nil {
+				return err
+			}
+
+			if cmd.String("stratify-by") != "" {
+				table := u.CreateTable([]string{"Group", "Matching", "Sampled"})
+				for _, group := range groups {
+					table.Row(group.Key, fmt.Sprint(group.Size), fmt.Sprint(group.Allocated))
+				}
+				u.Println(table)
+			}
+
+			u.Info("Sampled %d of %d matching directories (seed %d)", len(sampled), len(candidates), seed)
+			if len(sampled) < cmd.Int("count") {
+				u.Warning("Only %d directories match the filters", len(candidates))
+			}
+
+			return nil
+		},
+	}
+}
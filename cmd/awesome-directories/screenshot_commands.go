@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/screenshot"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// screenshotCommand creates the screenshot command
+func screenshotCommand() *cli.Command {
+	defaults := screenshot.DefaultOptions()
+
+	return &cli.Command{
+		Name:      "screenshot",
+		Usage:     "Capture the directory page and your live listing as evidence for reports",
+		ArgsUsage: "<slug>",
+		Metadata: examples(
+			"awesome-directories screenshot betalist -o shots/",
+			"awesome-directories screenshot betalist -o shots/ --project acme --full-page",
+			"awesome-directories screenshot betalist --listing-url https://betalist.com/startups/acme",
+		),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "Directory to save the screenshots in",
+				Value:   ".",
+			},
+			projectFlag(),
+			&cli.StringFlag{
+				Name:  "listing-url",
+				Usage: "Live listing page to capture, instead of the one tracked with the submission",
+			},
+			&cli.BoolFlag{
+				Name:  "full-page",
+				Usage: "Capture whole pages instead of the first screen",
+			},
+			&cli.IntFlag{
+				Name:  "width",
+				Usage: "Browser window width",
+				Value: defaults.Width,
+			},
+			&cli.IntFlag{
+				Name:  "height",
+				Usage: "Browser window height",
+				Value: defaults.Height,
+			},
+			&cli.DurationFlag{
+				Name:  "wait",
+				Usage: "How long to let pages render after loading",
+				Value: defaults.Wait,
+			},
+			&cli.StringFlag{
+				Name:  "browser",
+				Usage: "Chrome or Chromium executable (default: found on the PATH)",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() == 0 {
+				return fmt.Errorf("directory slug is required")
+			}
+			slug := cmd.Args().First()
+
+			if !screenshot.Supported {
+				return screenshot.ErrUnsupported
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+			directories, err := loadDirectories(ctx, cmd, cacheClient)
+			if err != nil {
+				return err
+			}
+			directory := findDirectory(directories, slug)
+			if directory == nil {
+				return fmt.Errorf("directory not found: %s", slug)
+			}
+
+			// Pages to capture, with their file names
+			date := time.Now().Format("20060102")
+			names := []string{fmt.Sprintf("%s-directory-%s.png", slug, date)}
+			urls := []string{directory.URL}
+
+			listingURL := cmd.String("listing-url")
+			if listingURL == "" {
+				submission, err := store.New(cfg).Submission(cmd.String("project"), slug)
+				if err != nil {
+					return err
+				}
+				if submission != nil {
+					listingURL = submission.ListingURL
+				}
+			}
+			if listingURL != "" {
+				names = append(names, fmt.Sprintf("%s-listing-%s.png", slug, date))
+				urls = append(urls, listingURL)
+			} else {
+				u.Muted("No live listing tracked; set one with 'submissions track %s --listing-url <url>'", slug)
+			}
+
+			outputDir := cmd.String("output")
+			if err := os.MkdirAll(outputDir, 0755); err != nil {
+				return fmt.Errorf("failed to create output directory: %w", err)
+			}
+
+			options := defaults
+			options.Width = cmd.Int("width")
+			options.Height = cmd.Int("height")
+			options.Wait = cmd.Duration("wait")
+			options.FullPage = cmd.Bool("full-page")
+			options.BrowserPath = cmd.String("browser")
+
+			u.Muted("Capturing %d page(s)...", len(urls))
+			shots, err := screenshot.Capture(ctx, options, urls...)
+			if err != nil {
+				return err
+			}
+
+			for i, shot := range shots {
+				path := filepath.Join(outputDir, names[i])
+				if err := os.WriteFile(path, shot, 0644); err != nil {
+					return fmt.Errorf("failed to write screenshot: %w", err)
+				}
+				u.Success("Saved %s (%s)", path, urls[i])
+			}
+			recordResults(ctx, len(shots))
+
+			return nil
+		},
+	}
+}
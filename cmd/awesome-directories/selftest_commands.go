@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/demo"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// selftestCase is a command checked by selftest against its golden file
+type selftestCase struct {
+	name string
+	args []string
+
+	// file is written by the command and checked instead of its output
+	file string
+}
+
+// selftestCases exercise searching, filtering, rendering, exporting and
+// planning on the demo catalog. "{dir}" in arguments is the scratch
+// directory.
+var selftestCases = []selftestCase{
+	{name: "search.txt", args: []string{"search", "launch"}},
+	{name: "filter.txt", args: []string{"filter", "--pricing", "free", "--link-type", "dofollow", "--dr-min", "50"}},
+	{name: "filter-csv.csv", args: []string{"filter", "--category", "AI Tools", "--format", "csv"}},
+	{name: "show.json", args: []string{"show", "launch-ledger", "--format", "json"}},
+	{name: "plan.txt", args: []string{"plan", "--max-count", "8", "--budget", "60", "--currency", "USD"}},
+	{name: "export.md", args: []string{"export", "-f", "markdown", "-o", "{dir}/export.md", "--pricing", "free"}, file: "export.md"},
+	{name: "export.json", args: []string{"export", "-f", "json", "-o", "{dir}/export.json", "--dr-min", "60"}, file: "export.json"},
+}
+
+// selftestCommand creates the selftest command
+func selftestCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "selftest",
+		Usage: "Check that this installation works, running commands on demo data against known outputs",
+		Description: `Runs search, filter, show, plan and export on the demo catalog built into
+the CLI and compares their outputs with the expected ones, also built in.
+Nothing touches your account, your data or the network; it takes seconds.
+It also checks that read-only mode either blocks or allows every command.
+
+Exits with status 1 when an output differs, showing the first difference.`,
+		Metadata: examples(
+			"awesome-directories selftest",
+		),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:   "update-golden",
+				Usage:  "Write the outputs as the golden files of a `DIR` instead of checking them",
+				Hidden: true,
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			dir, cleanup, err := demoSandbox()
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			// Outputs are compared without colors or icons, whether or not
+			// this runs in a terminal
+			restoreColors := ui.PauseColors()
+			outputs, err := runSelftestCases(ctx, dir)
+			restoreColors()
+			if err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, problem := range readOnlyProblems(cmd.Root()) {
+				u.Error("%s", problem)
+				failed++
+			}
+			for i, c := range selftestCases {
+				output := outputs[i]
+				shown := strings.Join(tourArgs(output.args, dir), " ")
+				if output.err != nil {
+					u.Error("%s: %v", shown, output.err)
+					if output.errOutput != "" {
+						u.Muted("%s", strings.TrimSpace(output.errOutput))
+					}
+					failed++
+					continue
+				}
+
+				if goldenDir := cmd.String("update-golden"); goldenDir != "" {
+					if err := os.WriteFile(filepath.Join(goldenDir, c.name), output.output, 0644); err != nil {
+						return fmt.Errorf("failed to write golden file: %w", err)
+					}
+					u.Info("Wrote %s", c.name)
+					continue
+				}
+
+				want, err := demo.Golden(c.name)
+				if err != nil {
+					return err
+				}
+				if line, expected, got, same := firstDifference(want, output.output); !same {
+					u.Error("%s: output differs at line %d", shown, line)
+					u.Printf("    expected: %q\n    got:      %q\n", expected, got)
+					failed++
+					continue
+				}
+				u.Success("%s", shown)
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%d checks failed", failed)
+			}
+			if cmd.String("update-golden") == "" {
+				u.Success("All %d checks passed", len(selftestCases))
+			}
+			return nil
+		},
+	}
+}
+
+// selftestOutput is what a selftest case produced
+type selftestOutput struct {
+	args      []string
+	output    []byte
+	errOutput string
+	err       error
+}
+
+// runSelftestCases loads the demo catalog and runs each case in the scratch
+// directory dir
+func runSelftestCases(ctx context.Context, dir string) ([]selftestOutput, error) {
+	if _, _, err := runCaptured(ctx, []string{"sync"}); err != nil {
+		return nil, fmt.Errorf("failed to load the demo catalog: %w", err)
+	}
+
+	outputs := make([]selftestOutput, len(selftestCases))
+	for i, c := range selftestCases {
+		args := make([]string, len(c.args))
+		for j, arg := range c.args {
+			args[j] = strings.ReplaceAll(arg, "{dir}", dir)
+		}
+
+		output, errOutput, err := runCaptured(ctx, args)
+		if err == nil && c.file != "" {
+			output, err = os.ReadFile(filepath.Join(dir, c.file))
+		}
+		outputs[i] = selftestOutput{args: args, output: output, errOutput: errOutput, err: err}
+	}
+	return outputs, nil
+}
+
+// runCaptured runs the CLI with args, returning what it wrote to stdout and
+// stderr
+func runCaptured(ctx context.Context, args []string) ([]byte, string, error) {
+	var out, errOut bytes.Buffer
+	captured := ui.New(strings.NewReader(""), &out, &errOut)
+
+	err := newApp().Run(ui.WithContext(ctx, captured), append([]string{"awesome-directories"}, args...))
+	return out.Bytes(), errOut.String(), err
+}
+
+// firstDifference compares two outputs line by line, returning the first
+// line that differs
+func firstDifference(want, got []byte) (line int, expected, actual string, same bool) {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		expected, actual = "", ""
+		if i < len(wantLines) {
+			expected = wantLines[i]
+		}
+		if i < len(gotLines) {
+			actual = gotLines[i]
+		}
+		if expected != actual || i >= len(wantLines) || i >= len(gotLines) {
+			return i + 1, expected, actual, false
+		}
+	}
+	return 0, "", "", true
+}
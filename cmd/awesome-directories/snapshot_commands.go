@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// snapshotCommand creates the snapshot command
+func snapshotCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "snapshot",
+		Usage: "Freeze copies of the catalog for reproducible analyses",
+		Commands: []*cli.Command{
+			{
+				Name:      "create",
+				Usage:     "Save the current catalog under a name",
+				ArgsUsage: "<name>",
+				Metadata: examples(
+					"awesome-directories snapshot create",
+					"awesome-directories snapshot create before-launch",
+				),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("snapshot name is required")
+					}
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+
+					snapshot, err := cacheClient.CreateSnapshot(ctx, cmd.Args().First())
+					if err != nil {
+						return fmt.Errorf("failed to create snapshot: %w", err)
+					}
+
+					u.Success("Created snapshot '%s' with %d directories", snapshot.Name, snapshot.Count)
+
+					return nil
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "List stored snapshots",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					snapshots, err := cache.NewCache(cfg, api.NewClient(cfg)).ListSnapshots()
+					if err != nil {
+						return err
+					}
+
+					if len(snapshots) == 0 {
+						u.Warning("No snapshots yet. Use 'snapshot create <name>' to create one.")
+						return nil
+					}
+
+					table := u.CreateTable([]string{"Name", "Created", "Directories", "Size"})
+					for _, snapshot := range snapshots {
+						table.Row(
+							snapshot.Name,
+							snapshot.CreatedAt.Local().Format("2006-01-02 15:04"),
+							strconv.Itoa(snapshot.Count),
+							ui.FormatBytes(snapshot.Size),
+						)
+					}
+					u.Println(table)
+
+					return nil
+				},
+			},
+			{
+				Name:      "delete",
+				Aliases:   []string{"rm"},
+				Usage:     "Delete a stored snapshot",
+				ArgsUsage: "<name>",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("snapshot name is required")
+					}
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					name := cmd.Args().First()
+					if err := cache.NewCache(cfg, api.NewClient(cfg)).DeleteSnapshot(name); err != nil {
+						return err
+					}
+
+					u.Success("Deleted snapshot '%s'", name)
+
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// snapshotFlag returns the flag selecting a snapshot instead of live data
+func snapshotFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "snapshot",
+		Usage: "Read directories from a named snapshot instead of the cache",
+	}
+}
+
+// loadDirectories returns the directories of the snapshot selected with
+// --snapshot, or the cached catalog, and records where they came from
+func loadDirectories(ctx context.Context, cmd *cli.Command, cacheClient *cache.Cache) ([]models.Directory, error) {
+	if name := cmd.String("snapshot"); name != "" {
+		snapshot, err := cacheClient.LoadSnapshot(name)
+		if err != nil {
+			return nil, err
+		}
+		recordProvenance(ctx, cache.SnapshotProvenance(snapshot))
+		return snapshot.Directories, nil
+	}
+
+	directories, err := cacheClient.GetDirectories(ctx, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get directories: %w", err)
+	}
+	recordProvenance(ctx, cacheClient.Provenance())
+
+	// Local corrections, such as discovered submission URLs
+	if dataStore, err := openStore(); err == nil {
+		overrides, err := dataStore.Overrides()
+		if err != nil {
+			return nil, err
+		}
+		// Overrides made before a directory was renamed follow it
+		lineage := cacheClient.Lineage()
+		for slug, override := range overrides {
+			if current := lineage.Current(slug, cache.BySlug); current != slug {
+				if _, ok := overrides[current]; !ok {
+					overrides[current] = override
+				}
+			}
+		}
+		store.ApplyOverrides(directories, overrides)
+	}
+
+	return directories, nil
+}
+
+// findDirectory returns the directory with the given slug, or with the slug
+// it was renamed to since
+func findDirectory(directories []models.Directory, slug string) *models.Directory {
+	for i, dir := range directories {
+		if dir.Slug == slug {
+			return &directories[i]
+		}
+	}
+
+	if cfg, err := config.Load(); err == nil {
+		if current := cache.NewCache(cfg, nil).Lineage().Current(slug, cache.BySlug); current != slug {
+			return findDirectory(directories, current)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,407 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/term"
+	"gopkg.in/yaml.v3"
+
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// stateCommand creates the state command
+func stateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "state",
+		Usage: "Share submissions and product profiles with a team through a git repository",
+		Commands: []*cli.Command{
+			stateInitCommand(),
+			{
+				Name:  "status",
+				Usage: "Show uncommitted changes in the shared state dir",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					dir, err := sharedStateDir()
+					if err != nil {
+						return err
+					}
+					ui.FromContext(ctx).Muted("State dir: %s", dir)
+					return runGit(ctx, dir, "status", "--short", "--branch")
+				},
+			},
+			{
+				Name:  "pull",
+				Usage: "Commit local changes and merge the team's latest state",
+				Metadata: examples(
+					"awesome-directories state pull",
+					"awesome-directories state pull --prefer merge",
+				),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "prefer",
+						Usage: "Resolve conflicts without prompting: local, remote or merge (newer record, notes and todos from both)",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					var prefer store.Resolution
+					if value := cmd.String("prefer"); value != "" {
+						var err error
+						if prefer, err = store.ParseResolution(value); err != nil {
+							return err
+						}
+					}
+
+					dir, err := sharedStateDir()
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						return previewStateSync(ctx, u, dir, "pull the team's latest state")
+					}
+
+					if _, err := commitState(ctx, dir, "Update submission state"); err != nil {
+						return err
+					}
+
+					if pullErr := runGit(ctx, dir, "pull", "--no-rebase", "--no-edit"); pullErr != nil {
+						conflicts, err := gitOutput(ctx, dir, "diff", "--name-only", "--diff-filter=U")
+						if err != nil || conflicts == "" {
+							return pullErr
+						}
+						if err := resolveStateConflicts(ctx, u, dir, strings.Fields(conflicts), prefer); err != nil {
+							return err
+						}
+					}
+
+					u.Success("State is up to date")
+					return nil
+				},
+			},
+			{
+				Name:  "push",
+				Usage: "Commit local state changes and push them to the team",
+				Metadata: examples(
+					"awesome-directories state push",
+					`awesome-directories state push --message "Track launch week submissions"`,
+				),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "message",
+						Aliases: []string{"m"},
+						Usage:   "Commit message",
+						Value:   "Update submission state",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					dir, err := sharedStateDir()
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						return previewStateSync(ctx, u, dir, "push to the team")
+					}
+
+					committed, err := commitState(ctx, dir, cmd.String("message"))
+					if err != nil {
+						return err
+					}
+					if !committed {
+						u.Muted("No local changes to commit")
+					}
+
+					if err := runGit(ctx, dir, "push"); err != nil {
+						return err
+					}
+					u.Success("State pushed")
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// stateInitCommand creates the state init command
+func stateInitCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "init",
+		Usage:     "Keep submissions and product profiles in a shared state dir",
+		ArgsUsage: "<dir>",
+		Metadata: examples(
+			"git clone git@github.com:acme/launch-state.git ~/launch-state",
+			"awesome-directories state init ~/launch-state",
+			"awesome-directories state init ./state --no-copy",
+		),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "no-copy",
+				Usage: "Don't copy local submissions and product profiles into the state dir",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() != 1 {
+				return fmt.Errorf("state dir required")
+			}
+
+			dir, err := filepath.Abs(cmd.Args().First())
+			if err != nil {
+				return fmt.Errorf("failed to resolve state dir: %w", err)
+			}
+			if dryRun(cmd) {
+				u.Info("Dry run: would keep submissions and product profiles in %s and save it to the config", dir)
+				return nil
+			}
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create state dir: %w", err)
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+				if err := runGit(ctx, dir, "init", "--quiet"); err != nil {
+					u.Warning("Could not create a git repository in %s: %v", dir, err)
+				} else {
+					u.Info("Initialized a git repository in %s", dir)
+				}
+			}
+
+			if !cmd.Bool("no-copy") {
+				local := store.New(cfg).Local()
+				products, submissions, err := local.CopyTo(local.WithStateDir(dir))
+				if err != nil {
+					return fmt.Errorf("failed to copy local state: %w", err)
+				}
+				u.Info("Copied %d product profile(s) and %d submission(s)", products, submissions)
+			}
+
+			// Only the state dir is written, not what the environment set
+			cfg.StateDir = dir
+			if err := config.UpdateFile(func(file *config.Config) { file.StateDir = dir }); err != nil {
+				return fmt.Errorf("failed to save config: %w", err)
+			}
+			recordConfigAudit(cfg, "state_dir="+dir)
+
+			u.Success("Submissions and product profiles are now kept in %s", dir)
+			u.Muted("Run 'awesome-directories state push' to share them")
+			return nil
+		},
+	}
+}
+
+// previewStateSync prints the local changes a state pull or push would
+// commit before it does what
+func previewStateSync(ctx context.Context, u *ui.UI, dir, what string) error {
+	changes, err := gitOutput(ctx, dir, "status", "--short")
+	if err != nil {
+		return err
+	}
+
+	if changed := strings.Split(strings.TrimSpace(changes), "\n"); changes != "" {
+		u.Println(strings.TrimRight(changes, "\n"))
+		u.Info("Dry run: would commit %d changed file(s) and %s", len(changed), what)
+		return nil
+	}
+	u.Info("Dry run: no local changes to commit; would %s", what)
+	return nil
+}
+
+// sharedStateDir returns the configured state dir
+func sharedStateDir() (string, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	if cfg.StateDir == "" {
+		return "", fmt.Errorf("no state dir configured: run 'awesome-directories state init <dir>' first")
+	}
+	return cfg.StateDir, nil
+}
+
+// commitState commits all changes in the state dir, reporting whether there
+// was anything to commit
+func commitState(ctx context.Context, dir, message string) (bool, error) {
+	if err := runGit(ctx, dir, "add", "--all"); err != nil {
+		return false, err
+	}
+	// diff --quiet exits non-zero when something is staged
+	if runGit(ctx, dir, "diff", "--cached", "--quiet") == nil {
+		return false, nil
+	}
+	if err := runGit(ctx, dir, "commit", "--quiet", "--message", message); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// resolveStateConflicts resolves the files left conflicted by a merge, by
+// prompting for each one or applying prefer, and concludes the merge
+func resolveStateConflicts(ctx context.Context, u *ui.UI, dir string, paths []string, prefer store.Resolution) error {
+	if prefer == "" && !isInteractive(u) {
+		if err := runGit(ctx, dir, "merge", "--abort"); err != nil {
+			log.Error().Err(err).Msg("Failed to abort merge")
+		}
+		return fmt.Errorf("%d conflicting file(s) in the state dir: rerun with --prefer local, remote or merge", len(paths))
+	}
+
+	u.Warning("%d file(s) changed both locally and remotely", len(paths))
+
+	for _, path := range paths {
+		// Stage 2 holds our (local) version, stage 3 theirs (remote); a
+		// missing stage means the file was deleted on that side
+		local, _ := gitOutput(ctx, dir, "show", ":2:"+path)
+		remote, _ := gitOutput(ctx, dir, "show", ":3:"+path)
+
+		resolution := prefer
+		if resolution == "" {
+			printConflict(u, path, local, remote)
+
+			answer, err := u.Prompt("Keep [l]ocal, [r]emote, [m]erge notes, or [a]bort? ")
+			if err != nil {
+				return err
+			}
+			switch strings.ToLower(answer) {
+			case "l", "local":
+				resolution = store.ResolveLocal
+			case "r", "remote":
+				resolution = store.ResolveRemote
+			case "m", "merge":
+				resolution = store.ResolveMerge
+			default:
+				if err := runGit(ctx, dir, "merge", "--abort"); err != nil {
+					return err
+				}
+				return fmt.Errorf("pull aborted, local state unchanged")
+			}
+		}
+
+		content, err := store.ResolveConflict(path, conflictSide(local), conflictSide(remote), resolution)
+		if err != nil {
+			return err
+		}
+
+		if content == nil {
+			if err := runGit(ctx, dir, "rm", "--quiet", path); err != nil {
+				return err
+			}
+		} else {
+			if err := os.WriteFile(filepath.Join(dir, path), content, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+			if err := runGit(ctx, dir, "add", path); err != nil {
+				return err
+			}
+		}
+		u.Info("Resolved %s (%s)", path, resolution)
+	}
+
+	return runGit(ctx, dir, "commit", "--quiet", "--no-edit")
+}
+
+// printConflict shows the fields that differ between the local and remote
+// version of a conflicted file
+func printConflict(u *ui.UI, path, local, remote string) {
+	u.Println()
+	u.Bold("%s", path)
+
+	switch {
+	case local == "":
+		u.Muted("Deleted locally, changed remotely")
+		return
+	case remote == "":
+		u.Muted("Changed locally, deleted remotely")
+		return
+	}
+
+	var l, r map[string]interface{}
+	if yaml.Unmarshal([]byte(local), &l) != nil || yaml.Unmarshal([]byte(remote), &r) != nil {
+		u.Muted("Not valid YAML on one side")
+		return
+	}
+
+	keys := make([]string, 0, len(l)+len(r))
+	for key := range l {
+		keys = append(keys, key)
+	}
+	for key := range r {
+		if _, ok := l[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	table := u.CreateTable([]string{"Field", "Local", "Remote"})
+	for _, key := range keys {
+		lv, rv := conflictValue(l[key]), conflictValue(r[key])
+		if lv != rv {
+			table.Row(key, ui.TruncateString(lv, 40), ui.TruncateString(rv, 40))
+		}
+	}
+	u.Println(table)
+}
+
+// conflictValue formats a YAML value for printConflict
+func conflictValue(v interface{}) string {
+	if v == nil {
+		return "-"
+	}
+	if list, ok := v.([]interface{}); ok {
+		return fmt.Sprintf("%d item(s)", len(list))
+	}
+	return strings.ReplaceAll(fmt.Sprint(v), "\n", " ")
+}
+
+// conflictSide returns the content of one side of a conflict, nil when the
+// file was deleted there
+func conflictSide(content string) []byte {
+	if content == "" {
+		return nil
+	}
+	return []byte(content)
+}
+
+// isInteractive reports whether u reads from a terminal
+func isInteractive(u *ui.UI) bool {
+	file, ok := u.In.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// gitOutput runs git in dir and returns its output
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// runGit runs git in dir, passing its output through
+func runGit(ctx context.Context, dir string, args ...string) error {
+	log.Debug().Str("dir", dir).Strs("args", args).Msg("Running git")
+
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git %s failed: %w", args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,297 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// statusChange is a submission status update planned by set-status
+type statusChange struct {
+	slug string
+	from string // "" for a submission not tracked yet
+
+	// skipped explains why the change is not made, "" when it is
+	skipped string
+}
+
+// submissionSetStatusCommand creates the submissions set-status command
+func submissionSetStatusCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "set-status",
+		Usage:     "Move many submissions to a status at once, such as after a batch submission session",
+		ArgsUsage: "<status> [slug...]",
+		Description: `Submissions are picked by slug, from a file with --slugs-from, or by their
+current status with --where-status; combined, only submissions matching
+all of them change. Directories not tracked yet are tracked with the new
+status.
+
+Submissions only move forward in the pipeline (pending, submitted, then
+approved or rejected), or from rejected back to pending or submitted to try
+again; other changes are skipped and listed.`,
+		Metadata: examples(
+			"awesome-directories submissions set-status submitted --slugs-from plan.txt",
+			"awesome-directories plan --max-count 10 --format template --template '{{.Slug}}' | awesome-directories submissions set-status submitted --slugs-from -",
+			"awesome-directories submissions set-status submitted producthunt betalist --project acme",
+			"awesome-directories submissions set-status rejected --where-status submitted --dry-run",
+		),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "slugs-from",
+				Usage: "Read directory slugs from a `FILE`, one per line (- for stdin); text after the slug is ignored",
+			},
+			&cli.StringSliceFlag{
+				Name:  "where-status",
+				Usage: "Only change submissions currently in this status",
+			},
+			&cli.StringFlag{
+				Name:  "notes",
+				Usage: "Replace the notes of every changed submission",
+			},
+			projectFlag(),
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Track directories already submitted to in another project anyway",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show the changes without saving them",
+			},
+			yesFlag(),
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			if cmd.Args().Len() == 0 {
+				return fmt.Errorf("status is required")
+			}
+			status := strings.ToLower(cmd.Args().First())
+			if !models.ValidSubmissionStatus(status) {
+				return fmt.Errorf("invalid status: %s (use %s)", status, strings.Join(models.SubmissionStatuses, ", "))
+			}
+
+			slugs := cmd.Args().Tail()
+			if path := cmd.String("slugs-from"); path != "" {
+				fromFile, err := readSlugs(u, path)
+				if err != nil {
+					return err
+				}
+				slugs = append(slugs, fromFile...)
+			}
+			whereStatus := cmd.StringSlice("where-status")
+			if len(slugs) == 0 && len(whereStatus) == 0 {
+				return fmt.Errorf("no submissions selected: give slugs, --slugs-from or --where-status")
+			}
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			dataStore := store.New(cfg)
+			project := cmd.String("project")
+
+			var catalog []models.Directory
+			if len(slugs) > 0 {
+				cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+				if catalog, err = cacheClient.GetDirectories(ctx, false); err != nil {
+					return fmt.Errorf("failed to get directories: %w", err)
+				}
+			}
+
+			submissions, err := dataStore.Submissions()
+			if err != nil {
+				return err
+			}
+			changes := planStatusChanges(submissions, catalog, project, status, slugs, whereStatus, cmd.Bool("force"))
+
+			changed := printStatusChanges(u, changes, status)
+			recordResults(ctx, changed)
+			if dryRun(cmd) {
+				u.Info("Dry run: %d submissions would move to %s in project %s", changed, status, project)
+				return nil
+			}
+			if changed == 0 {
+				u.Info("No submissions to move")
+				return nil
+			}
+
+			confirmed, err := confirmBulk(u, cmd, fmt.Sprintf("Move %d submissions to %s?", changed, status))
+			if err != nil || !confirmed {
+				return err
+			}
+
+			var saved []*models.TrackedSubmission
+			var befores []interface{}
+			err = dataStore.Transaction(func() error {
+				for _, change := range changes {
+					if change.skipped != "" {
+						continue
+					}
+					submission, err := dataStore.Submission(project, change.slug)
+					if err != nil {
+						return err
+					}
+					var before interface{}
+					if submission == nil {
+						submission = &models.TrackedSubmission{Directory: change.slug, Project: project}
+					} else {
+						before = *submission
+					}
+					if submission.Status != change.from {
+						return fmt.Errorf("%s changed meanwhile; run set-status again", change.slug)
+					}
+					submission.Status = status
+					if cmd.IsSet("notes") {
+						submission.Notes = cmd.String("notes")
+					}
+					if err := dataStore.SaveSubmission(submission); err != nil {
+						return err
+					}
+					saved = append(saved, submission)
+					befores = append(befores, before)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			for i, submission := range saved {
+				detail, from := status, ""
+				if before, ok := befores[i].(models.TrackedSubmission); ok {
+					detail, from = before.Status+" → "+status, before.Status
+				}
+				recordChange(dataStore, "submissions.set-status", project+"/"+submission.Directory, detail, befores[i])
+				notifyStatusChange(ctx, u, cfg, submission, from)
+			}
+
+			u.Success("Moved %d submissions to %s in project %s", changed, status, project)
+			return nil
+		},
+	}
+}
+
+// planStatusChanges decides which submissions of project move to status.
+// Submissions are selected by slug when slugs are given, and by current
+// status when whereStatus is.
+func planStatusChanges(submissions []models.TrackedSubmission, catalog []models.Directory, project, status string, slugs, whereStatus []string, force bool) []statusChange {
+	tracked := make(map[string]models.TrackedSubmission)
+	for _, submission := range submissions {
+		if submission.Project == project {
+			tracked[submission.Directory] = submission
+		}
+	}
+
+	// Without slugs, every submission of the project is a candidate
+	if len(slugs) == 0 {
+		for _, submission := range submissions {
+			if submission.Project == project {
+				slugs = append(slugs, submission.Directory)
+			}
+		}
+	}
+
+	var changes []statusChange
+	seen := make(map[string]bool)
+	for _, slug := range slugs {
+		if seen[slug] {
+			continue
+		}
+		seen[slug] = true
+
+		submission, ok := tracked[slug]
+		change := statusChange{slug: slug, from: submission.Status}
+
+		switch {
+		case !ok && len(whereStatus) > 0:
+			continue
+		case ok && len(whereStatus) > 0 && !containsFold(whereStatus, submission.Status):
+			continue
+		case !ok && findDirectory(catalog, slug) == nil:
+			change.skipped = "not in the catalog"
+		case change.from == status:
+			change.skipped = "already " + status
+		case !models.CanTransition(change.from, status):
+			change.skipped = fmt.Sprintf("cannot go from %s to %s", change.from, status)
+		case !ok && !force && len(store.SubmittedElsewhere(submissions, project, slug)) > 0:
+			change.skipped = "already submitted in another project (use --force)"
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// printStatusChanges prints the changes and the skipped submissions with
+// why, returning how many submissions change
+func printStatusChanges(u *ui.UI, changes []statusChange, status string) int {
+	changed := 0
+	table := u.CreateTable([]string{"Directory", "Change"})
+	var skipped []statusChange
+	for _, change := range changes {
+		if change.skipped != "" {
+			skipped = append(skipped, change)
+			continue
+		}
+		from := change.from
+		if from == "" {
+			from = "untracked"
+		}
+		table.Row(change.slug, from+" → "+status)
+		changed++
+	}
+
+	if changed > 0 {
+		u.Println(table)
+	}
+	if len(skipped) > 0 {
+		u.Warning("Skipped %d:", len(skipped))
+		for _, change := range skipped {
+			u.Printf("  %s: %s\n", change.slug, change.skipped)
+		}
+		u.Println()
+	}
+	return changed
+}
+
+// readSlugs reads directory slugs from a file or, for "-", stdin: the first
+// word of each line, skipping blank lines and # comments
+func readSlugs(u *ui.UI, path string) ([]string, error) {
+	var r io.Reader = u.In
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s: %w", path, err)
+		}
+		defer func() { _ = file.Close() }()
+		r = file
+	}
+
+	var slugs []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		slug := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ','
+		})[0]
+		slugs = append(slugs, slug)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return slugs, nil
+}
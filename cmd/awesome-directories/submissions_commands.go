@@ -0,0 +1,674 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/internal/webhook"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// projectFlag returns the flag selecting the project submissions are tracked in
+func projectFlag() cli.Flag {
+	return &cli.StringFlag{
+		Name:  "project",
+		Usage: "Project the submissions belong to",
+		Value: models.DefaultProject,
+	}
+}
+
+// submissionsCommand creates the submissions command
+func submissionsCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "submissions",
+		Aliases: []string{"sub"},
+		Usage:   "Track directory submissions",
+		Commands: []*cli.Command{
+			{
+				Name:  "list",
+				Usage: "List tracked submissions",
+				Metadata: examples(
+					"awesome-directories submissions list",
+					"awesome-directories submissions list --project acme --status submitted",
+					"awesome-directories submissions list --all-projects",
+					"awesome-directories submissions list --with-todos",
+				),
+				Flags: []cli.Flag{
+					projectFlag(),
+					&cli.BoolFlag{
+						Name:  "all-projects",
+						Usage: "List submissions of every project",
+					},
+					&cli.StringSliceFlag{
+						Name:  "status",
+						Usage: "Only list submissions with this status",
+					},
+					&cli.BoolFlag{
+						Name:  "with-todos",
+						Usage: "Show the todo items of each submission",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					dataStore, err := openStore()
+					if err != nil {
+						return err
+					}
+
+					submissions, err := dataStore.Submissions()
+					if err != nil {
+						return err
+					}
+
+					var listed []models.TrackedSubmission
+					for _, submission := range submissions {
+						if !cmd.Bool("all-projects") && submission.Project != cmd.String("project") {
+							continue
+						}
+						if statuses := cmd.StringSlice("status"); len(statuses) > 0 && !containsFold(statuses, submission.Status) {
+							continue
+						}
+						listed = append(listed, submission)
+					}
+					recordResults(ctx, len(listed))
+
+					if len(listed) == 0 {
+						u.Warning("No submissions tracked yet. Use 'submissions track <slug> --status <status>' to add one.")
+						return nil
+					}
+
+					table := u.CreateTable([]string{"Directory", "Project", "Product", "Status", "Todos", "Updated", "Notes"})
+					for _, submission := range listed {
+						product := submission.Product
+						if product != "" && submission.Variant != "" {
+							product += " (" + submission.Variant + ")"
+						}
+						table.Row(
+							submission.Directory,
+							submission.Project,
+							product,
+							submission.Status,
+							formatTodoProgress(submission.Todos),
+							submission.UpdatedAt.Local().Format("2006-01-02"),
+							ui.TruncateString(strings.ReplaceAll(submission.Notes, "\n", " "), 40),
+						)
+					}
+					u.Println(table)
+
+					if cmd.Bool("with-todos") {
+						for _, submission := range listed {
+							if len(submission.Todos) == 0 {
+								continue
+							}
+							u.Bold("%s (%s)", submission.Directory, submission.Project)
+							printTodos(u, submission.Todos)
+							u.Println()
+						}
+					}
+
+					u.Info("%d submissions", len(listed))
+
+					return nil
+				},
+			},
+			{
+				Name:      "track",
+				Usage:     "Track a directory submission",
+				ArgsUsage: "<slug> --status <status>",
+				Metadata: examples(
+					"awesome-directories submissions track producthunt --status submitted",
+					"awesome-directories submissions track betalist --status pending --project acme --product acme --variant b",
+					"awesome-directories submissions track producthunt --status submitted --project side-project --force",
+					"awesome-directories submissions track betalist --status approved --listing-url https://betalist.com/startups/acme",
+				),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "status",
+						Usage:    "Submission status: " + strings.Join(models.SubmissionStatuses, ", "),
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:  "notes",
+						Usage: "Add notes about this submission",
+					},
+					&cli.StringFlag{
+						Name:  "listing-url",
+						Usage: "URL of the live listing, once published",
+					},
+					projectFlag(),
+					&cli.StringFlag{
+						Name:  "product",
+						Usage: "Product profile submitted",
+					},
+					&cli.StringFlag{
+						Name:  "variant",
+						Usage: "Copy variant of the product submitted",
+					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Track the submission even if the directory was already submitted to",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("directory slug is required")
+					}
+					slug := cmd.Args().First()
+
+					status := strings.ToLower(cmd.String("status"))
+					if !models.ValidSubmissionStatus(status) {
+						return fmt.Errorf("invalid status: %s (use %s)", status, strings.Join(models.SubmissionStatuses, ", "))
+					}
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+					directories, err := cacheClient.GetDirectories(ctx, false)
+					if err != nil {
+						return fmt.Errorf("failed to get directories: %w", err)
+					}
+					directory := findDirectory(directories, slug)
+					if directory == nil {
+						return fmt.Errorf("directory not found: %s", slug)
+					}
+					// A directory renamed upstream is tracked under its new slug
+					lineage := cacheClient.Lineage()
+					slug = directory.Slug
+
+					dataStore := store.New(cfg)
+					project := cmd.String("project")
+
+					if cmd.IsSet("product") {
+						product, err := dataStore.Product(cmd.String("product"))
+						if err != nil {
+							return err
+						}
+						if _, ok := product.WithVariant(cmd.String("variant")); !ok {
+							return fmt.Errorf("unknown variant %q of %s (available: %s)", cmd.String("variant"), product.Slug, strings.Join(product.VariantNames(), ", "))
+						}
+					} else if cmd.IsSet("variant") {
+						return fmt.Errorf("--variant requires --product")
+					}
+
+					// Check for duplicates and save in one transaction, so a
+					// concurrent track cannot slip in between
+					var submission *models.TrackedSubmission
+					var oldStatus string
+					var before interface{}
+					err = dataStore.Transaction(func() error {
+						submissions, err := dataStore.Submissions()
+						if err != nil {
+							return err
+						}
+
+						// A submission tracked under a former slug is updated
+						// in place, keeping its evidence
+						for i, existing := range submissions {
+							if existing.Project == project && lineage.Current(existing.Directory, cache.BySlug) == slug {
+								submission = &submissions[i]
+								if existing.Directory == slug {
+									break
+								}
+							}
+						}
+
+						// Warn before submitting to a directory twice. Submissions
+						// already sent in this project were checked when tracked.
+						var duplicates []models.TrackedSubmission
+						if submission == nil || !submission.IsSubmitted() {
+							duplicates = store.SubmittedElsewhere(submissions, project, slug)
+						}
+						if len(duplicates) > 0 && !cmd.Bool("force") {
+							for _, duplicate := range duplicates {
+								u.Warning("%s is already %s in project %s (updated %s)", directory.Name,
+									duplicate.Status, duplicate.Project, duplicate.UpdatedAt.Local().Format("2006-01-02"))
+							}
+							return fmt.Errorf("%s was already submitted to; use --force to track it anyway", slug)
+						}
+
+						if submission == nil {
+							submission = &models.TrackedSubmission{Directory: slug, Project: project}
+						} else {
+							before = *submission
+						}
+						oldStatus = submission.Status
+						submission.Status = status
+						if cmd.IsSet("notes") {
+							submission.Notes = cmd.String("notes")
+						}
+						if cmd.IsSet("listing-url") {
+							submission.ListingURL = cmd.String("listing-url")
+						}
+						if cmd.IsSet("product") {
+							submission.Product = cmd.String("product")
+							submission.Variant = strings.ToLower(cmd.String("variant"))
+						}
+
+						if dryRun(cmd) {
+							return nil
+						}
+						return dataStore.SaveSubmission(submission)
+					})
+					if err != nil {
+						return err
+					}
+
+					detail := status
+					if oldStatus != "" && oldStatus != status {
+						detail = oldStatus + " → " + status
+					}
+					if dryRun(cmd) {
+						u.Info("Dry run: would track %s as %s in project %s", directory.Name, detail, project)
+						return nil
+					}
+					recordChange(dataStore, "submissions.track", project+"/"+submission.Directory, detail, before)
+					u.Success("Tracked %s as %s in project %s", directory.Name, status, project)
+
+					if oldStatus != status {
+						notifyStatusChange(ctx, u, cfg, submission, oldStatus)
+					}
+
+					return nil
+				},
+			},
+			{
+				Name:      "notes",
+				Usage:     "Add notes to a submission",
+				ArgsUsage: "<slug> <notes>",
+				Flags:     []cli.Flag{projectFlag()},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() < 2 {
+						return fmt.Errorf("directory slug and notes are required")
+					}
+					slug := cmd.Args().First()
+					notes := strings.Join(cmd.Args().Tail(), " ")
+
+					dataStore, submission, err := updateSubmission(cmd.String("project"), slug, dryRun(cmd), func(submission *models.TrackedSubmission) error {
+						if submission.Notes != "" {
+							submission.Notes += "\n"
+						}
+						submission.Notes += notes
+						return nil
+					})
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would add notes to %s", slug)
+						return nil
+					}
+
+					recordAudit(dataStore, "submissions.notes", submission.Project+"/"+slug, notes)
+					u.Success("Added notes to %s", slug)
+					return nil
+				},
+			},
+			submissionTodoCommand(),
+			submissionSetStatusCommand(),
+			submissionAttachCommand(),
+			submissionEvidenceCommand(),
+			submissionIngestEmailCommand(),
+			submissionBoardCommand(),
+			submissionGitHubSyncCommand(),
+			submissionPushCommand(),
+			{
+				Name:  "dupes",
+				Usage: "Audit directories tracked in more than one project",
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					dataStore, err := openStore()
+					if err != nil {
+						return err
+					}
+
+					submissions, err := dataStore.Submissions()
+					if err != nil {
+						return err
+					}
+
+					overlaps := store.Overlaps(submissions)
+					recordResults(ctx, len(overlaps))
+
+					if len(overlaps) == 0 {
+						u.Success("No directory is tracked in more than one project")
+						return nil
+					}
+
+					slugs := make([]string, 0, len(overlaps))
+					for slug := range overlaps {
+						slugs = append(slugs, slug)
+					}
+					sort.Strings(slugs)
+
+					duplicated := 0
+					table := u.CreateTable([]string{"Directory", "Projects", "Submitted"})
+					for _, slug := range slugs {
+						var projects []string
+						submitted := 0
+						for _, submission := range overlaps[slug] {
+							projects = append(projects, submission.Project+": "+submission.Status)
+							if submission.IsSubmitted() {
+								submitted++
+							}
+						}
+
+						mark := "-"
+						if submitted > 1 {
+							mark = fmt.Sprintf("%d times", submitted)
+							duplicated++
+						}
+						table.Row(slug, strings.Join(projects, ", "), mark)
+					}
+					u.Println(table)
+
+					u.Info("%d directories are tracked in more than one project", len(overlaps))
+					if duplicated > 0 {
+						u.Warning("%d directories were submitted to more than once", duplicated)
+					}
+
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// submissionTodoCommand creates the submissions todo command
+func submissionTodoCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "todo",
+		Usage: "Manage the preparation steps of a submission",
+		Commands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     "Add a todo item to a submission",
+				ArgsUsage: "<slug> <text>",
+				Metadata: examples(
+					"awesome-directories submissions todo add producthunt \"prepare 512px logo\"",
+				),
+				Flags: []cli.Flag{projectFlag()},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() < 2 {
+						return fmt.Errorf("directory slug and todo text are required")
+					}
+					slug := cmd.Args().First()
+
+					dataStore, submission, err := updateSubmission(cmd.String("project"), slug, dryRun(cmd), func(submission *models.TrackedSubmission) error {
+						submission.Todos = append(submission.Todos, models.Todo{Text: strings.Join(cmd.Args().Tail(), " ")})
+						return nil
+					})
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would add todo %d to %s", len(submission.Todos), slug)
+						return nil
+					}
+
+					recordAudit(dataStore, "submissions.todo.add", submission.Project+"/"+slug, submission.Todos[len(submission.Todos)-1].Text)
+					u.Success("Added todo %d to %s", len(submission.Todos), slug)
+					return nil
+				},
+			},
+			{
+				Name:      "done",
+				Usage:     "Mark a todo item as done",
+				ArgsUsage: "<slug> <number>",
+				Metadata: examples(
+					"awesome-directories submissions todo done producthunt 1",
+				),
+				Flags: []cli.Flag{
+					projectFlag(),
+					&cli.BoolFlag{
+						Name:  "undo",
+						Usage: "Mark the item as not done",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					dataStore, submission, index, err := updateSubmissionTodo(cmd, func(submission *models.TrackedSubmission, index int) {
+						todo := &submission.Todos[index]
+						if cmd.Bool("undo") {
+							todo.Done, todo.DoneAt = false, nil
+						} else {
+							now := time.Now().UTC()
+							todo.Done, todo.DoneAt = true, &now
+						}
+					})
+					if err != nil {
+						return err
+					}
+					todo := submission.Todos[index]
+
+					if dryRun(cmd) {
+						state := "done"
+						if !todo.Done {
+							state = "not done"
+						}
+						u.Info("Dry run: would mark todo %d of %s as %s", index+1, submission.Directory, state)
+						return nil
+					}
+
+					action := "submissions.todo.done"
+					if cmd.Bool("undo") {
+						action = "submissions.todo.undo"
+					}
+					recordAudit(dataStore, action, submission.Project+"/"+submission.Directory, todo.Text)
+					u.Success("%s: %d of %d todos done", submission.Directory,
+						len(submission.Todos)-submission.OpenTodos(), len(submission.Todos))
+					return nil
+				},
+			},
+			{
+				Name:      "remove",
+				Aliases:   []string{"rm"},
+				Usage:     "Remove a todo item",
+				ArgsUsage: "<slug> <number>",
+				Flags:     []cli.Flag{projectFlag()},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					var text string
+					dataStore, submission, _, err := updateSubmissionTodo(cmd, func(submission *models.TrackedSubmission, index int) {
+						text = submission.Todos[index].Text
+						submission.Todos = append(submission.Todos[:index], submission.Todos[index+1:]...)
+					})
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would remove todo %q from %s", text, submission.Directory)
+						return nil
+					}
+
+					recordAudit(dataStore, "submissions.todo.remove", submission.Project+"/"+submission.Directory, text)
+					u.Success("Removed %q from %s", text, submission.Directory)
+					return nil
+				},
+			},
+			{
+				Name:      "list",
+				Usage:     "List the todo items of a submission",
+				ArgsUsage: "<slug>",
+				Flags:     []cli.Flag{projectFlag()},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("directory slug is required")
+					}
+
+					dataStore, err := openStore()
+					if err != nil {
+						return err
+					}
+
+					submission, err := trackedSubmission(dataStore, cmd.String("project"), cmd.Args().First())
+					if err != nil {
+						return err
+					}
+
+					if len(submission.Todos) == 0 {
+						u.Warning("No todos for %s. Use 'submissions todo add' to add one.", submission.Directory)
+						return nil
+					}
+
+					printTodos(u, submission.Todos)
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// trackedSubmission loads the submission to a directory tracked in a project
+func trackedSubmission(dataStore *store.Store, project, slug string) (*models.TrackedSubmission, error) {
+	submission, err := dataStore.Submission(project, slug)
+	if err != nil {
+		return nil, err
+	}
+	if submission == nil {
+		return nil, fmt.Errorf("%s is not tracked in project %s; use 'submissions track' first", slug, project)
+	}
+	return submission, nil
+}
+
+// notifyStatusChange calls the configured status webhook, if any, about a
+// submission that moved from oldStatus to its current status
+func notifyStatusChange(ctx context.Context, u *ui.UI, cfg *config.Config, submission *models.TrackedSubmission, oldStatus string) {
+	if cfg.WebhookURL == "" {
+		return
+	}
+
+	event := webhook.StatusChanged{
+		Event:     webhook.StatusChangedEvent,
+		Directory: submission.Directory,
+		Project:   submission.Project,
+		Product:   submission.Product,
+		OldStatus: oldStatus,
+		NewStatus: submission.Status,
+		Notes:     submission.Notes,
+		CreatedAt: submission.CreatedAt,
+		ChangedAt: submission.UpdatedAt,
+	}
+	if err := webhook.Send(ctx, cfg.WebhookURL, cfg.WebhookSecret, event); err != nil {
+		u.Warning("Failed to call the status webhook: %v", err)
+	}
+}
+
+// updateSubmission applies fn to a tracked submission and saves it, within
+// a store transaction so concurrent updates are not lost. With preview the
+// updated submission is returned without saving it.
+func updateSubmission(project, slug string, preview bool, fn func(*models.TrackedSubmission) error) (*store.Store, *models.TrackedSubmission, error) {
+	dataStore, err := openStore()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var submission *models.TrackedSubmission
+	err = dataStore.Transaction(func() error {
+		if submission, err = trackedSubmission(dataStore, project, slug); err != nil {
+			return err
+		}
+		if err := fn(submission); err != nil {
+			return err
+		}
+		if preview {
+			return nil
+		}
+		return dataStore.SaveSubmission(submission)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dataStore, submission, nil
+}
+
+// updateSubmissionTodo applies fn to the todo given as <slug> <number> and
+// saves the submission, returning the todo index
+func updateSubmissionTodo(cmd *cli.Command, fn func(submission *models.TrackedSubmission, index int)) (*store.Store, *models.TrackedSubmission, int, error) {
+	if cmd.Args().Len() < 2 {
+		return nil, nil, 0, fmt.Errorf("directory slug and todo number are required")
+	}
+
+	number, err := strconv.Atoi(cmd.Args().Get(1))
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("invalid todo number: %s", cmd.Args().Get(1))
+	}
+
+	dataStore, submission, err := updateSubmission(cmd.String("project"), cmd.Args().First(), dryRun(cmd), func(submission *models.TrackedSubmission) error {
+		if number < 1 || number > len(submission.Todos) {
+			return fmt.Errorf("%s has no todo %d (it has %d)", submission.Directory, number, len(submission.Todos))
+		}
+		fn(submission, number-1)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+
+	return dataStore, submission, number - 1, nil
+}
+
+// printTodos prints numbered todo items with their state
+func printTodos(u *ui.UI, todos []models.Todo) {
+	for i, todo := range todos {
+		if todo.Done {
+			u.Muted("  [x] %d. %s", i+1, todo.Text)
+		} else {
+			u.Printf("  [ ] %d. %s\n", i+1, todo.Text)
+		}
+	}
+}
+
+// formatTodoProgress formats how many todos are done, "-" without todos
+func formatTodoProgress(todos []models.Todo) string {
+	if len(todos) == 0 {
+		return "-"
+	}
+
+	done := 0
+	for _, todo := range todos {
+		if todo.Done {
+			done++
+		}
+	}
+	return fmt.Sprintf("%d/%d", done, len(todos))
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,468 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/digest"
+	"github.com/awesome-directories/cli/internal/mail"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/internal/webhook"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// Digest destinations besides Markdown files
+const (
+	deliverTerminal = "terminal"
+	deliverWebhook  = "webhook"
+)
+
+// subscribeCommand creates the subscribe command
+func subscribeCommand() *cli.Command {
+	return &cli.Command{
+		Name:    "subscribe",
+		Aliases: []string{"subscriptions"},
+		Usage:   "Get periodic digests of new and changed directories matching saved searches",
+		Description: `A subscription follows a preset from config.yaml. Its digest lists the
+directories that started matching the search, changed or were removed since
+the previous digest.
+
+'subscribe digest' sends the digests that are due, for a cron job; 'watch'
+sends them as they fall due too.`,
+		Commands: []*cli.Command{
+			{
+				Name:      "add",
+				Usage:     "Subscribe to a preset",
+				ArgsUsage: "<preset>",
+				Metadata: examples(
+					"awesome-directories subscribe add high-dr --digest weekly",
+					"awesome-directories subscribe add eu-b2b --digest daily --to webhook",
+					"awesome-directories subscribe add high-dr --name high-dr-file --to ~/digests/high-dr.md",
+					"awesome-directories subscribe add high-dr --name high-dr-mail --to me@acme.dev",
+				),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "name",
+						Usage: "Name of the subscription (default: the preset name)",
+					},
+					&cli.StringFlag{
+						Name:  "digest",
+						Usage: "How often to send the digest: daily or weekly",
+						Value: models.DigestWeekly,
+					},
+					&cli.StringFlag{
+						Name:  "to",
+						Usage: "Where to send the digest: terminal, webhook, an email address, or a Markdown file path",
+						Value: deliverTerminal,
+					},
+					snapshotFlag(),
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("preset name is required")
+					}
+					presetName := cmd.Args().First()
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					preset, err := cfg.Preset(presetName)
+					if err != nil {
+						return err
+					}
+					if _, err := models.DigestPeriod(cmd.String("digest")); err != nil {
+						return err
+					}
+					deliver, err := digestDestination(cfg, cmd.String("to"))
+					if err != nil {
+						return err
+					}
+
+					name := cmd.String("name")
+					if name == "" {
+						name = presetName
+					}
+
+					// The first digest reports changes from now on
+					directories, err := loadDirectories(ctx, cmd, cache.NewCache(cfg, api.NewClient(cfg)))
+					if err != nil {
+						return err
+					}
+					var seen []models.Directory
+					for _, dir := range directories {
+						if cache.Matches(dir, preset.FilterOptions()) {
+							seen = append(seen, dir)
+						}
+					}
+
+					subscription := &models.Subscription{
+						Name:    name,
+						Preset:  presetName,
+						Digest:  cmd.String("digest"),
+						Deliver: deliver,
+						Seen:    seen,
+					}
+
+					dataStore := store.New(cfg)
+					err = dataStore.Transaction(func() error {
+						if _, err := dataStore.Subscription(name); err == nil {
+							return fmt.Errorf("subscription already exists: %s (remove it first, or choose another --name)", name)
+						}
+						if dryRun(cmd) {
+							return nil
+						}
+						return dataStore.SaveSubscription(subscription)
+					})
+					if err != nil {
+						return err
+					}
+
+					if dryRun(cmd) {
+						u.Info("Dry run: would subscribe %s to preset %s (%s digest to %s, %d directories match now)",
+							name, presetName, subscription.Digest, deliver, len(seen))
+						return nil
+					}
+
+					recordAudit(dataStore, "subscription.add", name, presetName)
+					u.Success("Subscribed %s to preset %s: %s digest to %s", name, presetName, subscription.Digest, deliver)
+					u.Muted("%d directories match now; the first digest is due %s", len(seen), subscription.NextDigest().Local().Format("2006-01-02 15:04"))
+					return nil
+				},
+			},
+			{
+				Name:  "list",
+				Usage: "List subscriptions",
+				Metadata: examples(
+					"awesome-directories subscribe list",
+				),
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Output the subscriptions as JSON",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+
+					subscriptions, err := store.New(cfg).Subscriptions()
+					if err != nil {
+						return err
+					}
+					recordResults(ctx, len(subscriptions))
+
+					if cmd.Bool("json") {
+						for i := range subscriptions {
+							subscriptions[i].Seen = nil
+						}
+						return printJSON(u, subscriptions)
+					}
+
+					if len(subscriptions) == 0 {
+						u.Info("No subscriptions yet; create one with 'awesome-directories subscribe add <preset>'")
+						return nil
+					}
+
+					table := u.CreateTable([]string{"Name", "Preset", "Digest", "To", "Last Digest", "Next Digest"})
+					for _, subscription := range subscriptions {
+						last := "-"
+						if !subscription.LastDigest.IsZero() {
+							last = subscription.LastDigest.Local().Format("2006-01-02 15:04")
+						}
+						table.Row(subscription.Name, subscription.Preset, subscription.Digest, subscription.Deliver,
+							last, subscription.NextDigest().Local().Format("2006-01-02 15:04"))
+					}
+					u.Println(table.String())
+
+					return nil
+				},
+			},
+			{
+				Name:      "remove",
+				Aliases:   []string{"rm"},
+				Usage:     "Remove a subscription",
+				ArgsUsage: "<name>",
+				Metadata: examples(
+					"awesome-directories subscribe remove high-dr",
+				),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					if cmd.Args().Len() == 0 {
+						return fmt.Errorf("subscription name is required")
+					}
+					name := cmd.Args().First()
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+					dataStore := store.New(cfg)
+
+					if dryRun(cmd) {
+						if _, err := dataStore.Subscription(name); err != nil {
+							return err
+						}
+						u.Info("Dry run: would remove subscription %s", name)
+						return nil
+					}
+
+					if err := dataStore.DeleteSubscription(name); err != nil {
+						return err
+					}
+
+					recordAudit(dataStore, "subscription.remove", name, "")
+					u.Success("Removed subscription %s", name)
+					return nil
+				},
+			},
+			{
+				Name:      "digest",
+				Usage:     "Send the digests that are due",
+				ArgsUsage: "[name...]",
+				Description: `Sends the digest of each subscription whose period has elapsed, or of
+the named ones, then remembers the matching directories for the next one.
+Digests with no changes are only shown in the terminal. Run it from cron,
+e.g. every morning:
+
+   0 8 * * * awesome-directories subscribe digest`,
+				Metadata: examples(
+					"awesome-directories subscribe digest",
+					"awesome-directories subscribe digest high-dr --force",
+					"awesome-directories subscribe digest --dry-run",
+				),
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Send the digests even if they are not due yet",
+					},
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Show the digests in the terminal without sending them or starting a new period",
+					},
+				},
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					u := ui.FromContext(ctx)
+
+					cfg, err := config.Load()
+					if err != nil {
+						return fmt.Errorf("failed to load config: %w", err)
+					}
+					dataStore := store.New(cfg)
+
+					var subscriptions []models.Subscription
+					if cmd.Args().Len() > 0 {
+						for _, name := range cmd.Args().Slice() {
+							subscription, err := dataStore.Subscription(name)
+							if err != nil {
+								return err
+							}
+							subscriptions = append(subscriptions, *subscription)
+						}
+					} else if subscriptions, err = dataStore.Subscriptions(); err != nil {
+						return err
+					}
+
+					if len(subscriptions) == 0 {
+						u.Info("No subscriptions yet; create one with 'awesome-directories subscribe add <preset>'")
+						return nil
+					}
+
+					directories, err := cache.NewCache(cfg, api.NewClient(cfg)).GetDirectories(ctx, false)
+					if err != nil {
+						return fmt.Errorf("failed to get directories: %w", err)
+					}
+
+					sent, err := sendDigests(ctx, u, cfg, subscriptions, directories, digestOptions{
+						force:  cmd.Bool("force") || cmd.Args().Len() > 0,
+						dryRun: dryRun(cmd),
+					})
+					recordResults(ctx, sent)
+					if err != nil {
+						return err
+					}
+
+					if sent == 0 {
+						u.Info("No digest is due yet; see 'subscribe list' for when they are")
+					}
+					return nil
+				},
+			},
+		},
+	}
+}
+
+// digestDestination checks where a digest goes: the terminal, the webhook,
+// an email address, or a Markdown file, returned as an absolute path since
+// digests may be sent from another directory
+func digestDestination(cfg *config.Config, to string) (string, error) {
+	switch to {
+	case deliverTerminal:
+		return to, nil
+	case deliverWebhook:
+		if cfg.WebhookURL == "" {
+			return "", fmt.Errorf("no webhook configured: set webhook_url in config.yaml or WEBHOOK_URL")
+		}
+		return to, nil
+	}
+
+	if isEmailDestination(to) {
+		if !mailConfig(cfg).Configured() {
+			return "", fmt.Errorf("no SMTP server configured: set smtp.server in config.yaml or SMTP_SERVER")
+		}
+		return mail.ParseAddress(to)
+	}
+	if !strings.HasSuffix(strings.ToLower(to), ".md") {
+		return "", fmt.Errorf("invalid digest destination %q: use terminal, webhook, an email address or a .md file path", to)
+	}
+	path, err := filepath.Abs(to)
+	if err != nil {
+		return "", fmt.Errorf("invalid digest file path: %w", err)
+	}
+	return path, nil
+}
+
+// isEmailDestination reports whether a digest goes to an email address
+// rather than a file
+func isEmailDestination(to string) bool {
+	return strings.Contains(to, "@") && !strings.HasSuffix(strings.ToLower(to), ".md")
+}
+
+// digestOptions control which digests sendDigests sends and whether it
+// only shows them
+type digestOptions struct {
+	force  bool
+	dryRun bool
+}
+
+// sendDigests sends the due digests of subscriptions, or all of them with
+// force, and starts their next period. It returns how many it sent.
+func sendDigests(ctx context.Context, u *ui.UI, cfg *config.Config, subscriptions []models.Subscription, directories []models.Directory, opts digestOptions) (int, error) {
+	dataStore := store.New(cfg)
+	now := time.Now().UTC()
+
+	sent := 0
+	var failed []string
+	for _, subscription := range subscriptions {
+		if !opts.force && now.Before(subscription.NextDigest()) {
+			continue
+		}
+
+		preset, err := cfg.Preset(subscription.Preset)
+		if err != nil {
+			u.Error("%s: %v", subscription.Name, err)
+			failed = append(failed, subscription.Name)
+			continue
+		}
+		options := preset.FilterOptions()
+		d := digest.Build(subscription, directories, func(dir models.Directory) bool { return cache.Matches(dir, options) }, now)
+
+		if opts.dryRun {
+			printDigest(u, d)
+			u.Muted("Dry run: would send to %s", subscription.Deliver)
+			sent++
+			continue
+		}
+
+		if err := deliverDigest(ctx, u, cfg, subscription, d); err != nil {
+			u.Error("%s: failed to send digest: %v", subscription.Name, err)
+			failed = append(failed, subscription.Name)
+			continue
+		}
+
+		subscription.LastDigest = now
+		subscription.Seen = d.Matching
+		if err := dataStore.SaveSubscription(&subscription); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	if len(failed) > 0 {
+		return sent, fmt.Errorf("failed to send the digest of %s", strings.Join(failed, ", "))
+	}
+	return sent, nil
+}
+
+// deliverDueDigests sends the digests that fell due while watching.
+// Failures are logged, so watching goes on.
+func deliverDueDigests(ctx context.Context, u *ui.UI, cfg *config.Config, directories []models.Directory) {
+	subscriptions, err := store.New(cfg).Subscriptions()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load subscriptions")
+		return
+	}
+	if _, err := sendDigests(ctx, u, cfg, subscriptions, directories, digestOptions{}); err != nil {
+		log.Warn().Err(err).Str("event", "watch_digest").Msg("Failed to send digests")
+	}
+}
+
+// deliverDigest sends a digest where its subscription asks. Digests with no
+// changes are only shown in the terminal.
+func deliverDigest(ctx context.Context, u *ui.UI, cfg *config.Config, subscription models.Subscription, d *digest.Digest) error {
+	if subscription.Deliver == deliverTerminal || subscription.Deliver == "" {
+		printDigest(u, d)
+		return nil
+	}
+	if d.Empty() {
+		u.Muted("%s: nothing changed since %s", subscription.Name, d.Since.Local().Format("2006-01-02"))
+		return nil
+	}
+
+	switch {
+	case isEmailDestination(subscription.Deliver):
+		err := mail.Send(ctx, mailConfig(cfg), mail.Message{
+			To:      []string{subscription.Deliver},
+			Subject: fmt.Sprintf("%s (%s)", d.Title(), d.Summary()),
+			Text:    string(d.Markdown()),
+		})
+		if err != nil {
+			return err
+		}
+		u.Success("Emailed the %s digest to %s (%s)", subscription.Name, subscription.Deliver, d.Summary())
+	case subscription.Deliver == deliverWebhook:
+		if cfg.WebhookURL == "" {
+			return fmt.Errorf("no webhook configured")
+		}
+		if err := webhook.Send(ctx, cfg.WebhookURL, cfg.WebhookSecret, d); err != nil {
+			return err
+		}
+		u.Success("Sent the %s digest to the webhook (%s)", subscription.Name, d.Summary())
+	default:
+		if err := os.MkdirAll(filepath.Dir(subscription.Deliver), 0755); err != nil {
+			return fmt.Errorf("failed to create digest directory: %w", err)
+		}
+		if err := os.WriteFile(subscription.Deliver, d.Markdown(), 0644); err != nil {
+			return fmt.Errorf("failed to write digest: %w", err)
+		}
+		u.Success("Wrote the %s digest to %s (%s)", subscription.Name, subscription.Deliver, d.Summary())
+	}
+	return nil
+}
+
+// printDigest shows a digest in the terminal
+func printDigest(u *ui.UI, d *digest.Digest) {
+	u.Bold("%s", d.Title())
+	u.Muted("Preset %s, %s to %s: %s", d.Preset, d.Since.Local().Format("2006-01-02"), d.Until.Local().Format("2006-01-02"), d.Summary())
+	displayChanges(u, d.Changes)
+	u.Println()
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/demo"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// tourStep is a command run by the tour, with what it shows
+type tourStep struct {
+	title       string
+	explanation string
+	args        []string
+
+	// preview is a file written by the step, shown after it runs
+	preview string
+}
+
+// tourSteps walk through a typical launch, from finding directories to
+// exporting a plan. "{dir}" in arguments is the tour's scratch directory.
+var tourSteps = []tourStep{
+	{
+		title: "Download the catalog",
+		explanation: `The CLI keeps a copy of every directory on your computer, so searching
+and filtering are instant and work offline. 'sync' refreshes that copy; it
+also happens automatically once a day.`,
+		args: []string{"sync"},
+	},
+	{
+		title: "Search by name or description",
+		explanation: `Looking for places to list an AI product? Search matches directory names
+and descriptions.`,
+		args: []string{"search", "ai tools"},
+	},
+	{
+		title: "Filter by what matters to you",
+		explanation: `Filters narrow the catalog down. Here: free directories with a dofollow
+link and a domain rating (DR) of at least 50, which pass the most SEO value.
+See 'help filters' for every filter.`,
+		args: []string{"filter", "--pricing", "free", "--link-type", "dofollow", "--dr-min", "50"},
+	},
+	{
+		title: "Look at one directory",
+		explanation: `'show' gives everything known about a directory, including where to
+submit and how long reviews usually take.`,
+		args: []string{"show", "launch-ledger"},
+	},
+	{
+		title: "Keep a shortlist",
+		explanation: `Favorites are saved to your account, so they follow you to the website.
+(In the tour they only last until it ends.)`,
+		args: []string{"favorites", "add", "launch-ledger"},
+	},
+	{
+		args: []string{"favorites", "list"},
+	},
+	{
+		title: "Plan your launch",
+		explanation: `'plan' picks the directories worth the most within a budget: here the
+best 8 for at most $60 of listing fees, spread over the coming days.`,
+		args: []string{"plan", "--max-count", "8", "--budget", "60", "--currency", "USD", "--schedule", "--per-day", "3"},
+	},
+	{
+		title: "Export to a spreadsheet",
+		explanation: `Any list can be exported: CSV for spreadsheets, Markdown for docs, JSON
+for scripts. Here the free directories go to a CSV file.`,
+		args:    []string{"export", "-f", "csv", "-o", "{dir}/free-directories.csv", "--pricing", "free"},
+		preview: "free-directories.csv",
+	},
+}
+
+// tourCommand creates the tour command
+func tourCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "tour",
+		Usage: "Walk through the main commands step by step, using demo data",
+		Metadata: examples(
+			"awesome-directories tour",
+			"awesome-directories tour --no-pause",
+		),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "no-pause",
+				Usage: "Run every step without waiting for Enter",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			dir, cleanup, err := demoSandbox()
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+
+			u.Bold("Welcome to the awesome-directories tour!")
+			u.Println()
+			u.Println("You'll run the commands a launch usually needs, one at a time, on a small")
+			u.Println("catalog of made-up directories. Nothing touches your account, your data or")
+			u.Println("the real catalog.")
+
+			pause := !cmd.Bool("no-pause") && isInteractive(u)
+			input := bufio.NewReader(u.In)
+
+			number := 0
+			for _, step := range tourSteps {
+				args := make([]string, len(step.args))
+				for j, arg := range step.args {
+					args[j] = strings.ReplaceAll(arg, "{dir}", dir)
+				}
+
+				if step.title != "" {
+					number++
+					u.Println()
+					u.Bold("Step %d: %s", number, step.title)
+					u.Println(step.explanation)
+				}
+				u.Println()
+				u.Info("$ awesome-directories %s", strings.Join(tourArgs(args, dir), " "))
+
+				if pause {
+					fmt.Fprint(u.Err, "Press Enter to run it, or q to quit: ")
+					line, err := input.ReadString('\n')
+					if err != nil || strings.TrimSpace(strings.ToLower(line)) == "q" {
+						u.Println()
+						u.Muted("Tour stopped. Run 'awesome-directories tour' to start again.")
+						return nil
+					}
+				}
+				u.Println()
+
+				if err := newApp().Run(ctx, append([]string{"awesome-directories"}, args...)); err != nil {
+					return fmt.Errorf("tour step %q failed: %w", strings.Join(args, " "), err)
+				}
+
+				if step.preview != "" {
+					data, err := os.ReadFile(filepath.Join(dir, step.preview))
+					if err != nil {
+						return fmt.Errorf("failed to read %s: %w", step.preview, err)
+					}
+					lines := strings.SplitN(string(data), "\n", 5)
+					u.Muted("First lines of %s:", step.preview)
+					u.Println(strings.Join(lines[:min(len(lines), 4)], "\n"))
+				}
+			}
+
+			u.Println()
+			u.Success("That's the tour!")
+			u.Println("Run the same commands without the demo data to work with the real catalog:")
+			u.Println("  awesome-directories sync")
+			u.Println("  awesome-directories auth login       # for favorites")
+			u.Println("  awesome-directories help             # every command and guide")
+			return nil
+		},
+	}
+}
+
+// tourArgs returns arguments as shown to the user: the scratch directory is
+// hidden and arguments with spaces are quoted
+func tourArgs(args []string, dir string) []string {
+	shown := make([]string, len(args))
+	for i, arg := range args {
+		arg = strings.ReplaceAll(arg, dir+string(filepath.Separator), "")
+		if strings.ContainsAny(arg, " \t") {
+			arg = fmt.Sprintf("%q", arg)
+		}
+		shown[i] = arg
+	}
+	return shown
+}
+
+// demoSandbox points the configuration at a demo server and scratch config
+// and data directories, so commands run on the demo catalog without reading
+// or changing the user's own. cleanup stops the server, removes the scratch
+// directory and restores the environment.
+func demoSandbox() (dir string, cleanup func(), err error) {
+	dir, err = os.MkdirTemp("", "awesome-directories-demo-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create demo directory: %w", err)
+	}
+
+	server, err := demo.Start()
+	if err != nil {
+		_ = os.RemoveAll(dir)
+		return "", nil, err
+	}
+
+	// Settings from the user's environment, such as a webhook or a shared
+	// state directory, must not apply to the demo
+	saved := make(map[string]*string)
+	setenv := func(name, value string) {
+		if _, done := saved[name]; !done {
+			if old, ok := os.LookupEnv(name); ok {
+				saved[name] = &old
+			} else {
+				saved[name] = nil
+			}
+		}
+		if value == "" {
+			_ = os.Unsetenv(name)
+		} else {
+			_ = os.Setenv(name, value)
+		}
+	}
+	for _, name := range config.EnvNames() {
+		setenv(name, "")
+	}
+	setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+	setenv("SUPABASE_URL", server.URL)
+	setenv("SUPABASE_ANON_KEY", demo.AnonKey)
+	setenv("AUTH_TOKEN", demo.Token)
+
+	cleanup = func() {
+		if err := server.Close(); err != nil {
+			ui.Warning("Failed to stop the demo server: %v", err)
+		}
+		for name, value := range saved {
+			if value == nil {
+				_ = os.Unsetenv(name)
+			} else {
+				_ = os.Setenv(name, *value)
+			}
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			ui.Warning("Failed to remove %s: %v", dir, err)
+		}
+	}
+	return dir, cleanup, nil
+}
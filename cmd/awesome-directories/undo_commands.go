@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// undoAction is the audit action of an undo, whose target is the run undone
+const undoAction = "undo"
+
+// undoCommand creates the undo command
+func undoCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "undo",
+		Usage: "Revert the last change to submissions, collections or favorites",
+		Description: `Reverts every change made by the last command that changed submissions
+(track, set-status), collections or favorites, using the records kept in
+the audit log. Running it again reverts the command before that one.
+
+When something it would revert was changed again since, such as the notes
+of a submission, undo stops unless --force is given.`,
+		Metadata: examples(
+			"awesome-directories undo --dry-run",
+			"awesome-directories undo",
+		),
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Show what would be reverted without changing anything",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Revert even when the records were changed again since",
+			},
+			yesFlag(),
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			dataStore := store.New(cfg)
+
+			entries, err := dataStore.AuditLog(time.Time{})
+			if err != nil {
+				return err
+			}
+
+			changes, later := lastChange(entries)
+			if len(changes) == 0 {
+				u.Info("Nothing to undo")
+				return nil
+			}
+
+			u.Printf("Changes made %s by %s:\n\n", changes[0].Time.Local().Format("2006-01-02 15:04:05"), changes[0].User)
+			table := u.CreateTable([]string{"Action", "Target", "Detail"})
+			for _, change := range changes {
+				table.Row(change.Action, change.Target, ui.TruncateString(change.Detail, 50))
+			}
+			u.Println(table)
+
+			if conflicts := changedSince(changes, later); len(conflicts) > 0 && !cmd.Bool("force") {
+				for _, entry := range conflicts {
+					u.Warning("%s was changed again by %s on %s", entry.Target, entry.Action, entry.Time.Local().Format("2006-01-02 15:04"))
+				}
+				return fmt.Errorf("records were changed since; use --force to revert them anyway")
+			}
+
+			if dryRun(cmd) {
+				u.Info("Dry run: %d changes would be reverted", len(changes))
+				return nil
+			}
+			confirmed, err := confirmBulk(u, cmd, fmt.Sprintf("Revert %d changes?", len(changes)))
+			if err != nil || !confirmed {
+				return err
+			}
+
+			// Revert newest first, so a record changed twice ends up as it
+			// was before the first change
+			for i := len(changes) - 1; i >= 0; i-- {
+				if err := revertChange(ctx, cfg, dataStore, changes[i]); err != nil {
+					return fmt.Errorf("failed to revert %s of %s: %w", changes[i].Action, changes[i].Target, err)
+				}
+			}
+
+			recordAudit(dataStore, undoAction, changes[0].Run, fmt.Sprintf("%d changes by %s", len(changes), changes[0].Action))
+			u.Success("Reverted %d changes", len(changes))
+			return nil
+		},
+	}
+}
+
+// lastChange returns the undoable entries of the most recent run that was not
+// undone yet, along with the entries recorded after them
+func lastChange(entries []store.AuditEntry) (changes, later []store.AuditEntry) {
+	undone := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Action == undoAction {
+			undone[entry.Target] = true
+		}
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if entry.Run == "" || undone[entry.Run] || !entry.Undoable() {
+			continue
+		}
+
+		for _, other := range entries[:i+1] {
+			if other.Run == entry.Run && other.Undoable() {
+				changes = append(changes, other)
+			}
+		}
+		for _, next := range entries[i+1:] {
+			if next.Action != undoAction && !undone[next.Run] {
+				later = append(later, next)
+			}
+		}
+		return changes, later
+	}
+	return nil, nil
+}
+
+// changedSince returns the later entries that changed a record among changes
+func changedSince(changes, later []store.AuditEntry) []store.AuditEntry {
+	targets := make(map[string]bool)
+	for _, change := range changes {
+		targets[change.Target] = true
+	}
+
+	var conflicts []store.AuditEntry
+	for _, entry := range later {
+		if targets[entry.Target] {
+			conflicts = append(conflicts, entry)
+		}
+	}
+	return conflicts
+}
+
+// revertChange restores the record changed by an audit entry
+func revertChange(ctx context.Context, cfg *config.Config, dataStore *store.Store, change store.AuditEntry) error {
+	area, _, _ := strings.Cut(change.Action, ".")
+	switch area {
+	case "submissions":
+		project, slug, _ := strings.Cut(change.Target, "/")
+		if change.Created {
+			return dataStore.DeleteSubmission(project, slug)
+		}
+		var submission models.TrackedSubmission
+		if err := json.Unmarshal(change.Before, &submission); err != nil {
+			return fmt.Errorf("failed to parse the previous submission: %w", err)
+		}
+		return dataStore.SaveSubmission(&submission)
+
+	case "collection":
+		if change.Created {
+			if err := dataStore.DeleteCollection(change.Target); err != nil {
+				return err
+			}
+			if cfg.AuthToken != "" {
+				err := api.NewClient(cfg).DeleteCollection(ctx, change.Target)
+				if err != nil && !errors.Is(err, api.ErrCollectionsUnsupported) {
+					ui.FromContext(ctx).Warning("Deleted %s locally but not on the server: %v", change.Target, err)
+				}
+			}
+			return nil
+		}
+		var collection models.Collection
+		if err := json.Unmarshal(change.Before, &collection); err != nil {
+			return fmt.Errorf("failed to parse the previous collection: %w", err)
+		}
+		if err := dataStore.SaveCollection(&collection); err != nil {
+			return err
+		}
+		pushCollection(ctx, cfg, &collection)
+		return nil
+
+	case "favorites":
+		if cfg.AuthToken == "" {
+			return fmt.Errorf("authentication required: use 'auth login' or 'auth token' first")
+		}
+		apiClient := api.NewClient(cfg)
+		directory, err := apiClient.GetDirectory(ctx, change.Target)
+		if err != nil {
+			return fmt.Errorf("failed to get directory: %w", err)
+		}
+		defer invalidateUserData(cfg)
+		if change.Created {
+			return apiClient.RemoveFavorite(ctx, directory.ID)
+		}
+		return apiClient.AddFavorite(ctx, directory.ID)
+	}
+
+	return fmt.Errorf("%s cannot be undone", change.Action)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/internal/update"
+)
+
+// versionOutput is the JSON representation of the version command
+type versionOutput struct {
+	Version         string          `json:"version"`
+	Commit          string          `json:"commit"`
+	Date            string          `json:"date"`
+	BuiltBy         string          `json:"built_by"`
+	GoVersion       string          `json:"go_version"`
+	Platform        string          `json:"platform"`
+	ConfigDir       string          `json:"config_dir"`
+	CacheDir        string          `json:"cache_dir"`
+	DataDir         string          `json:"data_dir"`
+	SchemaVersion   int             `json:"schema_version"`
+	MinSchema       int             `json:"min_schema_version"`
+	CacheUpdatedAt  *time.Time      `json:"cache_updated_at,omitempty"`
+	LatestRelease   *update.Release `json:"latest_release,omitempty"`
+	UpdateAvailable bool            `json:"update_available"`
+}
+
+// versionCommand creates the version command
+func versionCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "version",
+		Usage: "Show version, build and environment information",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "check",
+				Usage: "Check whether a newer release is available",
+			},
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Output as JSON",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			configDir, err := config.GetConfigDir()
+			if err != nil {
+				return fmt.Errorf("failed to get config directory: %w", err)
+			}
+
+			out := versionOutput{
+				Version:   version,
+				Commit:    commit,
+				Date:      date,
+				BuiltBy:   builtBy,
+				GoVersion: runtime.Version(),
+				Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+				ConfigDir: configDir,
+				CacheDir:  cfg.CacheDir,
+				DataDir:   cfg.DataDir,
+
+				SchemaVersion: api.SchemaVersion,
+				MinSchema:     api.MinSchemaVersion,
+			}
+
+			cacheClient := cache.NewCache(cfg, api.NewClient(cfg))
+			if meta, err := cacheClient.Metadata(); err == nil {
+				out.CacheUpdatedAt = &meta.LastUpdated
+			}
+
+			var checkErr error
+			if cmd.Bool("check") {
+				out.LatestRelease, checkErr = update.LatestRelease(ctx)
+				if checkErr == nil {
+					out.UpdateAvailable = update.IsNewer(out.LatestRelease.Version, version)
+				}
+			}
+
+			if cmd.Bool("json") {
+				if checkErr != nil {
+					return checkErr
+				}
+				return printJSON(u, out)
+			}
+
+			u.Bold("awesome-directories %s", out.Version)
+			u.Printf("  Commit:     %s\n", out.Commit)
+			u.Printf("  Built:      %s by %s\n", out.Date, out.BuiltBy)
+			u.Printf("  Go:         %s %s\n", out.GoVersion, out.Platform)
+			u.Printf("  Config dir: %s\n", out.ConfigDir)
+			u.Printf("  Cache dir:  %s\n", out.CacheDir)
+			u.Printf("  Data dir:   %s\n", out.DataDir)
+			u.Printf("  API schema: %d (reads %d to %d)\n", out.SchemaVersion, out.MinSchema, out.SchemaVersion)
+
+			if out.CacheUpdatedAt != nil {
+				u.Printf("  Data age:   %s (synced %s)\n",
+					time.Since(*out.CacheUpdatedAt).Round(time.Minute), out.CacheUpdatedAt.Local().Format("2006-01-02 15:04"))
+			} else {
+				u.Printf("  Data age:   never synced\n")
+			}
+
+			if !cmd.Bool("check") {
+				return nil
+			}
+
+			u.Println()
+			switch {
+			case checkErr != nil:
+				u.Warning("Could not check for updates: %v", checkErr)
+			case out.UpdateAvailable:
+				u.Warning("Update available: %s (released %s)", out.LatestRelease.Version, out.LatestRelease.PublishedAt.Format("2006-01-02"))
+				u.Muted("  %s", out.LatestRelease.URL)
+			default:
+				u.Success("You are running the latest release (%s)", out.LatestRelease.Version)
+			}
+
+			return nil
+		},
+	}
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/internal/auth"
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/digest"
+	"github.com/awesome-directories/cli/internal/inbox"
+	"github.com/awesome-directories/cli/internal/mail"
+	"github.com/awesome-directories/cli/internal/store"
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+const (
+	watchMinBackoff = 5 * time.Second
+	watchMaxBackoff = 10 * time.Minute
+)
+
+// watchCommand creates the watch command
+func watchCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "watch",
+		Usage: "Monitor directories and report changes until interrupted",
+		Metadata: examples(
+			"awesome-directories watch",
+			"awesome-directories watch --interval 15m --slug producthunt --slug hacker-news",
+			"awesome-directories watch --preset high-dr",
+			"awesome-directories watch --preset high-dr --email me@acme.dev",
+			"IMAP_SERVER=imap.gmail.com IMAP_USERNAME=me@acme.io IMAP_PASSWORD=app-password awesome-directories watch",
+		),
+		Flags: []cli.Flag{
+			&cli.DurationFlag{
+				Name:    "interval",
+				Aliases: []string{"i"},
+				Usage:   "Polling interval",
+				Value:   time.Hour,
+			},
+			&cli.StringSliceFlag{
+				Name:  "slug",
+				Usage: "Only watch these directories (can be specified multiple times)",
+			},
+			&cli.StringFlag{
+				Name:  "preset",
+				Usage: "Only watch directories matching a preset from config.yaml",
+			},
+			&cli.BoolFlag{
+				Name:  "no-mail",
+				Usage: "Don't check the configured IMAP mailbox for confirmation emails",
+			},
+			&cli.StringSliceFlag{
+				Name:  "email",
+				Usage: "Email the changes found to this address through the configured SMTP server (can be specified multiple times)",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			cfg, err := config.Load()
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			interval := cmd.Duration("interval")
+			if interval <= 0 {
+				return fmt.Errorf("interval must be positive")
+			}
+
+			var alertRecipients []string
+			for _, address := range cmd.StringSlice("email") {
+				recipient, err := mail.ParseAddress(address)
+				if err != nil {
+					return err
+				}
+				alertRecipients = append(alertRecipients, recipient)
+			}
+			if len(alertRecipients) > 0 && !mailConfig(cfg).Configured() {
+				return fmt.Errorf("--email needs an SMTP server: set smtp.server in config.yaml or SMTP_SERVER")
+			}
+
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			apiClient := api.NewClient(cfg)
+			cacheClient := cache.NewCache(cfg, apiClient)
+
+			// Keep the session alive for the lifetime of the watch
+			go auth.Keepalive(ctx, cfg, apiClient.SetAuthToken)
+
+			previous, err := cacheClient.GetDirectories(ctx, false)
+			if err != nil {
+				return fmt.Errorf("failed to get directories: %w", err)
+			}
+
+			inScope, err := watchScope(cfg, cmd)
+			if err != nil {
+				return err
+			}
+
+			watched := 0
+			known := make(map[string]bool, len(previous))
+			for _, dir := range previous {
+				known[dir.Slug] = true
+				if inScope(dir) {
+					watched++
+				}
+			}
+			for _, slug := range cmd.StringSlice("slug") {
+				if !known[slug] {
+					u.Warning("Unknown directory slug: %s", slug)
+				}
+			}
+
+			u.Info("Watching %d directories every %s (press Ctrl+C to stop)", watched, interval)
+
+			// Confirmation emails update submissions, which read-only mode
+			// doesn't allow
+			checkMail := cfg.IMAPServer != "" && !cfg.ReadOnly && !cmd.Bool("no-mail")
+			if checkMail {
+				u.Info("Checking %s on %s for confirmation emails", cfg.IMAPUsername, cfg.IMAPServer)
+				ingestMailbox(ctx, u, cfg, previous)
+			}
+
+			for {
+				select {
+				case <-ctx.Done():
+					u.Info("Stopped watching")
+					return nil
+				case <-time.After(interval):
+				}
+
+				current, err := refreshWithBackoff(ctx, cacheClient)
+				if err != nil {
+					if ctx.Err() != nil {
+						u.Info("Stopped watching")
+						return nil
+					}
+					return err
+				}
+
+				changes := cache.DiffDirectories(previous, current).Filter(inScope)
+				log.Debug().
+					Str("event", "watch_poll").
+					Int("added", len(changes.Added)).
+					Int("removed", len(changes.Removed)).
+					Int("changed", len(changes.Changed)).
+					Msg("Polled directories")
+
+				if !changes.Empty() {
+					displayChangeSet(u, changes)
+
+					if err := cacheClient.RecordAlerts(changes); err != nil {
+						log.Warn().Err(err).Msg("Failed to record alerts")
+					}
+					if len(alertRecipients) > 0 {
+						emailChanges(ctx, u, cfg, alertRecipients, changes)
+					}
+				}
+
+				if checkMail {
+					ingestMailbox(ctx, u, cfg, current)
+				}
+				// Sending digests records them in the subscriptions
+				if !cfg.ReadOnly {
+					deliverDueDigests(ctx, u, cfg, current)
+				}
+
+				previous = current
+			}
+		},
+	}
+}
+
+// watchScope returns a predicate selecting the directories to watch, based
+// on the --slug and --preset flags
+func watchScope(cfg *config.Config, cmd *cli.Command) (func(models.Directory) bool, error) {
+	slugs := make(map[string]bool)
+	for _, slug := range cmd.StringSlice("slug") {
+		slugs[slug] = true
+	}
+
+	var options *models.FilterOptions
+	if name := cmd.String("preset"); name != "" {
+		preset, err := cfg.Preset(name)
+		if err != nil {
+			return nil, err
+		}
+		options = preset.FilterOptions()
+	}
+
+	return func(dir models.Directory) bool {
+		if len(slugs) > 0 && !slugs[dir.Slug] {
+			return false
+		}
+		return options == nil || cache.Matches(dir, options)
+	}, nil
+}
+
+// refreshWithBackoff refreshes the cache, retrying with exponential backoff
+// until it succeeds or ctx is cancelled
+func refreshWithBackoff(ctx context.Context, cacheClient *cache.Cache) ([]models.Directory, error) {
+	backoff := watchMinBackoff
+	for {
+		directories, err := cacheClient.Refresh(ctx)
+		if err == nil {
+			return directories, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		log.Warn().
+			Err(err).
+			Str("event", "watch_reconnect").
+			Dur("retry_in", backoff).
+			Msg("Failed to refresh directories")
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, watchMaxBackoff)
+	}
+}
+
+// ingestMailbox reads the confirmation emails that arrived in the configured
+// IMAP mailbox since the last check and updates the matching submissions,
+// keeping each email as evidence. Failures are logged, so watching goes on.
+func ingestMailbox(ctx context.Context, u *ui.UI, cfg *config.Config, directories []models.Directory) {
+	dataStore := store.New(cfg)
+	saved, err := dataStore.MailboxState()
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to load mailbox state")
+		return
+	}
+
+	messages, state, err := inbox.Poll(ctx, inbox.IMAPConfig{
+		Server:   cfg.IMAPServer,
+		Username: cfg.IMAPUsername,
+		Password: cfg.IMAPPassword,
+		Mailbox:  cfg.IMAPMailbox,
+	}, inbox.IMAPState{UIDValidity: saved.UIDValidity, LastUID: saved.LastUID})
+	if err != nil {
+		log.Warn().Err(err).Str("event", "watch_mail").Msg("Failed to check mailbox")
+	}
+
+	// Save progress even after a failure, so fetched emails are not
+	// ingested twice
+	if state.UIDValidity != saved.UIDValidity || state.LastUID != saved.LastUID {
+		if err := dataStore.SaveMailboxState(store.MailboxState{UIDValidity: state.UIDValidity, LastUID: state.LastUID}); err != nil {
+			log.Warn().Err(err).Msg("Failed to save mailbox state")
+		}
+	}
+
+	for _, raw := range messages {
+		msg, err := inbox.Parse(bytes.NewReader(raw))
+		if err != nil {
+			log.Debug().Err(err).Msg("Skipping unreadable email")
+			continue
+		}
+
+		result, err := ingestConfirmation(ctx, u, cfg, directories, msg, ingestOptions{attach: true})
+		switch {
+		case errors.Is(err, errNotConfirmation):
+			log.Debug().Str("subject", msg.Subject).Msg("Skipping email")
+		case err != nil:
+			u.Muted("Confirmation email %q not applied: %v", msg.Subject, err)
+		case !result.updated:
+			log.Debug().Str("directory", result.directory.Slug).Msg("Confirmation email changes nothing")
+		case result.status == "approved":
+			u.Success("Approved: %s (%s)", result.directory.Name, result.submission.Project)
+		default:
+			u.Info("%s: %s → %s (%s)", result.directory.Name, result.oldStatus, result.status, result.submission.Project)
+		}
+	}
+}
+
+// emailChanges sends the changes found by a poll by email. Failures are
+// logged, so watching goes on.
+func emailChanges(ctx context.Context, u *ui.UI, cfg *config.Config, recipients []string, changes *cache.ChangeSet) {
+	var text bytes.Buffer
+	fmt.Fprintf(&text, "Changes detected at %s: %s.\n", time.Now().Format("2006-01-02 15:04"), digest.Summarize(changes))
+	digest.WriteChanges(&text, changes)
+
+	err := mail.Send(ctx, mailConfig(cfg), mail.Message{
+		To:      recipients,
+		Subject: "Directory changes: " + digest.Summarize(changes),
+		Text:    text.String(),
+	})
+	if err != nil {
+		log.Warn().Err(err).Str("event", "watch_email").Msg("Failed to email changes")
+		return
+	}
+	u.Muted("Emailed the changes to %s", strings.Join(recipients, ", "))
+}
+
+// displayChangeSet prints the changes found between two polls
+func displayChangeSet(u *ui.UI, changes *cache.ChangeSet) {
+	u.Bold("Changes detected at %s:", time.Now().Format("2006-01-02 15:04"))
+	displayChanges(u, changes)
+}
+
+// displayChanges prints added, removed and changed directories
+func displayChanges(u *ui.UI, changes *cache.ChangeSet) {
+	for _, dir := range changes.Added {
+		u.Success("New: %s (%s)", dir.Name, dir.Slug)
+	}
+
+	for _, dir := range changes.Removed {
+		u.Warning("Removed: %s (%s)", dir.Name, dir.Slug)
+	}
+
+	for _, change := range changes.Changed {
+		u.Info("Changed: %s (%s)", change.Directory.Name, change.Directory.Slug)
+		for _, field := range change.Fields {
+			u.Printf("  %s: %s → %s\n", field.Field, field.Old, field.New)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// widgetTemplate is the line listed per directory in the picker: slug, name,
+// DR and URL, tab-separated
+const widgetTemplate = `{{.Slug}}{{"\t"}}{{.Name}}{{"\t"}}DR {{.DomainRating}}{{"\t"}}{{.URL}}`
+
+// widgetFields are the fields of widgetTemplate a widget can insert
+var widgetFields = map[string]int{"slug": 1, "url": 4}
+
+// widgetShell is a shell a widget can be emitted for. script is a format
+// taking the program, the template, the field to insert and the key binding.
+type widgetShell struct {
+	key    string
+	script string
+}
+
+var widgetShells = map[string]widgetShell{
+	"zsh": {
+		key: `^X^D`,
+		script: `# awesome-directories widget: pick a directory with fzf and insert it at
+# the cursor. Add to ~/.zshrc: eval "$(awesome-directories widget zsh)"
+_awesome_directories_widget() {
+  local selected
+  selected=$(%[1]s list --limit 0 --format template --template '%[2]s' 2>/dev/null |
+    fzf --height 40%% --reverse --delimiter '\t' --with-nth 2.. --prompt 'directory> ' |
+    cut -f%[3]d)
+  if [[ -n $selected ]]; then
+    LBUFFER+=$selected
+  fi
+  zle reset-prompt
+}
+zle -N _awesome_directories_widget
+bindkey '%[4]s' _awesome_directories_widget
+`,
+	},
+	"fish": {
+		key: `\cx\cd`,
+		script: `# awesome-directories widget: pick a directory with fzf and insert it at
+# the cursor. Add to ~/.config/fish/config.fish:
+#   awesome-directories widget fish | source
+function _awesome_directories_widget
+    set -l selected (%[1]s list --limit 0 --format template --template '%[2]s' 2>/dev/null | fzf --height 40%% --reverse --delimiter '\t' --with-nth 2.. --prompt 'directory> ' | cut -f%[3]d)
+    if test -n "$selected"
+        commandline --insert -- $selected
+    end
+    commandline --function repaint
+end
+bind %[4]s _awesome_directories_widget
+`,
+	},
+}
+
+// widgetCommand creates the widget command
+func widgetCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "widget",
+		Usage:     "Print a shell key binding that picks a directory with fzf and inserts it at the cursor",
+		ArgsUsage: "<" + strings.Join(widgetShellNames(), "|") + ">",
+		Metadata: examples(
+			`eval "$(awesome-directories widget zsh)"`,
+			"awesome-directories widget fish | source",
+			`eval "$(awesome-directories widget zsh --insert slug --key '^G')"`,
+		),
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "insert",
+				Usage: "What to insert for the picked directory: url, slug",
+				Value: "url",
+			},
+			&cli.StringFlag{
+				Name:  "key",
+				Usage: "Key binding, in the shell's syntax (default: Ctrl-X Ctrl-D)",
+			},
+		},
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			u := ui.FromContext(ctx)
+
+			name := cmd.Args().First()
+			shell, ok := widgetShells[name]
+			if !ok {
+				return fmt.Errorf("unsupported shell: %q (use %s)", name, strings.Join(widgetShellNames(), ", "))
+			}
+
+			field, ok := widgetFields[cmd.String("insert")]
+			if !ok {
+				return fmt.Errorf("invalid --insert value: %s (use url or slug)", cmd.String("insert"))
+			}
+
+			key := shell.key
+			if cmd.String("key") != "" {
+				key = cmd.String("key")
+			}
+
+			u.Printf(shell.script, cmd.Root().Name, widgetTemplate, field, key)
+			return nil
+		},
+	}
+}
+
+// widgetShellNames returns the shells widgets are available for
+func widgetShellNames() []string {
+	names := make([]string, 0, len(widgetShells))
+	for name := range widgetShells {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,188 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// GetSubmissions fetches the user's submissions
+func (c *Client) GetSubmissions(ctx context.Context) ([]models.Submission, error) {
+	var submissions []models.Submission
+	if err := c.getUserData(ctx, "user_submissions", &submissions); err != nil {
+		return nil, fmt.Errorf("failed to fetch submissions: %w", err)
+	}
+	return submissions, nil
+}
+
+// GetVotes fetches the user's helpful votes
+func (c *Client) GetVotes(ctx context.Context) ([]models.Vote, error) {
+	var votes []models.Vote
+	if err := c.getUserData(ctx, "user_votes", &votes); err != nil {
+		return nil, fmt.Errorf("failed to fetch votes: %w", err)
+	}
+	return votes, nil
+}
+
+// DeleteAccount permanently deletes the authenticated user's account and all
+// associated data
+func (c *Client) DeleteAccount(ctx context.Context) error {
+	if c.token() == "" {
+		return fmt.Errorf("authentication required: please login first")
+	}
+
+	c.logger(ctx).Debug().Msg("Deleting account")
+
+	endpoint := c.baseURL + "/rest/v1/rpc/delete_user_account"
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete account: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode == 401 {
+		return fmt.Errorf("unauthorized: please login again")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+// getUserData fetches all rows of a user-scoped table into out
+func (c *Client) getUserData(ctx context.Context, table string, out interface{}) error {
+	if c.token() == "" {
+		return fmt.Errorf("authentication required: please login first")
+	}
+
+	c.logger(ctx).Debug().Str("table", table).Msg("Fetching user data")
+
+	endpoint := c.baseURL + "/rest/v1/" + table + "?select=*"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode == 401 {
+		return fmt.Errorf("unauthorized: please login again")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if err := decode(resp.Body, out, table, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetAccountStats fetches the user's favorites count and the API quota status
+func (c *Client) GetAccountStats(ctx context.Context) (*models.AccountStats, error) {
+	if c.token() == "" {
+		return nil, fmt.Errorf("authentication required: please login first")
+	}
+
+	endpoint := c.baseURL + "/rest/v1/user_favorites?select=id"
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+	req.Header.Set("Prefer", "count=exact")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch account stats: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode == 401 {
+		return nil, fmt.Errorf("unauthorized: please login again")
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("API error (status %d)", resp.StatusCode)
+	}
+
+	return &models.AccountStats{
+		FavoritesCount: parseContentRangeTotal(resp.Header.Get("Content-Range")),
+		RateLimit:      parseRateLimit(resp.Header),
+	}, nil
+}
+
+// parseContentRangeTotal returns the total of a PostgREST Content-Range
+// header such as "0-24/42"
+func parseContentRangeTotal(contentRange string) int {
+	_, total, found := strings.Cut(contentRange, "/")
+	if !found {
+		return 0
+	}
+
+	n, err := strconv.Atoi(total)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+// parseRateLimit reads the X-RateLimit-* headers. It returns nil when the
+// backend doesn't report a quota.
+func parseRateLimit(header http.Header) *models.RateLimit {
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err != nil {
+		return nil
+	}
+
+	rateLimit := &models.RateLimit{Limit: limit}
+	rateLimit.Remaining, _ = strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		rateLimit.Reset = time.Unix(reset, 0)
+	}
+
+	return rateLimit
+}
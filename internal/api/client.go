@@ -3,27 +3,39 @@ package api
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/goccy/go-json"
 
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
 
 	"github.com/awesome-directories/cli/internal/config"
 	"github.com/awesome-directories/cli/pkg/models"
 )
 
+// ErrDirectoryNotFound is returned for a slug no directory has
+var ErrDirectoryNotFound = errors.New("directory not found")
+
 // Client represents a Supabase API client
 type Client struct {
 	baseURL   string
 	anonKey   string
 	authToken string
 	client    *http.Client
+
+	mu      sync.RWMutex
+	log     *zerolog.Logger // nil for the global logger
+	missing map[string]bool // directory columns the backend doesn't have
+
+	schemaChecked bool // the backend schema version was found supported
+	lowBandwidth  bool // leave LowBandwidthSkipped out of directory requests
 }
 
 // NewClient creates a new Supabase API client
@@ -33,25 +45,56 @@ func NewClient(cfg *config.Config) *Client {
 		anonKey:   cfg.SupabaseAnonKey,
 		authToken: cfg.AuthToken,
 		client: &http.Client{
-			Timeout: 30 * time.Second,
+			Timeout:   30 * time.Second,
+			Transport: sessionTransport(),
 		},
+		lowBandwidth: cfg.LowBandwidth,
 	}
 }
 
 // SetAuthToken sets the authentication token
 func (c *Client) SetAuthToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	c.authToken = token
 }
 
+// token returns the current authentication token
+func (c *Client) token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.authToken
+}
+
 // GetDirectories fetches all directories from Supabase
 func (c *Client) GetDirectories(ctx context.Context, options *models.FilterOptions) ([]models.Directory, error) {
-	log.Debug().Msg("Fetching directories from Supabase")
+	c.logger(ctx).Debug().Msg("Fetching directories from Supabase")
+
+	// A backend migrated past what the CLI reads fails here with upgrade
+	// advice rather than later with a decode error
+	if err := c.CheckSchema(ctx); err != nil {
+		return nil, err
+	}
 
-	endpoint := c.baseURL + "/rest/v1/directories"
+	directories, err := c.queryDirectories(ctx, directoryParams(options))
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger(ctx).Debug().Int("count", len(directories)).Msg("Fetched directories successfully")
+
+	return directories, nil
+}
+
+// DirectoriesURL returns the request GetDirectories sends for options,
+// without the columns known to be missing on the backend
+func (c *Client) DirectoriesURL(options *models.FilterOptions) string {
+	return c.directoriesURL(directoryParams(options))
+}
 
-	// Build query parameters
+// directoryParams builds the PostgREST query parameters for options
+func directoryParams(options *models.FilterOptions) url.Values {
 	params := url.Values{}
-	params.Set("select", "*")
 	params.Set("is_active", "eq.true")
 
 	// Apply filters if provided
@@ -95,75 +138,42 @@ func (c *Client) GetDirectories(ctx context.Context, options *models.FilterOptio
 		params.Set("order", "helpful_count.desc.nullslast")
 	}
 
-	reqURL := endpoint + "?" + params.Encode()
+	return params
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// GetDirectory fetches a single directory by slug
+func (c *Client) GetDirectory(ctx context.Context, slug string) (*models.Directory, error) {
+	c.logger(ctx).Debug().Str("slug", slug).Msg("Fetching directory")
 
-	c.setHeaders(req)
+	params := url.Values{}
+	params.Set("slug", "eq."+slug)
 
-	resp, err := c.client.Do(req)
+	directories, err := c.queryDirectories(ctx, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch directories: %w", err)
+		return nil, err
 	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close response body")
-		}
-	}()
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	var directories []models.Directory
-	if err := json.NewDecoder(resp.Body).Decode(&directories); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if len(directories) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrDirectoryNotFound, slug)
 	}
 
-	log.Debug().Int("count", len(directories)).Msg("Fetched directories successfully")
-
-	return directories, nil
+	return &directories[0], nil
 }
 
-// GetDirectory fetches a single directory by slug
-func (c *Client) GetDirectory(ctx context.Context, slug string) (*models.Directory, error) {
-	log.Debug().Str("slug", slug).Msg("Fetching directory")
-
-	endpoint := fmt.Sprintf("%s/rest/v1/directories?slug=eq.%s&select=*", c.baseURL, slug)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// GetDirectoryByID fetches a single directory by ID
+func (c *Client) GetDirectoryByID(ctx context.Context, id string) (*models.Directory, error) {
+	c.logger(ctx).Debug().Str("id", id).Msg("Fetching directory")
 
-	c.setHeaders(req)
+	params := url.Values{}
+	params.Set("id", "eq."+id)
 
-	resp, err := c.client.Do(req)
+	directories, err := c.queryDirectories(ctx, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch directory: %w", err)
-	}
-	defer func() {
-		if err := resp.Body.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close response body")
-		}
-	}()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
-	}
-
-	var directories []models.Directory
-	if err := json.NewDecoder(resp.Body).Decode(&directories); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, err
 	}
 
 	if len(directories) == 0 {
-		return nil, fmt.Errorf("directory not found: %s", slug)
+		return nil, fmt.Errorf("%w: id %s", ErrDirectoryNotFound, id)
 	}
 
 	return &directories[0], nil
@@ -171,11 +181,11 @@ func (c *Client) GetDirectory(ctx context.Context, slug string) (*models.Directo
 
 // GetFavorites fetches user's favorite directories
 func (c *Client) GetFavorites(ctx context.Context) ([]models.Favorite, error) {
-	if c.authToken == "" {
+	if c.token() == "" {
 		return nil, fmt.Errorf("authentication required: please login first")
 	}
 
-	log.Debug().Msg("Fetching user favorites")
+	c.logger(ctx).Debug().Msg("Fetching user favorites")
 
 	endpoint := c.baseURL + "/rest/v1/user_favorites?select=*"
 
@@ -192,7 +202,7 @@ func (c *Client) GetFavorites(ctx context.Context) ([]models.Favorite, error) {
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close response body")
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
 		}
 	}()
 
@@ -206,8 +216,8 @@ func (c *Client) GetFavorites(ctx context.Context) ([]models.Favorite, error) {
 	}
 
 	var favorites []models.Favorite
-	if err := json.NewDecoder(resp.Body).Decode(&favorites); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if err := decode(resp.Body, &favorites, "user_favorites", nil); err != nil {
+		return nil, err
 	}
 
 	return favorites, nil
@@ -215,11 +225,11 @@ func (c *Client) GetFavorites(ctx context.Context) ([]models.Favorite, error) {
 
 // AddFavorite adds a directory to favorites
 func (c *Client) AddFavorite(ctx context.Context, directoryID string) error {
-	if c.authToken == "" {
+	if c.token() == "" {
 		return fmt.Errorf("authentication required: please login first")
 	}
 
-	log.Debug().Str("directory_id", directoryID).Msg("Adding favorite")
+	c.logger(ctx).Debug().Str("directory_id", directoryID).Msg("Adding favorite")
 
 	endpoint := c.baseURL + "/rest/v1/user_favorites"
 
@@ -247,7 +257,7 @@ func (c *Client) AddFavorite(ctx context.Context, directoryID string) error {
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close response body")
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
 		}
 	}()
 
@@ -265,11 +275,11 @@ func (c *Client) AddFavorite(ctx context.Context, directoryID string) error {
 
 // RemoveFavorite removes a directory from favorites
 func (c *Client) RemoveFavorite(ctx context.Context, directoryID string) error {
-	if c.authToken == "" {
+	if c.token() == "" {
 		return fmt.Errorf("authentication required: please login first")
 	}
 
-	log.Debug().Str("directory_id", directoryID).Msg("Removing favorite")
+	c.logger(ctx).Debug().Str("directory_id", directoryID).Msg("Removing favorite")
 
 	endpoint := fmt.Sprintf("%s/rest/v1/user_favorites?directory_id=eq.%s", c.baseURL, directoryID)
 
@@ -286,7 +296,7 @@ func (c *Client) RemoveFavorite(ctx context.Context, directoryID string) error {
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close response body")
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
 		}
 	}()
 
@@ -306,8 +316,8 @@ func (c *Client) RemoveFavorite(ctx context.Context, directoryID string) error {
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("apikey", c.anonKey)
 
-	if c.authToken != "" {
-		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	if token := c.token(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	} else {
 		req.Header.Set("Authorization", "Bearer "+c.anonKey)
 	}
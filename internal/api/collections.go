@@ -0,0 +1,106 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/goccy/go-json"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// collectionsTable is the user-scoped table holding synced collections
+const collectionsTable = "user_collections"
+
+// ErrCollectionsUnsupported is returned when the backend has no collections
+// table, such as an older or self-hosted instance
+var ErrCollectionsUnsupported = errors.New("the backend does not support collections")
+
+// GetCollections fetches the user's collections stored on the server
+func (c *Client) GetCollections(ctx context.Context) ([]models.Collection, error) {
+	var collections []models.Collection
+	params := url.Values{"select": {"name,description,directories,created_at,updated_at"}}
+	if err := c.collectionsRequest(ctx, "GET", params, nil, &collections); err != nil {
+		return nil, fmt.Errorf("failed to fetch collections: %w", err)
+	}
+	return collections, nil
+}
+
+// PutCollection creates or replaces a collection on the server
+func (c *Client) PutCollection(ctx context.Context, collection models.Collection) error {
+	c.logger(ctx).Debug().Str("collection", collection.Name).Msg("Saving collection")
+
+	// The preset refers to the local config.yaml, so it isn't synced
+	collection.Preset = ""
+	body, err := json.Marshal(collection)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	if err := c.collectionsRequest(ctx, "POST", url.Values{"on_conflict": {"user_id,name"}}, body, nil); err != nil {
+		return fmt.Errorf("failed to save collection: %w", err)
+	}
+	return nil
+}
+
+// DeleteCollection removes a collection from the server
+func (c *Client) DeleteCollection(ctx context.Context, name string) error {
+	c.logger(ctx).Debug().Str("collection", name).Msg("Deleting collection")
+
+	if err := c.collectionsRequest(ctx, "DELETE", url.Values{"name": {"eq." + name}}, nil, nil); err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	return nil
+}
+
+// collectionsRequest sends a request to the collections table, decoding the
+// response into out when it is not nil
+func (c *Client) collectionsRequest(ctx context.Context, method string, params url.Values, body []byte, out interface{}) error {
+	if c.token() == "" {
+		return fmt.Errorf("authentication required: please login first")
+	}
+
+	endpoint := c.baseURL + "/rest/v1/" + collectionsTable + "?" + params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Prefer", "resolution=merge-duplicates,return=minimal")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == 401:
+		return fmt.Errorf("unauthorized: please login again")
+	case resp.StatusCode == 404:
+		// PostgREST answers 404 for a table that doesn't exist
+		return ErrCollectionsUnsupported
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(respBody))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return decode(resp.Body, out, collectionsTable, nil)
+}
@@ -0,0 +1,187 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/goccy/go-json"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// undefinedColumn is the PostgreSQL error code for a column that does not
+// exist
+const undefinedColumn = "42703"
+
+// missingColumnPattern extracts the column from an undefined column error,
+// such as "column directories.review_days does not exist"
+var missingColumnPattern = regexp.MustCompile(`column (?:\w+\.)?(\w+) does not exist`)
+
+// DirectoryColumns returns the directory columns the CLI knows about
+func DirectoryColumns() []string {
+	t := reflect.TypeOf(models.Directory{})
+	columns := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
+
+// LowBandwidthSkipped are the directory columns not fetched in low-bandwidth
+// mode: statistics and links only the detail view and some exports show
+var LowBandwidthSkipped = []string{
+	"organic_traffic", "organic_keywords", "view_count", "is_affiliate", "affiliate_url", "feed_url",
+}
+
+// SkippedColumns returns the directory columns this client leaves out of its
+// requests to save bandwidth. Their fields are left empty in fetched
+// directories.
+func (c *Client) SkippedColumns() []string {
+	if !c.lowBandwidth {
+		return nil
+	}
+	return slices.Clone(LowBandwidthSkipped)
+}
+
+// MissingColumns returns the directory columns found missing on the backend,
+// such as newer columns on an older or self-hosted instance. Their fields are
+// left empty in fetched directories.
+func (c *Client) MissingColumns() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	columns := make([]string, 0, len(c.missing))
+	for column := range c.missing {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// markMissing records a column as missing, reporting whether it was known
+// and missing before
+func (c *Client) markMissing(column string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.missing[column] {
+		return false
+	}
+	if c.missing == nil {
+		c.missing = make(map[string]bool)
+	}
+	c.missing[column] = true
+	return true
+}
+
+// isMissing reports whether a column is known to be missing
+func (c *Client) isMissing(column string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.missing[column]
+}
+
+// queryDirectories fetches directories matching the PostgREST params,
+// selecting the known columns. When the backend lacks a column, it is left
+// out of the select list, filters and ordering, and the request is retried.
+func (c *Client) queryDirectories(ctx context.Context, params url.Values) ([]models.Directory, error) {
+	for {
+		req, err := http.NewRequestWithContext(ctx, "GET", c.directoriesURL(params), nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		c.setHeaders(req)
+
+		resp, err := c.client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch directories: %w", err)
+		}
+
+		directories, retry, err := c.readDirectories(ctx, resp)
+		if !retry {
+			return directories, err
+		}
+	}
+}
+
+// directoriesURL returns the directories request for the PostgREST params,
+// selecting the known columns the backend has and, in low-bandwidth mode,
+// the listings need
+func (c *Client) directoriesURL(params url.Values) string {
+	query := url.Values{}
+	var selected []string
+	skipped := c.SkippedColumns()
+	for _, column := range DirectoryColumns() {
+		if !c.isMissing(column) && !slices.Contains(skipped, column) {
+			selected = append(selected, column)
+		}
+	}
+	query.Set("select", strings.Join(selected, ","))
+	for key, values := range params {
+		if c.isMissing(key) {
+			continue
+		}
+		if key == "order" && c.isMissing(strings.SplitN(values[0], ".", 2)[0]) {
+			continue
+		}
+		query[key] = values
+	}
+	return c.baseURL + "/rest/v1/directories?" + query.Encode()
+}
+
+// readDirectories reads a directories response. retry is set when the
+// request failed on a column the backend doesn't have, which is then
+// recorded as missing.
+func (c *Client) readDirectories(ctx context.Context, resp *http.Response) (directories []models.Directory, retry bool, err error) {
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		if column, ok := missingColumn(body); ok && c.markMissing(column) {
+			c.logger(ctx).Debug().Str("column", column).Msg("Backend has no such directory column, retrying without it")
+			return nil, true, nil
+		}
+		return nil, false, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	// Columns missing on the backend or skipped to save bandwidth weren't
+	// asked for
+	unselected := append(c.MissingColumns(), c.SkippedColumns()...)
+	if err := decode(resp.Body, &directories, "directories", unselected); err != nil {
+		return nil, false, err
+	}
+	return directories, false, nil
+}
+
+// missingColumn returns the column of an undefined column error body
+func missingColumn(body []byte) (string, bool) {
+	var apiErr struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Code != undefinedColumn {
+		return "", false
+	}
+
+	match := missingColumnPattern.FindStringSubmatch(apiErr.Message)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
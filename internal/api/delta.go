@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// GetDirectoryIDs fetches the IDs of the active directories, a fraction of
+// the catalog, so a delta sync can tell which cached directories are gone
+func (c *Client) GetDirectoryIDs(ctx context.Context) ([]string, error) {
+	if err := c.CheckSchema(ctx); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("select", "id")
+	query.Set("is_active", "eq.true")
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/rest/v1/directories?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch directory IDs: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var rows []struct {
+		ID string `json:"id"`
+	}
+	if err := decode(resp.Body, &rows, "directory IDs", nil); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(rows))
+	for i, row := range rows {
+		ids[i] = row.ID
+	}
+	return ids, nil
+}
+
+// GetDirectoriesUpdatedSince fetches the active directories updated at or
+// after since. A backend without an updated_at column returns them all.
+func (c *Client) GetDirectoriesUpdatedSince(ctx context.Context, since time.Time) ([]models.Directory, error) {
+	c.logger(ctx).Debug().Time("since", since).Msg("Fetching directories updated since the last sync")
+
+	if err := c.CheckSchema(ctx); err != nil {
+		return nil, err
+	}
+
+	params := directoryParams(nil)
+	params.Set("updated_at", "gte."+since.UTC().Format(time.RFC3339Nano))
+	return c.queryDirectories(ctx, params)
+}
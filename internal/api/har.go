@@ -0,0 +1,418 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// redacted replaces secrets in recorded sessions
+const redacted = "[redacted]"
+
+// sensitiveHeaders are dropped from recorded requests and responses
+var sensitiveHeaders = map[string]bool{
+	"apikey":        true,
+	"authorization": true,
+	"cookie":        true,
+	"set-cookie":    true,
+}
+
+// sensitiveFields are query parameters and JSON fields whose values are
+// redacted in recorded sessions
+var sensitiveFields = map[string]bool{
+	"access_token":  true,
+	"refresh_token": true,
+	"apikey":        true,
+	"password":      true,
+	"token":         true,
+}
+
+// session is the transport of every client created after Record or Replay;
+// nil for the default transport
+var (
+	sessionMu sync.Mutex
+	session   http.RoundTripper
+)
+
+// harFile is an HTTP Archive (HAR 1.2) document
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string       `json:"method"`
+	URL         string       `json:"url"`
+	HTTPVersion string       `json:"httpVersion"`
+	Headers     []harNameVal `json:"headers"`
+	QueryString []harNameVal `json:"queryString"`
+	Cookies     []harNameVal `json:"cookies"`
+	PostData    *harPostData `json:"postData,omitempty"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int          `json:"status"`
+	StatusText  string       `json:"statusText"`
+	HTTPVersion string       `json:"httpVersion"`
+	Headers     []harNameVal `json:"headers"`
+	Cookies     []harNameVal `json:"cookies"`
+	Content     harContent   `json:"content"`
+	RedirectURL string       `json:"redirectURL"`
+	HeadersSize int          `json:"headersSize"`
+	BodySize    int          `json:"bodySize"`
+}
+
+type harNameVal struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// Record makes clients created from now on save every request and response
+// to the HAR file at path, with credentials removed, so a session can be
+// attached to a bug report and replayed with Replay. version is the CLI
+// version noted in the file.
+func Record(path, version string) error {
+	r := &recorder{
+		path: path,
+		next: http.DefaultTransport,
+		har: harFile{Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{Name: "awesome-directories", Version: version},
+			Entries: []harEntry{},
+		}},
+	}
+	// Create the file up front so a bad path fails before any request
+	if err := r.save(); err != nil {
+		return err
+	}
+
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	session = r
+	return nil
+}
+
+// Replay makes clients created from now on answer requests from the HAR file
+// at path instead of the network. Requests are matched on method, path and
+// query; the same request recorded several times is answered in order.
+func Replay(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read recorded session: %w", err)
+	}
+
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return fmt.Errorf("failed to parse recorded session %s: %w", path, err)
+	}
+
+	r := &replayer{path: path, entries: make(map[string][]harEntry)}
+	for _, entry := range har.Log.Entries {
+		key, err := replayKey(entry.Request.Method, entry.Request.URL)
+		if err != nil {
+			return fmt.Errorf("invalid request in recorded session %s: %w", path, err)
+		}
+		r.entries[key] = append(r.entries[key], entry)
+	}
+
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	session = r
+	return nil
+}
+
+// sessionTransport returns the transport for a new client
+func sessionTransport() http.RoundTripper {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	return session
+}
+
+// recorder is a transport saving sanitized exchanges to a HAR file
+type recorder struct {
+	path string
+	next http.RoundTripper
+
+	mu  sync.Mutex
+	har harFile
+}
+
+// RoundTrip sends the request and records it with its response
+func (r *recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	started := time.Now()
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	elapsed := float64(time.Since(started).Microseconds()) / 1000
+
+	entry := harEntry{
+		StartedDateTime: started,
+		Time:            elapsed,
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         sanitizeURL(req.URL),
+			HTTPVersion: req.Proto,
+			Headers:     sanitizeHeaders(req.Header),
+			QueryString: sanitizeQuery(req.URL.Query()),
+			Cookies:     []harNameVal{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Status:      resp.StatusCode,
+			StatusText:  http.StatusText(resp.StatusCode),
+			HTTPVersion: resp.Proto,
+			Headers:     sanitizeHeaders(resp.Header),
+			Cookies:     []harNameVal{},
+			Content: harContent{
+				Size:     len(respBody),
+				MimeType: resp.Header.Get("Content-Type"),
+				Text:     sanitizeBody(respBody),
+			},
+			RedirectURL: resp.Header.Get("Location"),
+			HeadersSize: -1,
+			BodySize:    len(respBody),
+		},
+		Timings: harTimings{Wait: elapsed},
+	}
+	if reqBody != nil {
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     sanitizeBody(reqBody),
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.har.Log.Entries = append(r.har.Log.Entries, entry)
+	// Saving after every exchange keeps the session when the command fails
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// save writes the recorded session
+func (r *recorder) save() error {
+	data, err := json.MarshalIndent(r.har, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recorded session: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write recorded session: %w", err)
+	}
+	return nil
+}
+
+// replayer is a transport answering requests from a recorded session
+type replayer struct {
+	path string
+
+	mu      sync.Mutex
+	entries map[string][]harEntry
+}
+
+// RoundTrip answers the request with the next matching recorded response.
+// Once a request's recordings are used up, the last one is repeated.
+func (r *replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	key, err := replayKey(req.Method, sanitizeURL(req.URL))
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	entries := r.entries[key]
+	if len(entries) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("no response recorded in %s for %s", r.path, key)
+	}
+	entry := entries[0]
+	if len(entries) > 1 {
+		r.entries[key] = entries[1:]
+	}
+	r.mu.Unlock()
+
+	header := make(http.Header)
+	for _, h := range entry.Response.Headers {
+		header.Add(h.Name, h.Value)
+	}
+	body := entry.Response.Content.Text
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", entry.Response.Status, entry.Response.StatusText),
+		StatusCode:    entry.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// replayKey identifies a request independently of the host, so a session
+// replays against any Supabase URL
+func replayKey(method, rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	key := method + " " + u.Path
+	if u.RawQuery != "" {
+		// Encode sorts the parameters
+		key += "?" + u.Query().Encode()
+	}
+	return key, nil
+}
+
+// sanitizeURL returns the URL with sensitive query parameters redacted
+func sanitizeURL(u *url.URL) string {
+	sanitized := *u
+	sanitized.User = nil
+	query := u.Query()
+	for name := range query {
+		if sensitiveFields[strings.ToLower(name)] {
+			query.Set(name, redacted)
+		}
+	}
+	if u.RawQuery != "" {
+		sanitized.RawQuery = query.Encode()
+	}
+	return sanitized.String()
+}
+
+// sanitizeHeaders returns headers without credentials
+func sanitizeHeaders(header http.Header) []harNameVal {
+	headers := []harNameVal{}
+	for name, values := range header {
+		if sensitiveHeaders[strings.ToLower(name)] {
+			continue
+		}
+		for _, value := range values {
+			headers = append(headers, harNameVal{Name: name, Value: value})
+		}
+	}
+	return headers
+}
+
+// sanitizeQuery returns query parameters with sensitive values redacted
+func sanitizeQuery(query url.Values) []harNameVal {
+	params := []harNameVal{}
+	for name, values := range query {
+		for _, value := range values {
+			if sensitiveFields[strings.ToLower(name)] {
+				value = redacted
+			}
+			params = append(params, harNameVal{Name: name, Value: value})
+		}
+	}
+	return params
+}
+
+// sanitizeBody redacts sensitive fields of a JSON body. Other bodies are
+// kept as they are.
+func sanitizeBody(body []byte) string {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return string(body)
+	}
+	if !redactFields(doc) {
+		return string(body)
+	}
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
+
+// redactFields redacts sensitive fields of a decoded JSON value in place,
+// reporting whether any was found
+func redactFields(v interface{}) bool {
+	found := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if sensitiveFields[strings.ToLower(key)] {
+				v[key] = redacted
+				found = true
+				continue
+			}
+			if redactFields(value) {
+				found = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if redactFields(value) {
+				found = true
+			}
+		}
+	}
+	return found
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// SetLogger makes the client log to logger instead of the global logger, so
+// an application embedding it controls where its logs go and how verbose
+// they are
+func (c *Client) SetLogger(logger zerolog.Logger) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.log = &logger
+}
+
+// logger returns the logger for a request
+func (c *Client) logger(ctx context.Context) *zerolog.Logger {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return LoggerFor(ctx, c.log)
+}
+
+// LoggerFor returns the logger attached to ctx with zerolog's
+// Logger.WithContext, falling back to fallback and then to the global logger
+func LoggerFor(ctx context.Context, fallback *zerolog.Logger) *zerolog.Logger {
+	// zerolog.Ctx returns the same default logger for every context without
+	// one of its own
+	if logger := zerolog.Ctx(ctx); logger != zerolog.Ctx(context.Background()) {
+		return logger
+	}
+	if fallback != nil {
+		return fallback
+	}
+	return &log.Logger
+}
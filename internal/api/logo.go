@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// faviconServiceURL serves site icons as PNG, whatever format the site uses
+	faviconServiceURL = "https://www.google.com/s2/favicons?sz=64&domain="
+
+	maxLogoSize = 1 << 20
+)
+
+// LogoURL returns where the logo of the site at siteURL is served
+func LogoURL(siteURL string) (string, error) {
+	parsed, err := url.Parse(siteURL)
+	if err != nil || parsed.Hostname() == "" {
+		return "", fmt.Errorf("invalid site URL: %s", siteURL)
+	}
+	return faviconServiceURL + url.QueryEscape(parsed.Hostname()), nil
+}
+
+// GetLogo fetches the logo of the site at siteURL
+func (c *Client) GetLogo(ctx context.Context, siteURL string) ([]byte, error) {
+	logoURL, err := LogoURL(siteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	c.logger(ctx).Debug().Str("site", siteURL).Msg("Fetching logo")
+	return c.GetImage(ctx, logoURL)
+}
+
+// GetImage fetches an image of at most 1 MiB, such as the logo of a product
+func (c *Client) GetImage(ctx context.Context, imageURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", imageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch logo: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch logo: status %d", resp.StatusCode)
+	}
+
+	if contentType := resp.Header.Get("Content-Type"); !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("unexpected logo content type: %s", contentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLogoSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read logo: %w", err)
+	}
+
+	return data, nil
+}
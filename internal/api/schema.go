@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/goccy/go-json"
+)
+
+// The backend reports the version of its schema, bumped by migrations that
+// change what the CLI reads. SchemaVersion is the version this CLI is built
+// against; MinSchemaVersion is the oldest it still reads.
+const (
+	MinSchemaVersion = 1
+	SchemaVersion    = 1
+)
+
+// SchemaVersionError is returned when the backend schema is older or newer
+// than this CLI reads
+type SchemaVersionError struct {
+	// Backend is the schema version the backend reports
+	Backend int
+}
+
+// Error explains which side needs upgrading
+func (e *SchemaVersionError) Error() string {
+	if e.Backend > SchemaVersion {
+		return fmt.Sprintf("the API uses schema version %d but this CLI only reads up to version %d: "+
+			"upgrade the CLI (see `awesome-directories version --check`)", e.Backend, SchemaVersion)
+	}
+	return fmt.Sprintf("the API uses schema version %d but this CLI needs version %d or later: "+
+		"upgrade the backend, or use an older release of the CLI", e.Backend, MinSchemaVersion)
+}
+
+// BackendSchemaVersion asks the backend for its schema version. ok is false
+// when the backend predates the schema_version function and can't tell.
+func (c *Client) BackendSchemaVersion(ctx context.Context) (version int, ok bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/rest/v1/rpc/schema_version", nil)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setHeaders(req)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to fetch schema version: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return 0, false, nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, false, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&version); err != nil {
+		return 0, false, fmt.Errorf("failed to decode schema version: %w", err)
+	}
+	return version, true, nil
+}
+
+// CheckSchema fails with a SchemaVersionError when the backend schema is
+// outside the versions this CLI reads. The backend is asked once per
+// client; a backend that can't tell, or can't be asked, passes so the
+// request that follows reports its own errors.
+func (c *Client) CheckSchema(ctx context.Context) error {
+	c.mu.RLock()
+	checked := c.schemaChecked
+	c.mu.RUnlock()
+	if checked {
+		return nil
+	}
+
+	version, ok, err := c.BackendSchemaVersion(ctx)
+	switch {
+	case err != nil:
+		c.logger(ctx).Debug().Err(err).Msg("Could not check the backend schema version")
+		return nil
+	case !ok:
+		c.logger(ctx).Debug().Msg("Backend does not report a schema version")
+	case version < MinSchemaVersion || version > SchemaVersion:
+		return &SchemaVersionError{Backend: version}
+	default:
+		c.logger(ctx).Debug().Int("version", version).Msg("Backend schema version is supported")
+	}
+
+	c.mu.Lock()
+	c.schemaChecked = true
+	c.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,229 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// maxSchemaProblems is how many discrepancies a strict mode error lists
+const maxSchemaProblems = 20
+
+// strict makes responses that do not match the models an error
+var strict atomic.Bool
+
+// SetStrict turns strict mode on or off. In strict mode, responses with
+// fields the models do not know, values of the wrong type or missing fields
+// fail with an error listing them, instead of silently decoding to zero
+// values.
+func SetStrict(enabled bool) {
+	strict.Store(enabled)
+}
+
+// SchemaError lists how a response differs from the expected model
+type SchemaError struct {
+	// What is the decoded resource, such as "directories"
+	What     string
+	Problems []string
+}
+
+// Error returns the discrepancies, one per line
+func (e *SchemaError) Error() string {
+	shown := e.Problems
+	if len(shown) > maxSchemaProblems {
+		shown = shown[:maxSchemaProblems]
+	}
+	msg := fmt.Sprintf("%s response does not match the expected schema:\n  %s", e.What, strings.Join(shown, "\n  "))
+	if hidden := len(e.Problems) - len(shown); hidden > 0 {
+		msg += fmt.Sprintf("\n  ... and %d more", hidden)
+	}
+	return msg
+}
+
+// decode decodes a response body into out, validating it first in strict
+// mode. unselected are the columns left out of the request, whose fields
+// aren't missing when absent.
+func decode(body io.Reader, out interface{}, what string, unselected []string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if strict.Load() {
+		var raw interface{}
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		if problems := validateSchema(raw, reflect.TypeOf(out).Elem(), unselected); len(problems) > 0 {
+			return &SchemaError{What: what, Problems: problems}
+		}
+	}
+
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// validateSchema compares a decoded JSON value with the type it is decoded
+// into. Problems at the same place in different array elements are reported
+// once, with how many elements have them. Fields named in unselected may
+// be absent.
+func validateSchema(raw interface{}, t reflect.Type, unselected []string) []string {
+	counts := make(map[string]int)
+	checkValue(raw, t, "", unselected, counts)
+
+	problems := make([]string, 0, len(counts))
+	for problem, count := range counts {
+		if count > 1 {
+			problem += fmt.Sprintf(" (%d times)", count)
+		}
+		problems = append(problems, problem)
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// checkValue counts the problems of raw, at path, against t
+func checkValue(raw interface{}, t reflect.Type, path string, unselected []string, problems map[string]int) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// PostgREST returns null for empty nullable columns
+	if raw == nil {
+		return
+	}
+
+	wrongType := func(expected string) {
+		problems[fmt.Sprintf("%s: expected %s, got %s", pathOrRoot(path), expected, jsonType(raw))]++
+	}
+
+	switch {
+	case t == timeType:
+		s, ok := raw.(string)
+		if !ok {
+			wrongType("a timestamp")
+			return
+		}
+		if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+			problems[fmt.Sprintf("%s: invalid timestamp %q", pathOrRoot(path), s)]++
+		}
+
+	case t.Kind() == reflect.Struct:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			wrongType("an object")
+			return
+		}
+		known := make(map[string]bool)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, opts, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			known[name] = true
+
+			value, present := obj[name]
+			if !present {
+				if !strings.Contains(opts, "omitempty") && !slices.Contains(unselected, name) {
+					problems[fmt.Sprintf("%s: missing field", joinPath(path, name))]++
+				}
+				continue
+			}
+			checkValue(value, field.Type, joinPath(path, name), unselected, problems)
+		}
+		for name := range obj {
+			if !known[name] {
+				problems[fmt.Sprintf("%s: unknown field", joinPath(path, name))]++
+			}
+		}
+
+	case t.Kind() == reflect.Slice:
+		arr, ok := raw.([]interface{})
+		if !ok {
+			wrongType("an array")
+			return
+		}
+		for _, elem := range arr {
+			checkValue(elem, t.Elem(), path+"[]", unselected, problems)
+		}
+
+	case t.Kind() == reflect.Map:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			wrongType("an object")
+			return
+		}
+		for key, value := range obj {
+			checkValue(value, t.Elem(), joinPath(path, key), unselected, problems)
+		}
+
+	case t.Kind() == reflect.String:
+		if _, ok := raw.(string); !ok {
+			wrongType("a string")
+		}
+
+	case t.Kind() == reflect.Bool:
+		if _, ok := raw.(bool); !ok {
+			wrongType("a boolean")
+		}
+
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		n, ok := raw.(float64)
+		if !ok || n != float64(int64(n)) {
+			wrongType("an integer")
+		}
+
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		if _, ok := raw.(float64); !ok {
+			wrongType("a number")
+		}
+	}
+}
+
+// jsonType names the JSON type of a decoded value
+func jsonType(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "an integer"
+		}
+		return "a number"
+	case []interface{}:
+		return "an array"
+	case map[string]interface{}:
+		return "an object"
+	}
+	return "null"
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "response"
+	}
+	return path
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/goccy/go-json"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// ReportEvent sends an anonymous command event to the telemetry endpoint
+func (c *Client) ReportEvent(ctx context.Context, event models.CommandEvent) error {
+	c.logger(ctx).Debug().Str("command", event.Command).Msg("Reporting command event")
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/rest/v1/cli_events", bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Events are anonymous, so never send the user's token
+	req.Header.Set("apikey", c.anonKey)
+	req.Header.Set("Authorization", "Bearer "+c.anonKey)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Prefer", "return=minimal")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to report event: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			c.logger(ctx).Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("API error (status %d)", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,315 @@
+// Package assert evaluates the comparisons checked by the assert command,
+// such as "count(--dr-min 70 --pricing free) >= 25". Operands are numbers
+// and calls, combined with + - * / and parentheses; what a call computes is
+// up to the caller.
+package assert
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Call is a function call in an expression, such as
+// avg(domain_rating --pricing free)
+type Call struct {
+	Name string
+	Args []string // the arguments split into shell words
+}
+
+func (c Call) String() string {
+	return c.Name + "(" + strings.Join(c.Args, " ") + ")"
+}
+
+// Func computes the value of a call
+type Func func(call Call) (float64, error)
+
+// Result is the outcome of checking an assertion
+type Result struct {
+	Left   float64
+	Op     string
+	Right  float64
+	Passed bool
+}
+
+// Assertion is a parsed comparison
+type Assertion struct {
+	left  node
+	op    string
+	right node
+}
+
+// comparisons are the supported comparison operators, longest first so
+// ">=" isn't read as ">"
+var comparisons = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// Parse parses an assertion: two expressions compared with one of
+// >= <= == != > <
+func Parse(expression string) (*Assertion, error) {
+	p := &parser{input: expression}
+
+	left, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	op := ""
+	for _, candidate := range comparisons {
+		if strings.HasPrefix(p.input[p.pos:], candidate) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" {
+		return nil, p.errorf("expected a comparison (%s)", strings.Join(comparisons, " "))
+	}
+	p.pos += len(op)
+
+	right, err := p.sum()
+	if err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos:])
+	}
+	return &Assertion{left: left, op: op, right: right}, nil
+}
+
+// Calls returns the calls of the assertion, in order
+func (a *Assertion) Calls() []Call {
+	var calls []Call
+	a.left.walk(func(c Call) { calls = append(calls, c) })
+	a.right.walk(func(c Call) { calls = append(calls, c) })
+	return calls
+}
+
+// Check evaluates both sides, computing calls with fn, and compares them
+func (a *Assertion) Check(fn Func) (Result, error) {
+	left, err := a.left.eval(fn)
+	if err != nil {
+		return Result{}, err
+	}
+	right, err := a.right.eval(fn)
+	if err != nil {
+		return Result{}, err
+	}
+
+	result := Result{Left: left, Op: a.op, Right: right}
+	switch a.op {
+	case ">=":
+		result.Passed = left >= right
+	case "<=":
+		result.Passed = left <= right
+	case "==":
+		result.Passed = left == right
+	case "!=":
+		result.Passed = left != right
+	case ">":
+		result.Passed = left > right
+	case "<":
+		result.Passed = left < right
+	}
+	return result, nil
+}
+
+// node is a part of an expression
+type node interface {
+	eval(fn Func) (float64, error)
+	walk(visit func(Call))
+}
+
+type number float64
+
+func (n number) eval(Func) (float64, error) { return float64(n), nil }
+func (n number) walk(func(Call))            {}
+
+type call Call
+
+func (c call) eval(fn Func) (float64, error) { return fn(Call(c)) }
+func (c call) walk(visit func(Call))         { visit(Call(c)) }
+
+type binary struct {
+	op          byte
+	left, right node
+}
+
+func (b binary) eval(fn Func) (float64, error) {
+	left, err := b.left.eval(fn)
+	if err != nil {
+		return 0, err
+	}
+	right, err := b.right.eval(fn)
+	if err != nil {
+		return 0, err
+	}
+
+	switch b.op {
+	case '+':
+		return left + right, nil
+	case '-':
+		return left - right, nil
+	case '*':
+		return left * right, nil
+	default:
+		if right == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		return left / right, nil
+	}
+}
+
+func (b binary) walk(visit func(Call)) {
+	b.left.walk(visit)
+	b.right.walk(visit)
+}
+
+// parser is a recursive descent parser over an expression
+type parser struct {
+	input string
+	pos   int
+}
+
+// sum parses terms joined with + and -
+func (p *parser) sum() (node, error) {
+	left, err := p.product()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '+' && p.input[p.pos] != '-') {
+			return left, nil
+		}
+		op := p.input[p.pos]
+		p.pos++
+		right, err := p.product()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+}
+
+// product parses operands joined with * and /
+func (p *parser) product() (node, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.input) || (p.input[p.pos] != '*' && p.input[p.pos] != '/') {
+			return left, nil
+		}
+		op := p.input[p.pos]
+		p.pos++
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+}
+
+// operand parses a number, a call or a parenthesized expression
+func (p *parser) operand() (node, error) {
+	p.skipSpace()
+	if p.pos >= len(p.input) {
+		return nil, p.errorf("expected a number or a call")
+	}
+
+	start := p.pos
+	c := rune(p.input[p.pos])
+	switch {
+	case c == '(':
+		p.pos++
+		inner, err := p.sum()
+		if err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		if p.pos >= len(p.input) || p.input[p.pos] != ')' {
+			return nil, p.errorf("expected )")
+		}
+		p.pos++
+		return inner, nil
+
+	case unicode.IsDigit(c) || c == '.':
+		for p.pos < len(p.input) && (unicode.IsDigit(rune(p.input[p.pos])) || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", p.input[start:p.pos])
+		}
+		return number(value), nil
+
+	case unicode.IsLetter(c):
+		for p.pos < len(p.input) && (unicode.IsLetter(rune(p.input[p.pos])) || p.input[p.pos] == '_') {
+			p.pos++
+		}
+		name := strings.ToLower(p.input[start:p.pos])
+		if p.pos >= len(p.input) || p.input[p.pos] != '(' {
+			return nil, p.errorf("expected ( after %s", name)
+		}
+		args, err := p.args()
+		if err != nil {
+			return nil, err
+		}
+		return call{Name: name, Args: args}, nil
+	}
+
+	return nil, p.errorf("unexpected %q", p.input[p.pos:])
+}
+
+// args reads the arguments of a call up to the closing parenthesis, split
+// into words; single and double quotes group words
+func (p *parser) args() ([]string, error) {
+	p.pos++ // (
+
+	var args []string
+	var word strings.Builder
+	inWord := false
+	var quote byte
+	for ; p.pos < len(p.input); p.pos++ {
+		c := p.input[p.pos]
+		switch {
+		case quote != 0 && c == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteByte(c)
+		case c == '"' || c == '\'':
+			quote, inWord = c, true
+		case c == ')':
+			if inWord {
+				args = append(args, word.String())
+			}
+			p.pos++
+			return args, nil
+		case c == ' ' || c == '\t':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	return nil, p.errorf("missing )")
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("invalid assertion %q at column %d: %s", p.input, p.pos+1, fmt.Sprintf(format, args...))
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/goccy/go-json"
@@ -39,8 +40,16 @@ type AuthResponse struct {
 
 // User represents a Supabase user
 type User struct {
-	ID    string `json:"id"`
-	Email string `json:"email"`
+	ID          string      `json:"id"`
+	Email       string      `json:"email"`
+	CreatedAt   time.Time   `json:"created_at"`
+	AppMetadata AppMetadata `json:"app_metadata"`
+}
+
+// AppMetadata holds server-managed account attributes
+type AppMetadata struct {
+	Provider string `json:"provider"`
+	Plan     string `json:"plan"`
 }
 
 // LoginWithBrowser initiates browser-based OAuth flow
@@ -97,10 +106,9 @@ func LoginWithBrowser(cfg *config.Config, provider string) error {
 			log.Error().Err(err).Msg("Failed to shutdown server")
 		}
 
-		// Save token to config
-		cfg.AuthToken = authResp.AccessToken
-		if err := cfg.Save(); err != nil {
-			return fmt.Errorf("failed to save auth token: %w", err)
+		// Save session to config
+		if err := saveSession(cfg, authResp); err != nil {
+			return err
 		}
 
 		ui.Success("Successfully authenticated as %s", authResp.User.Email)
@@ -162,7 +170,9 @@ func LoginWithToken(cfg *config.Config, token string) error {
 	}
 
 	cfg.AuthToken = token
-	if err := cfg.Save(); err != nil {
+	cfg.RefreshToken = ""
+	cfg.TokenExpiresAt = tokenExpiry(token)
+	if err := saveTokens(cfg); err != nil {
 		return fmt.Errorf("failed to save auth token: %w", err)
 	}
 
@@ -178,7 +188,9 @@ func LoginWithToken(cfg *config.Config, token string) error {
 // Logout clears the auth token
 func Logout(cfg *config.Config) error {
 	cfg.AuthToken = ""
-	if err := cfg.Save(); err != nil {
+	cfg.RefreshToken = ""
+	cfg.TokenExpiresAt = time.Time{}
+	if err := saveTokens(cfg); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
@@ -264,8 +276,11 @@ func handleCallback(w http.ResponseWriter, r *http.Request, callbackChan chan *A
 		log.Error().Err(err).Msg("Failed to write success response")
 	}
 
+	expiresIn, _ := strconv.Atoi(r.URL.Query().Get("expires_in"))
 	authResp := &AuthResponse{
-		AccessToken: accessToken,
+		AccessToken:  accessToken,
+		RefreshToken: r.URL.Query().Get("refresh_token"),
+		ExpiresIn:    expiresIn,
 		User: User{
 			Email: r.URL.Query().Get("email"),
 		},
@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+
+	"github.com/awesome-directories/cli/internal/config"
+)
+
+const (
+	// refreshMargin is how long before expiry the session is refreshed
+	refreshMargin = 5 * time.Minute
+
+	minBackoff = 5 * time.Second
+	maxBackoff = 5 * time.Minute
+
+	// minRefreshInterval is the least time between two successful
+	// refreshes, for sessions with no known or a very short lifetime
+	minRefreshInterval = 30 * time.Second
+)
+
+// RefreshSession exchanges the stored refresh token for a new access token
+// and persists the new session to the config file
+func RefreshSession(ctx context.Context, cfg *config.Config) (*AuthResponse, error) {
+	if cfg.RefreshToken == "" {
+		return nil, fmt.Errorf("no refresh token available: please login again")
+	}
+
+	payload, err := json.Marshal(map[string]string{"refresh_token": cfg.RefreshToken})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	endpoint := cfg.SupabaseURL + "/auth/v1/token?grant_type=refresh_token"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("apikey", cfg.SupabaseAnonKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh session: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("session refresh failed (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var authResp AuthResponse
+	if err := json.NewDecoder(resp.Body).Decode(&authResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if err := saveSession(cfg, &authResp); err != nil {
+		return nil, err
+	}
+
+	return &authResp, nil
+}
+
+// Keepalive refreshes the session shortly before it expires until ctx is
+// cancelled. onRefresh is called with the new access token after every
+// successful refresh. Failures are retried with exponential backoff.
+func Keepalive(ctx context.Context, cfg *config.Config, onRefresh func(token string)) {
+	if cfg.AuthToken == "" {
+		return
+	}
+
+	if cfg.RefreshToken == "" {
+		if !cfg.TokenExpiresAt.IsZero() {
+			log.Warn().
+				Str("event", "session_not_refreshable").
+				Time("expires_at", cfg.TokenExpiresAt).
+				Msg("Session cannot be refreshed automatically; login again before it expires")
+		}
+		return
+	}
+
+	backoff := minBackoff
+
+	// early is the least wait before the next refresh. It grows while
+	// refreshes leave the session without an expiry beyond the margin, so
+	// they don't follow each other at once.
+	var early time.Duration
+	for {
+		wait := time.Until(cfg.TokenExpiresAt.Add(-refreshMargin))
+		if cfg.TokenExpiresAt.IsZero() || wait < early {
+			wait = early
+		}
+
+		log.Debug().
+			Str("event", "session_refresh_scheduled").
+			Dur("in", wait.Round(time.Second)).
+			Msg("Session refresh scheduled")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		authResp, err := RefreshSession(ctx, cfg)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.Warn().
+				Err(err).
+				Str("event", "session_refresh_failed").
+				Dur("retry_in", backoff).
+				Msg("Failed to refresh session")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff = min(backoff*2, maxBackoff)
+			continue
+		}
+
+		backoff = minBackoff
+		if cfg.TokenExpiresAt.IsZero() || time.Until(cfg.TokenExpiresAt.Add(-refreshMargin)) < minRefreshInterval {
+			early = min(max(early*2, minRefreshInterval), maxBackoff)
+		} else {
+			early = 0
+		}
+		log.Info().
+			Str("event", "session_refreshed").
+			Time("expires_at", cfg.TokenExpiresAt).
+			Msg("Session refreshed")
+
+		if onRefresh != nil {
+			onRefresh(authResp.AccessToken)
+		}
+	}
+}
+
+// saveSession stores the tokens of an auth response in cfg and the config
+// file
+func saveSession(cfg *config.Config, authResp *AuthResponse) error {
+	cfg.AuthToken = authResp.AccessToken
+	if authResp.RefreshToken != "" {
+		cfg.RefreshToken = authResp.RefreshToken
+	}
+
+	if authResp.ExpiresIn > 0 {
+		cfg.TokenExpiresAt = time.Now().Add(time.Duration(authResp.ExpiresIn) * time.Second)
+	} else {
+		cfg.TokenExpiresAt = tokenExpiry(authResp.AccessToken)
+	}
+
+	if err := saveTokens(cfg); err != nil {
+		return fmt.Errorf("failed to save auth token: %w", err)
+	}
+
+	return nil
+}
+
+// saveTokens writes the session of cfg to the config file, and nothing
+// else of it: cfg also holds what the environment set, such as tokens of
+// integrations, which isn't to end up in the file
+func saveTokens(cfg *config.Config) error {
+	if cfg.ReadOnly {
+		return config.ErrReadOnly
+	}
+	return config.UpdateFile(func(file *config.Config) {
+		file.AuthToken = cfg.AuthToken
+		file.RefreshToken = cfg.RefreshToken
+		file.TokenExpiresAt = cfg.TokenExpiresAt
+	})
+}
+
+// tokenClaims are the claims of a JWT access token used by the CLI
+type tokenClaims struct {
+	Exp int64  `json:"exp"`
+	Sub string `json:"sub"`
+}
+
+// parseToken reads the claims of a JWT access token without verifying it
+func parseToken(token string) (tokenClaims, bool) {
+	var claims tokenClaims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, false
+	}
+
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, false
+	}
+	return claims, true
+}
+
+// tokenExpiry reads the exp claim of a JWT access token. It returns the zero
+// time when the token cannot be parsed.
+func tokenExpiry(token string) time.Time {
+	claims, ok := parseToken(token)
+	if !ok || claims.Exp == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(claims.Exp, 0)
+}
+
+// TokenSubject returns the ID of the user an access token was issued to, or
+// "" when the token cannot be parsed
+func TokenSubject(token string) string {
+	claims, _ := parseToken(token)
+	return claims.Sub
+}
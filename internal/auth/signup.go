@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/internal/ui"
+)
+
+// MinPasswordLength is the minimum password length accepted by Supabase auth
+const MinPasswordLength = 6
+
+// signUpResponse represents the signup response. When email confirmation is
+// disabled a full session is returned, otherwise only the user.
+type signUpResponse struct {
+	AuthResponse
+	ID                 string `json:"id"`
+	Email              string `json:"email"`
+	ConfirmationSentAt string `json:"confirmation_sent_at"`
+}
+
+// SignUp creates a new account with email and password
+func SignUp(ctx context.Context, cfg *config.Config, email, password string) error {
+	if len(password) < MinPasswordLength {
+		return fmt.Errorf("password must be at least %d characters", MinPasswordLength)
+	}
+
+	var signUpResp signUpResponse
+	if err := postAuth(ctx, cfg, "/auth/v1/signup", map[string]string{
+		"email":    email,
+		"password": password,
+	}, &signUpResp); err != nil {
+		return fmt.Errorf("failed to sign up: %w", err)
+	}
+
+	// Email confirmation disabled: the account is ready to use
+	if signUpResp.AccessToken != "" {
+		if err := saveSession(cfg, &signUpResp.AuthResponse); err != nil {
+			return err
+		}
+		ui.Success("Account created and logged in as %s", email)
+		return nil
+	}
+
+	ui.Success("Account created for %s", email)
+	ui.Info("We sent a confirmation link to %s. Open it to activate your account.", email)
+	ui.Muted("Then login with: awesome-directories auth login --email %s", email)
+
+	return nil
+}
+
+// LoginWithPassword logs in with email and password
+func LoginWithPassword(ctx context.Context, cfg *config.Config, email, password string) error {
+	var authResp AuthResponse
+	if err := postAuth(ctx, cfg, "/auth/v1/token?grant_type=password", map[string]string{
+		"email":    email,
+		"password": password,
+	}, &authResp); err != nil {
+		return fmt.Errorf("failed to login: %w", err)
+	}
+
+	if err := saveSession(cfg, &authResp); err != nil {
+		return err
+	}
+
+	ui.Success("Successfully authenticated as %s", email)
+	return nil
+}
+
+// postAuth sends a JSON payload to a Supabase auth endpoint and decodes the
+// response into out
+func postAuth(ctx context.Context, cfg *config.Config, path string, payload interface{}, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", cfg.SupabaseURL+path, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("apikey", cfg.SupabaseAnonKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := getHTTPClient().Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, authErrorMessage(respBody))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// authErrorMessage extracts a readable message from a Supabase auth error body
+func authErrorMessage(body []byte) string {
+	var authErr struct {
+		Msg              string `json:"msg"`
+		Message          string `json:"message"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &authErr); err == nil {
+		for _, msg := range []string{authErr.Msg, authErr.Message, authErr.ErrorDescription} {
+			if msg != "" {
+				return msg
+			}
+		}
+	}
+	return string(body)
+}
@@ -0,0 +1,106 @@
+// Package badges awards the labels that make high-value directories stand
+// out in tables and detail views, such as the highest rated ones or those
+// both free and dofollow.
+package badges
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/awesome-directories/cli/internal/config"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// Names of the badges, as used to disable them in the config file
+const (
+	TopDR        = "top-dr"
+	New          = "new"
+	FreeDofollow = "free-dofollow"
+)
+
+// Names lists every badge
+var Names = []string{TopDR, New, FreeDofollow}
+
+// DefaultTopDR is how many of the highest rated directories get the top DR
+// badge unless configured
+const DefaultTopDR = 100
+
+// Awarder awards badges to the directories of a catalog
+type Awarder struct {
+	enabled map[string]bool
+	plain   bool
+
+	// topN directories rated minDR or more get the top DR badge; minDR is 0
+	// when the catalog is too small for the badge to mean anything
+	topN  int
+	minDR int
+
+	// month is the start of the current month
+	month time.Time
+}
+
+// NewAwarder returns an awarder following cfg. minDR is the domain rating
+// of the cfg.TopDR-th highest rated directory of the catalog, 0 if unknown.
+// plain shows words instead of emoji, for terminals without them.
+func NewAwarder(cfg config.Badges, minDR int, plain bool, now time.Time) *Awarder {
+	a := &Awarder{
+		enabled: make(map[string]bool),
+		plain:   plain || cfg.Plain,
+		topN:    TopN(cfg),
+		minDR:   minDR,
+		month:   time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()),
+	}
+	for _, name := range Names {
+		a.enabled[name] = !disabled(cfg, name)
+	}
+	return a
+}
+
+// disabled reports whether the config file turns a badge off
+func disabled(cfg config.Badges, name string) bool {
+	return slices.ContainsFunc(cfg.Disabled, func(d string) bool {
+		return strings.EqualFold(d, name) || strings.EqualFold(d, "all")
+	})
+}
+
+// TopN returns how many of the highest rated directories get the top DR
+// badge
+func TopN(cfg config.Badges) int {
+	if cfg.TopDR > 0 {
+		return cfg.TopDR
+	}
+	return DefaultTopDR
+}
+
+// Enabled reports whether any badge is shown
+func Enabled(cfg config.Badges) bool {
+	return slices.ContainsFunc(Names, func(name string) bool { return !disabled(cfg, name) })
+}
+
+// Labels returns the badges of a directory, in a fixed order
+func (a *Awarder) Labels(dir models.Directory) []string {
+	var labels []string
+	add := func(name, emoji, text string) {
+		if !a.enabled[name] {
+			return
+		}
+		if a.plain {
+			labels = append(labels, "["+text+"]")
+		} else {
+			labels = append(labels, emoji+" "+text)
+		}
+	}
+
+	if a.minDR > 0 && dir.DomainRating >= a.minDR {
+		add(TopDR, "🏆", "top-"+strconv.Itoa(a.topN)+" DR")
+	}
+	if !dir.CreatedAt.IsZero() && !dir.CreatedAt.Before(a.month) {
+		add(New, "🆕", "added this month")
+	}
+	if strings.EqualFold(dir.Pricing, "free") && strings.EqualFold(dir.LinkType, "dofollow") {
+		add(FreeDofollow, "💎", "free+dofollow")
+	}
+	return labels
+}
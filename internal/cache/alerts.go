@@ -0,0 +1,120 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// Alert is a recorded catalog change
+type Alert struct {
+	Time    time.Time `json:"time"`
+	Kind    string    `json:"kind"` // added, removed, changed
+	Slug    string    `json:"slug"`
+	Name    string    `json:"name"`
+	Details string    `json:"details,omitempty"`
+}
+
+// RecordAlerts appends the changes of a change set to the alert log
+func (c *Cache) RecordAlerts(changes *ChangeSet) error {
+	if changes.Empty() {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	var alerts []Alert
+
+	for _, dir := range changes.Added {
+		alerts = append(alerts, Alert{Time: now, Kind: "added", Slug: dir.Slug, Name: dir.Name})
+	}
+	for _, dir := range changes.Removed {
+		alerts = append(alerts, Alert{Time: now, Kind: "removed", Slug: dir.Slug, Name: dir.Name})
+	}
+	for _, change := range changes.Changed {
+		details := make([]string, 0, len(change.Fields))
+		for _, field := range change.Fields {
+			details = append(details, fmt.Sprintf("%s: %s → %s", field.Field, field.Old, field.New))
+		}
+		alerts = append(alerts, Alert{
+			Time:    now,
+			Kind:    "changed",
+			Slug:    change.Directory.Slug,
+			Name:    change.Directory.Name,
+			Details: strings.Join(details, "; "),
+		})
+	}
+
+	if err := os.MkdirAll(c.cfg.CacheDir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	file, err := os.OpenFile(c.alertsFile(), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open alert log: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			c.logger(context.Background()).Error().Err(err).Msg("Failed to close alert log")
+		}
+	}()
+
+	encoder := json.NewEncoder(file)
+	for _, alert := range alerts {
+		if err := encoder.Encode(alert); err != nil {
+			return fmt.Errorf("failed to write alert: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// RecentAlerts returns up to limit of the most recent alerts, newest first
+func (c *Cache) RecentAlerts(limit int) ([]Alert, error) {
+	file, err := os.Open(c.alertsFile())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open alert log: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			c.logger(context.Background()).Error().Err(err).Msg("Failed to close alert log")
+		}
+	}()
+
+	var alerts []Alert
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var alert Alert
+		if err := json.Unmarshal(scanner.Bytes(), &alert); err != nil {
+			continue
+		}
+		alerts = append(alerts, alert)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read alert log: %w", err)
+	}
+
+	// Newest first
+	for i, j := 0, len(alerts)-1; i < j; i, j = i+1, j-1 {
+		alerts[i], alerts[j] = alerts[j], alerts[i]
+	}
+
+	if limit > 0 && len(alerts) > limit {
+		alerts = alerts[:limit]
+	}
+
+	return alerts, nil
+}
+
+// alertsFile returns the path of the alert log
+func (c *Cache) alertsFile() string {
+	return filepath.Join(c.cfg.CacheDir, "alerts.jsonl")
+}
@@ -0,0 +1,219 @@
+package cache
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// BundleFormat is the version of the bundle layout this CLI writes and reads
+const BundleFormat = 1
+
+// Bundle carries catalog updates to a machine without API access: the whole
+// catalog, or the directories updated since a time, with checksums of both
+// the bundle and the catalog it brings a cache to
+type Bundle struct {
+	Format    int       `json:"format"`
+	CreatedAt time.Time `json:"created_at"`
+
+	// Since is when the changes carried start, zero for the whole catalog
+	Since time.Time `json:"since"`
+
+	// IDs are the active directories of the catalog, so importing drops
+	// those removed since
+	IDs []string `json:"ids"`
+
+	// Directories are those updated since Since
+	Directories []models.Directory `json:"directories"`
+
+	// MissingColumns are directory fields the catalog lacks
+	MissingColumns []string `json:"missing_columns,omitempty"`
+
+	// CatalogChecksum is the checksum of the catalog once the bundle is
+	// imported
+	CatalogChecksum string `json:"catalog_checksum"`
+
+	// Checksum is the checksum of the bundle, computed with this field empty
+	Checksum string `json:"checksum"`
+}
+
+// BundleImport is a catalog brought up to date from a bundle, and how it
+// differs from the cached one
+type BundleImport struct {
+	Directories []models.Directory
+
+	Added   int
+	Updated int
+	Removed int
+
+	missing []string
+}
+
+// ExportBundle bundles the cached catalog: the directories updated since
+// the given time, or all of them when it is zero
+func (c *Cache) ExportBundle(since time.Time) (*Bundle, error) {
+	directories, err := c.loadFromCache()
+	if err != nil {
+		return nil, fmt.Errorf("no catalog cached, run sync first: %w", err)
+	}
+
+	b := &Bundle{
+		Format:      BundleFormat,
+		CreatedAt:   time.Now().UTC(),
+		Since:       since,
+		IDs:         make([]string, 0, len(directories)),
+		Directories: []models.Directory{},
+	}
+	for _, dir := range directories {
+		b.IDs = append(b.IDs, dir.ID)
+		if since.IsZero() || !dir.UpdatedAt.Before(since) {
+			b.Directories = append(b.Directories, dir)
+		}
+	}
+	if meta, err := c.loadMetadata(); err == nil {
+		b.MissingColumns = meta.MissingColumns
+	}
+
+	if b.CatalogChecksum, err = catalogChecksum(directories); err != nil {
+		return nil, err
+	}
+	if b.Checksum, err = b.checksum(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// PlanBundle brings the cached catalog up to date from a bundle without
+// saving it. A bundle of changes needs a cache current to its start, and
+// the result has to match the catalog the bundle was exported from.
+func (c *Cache) PlanBundle(b *Bundle) (*BundleImport, error) {
+	before, err := c.loadFromCache()
+	if err != nil && !b.Since.IsZero() {
+		return nil, errors.New("no catalog cached to apply changes to: import a bundle of the whole catalog, exported without --since")
+	}
+
+	// A bundle of the whole catalog replaces the cached one
+	var base []models.Directory
+	if !b.Since.IsZero() {
+		if current := CatalogUpdatedAt(before); b.Since.After(current) {
+			return nil, fmt.Errorf("the bundle has changes since %s but the cache is only current to %s: export a bundle with --since %s",
+				b.Since.Local().Format("2006-01-02 15:04"), current.Local().Format("2006-01-02 15:04"), current.Local().Format("2006-01-02"))
+		}
+		base = before
+	}
+
+	merged, _, ok := mergeDelta(base, b.IDs, b.Directories)
+	if !ok {
+		return nil, errors.New("the cache lacks directories the bundle doesn't carry: import a bundle of the whole catalog, exported without --since")
+	}
+
+	checksum, err := catalogChecksum(merged)
+	if err != nil {
+		return nil, err
+	}
+	if checksum != b.CatalogChecksum {
+		return nil, errors.New("the imported catalog doesn't match the exported one (checksum mismatch): import a bundle of the whole catalog, exported without --since")
+	}
+
+	imp := &BundleImport{Directories: merged, missing: b.MissingColumns}
+	beforeByID := make(map[string]models.Directory, len(before))
+	for _, dir := range before {
+		beforeByID[dir.ID] = dir
+	}
+	afterByID := make(map[string]bool, len(merged))
+	for _, dir := range merged {
+		afterByID[dir.ID] = true
+		if old, ok := beforeByID[dir.ID]; !ok {
+			imp.Added++
+		} else if !reflect.DeepEqual(old, dir) {
+			imp.Updated++
+		}
+	}
+	for id := range beforeByID {
+		if !afterByID[id] {
+			imp.Removed++
+		}
+	}
+	return imp, nil
+}
+
+// ImportBundle saves a catalog planned from a bundle to the cache
+func (c *Cache) ImportBundle(imp *BundleImport) error {
+	return c.saveCatalog(imp.Directories, imp.missing)
+}
+
+// WriteBundle writes a bundle, gzip-compressed
+func WriteBundle(w io.Writer, b *Bundle) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(b); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	return nil
+}
+
+// ReadBundle reads a bundle written by WriteBundle, verifying its checksum
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a catalog bundle: %w", err)
+	}
+	defer func() { _ = zr.Close() }()
+
+	var b Bundle
+	if err := json.NewDecoder(zr).Decode(&b); err != nil {
+		return nil, fmt.Errorf("failed to read bundle: %w", err)
+	}
+	if b.Format != BundleFormat {
+		return nil, fmt.Errorf("unsupported bundle format %d (this CLI reads format %d)", b.Format, BundleFormat)
+	}
+
+	checksum, err := b.checksum()
+	if err != nil {
+		return nil, err
+	}
+	if checksum != b.Checksum {
+		return nil, errors.New("the bundle is corrupt (checksum mismatch)")
+	}
+	return &b, nil
+}
+
+// checksum returns the checksum of a bundle, computed with its Checksum
+// field empty
+func (b *Bundle) checksum() (string, error) {
+	unsigned := *b
+	unsigned.Checksum = ""
+	data, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal bundle: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// catalogChecksum returns the checksum of a catalog, whatever the order of
+// its directories
+func catalogChecksum(directories []models.Directory) (string, error) {
+	sorted := make([]models.Directory, len(directories))
+	copy(sorted, directories)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+
+	data, err := json.Marshal(sorted)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal catalog: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
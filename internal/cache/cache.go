@@ -2,14 +2,17 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/goccy/go-json"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
 
 	"github.com/awesome-directories/cli/internal/api"
 	"github.com/awesome-directories/cli/internal/config"
@@ -22,6 +25,11 @@ type Cache struct {
 	apiClient *api.Client
 	cacheFile string
 	metaFile  string
+
+	log *zerolog.Logger // nil for the global logger
+
+	// provenance is where the last GetDirectories call read from
+	provenance *Provenance
 }
 
 // CacheMetadata holds cache metadata
@@ -29,6 +37,10 @@ type CacheMetadata struct {
 	LastUpdated time.Time `json:"last_updated"`
 	Version     string    `json:"version"`
 	Count       int       `json:"count"`
+
+	// MissingColumns are directory fields the backend doesn't provide, or
+	// that weren't fetched in low-bandwidth mode
+	MissingColumns []string `json:"missing_columns,omitempty"`
 }
 
 // NewCache creates a new cache instance
@@ -41,53 +53,144 @@ func NewCache(cfg *config.Config, apiClient *api.Client) *Cache {
 	}
 }
 
+// SetLogger makes the cache log to logger instead of the global logger.
+// A logger attached to the context of a call takes precedence.
+func (c *Cache) SetLogger(logger zerolog.Logger) {
+	c.log = &logger
+}
+
+// logger returns the logger for a call
+func (c *Cache) logger(ctx context.Context) *zerolog.Logger {
+	return api.LoggerFor(ctx, c.log)
+}
+
 // GetDirectories retrieves directories from cache or API
 func (c *Cache) GetDirectories(ctx context.Context, forceRefresh bool) ([]models.Directory, error) {
 	// Check if cache exists and is valid
 	if !forceRefresh && c.isCacheValid() {
-		log.Debug().Msg("Using cached directories")
+		c.logger(ctx).Debug().Msg("Using cached directories")
 		directories, err := c.loadFromCache()
 		if err == nil {
+			NormalizeCategories(directories)
+			c.readFrom(SourceCache)
 			return directories, nil
 		}
-		log.Warn().Err(err).Msg("Failed to load from cache, fetching from API")
+		c.logger(ctx).Warn().Err(err).Msg("Failed to load from cache, fetching from API")
 	}
 
 	// Fetch from API
-	log.Info().Msg("Fetching directories from API...")
-	directories, err := c.apiClient.GetDirectories(ctx, nil)
+	c.logger(ctx).Info().Msg("Fetching directories from API...")
+	directories, err := c.fetchDirectories(ctx)
 	if err != nil {
 		// If API fails, try to use stale cache as fallback
 		if cachedDirs, cacheErr := c.loadFromCache(); cacheErr == nil {
-			log.Warn().Msg("API failed, using stale cache")
+			c.logger(ctx).Warn().Err(err).Msg("API failed, using stale cache")
+			NormalizeCategories(cachedDirs)
+			c.readFrom(SourceStaleCache)
 			return cachedDirs, nil
 		}
 		return nil, fmt.Errorf("failed to fetch directories: %w", err)
 	}
 
-	// Save to cache
+	// Save to cache, as received so changing aliases applies to it
 	if err := c.saveToCache(directories); err != nil {
-		log.Warn().Err(err).Msg("Failed to save to cache")
+		c.logger(ctx).Warn().Err(err).Msg("Failed to save to cache")
 	}
+	NormalizeCategories(directories)
+	c.readFrom(SourceAPI)
 
 	return directories, nil
 }
 
+// GetDirectory returns a directory by slug from the cache while it is fresh,
+// or else from the API, falling back to the stale cache when the API fails
+func (c *Cache) GetDirectory(ctx context.Context, slug string) (*models.Directory, error) {
+	return c.getDirectory(ctx, slug, false)
+}
+
+// RefreshDirectory returns a directory by slug from the API, falling back to
+// the cache when the API fails
+func (c *Cache) RefreshDirectory(ctx context.Context, slug string) (*models.Directory, error) {
+	return c.getDirectory(ctx, slug, true)
+}
+
+func (c *Cache) getDirectory(ctx context.Context, slug string, forceRefresh bool) (*models.Directory, error) {
+	if current := c.Lineage().Current(slug, BySlug); current != slug {
+		c.logger(ctx).Debug().Str("from", slug).Str("to", current).Msg("Following directory rename")
+		slug = current
+	}
+
+	cached := func() *models.Directory {
+		directories, err := c.loadFromCache()
+		if err != nil {
+			return nil
+		}
+		for _, dir := range directories {
+			if dir.Slug == slug {
+				dirs := []models.Directory{dir}
+				NormalizeCategories(dirs)
+				return &dirs[0]
+			}
+		}
+		return nil
+	}
+
+	// The index tells whether the catalog has the directory without loading
+	// it
+	if !forceRefresh && c.isCacheValid() {
+		if index, err := c.Index(); err != nil || index.Has(slug) {
+			if dir := cached(); dir != nil {
+				c.logger(ctx).Debug().Str("slug", slug).Msg("Using cached directory")
+				return dir, nil
+			}
+		}
+	}
+
+	dir, err := c.apiClient.GetDirectory(ctx, slug)
+	if err != nil {
+		if errors.Is(err, api.ErrDirectoryNotFound) {
+			return nil, err
+		}
+		if stale := cached(); stale != nil {
+			c.logger(ctx).Warn().Msg("API failed, using stale cache")
+			return stale, nil
+		}
+		return nil, err
+	}
+
+	dirs := []models.Directory{*dir}
+	NormalizeCategories(dirs)
+	return &dirs[0], nil
+}
+
 // Sync forces a cache refresh
 func (c *Cache) Sync(ctx context.Context) error {
-	log.Info().Msg("Syncing cache with API...")
+	c.logger(ctx).Info().Msg("Syncing cache with API...")
 
-	directories, err := c.apiClient.GetDirectories(ctx, nil)
+	directories, err := c.Refresh(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to fetch directories: %w", err)
+		return err
+	}
+
+	c.logger(ctx).Info().Int("count", len(directories)).Msg("Cache synced successfully")
+	return nil
+}
+
+// Refresh fetches directories from the API, stores them in the cache and
+// returns them
+func (c *Cache) Refresh(ctx context.Context) ([]models.Directory, error) {
+	directories, err := c.fetchDirectories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch directories: %w", err)
 	}
 
 	if err := c.saveToCache(directories); err != nil {
-		return fmt.Errorf("failed to save to cache: %w", err)
+		return nil, fmt.Errorf("failed to save to cache: %w", err)
 	}
+	c.autoGC()
+	NormalizeCategories(directories)
 
-	log.Info().Int("count", len(directories)).Msg("Cache synced successfully")
-	return nil
+	return directories, nil
 }
 
 // FilterDirectories filters directories based on criteria
@@ -99,110 +202,171 @@ func (c *Cache) FilterDirectories(directories []models.Directory, options *model
 	var filtered []models.Directory
 
 	for _, dir := range directories {
-		// Skip inactive directories
-		if !dir.IsActive {
-			continue
+		if Matches(dir, options) {
+			filtered = append(filtered, dir)
 		}
+	}
 
-		// Query filter (search in name and description)
-		if options.Query != "" {
-			query := strings.ToLower(options.Query)
-			name := strings.ToLower(dir.Name)
-			desc := strings.ToLower(dir.Description)
+	// Sort filtered results
+	c.sortDirectories(filtered, options.SortBy)
 
-			if !strings.Contains(name, query) && !strings.Contains(desc, query) {
-				continue
-			}
+	// Apply pagination
+	if options.Limit > 0 || options.Offset > 0 {
+		start := options.Offset
+		if start >= len(filtered) {
+			return []models.Directory{}
 		}
 
-		// Category filter
-		if len(options.Categories) > 0 {
-			hasCategory := false
-			for _, cat := range options.Categories {
-				for _, dirCat := range dir.Categories {
-					if strings.EqualFold(cat, dirCat) {
-						hasCategory = true
-						break
-					}
-				}
-				if hasCategory {
-					break
-				}
-			}
-			if !hasCategory {
-				continue
-			}
+		end := len(filtered)
+		if options.Limit > 0 && start+options.Limit < end {
+			end = start + options.Limit
 		}
 
-		// Pricing filter
-		if len(options.Pricing) > 0 {
-			hasPrice := false
-			for _, price := range options.Pricing {
-				if strings.EqualFold(price, dir.Pricing) {
-					hasPrice = true
-					break
-				}
-			}
-			if !hasPrice {
-				continue
-			}
+		filtered = filtered[start:end]
+	}
+
+	return filtered
+}
+
+// Matches reports whether a directory satisfies the filter criteria, ignoring
+// sorting and pagination
+func Matches(dir models.Directory, options *models.FilterOptions) bool {
+	if options == nil {
+		return true
+	}
+
+	// Skip inactive directories
+	if !dir.IsActive {
+		return false
+	}
+
+	// Query filter (search in name and description)
+	if options.Query != "" {
+		query := strings.ToLower(options.Query)
+		name := strings.ToLower(dir.Name)
+		desc := strings.ToLower(dir.Description)
+
+		if !strings.Contains(name, query) && !strings.Contains(desc, query) {
+			return false
 		}
+	}
 
-		// Link type filter
-		if len(options.LinkType) > 0 {
-			hasLinkType := false
-			for _, lt := range options.LinkType {
-				if strings.EqualFold(lt, dir.LinkType) {
-					hasLinkType = true
+	// Category filter
+	if len(options.Categories) > 0 {
+		hasCategory := false
+		for _, cat := range options.Categories {
+			for _, dirCat := range dir.Categories {
+				if MatchCategory(cat, dirCat) {
+					hasCategory = true
 					break
 				}
 			}
-			if !hasLinkType {
-				continue
+			if hasCategory {
+				break
 			}
 		}
+		if !hasCategory {
+			return false
+		}
+	}
 
-		// DR filter
-		if options.DRMin > 0 && dir.DomainRating > 0 {
-			if dir.DomainRating < options.DRMin {
-				continue
+	// Pricing filter
+	if len(options.Pricing) > 0 {
+		hasPrice := false
+		for _, price := range options.Pricing {
+			if strings.EqualFold(price, dir.Pricing) {
+				hasPrice = true
+				break
 			}
 		}
-		if options.DRMax > 0 && dir.DomainRating > 0 {
-			if dir.DomainRating > options.DRMax {
-				continue
-			}
+		if !hasPrice {
+			return false
 		}
-
-		filtered = append(filtered, dir)
 	}
 
-	// Sort filtered results
-	c.sortDirectories(filtered, options.SortBy)
+	// Link type filter
+	if len(options.LinkType) > 0 {
+		hasLinkType := false
+		for _, lt := range options.LinkType {
+			if strings.EqualFold(lt, dir.LinkType) {
+				hasLinkType = true
+				break
+			}
+		}
+		if !hasLinkType {
+			return false
+		}
+	}
 
-	// Apply pagination
-	if options.Limit > 0 {
-		start := options.Offset
-		end := start + options.Limit
+	// Locale filters. Directories without locale metadata are assumed to
+	// accept everyone.
+	if len(options.Countries) > 0 && len(dir.Countries) > 0 && !containsAnyFold(dir.Countries, options.Countries) {
+		return false
+	}
+	if len(options.Languages) > 0 && len(dir.Languages) > 0 && !containsAnyFold(dir.Languages, options.Languages) {
+		return false
+	}
+	if options.Audience != "" && dir.Audience != "" && !strings.EqualFold(dir.Audience, "both") &&
+		!strings.EqualFold(dir.Audience, options.Audience) {
+		return false
+	}
 
-		if start >= len(filtered) {
-			return []models.Directory{}
+	// Price filters. Paid directories without a known price are excluded
+	// when a maximum price is set.
+	if dir.PriceAmount > 0 {
+		if options.Currency != "" && !strings.EqualFold(dir.PriceCurrency, options.Currency) {
+			return false
 		}
-		if end > len(filtered) {
-			end = len(filtered)
+		if options.MaxPrice > 0 && dir.PriceAmount > options.MaxPrice {
+			return false
 		}
+	} else if options.MaxPrice > 0 && strings.EqualFold(dir.Pricing, "paid") {
+		return false
+	}
 
-		filtered = filtered[start:end]
+	// DR filter
+	if options.DRMin > 0 && dir.DomainRating > 0 {
+		if dir.DomainRating < options.DRMin {
+			return false
+		}
+	}
+	if options.DRMax > 0 && dir.DomainRating > 0 {
+		if dir.DomainRating > options.DRMax {
+			return false
+		}
 	}
 
-	return filtered
+	return true
+}
+
+// containsAnyFold reports whether values contains any of wanted, ignoring case
+func containsAnyFold(values, wanted []string) bool {
+	for _, w := range wanted {
+		for _, v := range values {
+			if strings.EqualFold(v, w) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
-// sortDirectories sorts directories based on sort option
+// sortDirectories sorts directories based on sort option, in the order the
+// API sorts them by. Ties keep their order.
 func (c *Cache) sortDirectories(directories []models.Directory, sortBy string) {
-	// Implement sorting logic
-	// For now, directories are already sorted by the API
-	// We can add client-side sorting if needed
+	var less func(a, b models.Directory) bool
+	switch sortBy {
+	case string(models.SortHighestDR):
+		less = func(a, b models.Directory) bool { return a.DomainRating > b.DomainRating }
+	case string(models.SortNewest):
+		less = func(a, b models.Directory) bool { return a.CreatedAt.After(b.CreatedAt) }
+	case string(models.SortAlpha):
+		less = func(a, b models.Directory) bool { return a.Name < b.Name }
+	default:
+		less = func(a, b models.Directory) bool { return a.HelpfulCount > b.HelpfulCount }
+	}
+
+	sort.SliceStable(directories, func(i, j int) bool { return less(directories[i], directories[j]) })
 }
 
 // isCacheValid checks if the cache is still valid
@@ -213,8 +377,8 @@ func (c *Cache) isCacheValid() bool {
 	}
 
 	// Check if cache is expired
-	if time.Since(meta.LastUpdated) > c.cfg.CacheTTL {
-		log.Debug().Dur("age", time.Since(meta.LastUpdated)).Msg("Cache expired")
+	if time.Since(meta.LastUpdated) > c.cfg.DirectoriesTTL() {
+		c.logger(context.Background()).Debug().Dur("age", time.Since(meta.LastUpdated)).Msg("Cache expired")
 		return false
 	}
 
@@ -226,6 +390,12 @@ func (c *Cache) isCacheValid() bool {
 	return true
 }
 
+// Fresh reports whether the cached catalog is younger than its TTL, so that
+// reading it doesn't ask the API
+func (c *Cache) Fresh() bool {
+	return c.isCacheValid()
+}
+
 // loadFromCache loads directories from cache file
 func (c *Cache) loadFromCache() ([]models.Directory, error) {
 	data, err := os.ReadFile(c.cacheFile)
@@ -243,11 +413,32 @@ func (c *Cache) loadFromCache() ([]models.Directory, error) {
 
 // saveToCache saves directories to cache file
 func (c *Cache) saveToCache(directories []models.Directory) error {
+	var missing []string
+	if c.apiClient != nil {
+		missing = c.apiClient.MissingColumns()
+		for _, column := range c.apiClient.SkippedColumns() {
+			if !slices.Contains(missing, column) {
+				missing = append(missing, column)
+			}
+		}
+		sort.Strings(missing)
+	}
+	return c.saveCatalog(directories, missing)
+}
+
+// saveCatalog saves directories to the cache file, recording the directory
+// fields they lack
+func (c *Cache) saveCatalog(directories []models.Directory, missing []string) error {
 	// Ensure cache directory exists
 	if err := os.MkdirAll(c.cfg.CacheDir, 0755); err != nil {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
+	// Renames are found by comparing with the catalog being replaced
+	if err := c.recordLineage(context.Background(), directories); err != nil {
+		c.logger(context.Background()).Warn().Err(err).Msg("Failed to record directory renames")
+	}
+
 	// Marshal directories
 	data, err := json.MarshalIndent(directories, "", "  ")
 	if err != nil {
@@ -261,16 +452,22 @@ func (c *Cache) saveToCache(directories []models.Directory) error {
 
 	// Update metadata
 	meta := CacheMetadata{
-		LastUpdated: time.Now(),
-		Version:     "1.0",
-		Count:       len(directories),
+		LastUpdated:    time.Now(),
+		Version:        "1.0",
+		Count:          len(directories),
+		MissingColumns: missing,
 	}
 
 	if err := c.saveMetadata(meta); err != nil {
 		return fmt.Errorf("failed to save metadata: %w", err)
 	}
 
-	log.Debug().Int("count", len(directories)).Msg("Cache saved successfully")
+	// Lookups derived from the catalog, replacing those of the last sync
+	if err := c.saveIndex(buildIndex(directories, meta.LastUpdated)); err != nil {
+		c.logger(context.Background()).Warn().Err(err).Msg("Failed to save cache index")
+	}
+
+	c.logger(context.Background()).Debug().Int("count", len(directories)).Msg("Cache saved successfully")
 	return nil
 }
 
@@ -303,6 +500,21 @@ func (c *Cache) saveMetadata(meta CacheMetadata) error {
 	return nil
 }
 
+// Metadata returns the metadata of the cached catalog
+func (c *Cache) Metadata() (*CacheMetadata, error) {
+	return c.loadMetadata()
+}
+
+// MissingColumns returns the directory fields the backend didn't provide
+// when the catalog was cached
+func (c *Cache) MissingColumns() []string {
+	meta, err := c.loadMetadata()
+	if err != nil {
+		return nil
+	}
+	return meta.MissingColumns
+}
+
 // GetCacheInfo returns cache information
 func (c *Cache) GetCacheInfo() (map[string]interface{}, error) {
 	info := make(map[string]interface{})
@@ -320,6 +532,9 @@ func (c *Cache) GetCacheInfo() (map[string]interface{}, error) {
 	info["age"] = time.Since(meta.LastUpdated).Round(time.Second).String()
 	info["valid"] = c.isCacheValid()
 	info["cache_file"] = c.cacheFile
+	if len(meta.MissingColumns) > 0 {
+		info["missing_columns"] = meta.MissingColumns
+	}
 
 	return info, nil
 }
@@ -334,6 +549,14 @@ func (c *Cache) Clear() error {
 		return fmt.Errorf("failed to remove metadata file: %w", err)
 	}
 
-	log.Info().Msg("Cache cleared successfully")
+	if err := os.Remove(c.indexFile()); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove index file: %w", err)
+	}
+
+	if err := c.InvalidateUserData(); err != nil {
+		return err
+	}
+
+	c.logger(context.Background()).Info().Msg("Cache cleared successfully")
 	return nil
 }
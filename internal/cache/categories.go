@@ -0,0 +1,221 @@
+package cache
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// CategorySeparator separates the levels of hierarchical category names,
+// such as Marketing/SEO
+const CategorySeparator = "/"
+
+// DefaultCategoryAliases map other names upstream uses for a category to the
+// name directories are grouped and filtered under
+var DefaultCategoryAliases = map[string]string{
+	"AI":                      "AI Tools",
+	"AI Tool":                 "AI Tools",
+	"Artificial Intelligence": "AI Tools",
+	"Dev Tools":               "Developer Tools",
+	"DevTools":                "Developer Tools",
+	"Developer Tool":          "Developer Tools",
+	"No Code":                 "No-Code",
+	"Nocode":                  "No-Code",
+	"Software as a Service":   "SaaS",
+}
+
+var (
+	categoryMu sync.RWMutex
+
+	// categoryNames maps lowercased aliases and canonical names to the
+	// canonical name
+	categoryNames = categoryLookup(nil)
+)
+
+// SetCategoryAliases adds aliases, such as those of the config file, to the
+// default ones. An alias given here replaces a default one.
+func SetCategoryAliases(aliases map[string]string) {
+	lookup := categoryLookup(aliases)
+
+	categoryMu.Lock()
+	defer categoryMu.Unlock()
+	categoryNames = lookup
+}
+
+// categoryLookup builds the case-insensitive lookup of the default aliases
+// and extra ones
+func categoryLookup(extra map[string]string) map[string]string {
+	lookup := make(map[string]string)
+	for _, aliases := range []map[string]string{DefaultCategoryAliases, extra} {
+		for alias, canonical := range aliases {
+			canonical = collapseSpace(canonical)
+			lookup[strings.ToLower(collapseSpace(alias))] = canonical
+			lookup[strings.ToLower(canonical)] = canonical
+		}
+	}
+
+	// A default alias follows its canonical name when the config renames it
+	for alias, canonical := range lookup {
+		if renamed, ok := lookup[strings.ToLower(canonical)]; ok {
+			lookup[alias] = renamed
+		}
+	}
+	return lookup
+}
+
+// NormalizeCategory returns the name a category is known under: the
+// canonical name of an alias, or the name with extra spaces removed
+func NormalizeCategory(name string) string {
+	name = collapseSpace(name)
+
+	categoryMu.RLock()
+	defer categoryMu.RUnlock()
+	if canonical, ok := categoryNames[strings.ToLower(name)]; ok {
+		return canonical
+	}
+	return name
+}
+
+// NormalizeCategories renames the categories of directories to their
+// canonical names, dropping those that become duplicates
+func NormalizeCategories(directories []models.Directory) {
+	for i := range directories {
+		var categories []string
+		for _, category := range directories[i].Categories {
+			category = NormalizeCategory(category)
+			if category != "" && !slices.ContainsFunc(categories, func(c string) bool { return strings.EqualFold(c, category) }) {
+				categories = append(categories, category)
+			}
+		}
+		directories[i].Categories = categories
+	}
+}
+
+// SameCategory reports whether two category names refer to the same
+// category
+func SameCategory(a, b string) bool {
+	return strings.EqualFold(NormalizeCategory(a), NormalizeCategory(b))
+}
+
+// MatchCategory reports whether a directory category matches a category
+// filter. A filter ending in "/*", such as Marketing/*, matches the category
+// and all of its subcategories.
+func MatchCategory(filter, category string) bool {
+	parent, ok := strings.CutSuffix(strings.TrimSpace(filter), CategorySeparator+"*")
+	if !ok {
+		return SameCategory(filter, category)
+	}
+
+	parent = strings.ToLower(NormalizeCategory(parent))
+	category = strings.ToLower(NormalizeCategory(category))
+	return category == parent || strings.HasPrefix(category, parent+CategorySeparator)
+}
+
+// CategoryNode is a category in the tree of hierarchical categories
+type CategoryNode struct {
+	// Name is the last level of the category, such as SEO
+	Name string `json:"name"`
+
+	// Path is the full name of the category, such as Marketing/SEO
+	Path string `json:"path"`
+
+	// Count is the number of directories in the category or any of its
+	// subcategories, each counted once
+	Count int `json:"count"`
+
+	// Direct is the number of directories in the category itself
+	Direct int `json:"direct"`
+
+	Children []*CategoryNode `json:"children,omitempty"`
+}
+
+// CategoryTree counts the directories of each category, with subcategories
+// under their parent. Categories are sorted by count, then name.
+func CategoryTree(directories []models.Directory) []*CategoryNode {
+	var roots []*CategoryNode
+	nodes := make(map[string]*CategoryNode)
+
+	for _, dir := range directories {
+		counted := make(map[*CategoryNode]bool)
+		for _, category := range dir.Categories {
+			category = NormalizeCategory(category)
+			if category == "" {
+				continue
+			}
+			levels := strings.Split(category, CategorySeparator)
+
+			var parent *CategoryNode
+			for i, level := range levels {
+				path := strings.Join(levels[:i+1], CategorySeparator)
+				node, ok := nodes[strings.ToLower(path)]
+				if !ok {
+					node = &CategoryNode{Name: level, Path: path}
+					nodes[strings.ToLower(path)] = node
+					if parent == nil {
+						roots = append(roots, node)
+					} else {
+						parent.Children = append(parent.Children, node)
+					}
+				}
+
+				if !counted[node] {
+					node.Count++
+					counted[node] = true
+				}
+				parent = node
+			}
+			parent.Direct++
+		}
+	}
+
+	sortCategoryNodes(roots)
+	return roots
+}
+
+// FlattenCategories lists the categories of a tree and their subcategories
+// together, without children, sorted by count
+func FlattenCategories(tree []*CategoryNode) []*CategoryNode {
+	var flat []*CategoryNode
+	var walk func(nodes []*CategoryNode)
+	walk = func(nodes []*CategoryNode) {
+		for _, node := range nodes {
+			leaf := *node
+			leaf.Children = nil
+			flat = append(flat, &leaf)
+			walk(node.Children)
+		}
+	}
+	walk(tree)
+
+	sortCategoryNodes(flat)
+	return flat
+}
+
+// sortCategoryNodes sorts categories and their subcategories by count, then
+// name
+func sortCategoryNodes(nodes []*CategoryNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Count != nodes[j].Count {
+			return nodes[i].Count > nodes[j].Count
+		}
+		return strings.ToLower(nodes[i].Name) < strings.ToLower(nodes[j].Name)
+	})
+	for _, node := range nodes {
+		sortCategoryNodes(node.Children)
+	}
+}
+
+// collapseSpace removes extra spaces from a category name, including around
+// the separators of hierarchical names
+func collapseSpace(s string) string {
+	var levels []string
+	for _, level := range strings.Split(s, CategorySeparator) {
+		if level = strings.Join(strings.Fields(level), " "); level != "" {
+			levels = append(levels, level)
+		}
+	}
+	return strings.Join(levels, CategorySeparator)
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// fetchDirectories fetches the catalog from the API. In low-bandwidth mode
+// with a catalog cached, only the directories changed since are fetched and
+// merged into it.
+func (c *Cache) fetchDirectories(ctx context.Context) ([]models.Directory, error) {
+	if c.cfg.LowBandwidth {
+		if cached, err := c.loadFromCache(); err == nil {
+			if merged, ok, err := c.fetchDelta(ctx, cached); err != nil || ok {
+				return merged, err
+			}
+		}
+	}
+	return c.apiClient.GetDirectories(ctx, nil)
+}
+
+// fetchDelta fetches the IDs of the active directories and those updated
+// since the newest cached one, and merges them into the cached catalog. ok
+// is false when the catalog has to be fetched whole instead.
+func (c *Cache) fetchDelta(ctx context.Context, cached []models.Directory) (merged []models.Directory, ok bool, err error) {
+	since := CatalogUpdatedAt(cached)
+	if since.IsZero() {
+		return nil, false, nil
+	}
+
+	ids, err := c.apiClient.GetDirectoryIDs(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	changed, err := c.apiClient.GetDirectoriesUpdatedSince(ctx, since)
+	if err != nil {
+		return nil, false, err
+	}
+
+	merged, removed, ok := mergeDelta(cached, ids, changed)
+	if !ok {
+		c.logger(ctx).Debug().Msg("Active directories neither cached nor updated, fetching the whole catalog")
+		return nil, false, nil
+	}
+
+	c.logger(ctx).Debug().
+		Int("updated", len(changed)).
+		Int("removed", removed).
+		Msg("Merged catalog changes")
+	return merged, true, nil
+}
+
+// CatalogUpdatedAt returns when the most recently updated directory of a
+// catalog was updated, zero when none has an update time
+func CatalogUpdatedAt(directories []models.Directory) time.Time {
+	var newest time.Time
+	for _, dir := range directories {
+		if dir.UpdatedAt.After(newest) {
+			newest = dir.UpdatedAt
+		}
+	}
+	return newest
+}
+
+// mergeDelta merges the directories changed since a catalog was cached into
+// it, dropping those whose IDs are no longer active, ordered as the API
+// orders the catalog. ok is false when some active directory is neither
+// cached nor changed, such as one made active again without being updated,
+// and the catalog can't be brought up to date from the delta.
+func mergeDelta(cached []models.Directory, ids []string, changed []models.Directory) (merged []models.Directory, removed int, ok bool) {
+	active := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		active[id] = true
+	}
+	updates := make(map[string]models.Directory, len(changed))
+	for _, dir := range changed {
+		updates[dir.ID] = dir
+	}
+
+	merged = make([]models.Directory, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, dir := range cached {
+		if !active[dir.ID] {
+			removed++
+			continue
+		}
+		if seen[dir.ID] {
+			continue
+		}
+		if update, ok := updates[dir.ID]; ok {
+			dir = update
+		}
+		seen[dir.ID] = true
+		merged = append(merged, dir)
+	}
+	for _, dir := range changed {
+		if !seen[dir.ID] {
+			seen[dir.ID] = true
+			merged = append(merged, dir)
+		}
+	}
+
+	for _, id := range ids {
+		if !seen[id] {
+			return nil, 0, false
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool { return merged[i].HelpfulCount > merged[j].HelpfulCount })
+	return merged, removed, true
+}
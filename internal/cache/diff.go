@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// FieldChange describes a single changed field of a directory
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// DirectoryChange describes all changed fields of a directory
+type DirectoryChange struct {
+	Directory models.Directory `json:"directory"`
+	Previous  models.Directory `json:"-"`
+	Fields    []FieldChange    `json:"fields"`
+}
+
+// ChangeSet holds the differences between two sets of directories
+type ChangeSet struct {
+	Added   []models.Directory `json:"added"`
+	Removed []models.Directory `json:"removed"`
+	Changed []DirectoryChange  `json:"changed"`
+}
+
+// Empty reports whether the change set contains no changes
+func (cs *ChangeSet) Empty() bool {
+	return len(cs.Added) == 0 && len(cs.Removed) == 0 && len(cs.Changed) == 0
+}
+
+// Filter returns the changes affecting directories for which keep returns
+// true. Changed directories are kept when either their old or new state
+// matches.
+func (cs *ChangeSet) Filter(keep func(models.Directory) bool) *ChangeSet {
+	filtered := &ChangeSet{
+		Added:   []models.Directory{},
+		Removed: []models.Directory{},
+		Changed: []DirectoryChange{},
+	}
+
+	for _, dir := range cs.Added {
+		if keep(dir) {
+			filtered.Added = append(filtered.Added, dir)
+		}
+	}
+
+	for _, dir := range cs.Removed {
+		if keep(dir) {
+			filtered.Removed = append(filtered.Removed, dir)
+		}
+	}
+
+	for _, change := range cs.Changed {
+		if keep(change.Directory) || keep(change.Previous) {
+			filtered.Changed = append(filtered.Changed, change)
+		}
+	}
+
+	return filtered
+}
+
+// DiffDirectories compares two sets of directories by ID
+func DiffDirectories(before, after []models.Directory) *ChangeSet {
+	cs := &ChangeSet{
+		Added:   []models.Directory{},
+		Removed: []models.Directory{},
+		Changed: []DirectoryChange{},
+	}
+
+	beforeByID := make(map[string]models.Directory, len(before))
+	for _, dir := range before {
+		beforeByID[dir.ID] = dir
+	}
+
+	afterByID := make(map[string]bool, len(after))
+	for _, dir := range after {
+		afterByID[dir.ID] = true
+
+		old, ok := beforeByID[dir.ID]
+		if !ok {
+			cs.Added = append(cs.Added, dir)
+			continue
+		}
+
+		if fields := diffFields(old, dir); len(fields) > 0 {
+			cs.Changed = append(cs.Changed, DirectoryChange{Directory: dir, Previous: old, Fields: fields})
+		}
+	}
+
+	for _, dir := range before {
+		if !afterByID[dir.ID] {
+			cs.Removed = append(cs.Removed, dir)
+		}
+	}
+
+	sort.Slice(cs.Added, func(i, j int) bool { return cs.Added[i].Name < cs.Added[j].Name })
+	sort.Slice(cs.Removed, func(i, j int) bool { return cs.Removed[i].Name < cs.Removed[j].Name })
+	sort.Slice(cs.Changed, func(i, j int) bool { return cs.Changed[i].Directory.Name < cs.Changed[j].Directory.Name })
+
+	return cs
+}
+
+// diffFields returns the tracked fields that differ between two directories
+func diffFields(old, cur models.Directory) []FieldChange {
+	var fields []FieldChange
+
+	compare := func(field, a, b string) {
+		if a != b {
+			fields = append(fields, FieldChange{Field: field, Old: a, New: b})
+		}
+	}
+
+	compare("name", old.Name, cur.Name)
+	compare("slug", old.Slug, cur.Slug)
+	compare("url", old.URL, cur.URL)
+	compare("domain_rating", strconv.Itoa(old.DomainRating), strconv.Itoa(cur.DomainRating))
+	compare("pricing", old.Pricing, cur.Pricing)
+	compare("price", ui.FormatPrice(old.PriceAmount, old.PriceCurrency), ui.FormatPrice(cur.PriceAmount, cur.PriceCurrency))
+	compare("link_type", old.LinkType, cur.LinkType)
+	compare("review_days", strconv.Itoa(old.ReviewDays), strconv.Itoa(cur.ReviewDays))
+	compare("categories", strings.Join(old.Categories, ", "), strings.Join(cur.Categories, ", "))
+	compare("submission_url", old.SubmissionURL, cur.SubmissionURL)
+	compare("is_active", strconv.FormatBool(old.IsActive), strconv.FormatBool(cur.IsActive))
+
+	return fields
+}
@@ -0,0 +1,300 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/goccy/go-json"
+)
+
+// GCOptions controls what GC prunes
+type GCOptions struct {
+	// MaxSize is the size in bytes the cache is pruned down to, removing
+	// the oldest crash reports, then snapshots, then alerts. Zero means no
+	// limit.
+	MaxSize int64
+
+	// Before prunes alerts and crash reports older than it, when set
+	Before time.Time
+
+	// DryRun reports what would be pruned without removing anything
+	DryRun bool
+}
+
+// GCResult reports what GC pruned
+type GCResult struct {
+	SizeBefore   int64
+	SizeAfter    int64
+	Snapshots    []string
+	Alerts       int
+	CrashReports []string
+}
+
+// Reclaimed returns the number of bytes freed
+func (r *GCResult) Reclaimed() int64 {
+	return r.SizeBefore - r.SizeAfter
+}
+
+// gcFile is a prunable file with its age and size
+type gcFile struct {
+	name    string
+	path    string
+	size    int64
+	created time.Time
+}
+
+// alertLine is a raw line of the alert log with its time
+type alertLine struct {
+	data []byte
+	time time.Time
+}
+
+// DiskUsage returns the bytes used by the cache dir and crash reports
+func (c *Cache) DiskUsage() (int64, error) {
+	var total int64
+	for _, dir := range []string{c.cfg.CacheDir, c.crashDir()} {
+		size, err := dirSize(dir)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// GC prunes old snapshots, alerts and crash reports. The cached catalog and
+// the most recent snapshot are never removed.
+func (c *Cache) GC(options GCOptions) (*GCResult, error) {
+	size, err := c.DiskUsage()
+	if err != nil {
+		return nil, err
+	}
+	result := &GCResult{SizeBefore: size}
+
+	alerts, err := c.readAlertLines()
+	if err != nil {
+		return nil, err
+	}
+	crashes, err := c.crashReports()
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := c.snapshotFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var removeFiles []gcFile
+	keptAlerts := alerts[:0:0]
+
+	if !options.Before.IsZero() {
+		for _, alert := range alerts {
+			if alert.time.Before(options.Before) {
+				size -= int64(len(alert.data)) + 1
+				result.Alerts++
+				continue
+			}
+			keptAlerts = append(keptAlerts, alert)
+		}
+		alerts = keptAlerts
+
+		for len(crashes) > 0 && crashes[0].created.Before(options.Before) {
+			removeFiles = append(removeFiles, crashes[0])
+			size -= crashes[0].size
+			crashes = crashes[1:]
+		}
+	}
+
+	if options.MaxSize > 0 {
+		for size > options.MaxSize && len(crashes) > 0 {
+			removeFiles = append(removeFiles, crashes[0])
+			size -= crashes[0].size
+			crashes = crashes[1:]
+		}
+		for size > options.MaxSize && len(snapshots) > 1 {
+			removeFiles = append(removeFiles, snapshots[0])
+			size -= snapshots[0].size
+			snapshots = snapshots[1:]
+		}
+		for size > options.MaxSize && len(alerts) > 0 {
+			size -= int64(len(alerts[0].data)) + 1
+			result.Alerts++
+			alerts = alerts[1:]
+		}
+	}
+
+	for _, file := range removeFiles {
+		if filepath.Dir(file.path) == c.snapshotDir() {
+			result.Snapshots = append(result.Snapshots, file.name)
+		} else {
+			result.CrashReports = append(result.CrashReports, file.name)
+		}
+	}
+
+	if options.DryRun {
+		result.SizeAfter = size
+		return result, nil
+	}
+
+	for _, file := range removeFiles {
+		if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove %s: %w", file.name, err)
+		}
+	}
+	if result.Alerts > 0 {
+		if err := c.writeAlertLines(alerts); err != nil {
+			return nil, err
+		}
+	}
+
+	if result.SizeAfter, err = c.DiskUsage(); err != nil {
+		return nil, err
+	}
+
+	c.logger(context.Background()).Debug().
+		Int("snapshots", len(result.Snapshots)).
+		Int("alerts", result.Alerts).
+		Int("crash_reports", len(result.CrashReports)).
+		Int64("reclaimed", result.Reclaimed()).
+		Msg("Cache garbage collected")
+	return result, nil
+}
+
+// autoGC prunes the cache down to the configured maximum size
+func (c *Cache) autoGC() {
+	if c.cfg.CacheMaxSizeMB <= 0 {
+		return
+	}
+
+	if _, err := c.GC(GCOptions{MaxSize: int64(c.cfg.CacheMaxSizeMB) << 20}); err != nil {
+		c.logger(context.Background()).Warn().Err(err).Msg("Failed to garbage collect cache")
+	}
+}
+
+// readAlertLines reads the alert log, oldest first
+func (c *Cache) readAlertLines() ([]alertLine, error) {
+	data, err := os.ReadFile(c.alertsFile())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alert log: %w", err)
+	}
+
+	var lines []alertLine
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		var alert Alert
+		if err := json.Unmarshal(scanner.Bytes(), &alert); err != nil {
+			continue
+		}
+		lines = append(lines, alertLine{data: append([]byte(nil), scanner.Bytes()...), time: alert.Time})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read alert log: %w", err)
+	}
+
+	return lines, nil
+}
+
+// writeAlertLines atomically replaces the alert log with lines
+func (c *Cache) writeAlertLines(lines []alertLine) error {
+	var buf bytes.Buffer
+	for _, line := range lines {
+		buf.Write(line.data)
+		buf.WriteByte('\n')
+	}
+
+	tmp := c.alertsFile() + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write alert log: %w", err)
+	}
+	if err := os.Rename(tmp, c.alertsFile()); err != nil {
+		return fmt.Errorf("failed to write alert log: %w", err)
+	}
+	return nil
+}
+
+// snapshotFiles returns the stored snapshots, oldest first
+func (c *Cache) snapshotFiles() ([]gcFile, error) {
+	snapshots, err := c.ListSnapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]gcFile, len(snapshots))
+	for i, snapshot := range snapshots {
+		files[i] = gcFile{
+			name:    snapshot.Name,
+			path:    c.snapshotPath(snapshot.Name),
+			size:    snapshot.Size,
+			created: snapshot.CreatedAt,
+		}
+	}
+	return files, nil
+}
+
+// crashReports returns the stored crash reports, oldest first
+func (c *Cache) crashReports() ([]gcFile, error) {
+	entries, err := os.ReadDir(c.crashDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read crash directory: %w", err)
+	}
+
+	var files []gcFile
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		files = append(files, gcFile{
+			name:    entry.Name(),
+			path:    filepath.Join(c.crashDir(), entry.Name()),
+			size:    info.Size(),
+			created: info.ModTime(),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].created.Before(files[j].created) })
+	return files, nil
+}
+
+// crashDir returns the directory holding crash reports
+func (c *Cache) crashDir() string {
+	return filepath.Join(c.cfg.DataDir, "crashes")
+}
+
+// dirSize returns the total size of the files below dir
+func dirSize(dir string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure %s: %w", dir, err)
+	}
+	return total, nil
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// hydrateWorkers bounds the concurrent API requests of FetchDirectories
+const hydrateWorkers = 8
+
+// DirectoryKey is what identifies directories in a list, such as a
+// collection's slugs or the directory IDs of favorites
+type DirectoryKey int
+
+const (
+	BySlug DirectoryKey = iota
+	ByID
+)
+
+// of returns the key of a directory
+func (k DirectoryKey) of(dir models.Directory) string {
+	if k == ByID {
+		return dir.ID
+	}
+	return dir.Slug
+}
+
+// Resolve looks up directories by key in a catalog, in the order of keys,
+// returning the keys the catalog doesn't have. Keys of renamed directories
+// are followed to their current ones when lineage is set.
+func Resolve(catalog []models.Directory, keys []string, by DirectoryKey, lineage *Lineage) (found []models.Directory, missing []string) {
+	byKey := make(map[string]int, len(catalog))
+	for i, dir := range catalog {
+		byKey[by.of(dir)] = i
+	}
+
+	for _, key := range keys {
+		i, ok := byKey[key]
+		if !ok && lineage != nil {
+			i, ok = byKey[lineage.Current(key, by)]
+		}
+		if ok {
+			found = append(found, catalog[i])
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	return found, missing
+}
+
+// FetchDirectories fetches directories by key from the API, a few at a time,
+// in the order of keys. onDone, when set, is called as each request
+// completes. Keys no directory has are returned in gone; err is the first
+// other failure, if any.
+func (c *Cache) FetchDirectories(ctx context.Context, keys []string, by DirectoryKey, onDone func()) (directories []models.Directory, gone []string, err error) {
+	fetch := c.apiClient.GetDirectory
+	if by == ByID {
+		fetch = c.apiClient.GetDirectoryByID
+	}
+
+	results := make([]*models.Directory, len(keys))
+	errs := make([]error, len(keys))
+
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for range min(hydrateWorkers, len(keys)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i], errs[i] = fetch(ctx, keys[i])
+				if onDone != nil {
+					mu.Lock()
+					onDone()
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for i := range keys {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i, dir := range results {
+		switch {
+		case errors.Is(errs[i], api.ErrDirectoryNotFound):
+			gone = append(gone, keys[i])
+		case errs[i] != nil:
+			if err == nil {
+				err = errs[i]
+			}
+		default:
+			directories = append(directories, *dir)
+		}
+	}
+	NormalizeCategories(directories)
+
+	return directories, gone, err
+}
@@ -0,0 +1,147 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// indexFileName is the file holding the lookups derived from the catalog
+const indexFileName = "index.json"
+
+// indexVersion changes when the index gains lookups, so older ones are
+// rebuilt
+const indexVersion = 2
+
+// Index holds lookups derived from the cached catalog. It is built on every
+// sync so commands don't recompute them on every run.
+type Index struct {
+	Version int `json:"version"`
+
+	// SyncedAt is the sync the index was built from; an index of another
+	// sync is stale and rebuilt on first use
+	SyncedAt time.Time `json:"synced_at"`
+	Count    int       `json:"count"`
+
+	// Categories counts the directories of each category, as received from
+	// the API before aliases apply
+	Categories map[string]int `json:"categories"`
+
+	// Slugs locates each directory in the cached catalog
+	Slugs map[string]int `json:"slugs"`
+
+	// SearchText holds the lowercase name and description of each
+	// directory, by slug
+	SearchText map[string]string `json:"search_text"`
+
+	// DRHistogram counts the directories per band of ten domain rating
+	// points, 90 to 100 in the last
+	DRHistogram [10]int `json:"dr_histogram"`
+
+	// DomainRatings holds the domain rating of every directory, highest
+	// first
+	DomainRatings []int `json:"domain_ratings"`
+}
+
+// buildIndex derives the index of a catalog synced at syncedAt
+func buildIndex(directories []models.Directory, syncedAt time.Time) *Index {
+	index := &Index{
+		Version:    indexVersion,
+		SyncedAt:   syncedAt,
+		Count:      len(directories),
+		Categories: make(map[string]int),
+		Slugs:      make(map[string]int, len(directories)),
+		SearchText: make(map[string]string, len(directories)),
+	}
+	for i, dir := range directories {
+		for _, category := range dir.Categories {
+			index.Categories[category]++
+		}
+		index.Slugs[dir.Slug] = i
+		index.SearchText[dir.Slug] = strings.ToLower(dir.Name + "\n" + dir.Description)
+		index.DRHistogram[min(max(dir.DomainRating, 0)/10, 9)]++
+		index.DomainRatings = append(index.DomainRatings, dir.DomainRating)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(index.DomainRatings)))
+	return index
+}
+
+// Index returns the lookups derived from the cached catalog, rebuilding them
+// when they are missing or older than the catalog
+func (c *Cache) Index() (*Index, error) {
+	meta, err := c.loadMetadata()
+	if err != nil {
+		return nil, err
+	}
+
+	var index Index
+	if data, err := os.ReadFile(c.indexFile()); err == nil && json.Unmarshal(data, &index) == nil &&
+		index.Version == indexVersion && index.SyncedAt.Equal(meta.LastUpdated) {
+		return &index, nil
+	}
+
+	directories, err := c.loadFromCache()
+	if err != nil {
+		return nil, err
+	}
+	rebuilt := buildIndex(directories, meta.LastUpdated)
+	if err := c.saveIndex(rebuilt); err != nil {
+		return nil, err
+	}
+	return rebuilt, nil
+}
+
+// Search keeps the directories whose name or description contains query,
+// ignoring case, along with any the index doesn't know
+func (idx *Index) Search(directories []models.Directory, query string) []models.Directory {
+	query = strings.ToLower(query)
+
+	var found []models.Directory
+	for _, dir := range directories {
+		text, ok := idx.SearchText[dir.Slug]
+		if !ok || strings.Contains(text, query) {
+			found = append(found, dir)
+		}
+	}
+	return found
+}
+
+// TopDR returns the domain rating a directory needs to be among the n
+// highest rated of the catalog, or false when the catalog has no more than n
+// directories
+func (idx *Index) TopDR(n int) (int, bool) {
+	if n <= 0 || len(idx.DomainRatings) <= n {
+		return 0, false
+	}
+	return idx.DomainRatings[n-1], true
+}
+
+// Has reports whether the catalog has a directory
+func (idx *Index) Has(slug string) bool {
+	_, ok := idx.Slugs[slug]
+	return ok
+}
+
+// indexFile returns the path of the index
+func (c *Cache) indexFile() string {
+	return filepath.Join(c.cfg.CacheDir, indexFileName)
+}
+
+// saveIndex writes the index
+func (c *Cache) saveIndex(index *Index) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to marshal index: %w", err)
+	}
+	if err := os.WriteFile(c.indexFile(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write index: %w", err)
+	}
+	return nil
+}
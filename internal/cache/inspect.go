@@ -0,0 +1,132 @@
+package cache
+
+import (
+	"os"
+	"time"
+)
+
+// Inspection describes the contents of the cache
+type Inspection struct {
+	Dir           string    `json:"dir"`
+	Source        string    `json:"source"`
+	SchemaVersion string    `json:"schema_version,omitempty"`
+	LastSync      time.Time `json:"last_sync,omitempty"`
+	Valid         bool      `json:"valid"`
+	Directories   int       `json:"directories"`
+
+	// Files lists the parts of the cache with their entry counts and sizes
+	Files     []FileUsage `json:"files"`
+	TotalSize int64       `json:"total_size"`
+	MaxSize   int64       `json:"max_size,omitempty"`
+
+	// Partitions counts the cached directories per pricing, link type and
+	// category
+	Partitions map[string]map[string]int `json:"partitions"`
+
+	// DRHistogram counts the cached directories per band of ten domain
+	// rating points
+	DRHistogram *[10]int `json:"dr_histogram,omitempty"`
+}
+
+// FileUsage is the entry count and size of a part of the cache
+type FileUsage struct {
+	Name    string `json:"name"`
+	Entries int    `json:"entries"`
+	Size    int64  `json:"size"`
+}
+
+// Inspect reports what the cache holds
+func (c *Cache) Inspect() (*Inspection, error) {
+	inspection := &Inspection{
+		Dir:     c.cfg.CacheDir,
+		Source:  c.cfg.SupabaseURL,
+		MaxSize: int64(c.cfg.CacheMaxSizeMB) << 20,
+		Partitions: map[string]map[string]int{
+			"pricing":   {},
+			"link_type": {},
+			"category":  {},
+		},
+	}
+
+	metadata := FileUsage{Name: "metadata", Size: fileSize(c.metaFile)}
+	if meta, err := c.loadMetadata(); err == nil {
+		metadata.Entries = 1
+		inspection.SchemaVersion = meta.Version
+		inspection.LastSync = meta.LastUpdated
+		inspection.Valid = c.isCacheValid()
+	}
+
+	catalog := FileUsage{Name: "catalog", Size: fileSize(c.cacheFile)}
+	if directories, err := c.loadFromCache(); err == nil {
+		catalog.Entries = len(directories)
+		for _, dir := range directories {
+			inspection.Partitions["pricing"][valueOr(dir.Pricing, "unknown")]++
+			inspection.Partitions["link_type"][valueOr(dir.LinkType, "unknown")]++
+			for _, category := range dir.Categories {
+				inspection.Partitions["category"][category]++
+			}
+		}
+	}
+	inspection.Directories = catalog.Entries
+
+	index := FileUsage{Name: "index"}
+	if idx, err := c.Index(); err == nil {
+		index.Entries = idx.Count
+		inspection.DRHistogram = &idx.DRHistogram
+	}
+	index.Size = fileSize(c.indexFile())
+
+	alerts, err := c.readAlertLines()
+	if err != nil {
+		return nil, err
+	}
+	snapshots, err := c.snapshotFiles()
+	if err != nil {
+		return nil, err
+	}
+	crashes, err := c.crashReports()
+	if err != nil {
+		return nil, err
+	}
+
+	inspection.Files = []FileUsage{
+		catalog,
+		metadata,
+		index,
+		{Name: "alerts", Entries: len(alerts), Size: fileSize(c.alertsFile())},
+		{Name: "snapshots", Entries: len(snapshots), Size: totalSize(snapshots)},
+		{Name: "crash reports", Entries: len(crashes), Size: totalSize(crashes)},
+	}
+
+	if inspection.TotalSize, err = c.DiskUsage(); err != nil {
+		return nil, err
+	}
+
+	return inspection, nil
+}
+
+// fileSize returns the size of a file, 0 when it doesn't exist
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
+// totalSize sums the sizes of files
+func totalSize(files []gcFile) int64 {
+	var total int64
+	for _, file := range files {
+		total += file.size
+	}
+	return total
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
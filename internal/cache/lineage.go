@@ -0,0 +1,160 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-json"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// lineageFileName is the file mapping the former slugs and IDs of
+// directories to their current ones. It is kept in the data dir so clearing
+// the cache keeps it.
+const lineageFileName = "lineage.json"
+
+// Lineage maps the slugs and IDs directories had before an upstream rename
+// or migration to the ones they have now, so favorites, submissions and
+// collections referring to the old ones still find them
+type Lineage struct {
+	Slugs map[string]string `json:"slugs,omitempty"`
+	IDs   map[string]string `json:"ids,omitempty"`
+}
+
+// Lineage returns the recorded renames, empty when there are none or they
+// can't be read
+func (c *Cache) Lineage() *Lineage {
+	lineage := &Lineage{Slugs: make(map[string]string), IDs: make(map[string]string)}
+
+	data, err := os.ReadFile(c.lineageFile())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			c.logger(context.Background()).Debug().Err(err).Msg("Failed to read directory lineage")
+		}
+		return lineage
+	}
+	if err := json.Unmarshal(data, lineage); err != nil {
+		c.logger(context.Background()).Debug().Err(err).Msg("Failed to parse directory lineage")
+	}
+	if lineage.Slugs == nil {
+		lineage.Slugs = make(map[string]string)
+	}
+	if lineage.IDs == nil {
+		lineage.IDs = make(map[string]string)
+	}
+	return lineage
+}
+
+// Current returns the key a directory known by key has now, following
+// successive renames, or key itself when it wasn't renamed
+func (l *Lineage) Current(key string, by DirectoryKey) string {
+	renames := l.Slugs
+	if by == ByID {
+		renames = l.IDs
+	}
+
+	// Each rename is followed at most once, in case of a cycle
+	for range len(renames) {
+		next, ok := renames[key]
+		if !ok || next == key {
+			break
+		}
+		key = next
+	}
+	return key
+}
+
+// Alias adds the former keys of the directories of byKey, so lookups by an
+// old slug or ID find the directory. Current keys are left as they are.
+func (l *Lineage) Alias(byKey map[string]models.Directory, by DirectoryKey) {
+	renames := l.Slugs
+	if by == ByID {
+		renames = l.IDs
+	}
+
+	for old := range renames {
+		if _, ok := byKey[old]; ok {
+			continue
+		}
+		if dir, ok := byKey[l.Current(old, by)]; ok {
+			byKey[old] = dir
+		}
+	}
+}
+
+// recordLineage records the renames between the cached catalog and the one
+// replacing it: a directory keeping its ID under a new slug, or its slug
+// under a new ID. Keys in use after the sync are never mapped elsewhere.
+func (c *Cache) recordLineage(ctx context.Context, after []models.Directory) error {
+	before, err := c.loadFromCache()
+	if err != nil {
+		// Nothing was cached to compare with
+		return nil
+	}
+
+	slugs := make(map[string]bool, len(after))
+	ids := make(map[string]bool, len(after))
+	for _, dir := range after {
+		slugs[dir.Slug] = true
+		ids[dir.ID] = true
+	}
+	beforeByID := make(map[string]models.Directory, len(before))
+	beforeBySlug := make(map[string]models.Directory, len(before))
+	for _, dir := range before {
+		beforeByID[dir.ID] = dir
+		beforeBySlug[dir.Slug] = dir
+	}
+
+	lineage := c.Lineage()
+	changes := 0
+	for _, dir := range after {
+		if old, ok := beforeByID[dir.ID]; ok && old.Slug != dir.Slug && !slugs[old.Slug] {
+			lineage.Slugs[old.Slug] = dir.Slug
+			changes++
+			c.logger(ctx).Info().Str("from", old.Slug).Str("to", dir.Slug).Msg("Directory renamed upstream")
+		}
+		if old, ok := beforeBySlug[dir.Slug]; ok && old.ID != dir.ID && !ids[old.ID] {
+			lineage.IDs[old.ID] = dir.ID
+			changes++
+			c.logger(ctx).Debug().Str("slug", dir.Slug).Str("from", old.ID).Str("to", dir.ID).Msg("Directory ID changed upstream")
+		}
+	}
+
+	// A former key in use again, such as a rename that was reverted, now
+	// names a directory of its own
+	for key := range lineage.Slugs {
+		if slugs[key] {
+			delete(lineage.Slugs, key)
+			changes++
+		}
+	}
+	for key := range lineage.IDs {
+		if ids[key] {
+			delete(lineage.IDs, key)
+			changes++
+		}
+	}
+	if changes == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(c.cfg.DataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	data, err := json.MarshalIndent(lineage, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal directory lineage: %w", err)
+	}
+	if err := os.WriteFile(c.lineageFile(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write directory lineage: %w", err)
+	}
+	return nil
+}
+
+// lineageFile returns the path of the directory lineage
+func (c *Cache) lineageFile() string {
+	return filepath.Join(c.cfg.DataDir, lineageFileName)
+}
@@ -0,0 +1,45 @@
+package cache
+
+import "time"
+
+// Sources of the catalog a command read
+const (
+	SourceCache      = "cache"
+	SourceAPI        = "api"
+	SourceStaleCache = "stale_cache"
+	SourceSnapshot   = "snapshot"
+)
+
+// Provenance tells where a catalog read came from and how old its data is
+type Provenance struct {
+	Source string `json:"source"`
+
+	// Snapshot names the snapshot read, with SourceSnapshot
+	Snapshot string `json:"snapshot,omitempty"`
+
+	// SyncedAt is when the data was fetched from the API
+	SyncedAt time.Time `json:"synced_at"`
+}
+
+// Provenance returns where the last GetDirectories call got the catalog
+// from, or nil before any call
+func (c *Cache) Provenance() *Provenance {
+	return c.provenance
+}
+
+// SnapshotProvenance returns the provenance of the directories of a snapshot
+func SnapshotProvenance(snapshot *Snapshot) *Provenance {
+	return &Provenance{Source: SourceSnapshot, Snapshot: snapshot.Name, SyncedAt: snapshot.CreatedAt}
+}
+
+// readFrom records where the catalog was read from. Cached data is as old as
+// the last sync.
+func (c *Cache) readFrom(source string) {
+	provenance := &Provenance{Source: source, SyncedAt: time.Now()}
+	if source != SourceAPI {
+		if meta, err := c.loadMetadata(); err == nil {
+			provenance.SyncedAt = meta.LastUpdated
+		}
+	}
+	c.provenance = provenance
+}
@@ -0,0 +1,193 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+var snapshotNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Snapshot is a frozen copy of the catalog
+type Snapshot struct {
+	Name        string             `json:"name"`
+	CreatedAt   time.Time          `json:"created_at"`
+	Count       int                `json:"count"`
+	Directories []models.Directory `json:"directories"`
+}
+
+// SnapshotInfo describes a stored snapshot without its directories
+type SnapshotInfo struct {
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Count     int       `json:"count"`
+	Size      int64     `json:"size"`
+}
+
+// CreateSnapshot freezes the current catalog under name
+func (c *Cache) CreateSnapshot(ctx context.Context, name string) (*Snapshot, error) {
+	if !snapshotNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid snapshot name %q: use letters, digits, '.', '_' and '-'", name)
+	}
+
+	path := c.snapshotPath(name)
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("snapshot already exists: %s", name)
+	}
+
+	directories, err := c.GetDirectories(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{
+		Name:        name,
+		CreatedAt:   time.Now().UTC(),
+		Count:       len(directories),
+		Directories: directories,
+	}
+
+	if err := os.MkdirAll(c.snapshotDir(), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	c.logger(ctx).Debug().Str("name", name).Int("count", snapshot.Count).Msg("Snapshot created")
+	return snapshot, nil
+}
+
+// LoadSnapshot loads a stored snapshot
+func (c *Cache) LoadSnapshot(name string) (*Snapshot, error) {
+	if !snapshotNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid snapshot name %q", name)
+	}
+
+	data, err := os.ReadFile(c.snapshotPath(name))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("snapshot not found: %s (see 'snapshot list')", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+	NormalizeCategories(snapshot.Directories)
+
+	return &snapshot, nil
+}
+
+// ListSnapshots returns all stored snapshots, oldest first
+func (c *Cache) ListSnapshots() ([]SnapshotInfo, error) {
+	entries, err := os.ReadDir(c.snapshotDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	var snapshots []SnapshotInfo
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		snapshot, err := c.LoadSnapshot(name)
+		if err != nil {
+			c.logger(context.Background()).Warn().Err(err).Str("name", name).Msg("Skipping unreadable snapshot")
+			continue
+		}
+
+		info := SnapshotInfo{
+			Name:      snapshot.Name,
+			CreatedAt: snapshot.CreatedAt,
+			Count:     snapshot.Count,
+		}
+		if fi, err := entry.Info(); err == nil {
+			info.Size = fi.Size()
+		}
+
+		snapshots = append(snapshots, info)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].CreatedAt.Before(snapshots[j].CreatedAt)
+	})
+
+	return snapshots, nil
+}
+
+// DeleteSnapshot removes a stored snapshot
+func (c *Cache) DeleteSnapshot(name string) error {
+	if !snapshotNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q", name)
+	}
+
+	if err := os.Remove(c.snapshotPath(name)); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("snapshot not found: %s", name)
+		}
+		return fmt.Errorf("failed to remove snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// snapshotDir returns the directory holding snapshots
+func (c *Cache) snapshotDir() string {
+	return filepath.Join(c.cfg.CacheDir, "snapshots")
+}
+
+// snapshotPath returns the file path of a snapshot
+func (c *Cache) snapshotPath(name string) string {
+	return filepath.Join(c.snapshotDir(), name+".json")
+}
+
+// ResolveSnapshot loads a snapshot by name, or by date (YYYY-MM-DD) in which
+// case the latest snapshot created on or before that day is used
+func (c *Cache) ResolveSnapshot(ref string) (*Snapshot, error) {
+	day, err := time.ParseInLocation("2006-01-02", ref, time.Local)
+	if err != nil {
+		return c.LoadSnapshot(ref)
+	}
+
+	snapshots, err := c.ListSnapshots()
+	if err != nil {
+		return nil, err
+	}
+
+	end := day.AddDate(0, 0, 1)
+	var match *SnapshotInfo
+	for i := range snapshots {
+		if snapshots[i].CreatedAt.Before(end) {
+			match = &snapshots[i]
+		}
+	}
+
+	if match == nil {
+		return nil, fmt.Errorf("no snapshot created on or before %s", ref)
+	}
+
+	return c.LoadSnapshot(match.Name)
+}
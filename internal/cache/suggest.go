@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// PricingValues are the pricing models directories are filtered by
+var PricingValues = []string{"free", "freemium", "paid"}
+
+// LinkTypeValues are the link types directories are filtered by
+var LinkTypeValues = []string{"dofollow", "nofollow"}
+
+// maxSuggestions caps the values Suggest returns
+const maxSuggestions = 3
+
+// CategoryNames returns the canonical names of the categories in the catalog
+// and of their parent categories, sorted
+func (idx *Index) CategoryNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	add := func(name string) {
+		if key := strings.ToLower(name); name != "" && !seen[key] {
+			seen[key] = true
+			names = append(names, name)
+		}
+	}
+
+	for category := range idx.Categories {
+		category = NormalizeCategory(category)
+		add(category)
+		for i := range category {
+			if strings.HasPrefix(category[i:], CategorySeparator) {
+				add(category[:i])
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Suggest returns the known values closest to a misspelled value, closest
+// first, ignoring case. Values more than a third of their length away aren't
+// suggested.
+func Suggest(value string, known []string) []string {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	type candidate struct {
+		name     string
+		distance int
+	}
+	var candidates []candidate
+	for _, name := range known {
+		distance := editDistance(value, strings.ToLower(name))
+		if distance <= max(1, min(len(value), len(name))/3) {
+			candidates = append(candidates, candidate{name, distance})
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(a, b candidate) int { return a.distance - b.distance })
+	var names []string
+	for _, c := range candidates[:min(len(candidates), maxSuggestions)] {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// Correct returns the single known value a misspelled value most likely
+// meant, or false when no value or several values are equally close
+func Correct(value string, known []string) (string, bool) {
+	suggestions := Suggest(value, known)
+	if len(suggestions) == 0 {
+		return "", false
+	}
+	best := editDistance(strings.ToLower(value), strings.ToLower(suggestions[0]))
+	if len(suggestions) > 1 && editDistance(strings.ToLower(value), strings.ToLower(suggestions[1])) == best {
+		return "", false
+	}
+	return suggestions[0], true
+}
+
+// editDistance counts the insertions, deletions, substitutions and swaps of
+// adjacent characters that turn a into b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	// Three rows of the distance matrix are enough to detect swaps
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
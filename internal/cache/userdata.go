@@ -0,0 +1,83 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"github.com/awesome-directories/cli/internal/auth"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// userDataDir holds cached responses about the signed-in user, beside the
+// catalog
+const userDataDir = "user"
+
+// userEntry is a cached response about a user
+type userEntry[T any] struct {
+	User      string    `json:"user"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Data      T         `json:"data"`
+}
+
+// GetFavorites returns the favorites of the signed-in user, from the cache
+// while they are younger than the favorites TTL
+func (c *Cache) GetFavorites(ctx context.Context, forceRefresh bool) ([]models.Favorite, error) {
+	return cachedUserData(ctx, c, "favorites", c.cfg.FavoritesTTL(), forceRefresh, c.apiClient.GetFavorites)
+}
+
+// GetAccountStats returns the account statistics of the signed-in user, from
+// the cache while they are younger than the account TTL
+func (c *Cache) GetAccountStats(ctx context.Context, forceRefresh bool) (*models.AccountStats, error) {
+	return cachedUserData(ctx, c, "account", c.cfg.AccountTTL(), forceRefresh, c.apiClient.GetAccountStats)
+}
+
+// InvalidateUserData drops the cached responses about the user, after the
+// CLI changed them or the user signed out
+func (c *Cache) InvalidateUserData() error {
+	if err := os.RemoveAll(filepath.Join(c.cfg.CacheDir, userDataDir)); err != nil {
+		return fmt.Errorf("failed to remove cached user data: %w", err)
+	}
+	return nil
+}
+
+// cachedUserData returns the cached response of kind when it belongs to the
+// signed-in user and is younger than ttl, or else fetches and caches it. With
+// no ttl nothing is cached.
+func cachedUserData[T any](ctx context.Context, c *Cache, kind string, ttl time.Duration, forceRefresh bool, fetch func(context.Context) (T, error)) (T, error) {
+	user := auth.TokenSubject(c.cfg.AuthToken)
+	if ttl <= 0 || user == "" {
+		return fetch(ctx)
+	}
+
+	path := filepath.Join(c.cfg.CacheDir, userDataDir, kind+".json")
+	if !forceRefresh {
+		var entry userEntry[T]
+		if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &entry) == nil &&
+			entry.User == user && time.Since(entry.FetchedAt) < ttl {
+			c.logger(ctx).Debug().Str("kind", kind).Msg("Using cached user data")
+			return entry.Data, nil
+		}
+	}
+
+	value, err := fetch(ctx)
+	if err != nil {
+		return value, err
+	}
+
+	data, err := json.Marshal(userEntry[T]{User: user, FetchedAt: time.Now(), Data: value})
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0700)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0600)
+	}
+	if err != nil {
+		c.logger(ctx).Warn().Err(err).Str("kind", kind).Msg("Failed to cache user data")
+	}
+	return value, nil
+}
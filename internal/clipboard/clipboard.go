@@ -0,0 +1,87 @@
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// ErrUnavailable is returned when no clipboard tool is installed
+var ErrUnavailable = errors.New("no clipboard tool found (install xclip, xsel or wl-clipboard)")
+
+// tool is a pair of commands reading and writing the clipboard
+type tool struct {
+	copy  []string
+	paste []string
+}
+
+// tools returns the clipboard tools to try on this platform, best first
+func tools() []tool {
+	switch runtime.GOOS {
+	case "darwin":
+		return []tool{{copy: []string{"pbcopy"}, paste: []string{"pbpaste"}}}
+	case "windows":
+		return []tool{{
+			copy:  []string{"clip.exe"},
+			paste: []string{"powershell.exe", "-NoProfile", "-Command", "Get-Clipboard"},
+		}}
+	}
+
+	var candidates []tool
+	if os.Getenv("WAYLAND_DISPLAY") != "" {
+		candidates = append(candidates, tool{copy: []string{"wl-copy"}, paste: []string{"wl-paste", "--no-newline"}})
+	}
+	return append(candidates,
+		tool{copy: []string{"xclip", "-selection", "clipboard"}, paste: []string{"xclip", "-selection", "clipboard", "-o"}},
+		tool{copy: []string{"xsel", "--clipboard", "--input"}, paste: []string{"xsel", "--clipboard", "--output"}},
+	)
+}
+
+// find returns the first installed clipboard tool
+func find() (tool, error) {
+	for _, t := range tools() {
+		if _, err := exec.LookPath(t.copy[0]); err == nil {
+			return t, nil
+		}
+	}
+	return tool{}, ErrUnavailable
+}
+
+// Available reports whether the clipboard can be used
+func Available() bool {
+	_, err := find()
+	return err == nil
+}
+
+// Read returns the text currently in the clipboard
+func Read() (string, error) {
+	t, err := find()
+	if err != nil {
+		return "", err
+	}
+
+	out, err := exec.Command(t.paste[0], t.paste[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to read clipboard: %w", err)
+	}
+	return strings.TrimRight(string(out), "\r\n"), nil
+}
+
+// Write replaces the clipboard contents with text
+func Write(text string) error {
+	t, err := find()
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.Command(t.copy[0], t.copy[1:]...)
+	cmd.Stdin = bytes.NewBufferString(text)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to write clipboard: %w", err)
+	}
+	return nil
+}
@@ -1,13 +1,19 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"time"
+	_ "time/tzdata" // timezones on systems without a zoneinfo database
 
 	"github.com/caarlos0/env/v11"
 	"gopkg.in/yaml.v3"
+
+	"github.com/awesome-directories/cli/internal/plan"
+	"github.com/awesome-directories/cli/pkg/models"
 )
 
 var (
@@ -15,36 +21,318 @@ var (
 	BuildSupabaseAnonKey string
 )
 
+// ErrReadOnly is returned for changes refused in read-only mode
+var ErrReadOnly = errors.New("disabled in read-only mode (read_only in the config file, or READ_ONLY)")
+
 // Config holds all configuration for the CLI
 type Config struct {
+	// Version is the config file format, see CurrentVersion
+	Version int `yaml:"version,omitempty"`
+
 	// Supabase configuration
 	SupabaseURL     string `env:"SUPABASE_URL" yaml:"supabase_url"`
 	SupabaseAnonKey string `env:"SUPABASE_ANON_KEY" yaml:"supabase_anon_key"`
 
 	// Auth configuration
-	AuthToken string `env:"AUTH_TOKEN" yaml:"auth_token"`
+	AuthToken      string    `env:"AUTH_TOKEN" yaml:"auth_token"`
+	RefreshToken   string    `env:"REFRESH_TOKEN" yaml:"refresh_token,omitempty"`
+	TokenExpiresAt time.Time `yaml:"token_expires_at,omitempty"`
 
 	// Cache configuration
-	CacheDir string        `env:"CACHE_DIR" yaml:"cache_dir"`
+	CacheDir string        `env:"CACHE_DIR" yaml:"cache_dir,omitempty"`
 	CacheTTL time.Duration `env:"CACHE_TTL" yaml:"cache_ttl"`
 
+	// CacheTTLs set how long each kind of data stays cached
+	CacheTTLs CacheTTLs `yaml:"cache_ttls,omitempty"`
+
+	// CacheMaxSizeMB caps the cache, pruning old snapshots and alerts after
+	// each sync; 0 disables the limit
+	CacheMaxSizeMB int `env:"CACHE_MAX_SIZE_MB" yaml:"cache_max_size_mb"`
+
+	// DataDir holds user data such as crash reports
+	DataDir string `env:"DATA_DIR" yaml:"data_dir,omitempty"`
+
+	// StateDir, when set, holds submissions and product profiles as YAML
+	// files meant to be shared through a git repository
+	StateDir string `env:"STATE_DIR" yaml:"state_dir,omitempty"`
+
+	// Export signing
+	SigningTool string `env:"SIGNING_TOOL" yaml:"signing_tool,omitempty"`
+	SigningKey  string `env:"SIGNING_KEY" yaml:"signing_key,omitempty"`
+
+	// Issue tracker integrations
+	GitHubToken  string `env:"GITHUB_TOKEN" yaml:"github_token,omitempty"`
+	LinearAPIKey string `env:"LINEAR_API_KEY" yaml:"linear_api_key,omitempty"`
+	JiraURL      string `env:"JIRA_URL" yaml:"jira_url,omitempty"`
+	JiraEmail    string `env:"JIRA_EMAIL" yaml:"jira_email,omitempty"`
+	JiraAPIToken string `env:"JIRA_API_TOKEN" yaml:"jira_api_token,omitempty"`
+
+	// Webhook called when a submission changes status
+	WebhookURL    string `env:"WEBHOOK_URL" yaml:"webhook_url,omitempty"`
+	WebhookSecret string `env:"WEBHOOK_SECRET" yaml:"webhook_secret,omitempty"`
+
+	// Mailbox polled by watch for directory confirmation emails
+	IMAPServer   string `env:"IMAP_SERVER" yaml:"imap_server,omitempty"`
+	IMAPUsername string `env:"IMAP_USERNAME" yaml:"imap_username,omitempty"`
+	IMAPPassword string `env:"IMAP_PASSWORD" yaml:"imap_password,omitempty"`
+	IMAPMailbox  string `env:"IMAP_MAILBOX" yaml:"imap_mailbox,omitempty"`
+
+	// SMTP is the mail server sending digests, alerts and reports by email
+	SMTP SMTP `yaml:"smtp,omitempty"`
+
+	// Presets are named, reusable filters
+	Presets map[string]Preset `yaml:"presets,omitempty"`
+
+	// ProjectPresets names the preset whose filters and sort list, search and
+	// plan apply by default within a project; "default" applies to projects
+	// without their own
+	ProjectPresets map[string]string `yaml:"project_presets,omitempty"`
+
+	// CategoryAliases map other names of a category to the name directories
+	// are filtered and grouped under, such as "ML: AI Tools", on top of
+	// the built-in aliases
+	CategoryAliases map[string]string `yaml:"category_aliases,omitempty"`
+
+	// Pacing holds the scheduling rules of plan by project; "default"
+	// applies to projects without their own
+	Pacing map[string]Pacing `yaml:"pacing,omitempty"`
+
+	// Defaults holds default flag values by command, such as sort: dr for
+	// list; flags given on the command line take precedence. Subcommands go
+	// by their full name, such as "favorites list".
+	Defaults map[string]map[string]interface{} `yaml:"defaults,omitempty"`
+
+	// Badges configures the labels highlighting directories in tables and
+	// show
+	Badges Badges `yaml:"badges,omitempty"`
+
+	// Timezone, an IANA name such as Europe/Paris, sets the days dates fall
+	// on and how times are shown; the system timezone by default
+	Timezone string `env:"TIMEZONE" yaml:"timezone,omitempty"`
+
 	// General settings
-	Debug   bool `env:"DEBUG" yaml:"debug"`
-	NoColor bool `env:"NO_COLOR" yaml:"no_color"`
+	Debug     bool `env:"DEBUG" yaml:"debug"`
+	NoColor   bool `env:"NO_COLOR" yaml:"no_color"`
+	Telemetry bool `env:"TELEMETRY" yaml:"telemetry,omitempty"`
+
+	// Provenance makes listings tell whether their data came from the cache
+	// or the API and how old it is, in a footer or the meta of JSON output
+	Provenance bool `env:"PROVENANCE" yaml:"provenance,omitempty"`
+
+	// LowBandwidth is for metered or slow connections: syncs fetch only the
+	// changes since the last one and the columns listings need, caches stay
+	// fresh longer and logos and feeds aren't looked up
+	LowBandwidth bool `env:"LOW_BANDWIDTH" yaml:"low_bandwidth,omitempty"`
+
+	// ReadOnly is for shared terminals such as kiosks: commands changing the
+	// account, personal data or the config file are refused, and tokens,
+	// keys and passwords are neither used nor shown. The environment can
+	// turn it on, but not off when the config file does.
+	ReadOnly bool `env:"READ_ONLY" yaml:"read_only,omitempty"`
+
+	// UsageLog records the commands run and the filters they used in the
+	// data dir, for insights; nothing is sent anywhere
+	UsageLog bool `env:"USAGE_LOG" yaml:"usage_log,omitempty"`
+}
+
+// SMTP configures the mail server used to send email
+type SMTP struct {
+	// Server is host or host:port; port 587 with STARTTLS by default, 465
+	// for implicit TLS
+	Server   string `env:"SMTP_SERVER" yaml:"server,omitempty"`
+	Username string `env:"SMTP_USERNAME" yaml:"username,omitempty"`
+	Password string `env:"SMTP_PASSWORD" yaml:"password,omitempty"`
+	// From is the sender address; the username by default
+	From string `env:"SMTP_FROM" yaml:"from,omitempty"`
+}
+
+// Badges configures the labels highlighting high-value directories
+type Badges struct {
+	// Disabled lists the badges not to show: top-dr, new and free-dofollow,
+	// or all
+	Disabled []string `yaml:"disabled,omitempty"`
+
+	// TopDR is how many of the highest rated directories get the top DR
+	// badge; 100 by default
+	TopDR int `env:"BADGES_TOP_DR" yaml:"top_dr,omitempty"`
+
+	// Plain shows badges as words instead of emoji
+	Plain bool `env:"BADGES_PLAIN" yaml:"plain,omitempty"`
+}
+
+// CacheTTLs are how long the responses of each API endpoint stay cached.
+// The catalog follows CacheTTL unless given its own; personal data is
+// fetched every time unless given one.
+type CacheTTLs struct {
+	Directories time.Duration `env:"CACHE_TTL_DIRECTORIES" yaml:"directories,omitempty"`
+	Favorites   time.Duration `env:"CACHE_TTL_FAVORITES" yaml:"favorites,omitempty"`
+	Account     time.Duration `env:"CACHE_TTL_ACCOUNT" yaml:"account,omitempty"`
+}
+
+// DirectoriesTTL returns how long the cached catalog stays fresh, at least
+// LowBandwidthTTL in low-bandwidth mode
+func (c *Config) DirectoriesTTL() time.Duration {
+	ttl := c.CacheTTL
+	if c.CacheTTLs.Directories > 0 {
+		ttl = c.CacheTTLs.Directories
+	}
+	if c.LowBandwidth {
+		return max(ttl, LowBandwidthTTL)
+	}
+	return ttl
+}
+
+// FavoritesTTL returns how long cached favorites stay fresh, 0 to fetch
+// them every time
+func (c *Config) FavoritesTTL() time.Duration {
+	return c.userDataTTL(c.CacheTTLs.Favorites)
+}
+
+// AccountTTL returns how long cached account statistics stay fresh, 0 to
+// fetch them every time
+func (c *Config) AccountTTL() time.Duration {
+	return c.userDataTTL(c.CacheTTLs.Account)
+}
+
+// userDataTTL returns a personal data TTL, at least LowBandwidthUserDataTTL
+// in low-bandwidth mode
+func (c *Config) userDataTTL(ttl time.Duration) time.Duration {
+	if c.LowBandwidth {
+		return max(ttl, LowBandwidthUserDataTTL)
+	}
+	return ttl
+}
+
+// Preset is a named set of filter criteria
+type Preset struct {
+	Query      string   `yaml:"query,omitempty"`
+	Categories []string `yaml:"categories,omitempty"`
+	Pricing    []string `yaml:"pricing,omitempty"`
+	LinkType   []string `yaml:"link_type,omitempty"`
+	DRMin      int      `yaml:"dr_min,omitempty"`
+	DRMax      int      `yaml:"dr_max,omitempty"`
+	Countries  []string `yaml:"countries,omitempty"`
+	Languages  []string `yaml:"languages,omitempty"`
+	Audience   string   `yaml:"audience,omitempty"`
+	Sort       string   `yaml:"sort,omitempty"`
+}
+
+// FilterOptions converts the preset to filter options
+func (p Preset) FilterOptions() *models.FilterOptions {
+	return &models.FilterOptions{
+		Query:      p.Query,
+		Categories: p.Categories,
+		Pricing:    p.Pricing,
+		LinkType:   p.LinkType,
+		DRMin:      p.DRMin,
+		DRMax:      p.DRMax,
+		Countries:  p.Countries,
+		Languages:  p.Languages,
+		Audience:   p.Audience,
+		SortBy:     p.Sort,
+	}
+}
+
+// Preset returns the preset with the given name
+func (c *Config) Preset(name string) (Preset, error) {
+	preset, ok := c.Presets[name]
+	if !ok {
+		return Preset{}, fmt.Errorf("unknown preset: %s (define it under 'presets' in config.yaml)", name)
+	}
+	return preset, nil
+}
+
+// ProjectPreset returns the name of the default preset of a project,
+// falling back to the "default" entry, and whether one is configured
+func (c *Config) ProjectPreset(project string) (string, bool) {
+	if name, ok := c.ProjectPresets[project]; ok {
+		return name, true
+	}
+	name, ok := c.ProjectPresets[models.DefaultProject]
+	return name, ok
+}
+
+// Pacing limits how many submissions plan schedules per day and week
+type Pacing struct {
+	PerDay          int  `yaml:"per_day,omitempty"`
+	PerWeek         int  `yaml:"per_week,omitempty"`
+	CategoryPerWeek int  `yaml:"category_per_week,omitempty"`
+	SkipWeekends    bool `yaml:"skip_weekends,omitempty"`
+}
+
+// PlanPacing converts the pacing rules for the plan package
+func (p Pacing) PlanPacing() plan.Pacing {
+	return plan.Pacing{
+		PerDay:          p.PerDay,
+		PerWeek:         p.PerWeek,
+		CategoryPerWeek: p.CategoryPerWeek,
+		SkipWeekends:    p.SkipWeekends,
+	}
+}
+
+// ProjectPacing returns the pacing rules of a project, falling back to the
+// "default" entry, and whether any are configured
+func (c *Config) ProjectPacing(project string) (Pacing, bool) {
+	if pacing, ok := c.Pacing[project]; ok {
+		return pacing, true
+	}
+	pacing, ok := c.Pacing[models.DefaultProject]
+	return pacing, ok
 }
 
 // Default values
 const (
-	DefaultCacheTTL = 24 * time.Hour
+	DefaultCacheTTL       = 24 * time.Hour
+	DefaultCacheMaxSizeMB = 500
+
+	// Shortest TTLs of the catalog and of personal data in low-bandwidth
+	// mode
+	LowBandwidthTTL         = 7 * 24 * time.Hour
+	LowBandwidthUserDataTTL = time.Hour
 )
 
-// Load loads configuration from environment and config file
-func Load() (*Config, error) {
-	cfg := &Config{
+// Overrides are settings given on the command line, taking precedence over
+// the config file and environment variables
+type Overrides struct {
+	CacheDir string
+	DataDir  string
+	StateDir string
+
+	// Pure ignores the environment, XDG directories and home directory
+	// included: the config file and cache live in DataDir, for reproducible
+	// runs in Nix builds and hermetic CI
+	Pure bool
+
+	// LowBandwidth turns low-bandwidth mode on whatever the config says
+	LowBandwidth bool
+}
+
+// overrides are applied by every Load
+var overrides Overrides
+
+// SetOverrides sets the command line overrides applied by Load
+func SetOverrides(o Overrides) error {
+	if o.Pure && o.DataDir == "" {
+		return fmt.Errorf("--pure requires --data-dir")
+	}
+	overrides = o
+	return nil
+}
+
+// defaults returns the configuration before the config file and the
+// environment apply
+func defaults() *Config {
+	return &Config{
 		SupabaseURL:     BuildSupabaseURL,
 		SupabaseAnonKey: BuildSupabaseAnonKey,
 		CacheTTL:        DefaultCacheTTL,
+		CacheMaxSizeMB:  DefaultCacheMaxSizeMB,
 	}
+}
+
+// Load loads configuration from environment and config file
+func Load() (*Config, error) {
+	cfg := defaults()
 
 	// Get config directory
 	configDir, err := getConfigDir()
@@ -55,6 +343,12 @@ func Load() (*Config, error) {
 	// Set cache directory
 	cfg.CacheDir = filepath.Join(configDir, "cache")
 
+	dataDir, err := getDataDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get data directory: %w", err)
+	}
+	cfg.DataDir = dataDir
+
 	// Load from config file if it exists
 	configFile := filepath.Join(configDir, "config.yaml")
 	if _, err := os.Stat(configFile); err == nil {
@@ -64,11 +358,46 @@ func Load() (*Config, error) {
 	}
 
 	// Override with environment variables
-	if err := env.Parse(cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse environment variables: %w", err)
+	readOnly := cfg.ReadOnly
+	if !overrides.Pure {
+		if err := env.Parse(cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse environment variables: %w", err)
+		}
+	}
+	if readOnly || cfg.ReadOnly {
+		cfg.ReadOnly = true
+		cfg.dropCredentials()
+	}
+
+	if overrides.CacheDir != "" {
+		cfg.CacheDir = overrides.CacheDir
+	}
+	if overrides.DataDir != "" {
+		cfg.DataDir = overrides.DataDir
+	}
+	if overrides.StateDir != "" {
+		cfg.StateDir = overrides.StateDir
+	}
+	if overrides.LowBandwidth {
+		cfg.LowBandwidth = true
+	}
+
+	// A team sharing state sees the same dates whatever their machines use
+	if cfg.Timezone != "" {
+		location, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q: %w", cfg.Timezone, err)
+		}
+		time.Local = location
+	} else if overrides.Pure {
+		// The system timezone comes from the environment too
+		time.Local = time.UTC
 	}
 
 	if cfg.SupabaseURL == "" || cfg.SupabaseAnonKey == "" {
+		if overrides.Pure {
+			return nil, fmt.Errorf("supabase URL and anon key are missing. provide them as supabase_url & supabase_anon_key in %s", configFile)
+		}
 		return nil, fmt.Errorf("supabase URL and anon key are missing. provide them with env var SUPABASE_URL & SUPABASE_ANON_KEY")
 	}
 
@@ -80,8 +409,15 @@ func Load() (*Config, error) {
 	return cfg, nil
 }
 
-// Save saves configuration to file
-func (c *Config) Save() error {
+// save writes configuration to the config file. It is only given what
+// UpdateFile read from the file, never a loaded configuration, which holds
+// the environment and the command line overrides too.
+func (c *Config) save() error {
+	// A config file in read-only mode isn't to be changed
+	if c.ReadOnly {
+		return ErrReadOnly
+	}
+
 	configDir, err := getConfigDir()
 	if err != nil {
 		return fmt.Errorf("failed to get config directory: %w", err)
@@ -94,8 +430,11 @@ func (c *Config) Save() error {
 
 	configFile := filepath.Join(configDir, "config.yaml")
 
+	saved := *c
+	saved.Version = CurrentVersion
+
 	// Marshal to YAML
-	data, err := yaml.Marshal(c)
+	data, err := yaml.Marshal(&saved)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -108,8 +447,33 @@ func (c *Config) Save() error {
 	return nil
 }
 
+// UpdateFile changes settings of the config file alone. Nothing given by
+// environment variables, such as tokens and passwords, or by command line
+// overrides is written to the file.
+func UpdateFile(change func(cfg *Config)) error {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	cfg := defaults()
+	configFile := filepath.Join(configDir, "config.yaml")
+	if _, err := os.Stat(configFile); err == nil {
+		if err := loadFromFile(configFile, cfg); err != nil {
+			return fmt.Errorf("failed to load config file: %w", err)
+		}
+	}
+
+	change(cfg)
+	return cfg.save()
+}
+
 // getConfigDir returns the configuration directory path
 func getConfigDir() (string, error) {
+	if overrides.Pure {
+		return overrides.DataDir, nil
+	}
+
 	// Try XDG_CONFIG_HOME first
 	if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
 		return filepath.Join(xdgConfig, "awesome-directories"), nil
@@ -124,11 +488,36 @@ func getConfigDir() (string, error) {
 	return filepath.Join(home, ".config", "awesome-directories"), nil
 }
 
+// getDataDir returns the default data directory path
+func getDataDir() (string, error) {
+	if overrides.DataDir != "" {
+		return overrides.DataDir, nil
+	}
+
+	// Try XDG_DATA_HOME first
+	if xdgData := os.Getenv("XDG_DATA_HOME"); xdgData != "" {
+		return filepath.Join(xdgData, "awesome-directories"), nil
+	}
+
+	// Fall back to ~/.local/share
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".local", "share", "awesome-directories"), nil
+}
+
 // GetConfigDir returns the configuration directory (public helper)
 func GetConfigDir() (string, error) {
 	return getConfigDir()
 }
 
+// GetDataDir returns the default data directory (public helper)
+func GetDataDir() (string, error) {
+	return getDataDir()
+}
+
 // loadFromFile loads configuration from YAML file
 func loadFromFile(path string, cfg *Config) error {
 	data, err := os.ReadFile(path)
@@ -138,3 +527,61 @@ func loadFromFile(path string, cfg *Config) error {
 
 	return yaml.Unmarshal(data, cfg)
 }
+
+// EnvNames returns the environment variables configuration is read from
+func EnvNames() []string {
+	return envNames(reflect.TypeOf(Config{}))
+}
+
+// envNames returns the environment variables of the fields of a struct type,
+// including those of nested blocks such as smtp
+func envNames(fields reflect.Type) []string {
+	var names []string
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		if name := field.Tag.Get("env"); name != "" {
+			names = append(names, name)
+		} else if field.Type.Kind() == reflect.Struct && field.Type.PkgPath() == fields.PkgPath() {
+			names = append(names, envNames(field.Type)...)
+		}
+	}
+	return names
+}
+
+// dropCredentials forgets the account tokens and the keys and passwords of
+// integrations, so nothing run in read-only mode can use or show them. The
+// anon key is public and kept for browsing the catalog.
+func (c *Config) dropCredentials() {
+	c.AuthToken = ""
+	c.RefreshToken = ""
+	c.GitHubToken = ""
+	c.LinearAPIKey = ""
+	c.JiraAPIToken = ""
+	c.WebhookSecret = ""
+	c.IMAPPassword = ""
+	c.SMTP.Password = ""
+}
+
+// Sanitized returns a copy of the configuration with secrets redacted, safe
+// to include in bug reports
+func (c *Config) Sanitized() *Config {
+	sanitized := *c
+
+	redact := func(s *string) {
+		if *s != "" {
+			*s = "[redacted]"
+		}
+	}
+	redact(&sanitized.SupabaseAnonKey)
+	redact(&sanitized.AuthToken)
+	redact(&sanitized.RefreshToken)
+	redact(&sanitized.SigningKey)
+	redact(&sanitized.GitHubToken)
+	redact(&sanitized.LinearAPIKey)
+	redact(&sanitized.JiraAPIToken)
+	redact(&sanitized.WebhookSecret)
+	redact(&sanitized.IMAPPassword)
+	redact(&sanitized.SMTP.Password)
+
+	return &sanitized
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CurrentVersion is the config file format written by this version of the CLI
+const CurrentVersion = 1
+
+// Migration upgrades a config file from the previous version
+type Migration struct {
+	Version     int
+	Description string
+	Apply       func(doc map[string]interface{}, configDir string) error
+}
+
+// migrations are applied in order to config files older than their version.
+// Add an entry here whenever a config key, env var or file layout changes.
+var migrations = []Migration{
+	{
+		Version:     1,
+		Description: "Stop pinning the default cache directory in config.yaml",
+		Apply: func(doc map[string]interface{}, configDir string) error {
+			if dir, ok := doc["cache_dir"].(string); ok && filepath.Clean(dir) == filepath.Join(configDir, "cache") {
+				delete(doc, "cache_dir")
+			}
+			return nil
+		},
+	},
+}
+
+// PendingMigrations returns the migrations not yet applied to the config file
+// that would change it
+func PendingMigrations() ([]Migration, error) {
+	doc, err := readConfigDocument()
+	if err != nil || doc == nil {
+		return nil, err
+	}
+
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	var changing []Migration
+	for _, m := range pendingFor(doc) {
+		before := fmt.Sprint(doc)
+		if err := m.Apply(doc, configDir); err != nil {
+			return nil, fmt.Errorf("failed to apply config migration %d: %w", m.Version, err)
+		}
+		if fmt.Sprint(doc) != before {
+			changing = append(changing, m)
+		}
+	}
+
+	return changing, nil
+}
+
+// Migrate applies pending migrations to the config file, keeping a copy of
+// the original in config.yaml.bak. It returns the applied migrations.
+func Migrate() ([]Migration, error) {
+	doc, err := readConfigDocument()
+	if err != nil || doc == nil {
+		return nil, err
+	}
+
+	pending := pendingFor(doc)
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	for _, m := range pending {
+		if err := m.Apply(doc, configDir); err != nil {
+			return nil, fmt.Errorf("failed to apply config migration %d: %w", m.Version, err)
+		}
+		doc["version"] = m.Version
+	}
+
+	configFile := filepath.Join(configDir, "config.yaml")
+
+	original, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	if err := os.WriteFile(configFile+".bak", original, 0600); err != nil {
+		return nil, fmt.Errorf("failed to back up config file: %w", err)
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := os.WriteFile(configFile, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return pending, nil
+}
+
+// pendingFor returns the migrations newer than the version of doc
+func pendingFor(doc map[string]interface{}) []Migration {
+	version, _ := doc["version"].(int)
+
+	var pending []Migration
+	for _, m := range migrations {
+		if m.Version > version {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// readConfigDocument reads the config file as a generic document. It returns
+// nil when there is no config file.
+func readConfigDocument() (map[string]interface{}, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, "config.yaml"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	doc := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return doc, nil
+}
@@ -0,0 +1,105 @@
+package crash
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/awesome-directories/cli/internal/config"
+)
+
+// LogBuffer keeps the most recent log lines in memory so they can be
+// included in crash reports
+type LogBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	size  int
+}
+
+// NewLogBuffer creates a buffer holding up to size lines
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{size: size}
+}
+
+// Write implements io.Writer, storing each line written
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, line := range strings.Split(strings.TrimRight(string(p), "\n"), "\n") {
+		b.lines = append(b.lines, line)
+	}
+	if len(b.lines) > b.size {
+		b.lines = b.lines[len(b.lines)-b.size:]
+	}
+
+	return len(p), nil
+}
+
+// Lines returns the buffered lines, oldest first
+func (b *LogBuffer) Lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.lines...)
+}
+
+// Report describes a crash
+type Report struct {
+	Time    time.Time
+	Version string
+	Args    []string
+	Panic   interface{}
+	Stack   []byte
+	Logs    []string
+}
+
+// Write writes the report to a timestamped file in the crashes directory of
+// the data dir and returns its path
+func (r *Report) Write(cfg *config.Config) (string, error) {
+	dir := filepath.Join(cfg.DataDir, "crashes")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create crash directory: %w", err)
+	}
+
+	path := filepath.Join(dir, "crash-"+r.Time.Format("20060102-150405")+".txt")
+	if err := os.WriteFile(path, r.render(cfg), 0600); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+
+	return path, nil
+}
+
+// render formats the report as plain text
+func (r *Report) render(cfg *config.Config) []byte {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "awesome-directories crash report\n\n")
+	fmt.Fprintf(&b, "Time:    %s\n", r.Time.Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version: %s\n", r.Version)
+	fmt.Fprintf(&b, "Go:      %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "Command: %s\n", strings.Join(r.Args, " "))
+	fmt.Fprintf(&b, "Panic:   %v\n", r.Panic)
+
+	fmt.Fprintf(&b, "\n== Stack ==\n%s\n", r.Stack)
+
+	fmt.Fprintf(&b, "\n== Config ==\n")
+	if data, err := yaml.Marshal(cfg.Sanitized()); err == nil {
+		b.Write(data)
+	} else {
+		fmt.Fprintf(&b, "unavailable: %v\n", err)
+	}
+
+	fmt.Fprintf(&b, "\n== Recent log lines ==\n")
+	for _, line := range r.Logs {
+		fmt.Fprintln(&b, line)
+	}
+
+	return b.Bytes()
+}
@@ -0,0 +1,89 @@
+// Package dates parses the dates given on the command line, either absolute
+// or relative to today, such as "next monday" or "in 3 business days"
+package dates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout is the format of absolute dates
+const Layout = "2006-01-02"
+
+// units are the spans usable in "in <n> <unit>"
+var units = map[string]func(t time.Time, n int) time.Time{
+	"day":          func(t time.Time, n int) time.Time { return t.AddDate(0, 0, n) },
+	"week":         func(t time.Time, n int) time.Time { return t.AddDate(0, 0, 7*n) },
+	"month":        func(t time.Time, n int) time.Time { return t.AddDate(0, n, 0) },
+	"business day": AddBusinessDays,
+}
+
+// Parse parses a day: YYYY-MM-DD, today, tomorrow, yesterday, a weekday
+// ("monday" or "next monday", both the next one after today), or
+// "in <n> days|weeks|months|business days". Relative dates are counted from
+// now, in its location. The result is midnight of that day.
+func Parse(value string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	value = strings.Join(strings.Fields(strings.ToLower(value)), " ")
+
+	if t, err := time.ParseInLocation(Layout, value, now.Location()); err == nil {
+		return t, nil
+	}
+
+	switch value {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if weekday, ok := ParseWeekday(strings.TrimPrefix(value, "next ")); ok {
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+
+	if rest, ok := strings.CutPrefix(value, "in "); ok {
+		count, unit, _ := strings.Cut(rest, " ")
+		n, err := strconv.Atoi(count)
+		if add, known := units[strings.TrimSuffix(unit, "s")]; err == nil && n >= 0 && known {
+			return add(today, n), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date: %s (use YYYY-MM-DD, today, tomorrow, a weekday such as \"next monday\", or \"in 3 days\", \"in 2 weeks\", \"in 3 business days\")", value)
+}
+
+// AddBusinessDays returns the day n working days (Monday to Friday) after t
+func AddBusinessDays(t time.Time, n int) time.Time {
+	for n > 0 {
+		t = t.AddDate(0, 0, 1)
+		if !IsWeekend(t) {
+			n--
+		}
+	}
+	return t
+}
+
+// IsWeekend reports whether t falls on a Saturday or Sunday
+func IsWeekend(t time.Time) bool {
+	return t.Weekday() == time.Saturday || t.Weekday() == time.Sunday
+}
+
+// ParseWeekday parses a weekday name, full or abbreviated, in any case
+func ParseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || len(name) >= 3 && strings.HasPrefix(full, name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,26 @@
+// Package demo provides a small, fictional directory catalog and a local
+// stand-in for the API serving it, so the CLI can be tried out or checked
+// without an account or network access
+package demo
+
+import (
+	_ "embed"
+	"fmt"
+
+	"github.com/goccy/go-json"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+//go:embed directories.json
+var directoriesJSON []byte
+
+// Directories returns the demo catalog, ordered by helpful votes like the
+// API returns it. The directories and their sites are fictional.
+func Directories() ([]models.Directory, error) {
+	var directories []models.Directory
+	if err := json.Unmarshal(directoriesJSON, &directories); err != nil {
+		return nil, fmt.Errorf("failed to decode demo directories: %w", err)
+	}
+	return directories, nil
+}
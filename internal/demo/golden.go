@@ -0,0 +1,21 @@
+package demo
+
+import (
+	"embed"
+	"fmt"
+)
+
+// golden holds the expected outputs of the selftest command on the demo
+// catalog
+//
+//go:embed golden
+var golden embed.FS
+
+// Golden returns the expected output of a selftest case
+func Golden(name string) ([]byte, error) {
+	data, err := golden.ReadFile("golden/" + name)
+	if err != nil {
+		return nil, fmt.Errorf("no golden file for %s: %w", name, err)
+	}
+	return data, nil
+}
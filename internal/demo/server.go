@@ -0,0 +1,158 @@
+package demo
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// AnonKey and Token are accepted by the demo server in place of real
+// credentials
+const (
+	AnonKey = "demo-anon-key"
+	Token   = "demo-token"
+)
+
+// Server is a local stand-in for the API, serving the demo catalog and
+// keeping favorites in memory. Other tables are empty.
+type Server struct {
+	// URL is where the server listens, to use as the Supabase URL
+	URL string
+
+	server      *http.Server
+	directories []models.Directory
+
+	mu        sync.Mutex
+	favorites []models.Favorite
+}
+
+// Start starts a demo server on a free local port
+func Start() (*Server, error) {
+	directories, err := Directories()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start demo server: %w", err)
+	}
+
+	s := &Server{
+		URL:         "http://" + listener.Addr().String(),
+		directories: directories,
+	}
+	s.server = &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("Demo server failed")
+		}
+	}()
+	return s, nil
+}
+
+// Close stops the server
+func (s *Server) Close() error {
+	return s.server.Close()
+}
+
+// ServeHTTP answers the PostgREST requests the CLI makes
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	table, ok := strings.CutPrefix(r.URL.Path, "/rest/v1/")
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"message": "not available in the demo"})
+		return
+	}
+
+	switch {
+	case table == "directories" && r.Method == http.MethodGet:
+		directories := s.directories
+		if slug, ok := strings.CutPrefix(r.URL.Query().Get("slug"), "eq."); ok {
+			directories = nil
+			for _, dir := range s.directories {
+				if dir.Slug == slug {
+					directories = append(directories, dir)
+				}
+			}
+		}
+		writeJSON(w, http.StatusOK, directories)
+
+	case table == "rpc/schema_version":
+		writeJSON(w, http.StatusOK, api.SchemaVersion)
+
+	case table == "user_favorites":
+		s.favoritesHandler(w, r)
+
+	case r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, []struct{}{})
+
+	default:
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// favoritesHandler lists, adds and removes favorites
+func (s *Server) favoritesHandler(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.favorites)
+
+	case http.MethodPost:
+		var payload struct {
+			DirectoryID string `json:"directory_id"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		s.favorites = append(s.favorites, models.Favorite{
+			ID:          len(s.favorites) + 1,
+			UserID:      "demo",
+			DirectoryID: payload.DirectoryID,
+			CreatedAt:   time.Now().UTC(),
+		})
+		w.WriteHeader(http.StatusCreated)
+
+	case http.MethodDelete:
+		id := strings.TrimPrefix(r.URL.Query().Get("directory_id"), "eq.")
+		kept := s.favorites[:0]
+		for _, favorite := range s.favorites {
+			if favorite.DirectoryID != id {
+				kept = append(kept, favorite)
+			}
+		}
+		s.favorites = kept
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(data); err != nil {
+		log.Debug().Err(err).Msg("Failed to write demo response")
+	}
+}
@@ -0,0 +1,131 @@
+// Package digest builds the periodic digests of subscriptions: the
+// directories a saved search gained, lost or saw change since the last one.
+package digest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/awesome-directories/cli/internal/cache"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// Event is the webhook event name of digests
+const Event = "subscription.digest"
+
+// Digest is what changed for a subscription over a period
+type Digest struct {
+	Event        string           `json:"event"`
+	Subscription string           `json:"subscription"`
+	Preset       string           `json:"preset"`
+	Period       string           `json:"period"`
+	Since        time.Time        `json:"since"`
+	Until        time.Time        `json:"until"`
+	Changes      *cache.ChangeSet `json:"changes"`
+
+	// Matching are the directories matching the search now, which the next
+	// digest compares against
+	Matching []models.Directory `json:"-"`
+}
+
+// Build compares the directories the subscription saw at its last digest
+// with the current ones, keeping those matching the search either then or
+// now. Directories that start matching count as added.
+func Build(subscription models.Subscription, current []models.Directory, matches func(models.Directory) bool, now time.Time) *Digest {
+	var matching []models.Directory
+	for _, dir := range current {
+		if matches(dir) {
+			matching = append(matching, dir)
+		}
+	}
+
+	seen := make(map[string]bool, len(subscription.Seen))
+	for _, dir := range subscription.Seen {
+		seen[dir.ID] = true
+	}
+	changes := cache.DiffDirectories(subscription.Seen, current).Filter(func(dir models.Directory) bool {
+		return seen[dir.ID] || matches(dir)
+	})
+
+	return &Digest{
+		Event:        Event,
+		Subscription: subscription.Name,
+		Preset:       subscription.Preset,
+		Period:       subscription.Digest,
+		Since:        subscription.Since(),
+		Until:        now,
+		Changes:      changes,
+		Matching:     matching,
+	}
+}
+
+// Empty reports whether nothing changed over the period
+func (d *Digest) Empty() bool {
+	return d.Changes.Empty()
+}
+
+// Summary describes the counts of a digest in one line
+func (d *Digest) Summary() string {
+	return Summarize(d.Changes)
+}
+
+// Summarize describes the counts of a change set in one line
+func Summarize(changes *cache.ChangeSet) string {
+	return fmt.Sprintf("%d new, %d changed, %d removed", len(changes.Added), len(changes.Changed), len(changes.Removed))
+}
+
+// Title returns the heading of the digest
+func (d *Digest) Title() string {
+	return fmt.Sprintf("%s digest: %s", capitalize(d.Period), d.Subscription)
+}
+
+// Markdown renders the digest as a Markdown document
+func (d *Digest) Markdown() []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "# %s\n\n", d.Title())
+	fmt.Fprintf(&buf, "Directories matching the %s search from %s to %s: %s.\n",
+		d.Preset, d.Since.Format("January 2, 2006"), d.Until.Format("January 2, 2006"), d.Summary())
+
+	WriteChanges(&buf, d.Changes)
+
+	return buf.Bytes()
+}
+
+// WriteChanges writes the new, changed and removed directories of a change
+// set as Markdown sections
+func WriteChanges(buf *bytes.Buffer, changes *cache.ChangeSet) {
+	if len(changes.Added) > 0 {
+		buf.WriteString("\n## New\n\n")
+		for _, dir := range changes.Added {
+			fmt.Fprintf(buf, "- [%s](%s): DR %d, %s, %s\n", dir.Name, dir.URL, dir.DomainRating, dir.Pricing, dir.LinkType)
+		}
+	}
+
+	if len(changes.Changed) > 0 {
+		buf.WriteString("\n## Changed\n\n")
+		for _, change := range changes.Changed {
+			fields := make([]string, 0, len(change.Fields))
+			for _, field := range change.Fields {
+				fields = append(fields, fmt.Sprintf("%s %s → %s", field.Field, field.Old, field.New))
+			}
+			fmt.Fprintf(buf, "- [%s](%s): %s\n", change.Directory.Name, change.Directory.URL, strings.Join(fields, "; "))
+		}
+	}
+
+	if len(changes.Removed) > 0 {
+		buf.WriteString("\n## Removed\n\n")
+		for _, dir := range changes.Removed {
+			fmt.Fprintf(buf, "- %s (%s)\n", dir.Name, dir.Slug)
+		}
+	}
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,266 @@
+package discover
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// analyzeWorkers is how many submission pages AnalyzeForms fetches at once
+const analyzeWorkers = 4
+
+// Difficulty ratings of a submission form
+const (
+	DifficultyEasy    = "easy"
+	DifficultyMedium  = "medium"
+	DifficultyHard    = "hard"
+	DifficultyUnknown = "unknown"
+)
+
+// FormAnalysis describes the complexity of a submission page
+type FormAnalysis struct {
+	URL string
+
+	// RobotsDisallowed is set when robots.txt excludes the page, which is
+	// then not fetched
+	RobotsDisallowed bool
+
+	LoginWall      bool
+	Captcha        bool
+	ExternalForm   bool // the form is embedded from a form service
+	Fields         int
+	RequiredFields int
+
+	Difficulty string
+}
+
+// Signals lists the findings that make the form harder to fill in
+func (a *FormAnalysis) Signals() []string {
+	var signals []string
+	if a.RobotsDisallowed {
+		signals = append(signals, "disallowed by robots.txt")
+	}
+	if a.LoginWall {
+		signals = append(signals, "login required")
+	}
+	if a.Captcha {
+		signals = append(signals, "captcha")
+	}
+	if a.ExternalForm {
+		signals = append(signals, "external form")
+	}
+	return signals
+}
+
+var (
+	formPattern     = regexp.MustCompile(`(?is)<form\b[^>]*>(.*?)</form>`)
+	fieldPattern    = regexp.MustCompile(`(?is)<(input|textarea|select)\b([^>]*)>`)
+	typePattern     = regexp.MustCompile(`(?i)\btype\s*=\s*["']?([a-z-]+)`)
+	requiredPattern = regexp.MustCompile(`(?i)\brequired\b|aria-required\s*=\s*["']?true`)
+	captchaPattern  = regexp.MustCompile(`(?i)g-recaptcha|recaptcha/api|hcaptcha|cf-turnstile|captcha`)
+	embedPattern    = regexp.MustCompile(`(?i)typeform\.com|docs\.google\.com/forms|forms\.gle/|tally\.so|airtable\.com/embed|jotform\.com|forms\.office\.com`)
+	passwordPattern = regexp.MustCompile(`(?i)<input\b[^>]*\btype\s*=\s*["']?password`)
+	loginPattern    = regexp.MustCompile(`(?i)(^|/)(login|log-in|signin|sign-in|signup|sign-up|register|auth)(/|$)`)
+)
+
+// ignoredInputs are input types that are not filled in by the submitter
+var ignoredInputs = map[string]bool{
+	"hidden": true,
+	"submit": true,
+	"button": true,
+	"image":  true,
+	"reset":  true,
+	"search": true,
+}
+
+// AnalyzeForm fetches a submission page, unless robots.txt disallows it,
+// and rates how hard its form is to fill in from its login wall, captcha
+// and required fields
+func AnalyzeForm(ctx context.Context, pageURL string) (*FormAnalysis, error) {
+	page, err := url.Parse(pageURL)
+	if err != nil || page.Host == "" {
+		return nil, fmt.Errorf("invalid page URL: %s", pageURL)
+	}
+
+	analysis := &FormAnalysis{URL: pageURL, Difficulty: DifficultyUnknown}
+	if !robotsAllowed(ctx, page) {
+		analysis.RobotsDisallowed = true
+		return analysis, nil
+	}
+
+	data, final, err := fetchPage(ctx, pageURL)
+	if err != nil {
+		return nil, err
+	}
+	body := string(data)
+
+	// Redirected to a login page, or asked for a password on the page
+	analysis.LoginWall = (final.String() != page.String() && loginPattern.MatchString(strings.ToLower(final.Path))) ||
+		passwordPattern.MatchString(body)
+	analysis.Captcha = captchaPattern.MatchString(body)
+	analysis.ExternalForm = embedPattern.MatchString(body)
+
+	// Count the fields of the largest form, which on pages with search or
+	// newsletter forms is the submission form. Pages rendering the form
+	// with JavaScript may not have a <form> element.
+	forms := formPattern.FindAllStringSubmatch(body, -1)
+	if len(forms) == 0 {
+		forms = [][]string{{body, body}}
+	}
+	for _, form := range forms {
+		fields, required := countFields(form[1])
+		if fields > analysis.Fields {
+			analysis.Fields, analysis.RequiredFields = fields, required
+		}
+	}
+
+	analysis.Difficulty = rateDifficulty(analysis)
+	return analysis, nil
+}
+
+// AnalyzeForms analyzes the submission pages in pages, keyed by directory
+// slug, and returns the analyses and errors by slug. onDone, when set, is
+// called once per page.
+func AnalyzeForms(ctx context.Context, pages map[string]string, onDone func()) (map[string]*FormAnalysis, map[string]error) {
+	jobs := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	analyses := make(map[string]*FormAnalysis)
+	errs := make(map[string]error)
+
+	for range analyzeWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for slug := range jobs {
+				analysis, err := AnalyzeForm(ctx, pages[slug])
+
+				mu.Lock()
+				if err != nil {
+					errs[slug] = err
+				} else {
+					analyses[slug] = analysis
+				}
+				if onDone != nil {
+					onDone()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for slug := range pages {
+		jobs <- slug
+	}
+	close(jobs)
+	wg.Wait()
+
+	return analyses, errs
+}
+
+// countFields counts the fields of a form and how many are required
+func countFields(form string) (fields, required int) {
+	for _, match := range fieldPattern.FindAllStringSubmatch(form, -1) {
+		attrs := match[2]
+		if strings.EqualFold(match[1], "input") {
+			if t := typePattern.FindStringSubmatch(attrs); t != nil && ignoredInputs[strings.ToLower(t[1])] {
+				continue
+			}
+		}
+		fields++
+		if requiredPattern.MatchString(attrs) {
+			required++
+		}
+	}
+	return fields, required
+}
+
+// rateDifficulty turns the signals of an analysis into a difficulty rating
+func rateDifficulty(a *FormAnalysis) string {
+	if a.Fields == 0 && !a.LoginWall && !a.ExternalForm {
+		return DifficultyUnknown
+	}
+
+	points := 0
+	if a.LoginWall {
+		points += 2
+	}
+	if a.Captcha {
+		points++
+	}
+	switch {
+	case a.RequiredFields >= 8:
+		points += 2
+	case a.RequiredFields >= 4:
+		points++
+	}
+
+	switch {
+	case points == 0:
+		return DifficultyEasy
+	case points <= 2:
+		return DifficultyMedium
+	default:
+		return DifficultyHard
+	}
+}
+
+// robotsAllowed reports whether the site's robots.txt allows fetching page.
+// A missing or unreadable robots.txt allows everything.
+func robotsAllowed(ctx context.Context, page *url.URL) bool {
+	data, err := fetch(ctx, page.ResolveReference(&url.URL{Path: "/robots.txt"}).String())
+	if err != nil {
+		return true
+	}
+
+	// Apply the rules of the groups for all user agents; the longest
+	// matching rule wins, and Allow wins ties
+	path := page.EscapedPath()
+	if page.RawQuery != "" {
+		path += "?" + page.RawQuery
+	}
+	allowed, longest := true, -1
+	inGroup, groupStarted := false, false
+	for _, line := range strings.Split(string(data), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if groupStarted {
+				inGroup, groupStarted = false, false
+			}
+			if value == "*" {
+				inGroup = true
+			}
+		case "allow", "disallow":
+			groupStarted = true
+			if !inGroup || value == "" || !robotsMatch(value, path) {
+				continue
+			}
+			allow := key == "allow"
+			if len(value) > longest || (len(value) == longest && allow) {
+				allowed, longest = allow, len(value)
+			}
+		}
+	}
+	return allowed
+}
+
+// robotsMatch reports whether a robots.txt path pattern, which may contain
+// * wildcards and end with $, matches path
+func robotsMatch(pattern, path string) bool {
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*")
+	if strings.HasSuffix(expr, `\$`) {
+		expr = strings.TrimSuffix(expr, `\$`) + "$"
+	}
+	re, err := regexp.Compile(expr)
+	return err == nil && re.MatchString(path)
+}
@@ -0,0 +1,271 @@
+package discover
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxBodySize is how much of a page or sitemap is read
+const maxBodySize = 2 << 20
+
+// maxSitemaps is how many sitemaps of a sitemap index are followed
+const maxSitemaps = 5
+
+// Candidate is a page that looks like a submission form
+type Candidate struct {
+	URL    string
+	Score  int
+	Source string // sitemap or homepage
+	Text   string // link text, for homepage links
+}
+
+// keywords score the link text of candidate pages, and their path with
+// spaces written as dashes
+var keywords = []struct {
+	word  string
+	score int
+}{
+	{"submit", 5},
+	{"add your", 4},
+	{"list your", 4},
+	{"get listed", 4},
+	{"suggest", 4},
+	{"add tool", 3},
+	{"add product", 3},
+	{"add startup", 3},
+	{"new listing", 3},
+	{"launch", 2},
+	{"advertise", 1},
+	{"pricing", 1},
+}
+
+// noise marks pages that mention submitting without being the form
+var noise = []string{"/blog/", "/tag/", "/tags/", "/category/", "/news/", "/article", "/post/", ".xml", ".jpg", ".png"}
+
+var (
+	anchorPattern = regexp.MustCompile(`(?is)<a\b[^>]*?href\s*=\s*("[^"]*"|'[^']*'|[^\s>]+)[^>]*>(.*?)</a>`)
+	tagPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
+// SubmitPages looks for the submission page of the site at siteURL, in its
+// sitemaps and the links on its homepage, and returns the candidates, best
+// first
+func SubmitPages(ctx context.Context, siteURL string) ([]Candidate, error) {
+	site, err := url.Parse(siteURL)
+	if err != nil || site.Host == "" {
+		return nil, fmt.Errorf("invalid site URL: %s", siteURL)
+	}
+
+	candidates := make(map[string]*Candidate)
+	add := func(link *url.URL, source, text string) {
+		if !sameSite(site, link) {
+			return
+		}
+		link.Fragment = ""
+		score := scoreLink(link, text)
+		if score <= 0 {
+			return
+		}
+
+		key := link.String()
+		if existing, ok := candidates[key]; ok {
+			// Found both in the sitemap and on the homepage
+			existing.Score = max(existing.Score, score) + 1
+			if existing.Text == "" {
+				existing.Text = text
+			}
+			return
+		}
+		candidates[key] = &Candidate{URL: key, Score: score, Source: source, Text: text}
+	}
+
+	sitemaps, err := sitemapURLs(ctx, site)
+	if err != nil {
+		log.Debug().Err(err).Str("site", siteURL).Msg("No sitemap")
+	}
+	for _, loc := range sitemaps {
+		if link, err := url.Parse(loc); err == nil {
+			add(link, "sitemap", "")
+		}
+	}
+
+	homepageErr := homepageLinks(ctx, site, func(link *url.URL, text string) {
+		add(link, "homepage", text)
+	})
+	if homepageErr != nil && len(sitemaps) == 0 {
+		return nil, homepageErr
+	}
+
+	list := make([]Candidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		list = append(list, *candidate)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Score != list[j].Score {
+			return list[i].Score > list[j].Score
+		}
+		// Prefer shorter, more canonical URLs
+		return len(list[i].URL) < len(list[j].URL)
+	})
+
+	return list, nil
+}
+
+// scoreLink rates how much a link looks like a submission page
+func scoreLink(link *url.URL, text string) int {
+	path := strings.ToLower(link.Path)
+	for _, n := range noise {
+		if strings.Contains(path, n) {
+			return 0
+		}
+	}
+
+	text = strings.ToLower(text)
+	score := 0
+	for _, k := range keywords {
+		if strings.Contains(path, strings.ReplaceAll(k.word, " ", "-")) {
+			score += k.score
+		}
+		if text != "" && strings.Contains(text, k.word) {
+			score += k.score
+		}
+	}
+
+	// Deep pages are rarely the main form
+	if depth := strings.Count(strings.Trim(path, "/"), "/"); depth > 1 {
+		score -= depth - 1
+	}
+	return score
+}
+
+// sameSite reports whether link is on the site, ignoring a www. prefix
+func sameSite(site, link *url.URL) bool {
+	if link.Scheme != "http" && link.Scheme != "https" {
+		return false
+	}
+	trim := func(host string) string { return strings.TrimPrefix(strings.ToLower(host), "www.") }
+	return trim(link.Hostname()) == trim(site.Hostname())
+}
+
+// sitemapURLs returns the page URLs of the site's sitemaps, found through
+// robots.txt or at /sitemap.xml
+func sitemapURLs(ctx context.Context, site *url.URL) ([]string, error) {
+	var queue []string
+	if robots, err := fetch(ctx, site.ResolveReference(&url.URL{Path: "/robots.txt"}).String()); err == nil {
+		for _, line := range strings.Split(string(robots), "\n") {
+			if key, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(key), "sitemap") {
+				queue = append(queue, strings.TrimSpace(value))
+			}
+		}
+	}
+	if len(queue) == 0 {
+		queue = append(queue, site.ResolveReference(&url.URL{Path: "/sitemap.xml"}).String())
+	}
+
+	var pages []string
+	var lastErr error
+	for fetched := 0; len(queue) > 0 && fetched < maxSitemaps; fetched++ {
+		sitemapURL := queue[0]
+		queue = queue[1:]
+
+		data, err := fetch(ctx, sitemapURL)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		var doc struct {
+			URLs     []string `xml:"url>loc"`
+			Sitemaps []string `xml:"sitemap>loc"`
+		}
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			lastErr = fmt.Errorf("failed to parse %s: %w", sitemapURL, err)
+			continue
+		}
+
+		for _, loc := range doc.URLs {
+			pages = append(pages, strings.TrimSpace(loc))
+		}
+		// Sitemap indexes often split pages by type; follow the page
+		// sitemaps before those of posts or products
+		sort.SliceStable(doc.Sitemaps, func(i, j int) bool {
+			return strings.Contains(doc.Sitemaps[i], "page") && !strings.Contains(doc.Sitemaps[j], "page")
+		})
+		for _, loc := range doc.Sitemaps {
+			queue = append(queue, strings.TrimSpace(loc))
+		}
+	}
+
+	if len(pages) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return pages, nil
+}
+
+// homepageLinks calls fn with every link on the site's homepage and its text
+func homepageLinks(ctx context.Context, site *url.URL, fn func(link *url.URL, text string)) error {
+	page, err := fetch(ctx, site.String())
+	if err != nil {
+		return err
+	}
+
+	for _, match := range anchorPattern.FindAllStringSubmatch(string(page), -1) {
+		href := html.UnescapeString(strings.Trim(match[1], `"'`))
+		link, err := site.Parse(href)
+		if err != nil {
+			continue
+		}
+
+		text := html.UnescapeString(tagPattern.ReplaceAllString(match[2], " "))
+		fn(link, strings.Join(strings.Fields(text), " "))
+	}
+	return nil
+}
+
+// fetch returns the body of a successful GET request
+func fetch(ctx context.Context, pageURL string) ([]byte, error) {
+	data, _, err := fetchPage(ctx, pageURL)
+	return data, err
+}
+
+// fetchPage returns the body of a successful GET request and the URL it was
+// served from after redirects
+func fetchPage(ctx context.Context, pageURL string) ([]byte, *url.URL, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to fetch %s: status %d", pageURL, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", pageURL, err)
+	}
+	return data, resp.Request.URL, nil
+}
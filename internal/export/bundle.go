@@ -0,0 +1,132 @@
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+
+	"github.com/awesome-directories/cli/internal/render"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// BundleFormats are the formats written into an archive bundle
+var BundleFormats = []struct {
+	Format string
+	Name   string
+}{
+	{"json", "directories.json"},
+	{"csv", "directories.csv"},
+	{"markdown", "directories.md"},
+}
+
+// BundleMetadata describes the contents of an archive bundle
+type BundleMetadata struct {
+	CreatedAt time.Time `json:"created_at"`
+	Count     int       `json:"count"`
+	Files     []string  `json:"files"`
+	Generator string    `json:"generator"`
+}
+
+// ToBundle writes a gzipped tar archive containing the directories in every
+// bundle format plus a metadata.json file
+func ToBundle(directories []models.Directory, outputPath string, opts render.Options) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create bundle file: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close bundle file")
+		}
+	}()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+
+	now := time.Now().UTC()
+	prefix := bundlePrefix(outputPath)
+	meta := BundleMetadata{
+		CreatedAt: now,
+		Count:     len(directories),
+		Generator: "awesome-directories",
+	}
+
+	// Only count rows once, while writing the first format
+	first := true
+	for _, bf := range BundleFormats {
+		renderer, err := render.Get(bf.Format)
+		if err != nil {
+			return err
+		}
+
+		formatOpts := render.Options{Template: opts.Template, Missing: opts.Missing, CanonicalJSON: opts.CanonicalJSON}
+		if first {
+			formatOpts.OnRow = opts.OnRow
+			first = false
+		}
+
+		var buf bytes.Buffer
+		if err := renderer.Render(&buf, directories, formatOpts); err != nil {
+			return fmt.Errorf("failed to render %s: %w", bf.Format, err)
+		}
+
+		if err := addTarFile(tw, prefix+bf.Name, buf.Bytes(), now); err != nil {
+			return err
+		}
+		meta.Files = append(meta.Files, bf.Name)
+	}
+
+	metaData, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	if err := addTarFile(tw, prefix+"metadata.json", append(metaData, '\n'), now); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish compression: %w", err)
+	}
+
+	return nil
+}
+
+// addTarFile adds a regular file to a tar archive
+func addTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}
+
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to write archive header for %s: %w", name, err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %w", name, err)
+	}
+
+	return nil
+}
+
+// bundlePrefix returns the directory inside the archive, derived from the
+// archive name: dirs.tar.gz contains dirs/
+func bundlePrefix(outputPath string) string {
+	base := filepath.Base(outputPath)
+	for _, ext := range []string{".tar.gz", ".tgz"} {
+		base = strings.TrimSuffix(base, ext)
+	}
+	return base + "/"
+}
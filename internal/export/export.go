@@ -1,171 +1,156 @@
 package export
 
 import (
-	"encoding/csv"
+	"errors"
 	"fmt"
 	"os"
-	"strconv"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/goccy/go-json"
 	"github.com/rs/zerolog/log"
 
+	"github.com/awesome-directories/cli/internal/render"
 	"github.com/awesome-directories/cli/pkg/models"
 )
 
-// ExportToCSV exports directories to CSV format
-func ExportToCSV(directories []models.Directory, outputPath string) error {
-	file, err := os.Create(outputPath)
+// ToFile exports directories to outputPath using the renderer registered for
+// format
+func ToFile(directories []models.Directory, format string, outputPath string, opts render.Options) error {
+	renderer, err := render.Get(format)
 	if err != nil {
-		return fmt.Errorf("failed to create CSV file: %w", err)
-	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close CSV file")
-		}
-	}()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
-
-	// Write header
-	header := []string{
-		"Name",
-		"URL",
-		"Description",
-		"Categories",
-		"Pricing",
-		"Link Type",
-		"Domain Rating",
-		"Organic Traffic",
-		"Organic Keywords",
-		"Helpful Votes",
-		"Submission URL",
-	}
-	if err := writer.Write(header); err != nil {
-		return fmt.Errorf("failed to write CSV header: %w", err)
-	}
-
-	// Write rows
-	for _, dir := range directories {
-		row := []string{
-			dir.Name,
-			dir.URL,
-			dir.Description,
-			strings.Join(dir.Categories, ", "),
-			dir.Pricing,
-			dir.LinkType,
-			strconv.Itoa(dir.DomainRating),
-			strconv.Itoa(dir.OrganicTraffic),
-			strconv.Itoa(dir.OrganicKeywords),
-			strconv.Itoa(dir.HelpfulCount),
-			dir.SubmissionURL,
-		}
-
-		if err := writer.Write(row); err != nil {
-			return fmt.Errorf("failed to write CSV row: %w", err)
-		}
+		return err
 	}
 
-	return nil
-}
-
-// ExportToJSON exports directories to JSON format
-func ExportToJSON(directories []models.Directory, outputPath string) error {
 	file, err := os.Create(outputPath)
 	if err != nil {
-		return fmt.Errorf("failed to create JSON file: %w", err)
+		return fmt.Errorf("failed to create %s file: %w", format, err)
 	}
 	defer func() {
 		if err := file.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close JSON file")
+			log.Error().Err(err).Msgf("Failed to close %s file", format)
 		}
 	}()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-
-	if err := encoder.Encode(directories); err != nil {
-		return fmt.Errorf("failed to write JSON: %w", err)
+	if err := renderer.Render(file, directories, opts); err != nil {
+		return err
 	}
 
 	return nil
 }
 
-// ExportToMarkdown exports directories to Markdown format
-func ExportToMarkdown(directories []models.Directory, outputPath string) error {
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create Markdown file: %w", err)
-	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			log.Error().Err(err).Msg("Failed to close Markdown file")
-		}
-	}()
+// ErrOutputExists is returned when the export destination already exists
+var ErrOutputExists = errors.New("output file already exists")
 
-	if _, err := fmt.Fprintf(file, "# Awesome Directories Export\n\n"); err != nil {
-		return fmt.Errorf("failed to write header: %w", err)
+// PrepareOutput makes sure outputPath can be written. An existing file is
+// only replaced when force is set, or after it has been moved to a
+// timestamped backup when backup is set. It returns the backup path, if any.
+func PrepareOutput(outputPath string, force, backup bool) (string, error) {
+	info, err := os.Stat(outputPath)
+	if os.IsNotExist(err) {
+		return "", nil
 	}
-	if _, err := fmt.Fprintf(file, "Total directories: %d\n\n", len(directories)); err != nil {
-		return fmt.Errorf("failed to write content: %w", err)
+	if err != nil {
+		return "", fmt.Errorf("failed to check output file: %w", err)
 	}
-	if _, err := fmt.Fprintf(file, "---\n\n"); err != nil {
-		return fmt.Errorf("failed to write separator: %w", err)
+
+	if info.IsDir() {
+		return "", fmt.Errorf("output path %s is a directory", outputPath)
 	}
 
-	// Group by category
-	categoryMap := make(map[string][]models.Directory)
-	for _, dir := range directories {
-		for _, cat := range dir.Categories {
-			categoryMap[cat] = append(categoryMap[cat], dir)
+	if backup {
+		backupPath := BackupPath(outputPath, time.Now())
+		if err := os.Rename(outputPath, backupPath); err != nil {
+			return "", fmt.Errorf("failed to back up %s: %w", outputPath, err)
 		}
+		log.Debug().Str("backup", backupPath).Msg("Backed up existing output file")
+		return backupPath, nil
 	}
 
-	// Write by category
-	for category, dirs := range categoryMap {
-		if _, err := fmt.Fprintf(file, "## %s\n\n", category); err != nil {
-			return fmt.Errorf("failed to write category: %w", err)
-		}
+	if !force {
+		return "", fmt.Errorf("%w: %s (use --force to overwrite or --backup to keep a copy)", ErrOutputExists, outputPath)
+	}
 
-		for _, dir := range dirs {
-			if _, err := fmt.Fprintf(file, "### [%s](%s)\n\n", dir.Name, dir.URL); err != nil {
-				return fmt.Errorf("failed to write directory name: %w", err)
-			}
-			if _, err := fmt.Fprintf(file, "%s\n\n", dir.Description); err != nil {
-				return fmt.Errorf("failed to write description: %w", err)
-			}
+	return "", nil
+}
 
-			if _, err := fmt.Fprintf(file, "- **Pricing:** %s\n", dir.Pricing); err != nil {
-				return fmt.Errorf("failed to write pricing: %w", err)
-			}
-			if _, err := fmt.Fprintf(file, "- **Link Type:** %s\n", dir.LinkType); err != nil {
-				return fmt.Errorf("failed to write link type: %w", err)
-			}
+// BackupPath returns the timestamped backup path for a file, keeping its
+// extension: dirs.csv becomes dirs.20240115-093000.csv
+func BackupPath(path string, t time.Time) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "." + t.Format("20060102-150405") + ext
+}
 
-			if dir.DomainRating > 0 {
-				if _, err := fmt.Fprintf(file, "- **Domain Rating:** %d\n", dir.DomainRating); err != nil {
-					return fmt.Errorf("failed to write domain rating: %w", err)
-				}
-			}
+// Target is a format to export and the file it is written to
+type Target struct {
+	Format string
+	Path   string
+}
 
-			if dir.HelpfulCount > 0 {
-				if _, err := fmt.Fprintf(file, "- **Helpful Votes:** %d\n", dir.HelpfulCount); err != nil {
-					return fmt.Errorf("failed to write helpful votes: %w", err)
-				}
-			}
+// fileNames are the file names of formats exported into a directory, so
+// formats sharing an extension don't collide
+var fileNames = map[string]string{
+	"json":       "directories.json",
+	"yaml":       "directories.yaml",
+	"csv":        "directories.csv",
+	"markdown":   "directories.md",
+	"awesome-md": "awesome.md",
+	"bookmarks":  "bookmarks.html",
+	"opml":       "directories.opml",
+	"alfred":     "alfred.json",
+	"raycast":    "raycast.json",
+	"table":      "directories.txt",
+	"template":   "template.txt",
+	"bundle":     "directories.tar.gz",
+}
 
-			if dir.SubmissionURL != "" {
-				if _, err := fmt.Fprintf(file, "- **Submission URL:** %s\n", dir.SubmissionURL); err != nil {
-					return fmt.Errorf("failed to write submission URL: %w", err)
-				}
-			}
+// FileName returns the name of the file a format is exported to within an
+// output directory, such as directories.csv for csv
+func FileName(format string) (string, error) {
+	if format != "bundle" {
+		canonical, err := render.Canonical(format)
+		if err != nil {
+			return "", err
+		}
+		format = canonical
+	}
 
-			if _, err := fmt.Fprintf(file, "\n"); err != nil {
-				return fmt.Errorf("failed to write newline: %w", err)
-			}
+	name, ok := fileNames[format]
+	if !ok {
+		name = "directories." + format
+	}
+	return name, nil
+}
+
+// ToFiles exports directories to every target in one pass, rendering the
+// formats concurrently. opts.OnRow is only called while writing the first
+// target, so rows are counted once.
+func ToFiles(directories []models.Directory, targets []Target, opts render.Options) error {
+	errs := make([]error, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		targetOpts := render.Options{Template: opts.Template, Missing: opts.Missing, CanonicalJSON: opts.CanonicalJSON}
+		if i == 0 {
+			targetOpts.OnRow = opts.OnRow
 		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var err error
+			if target.Format == "bundle" {
+				err = ToBundle(directories, target.Path, targetOpts)
+			} else {
+				err = ToFile(directories, target.Format, target.Path, targetOpts)
+			}
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", target.Path, err)
+			}
+		}()
 	}
+	wg.Wait()
 
-	return nil
+	return errors.Join(errs...)
 }
@@ -0,0 +1,431 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif" // decoders for logos
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+)
+
+// A4 page size and margins, in points
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 48.0
+)
+
+// PDF fonts: the standard Helvetica faces every viewer has, so nothing needs
+// to be embedded
+const (
+	pdfRegular = "F1"
+	pdfBold    = "F2"
+)
+
+// pdfColor is an RGB color with components from 0 to 1
+type pdfColor struct{ r, g, b float64 }
+
+var (
+	pdfBlack  = pdfColor{0.13, 0.13, 0.15}
+	pdfMuted  = pdfColor{0.42, 0.44, 0.48}
+	pdfAccent = pdfColor{0.15, 0.35, 0.75}
+	pdfRule   = pdfColor{0.85, 0.86, 0.88}
+	pdfStripe = pdfColor{0.96, 0.97, 0.98}
+	pdfWhite  = pdfColor{1, 1, 1}
+)
+
+// pdfDocument lays out text, rules, boxes and one image on A4 pages, top to
+// bottom, and writes them as a PDF file
+type pdfDocument struct {
+	pages []*bytes.Buffer
+
+	// current is the page drawn on
+	current int
+
+	// y is where the next content goes on the current page, from the bottom
+	y float64
+
+	// image is the logo drawn with drawImage, if any
+	image *pdfImage
+
+	// header is drawn at the top of every page after the first
+	header func()
+}
+
+// pdfImage is an image XObject
+type pdfImage struct {
+	width, height int
+	colorSpace    string
+	filter        string
+	data          []byte
+}
+
+// newPage starts a page and moves to its top
+func (d *pdfDocument) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.current = len(d.pages) - 1
+	d.y = pdfPageHeight - pdfMargin
+	if d.header != nil && len(d.pages) > 1 {
+		d.header()
+	}
+}
+
+// ensure starts a new page unless height fits above the bottom margin,
+// reporting whether it did
+func (d *pdfDocument) ensure(height float64) bool {
+	if len(d.pages) > 0 && d.y-height >= pdfMargin+20 {
+		return false
+	}
+	d.newPage()
+	return true
+}
+
+// page returns the content stream of the current page
+func (d *pdfDocument) page() *bytes.Buffer {
+	return d.pages[d.current]
+}
+
+// eachPage calls draw on every page once the layout is done, for footers
+// showing the page count
+func (d *pdfDocument) eachPage(draw func(number, total int)) {
+	for i := range d.pages {
+		d.current = i
+		draw(i+1, len(d.pages))
+	}
+}
+
+// text draws s with its baseline at x, y
+func (d *pdfDocument) text(x, y float64, font string, size float64, c pdfColor, s string) {
+	fmt.Fprintf(d.page(), "BT %.3f %.3f %.3f rg /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n",
+		c.r, c.g, c.b, font, size, x, y, pdfEscape(pdfEncode(s)))
+}
+
+// textRight draws s ending at x
+func (d *pdfDocument) textRight(x, y float64, font string, size float64, c pdfColor, s string) {
+	d.text(x-pdfTextWidth(font, size, s), y, font, size, c, s)
+}
+
+// paragraph draws s wrapped to width from the current position and moves
+// below it
+func (d *pdfDocument) paragraph(font string, size float64, c pdfColor, width float64, s string) {
+	leading := size * 1.4
+	for _, line := range pdfWrap(font, size, width, s) {
+		d.ensure(leading)
+		d.y -= leading
+		d.text(pdfMargin, d.y, font, size, c, line)
+	}
+}
+
+// fillRect draws a filled rectangle with its lower left corner at x, y
+func (d *pdfDocument) fillRect(x, y, w, h float64, c pdfColor) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f\n", c.r, c.g, c.b, x, y, w, h)
+}
+
+// rule draws a horizontal line across the page at y
+func (d *pdfDocument) rule(y float64, c pdfColor) {
+	fmt.Fprintf(d.page(), "%.3f %.3f %.3f RG 0.75 w %.2f %.2f m %.2f %.2f l S\n",
+		c.r, c.g, c.b, pdfMargin, y, pdfPageWidth-pdfMargin, y)
+}
+
+// drawImage draws the image scaled to fit a box of size points with its lower
+// left corner at x, y, returning the width it took
+func (d *pdfDocument) drawImage(x, y, size float64) float64 {
+	w, h := size, size
+	if d.image.width > d.image.height {
+		h = size * float64(d.image.height) / float64(d.image.width)
+	} else {
+		w = size * float64(d.image.width) / float64(d.image.height)
+	}
+	fmt.Fprintf(d.page(), "q %.2f 0 0 %.2f %.2f %.2f cm /Im1 Do Q\n", w, h, x, y+(size-h)/2)
+	return w
+}
+
+// bytes writes the document as a PDF file
+func (d *pdfDocument) bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	// Objects are numbered from 1: catalog, page tree, fonts, the image, then
+	// each page and its content
+	begin := func() int {
+		offsets = append(offsets, buf.Len())
+		n := len(offsets)
+		fmt.Fprintf(&buf, "%d 0 obj\n", n)
+		return n
+	}
+	stream := func(dict string, data []byte) {
+		begin()
+		fmt.Fprintf(&buf, "<< %s /Length %d >>\nstream\n", dict, len(data))
+		buf.Write(data)
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	firstPage := 5
+	if d.image != nil {
+		firstPage = 6
+	}
+	var kids []string
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", firstPage+2*i))
+	}
+
+	begin()
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	begin()
+	fmt.Fprintf(&buf, "<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), len(d.pages))
+	for _, font := range []string{"Helvetica", "Helvetica-Bold"} {
+		begin()
+		fmt.Fprintf(&buf, "<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>\nendobj\n", font)
+	}
+
+	resources := "/Font << /F1 3 0 R /F2 4 0 R >>"
+	if d.image != nil {
+		dict := fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /%s",
+			d.image.width, d.image.height, d.image.colorSpace, d.image.filter)
+		stream(dict, d.image.data)
+		resources += " /XObject << /Im1 5 0 R >>"
+	}
+
+	for i, content := range d.pages {
+		begin()
+		fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << %s >> /Contents %d 0 R >>\nendobj\n",
+			pdfPageWidth, pdfPageHeight, resources, firstPage+2*i+1)
+
+		compressed, err := pdfDeflate(content.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		stream("/Filter /FlateDecode", compressed)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.Bytes(), nil
+}
+
+// CheckImage reports whether an image can be put in a PDF report
+func CheckImage(data []byte) error {
+	_, err := newPDFImage(data)
+	return err
+}
+
+// newPDFImage prepares a PNG, JPEG or GIF image for a PDF. JPEGs are embedded
+// as they are; other images are flattened onto white.
+func newPDFImage(data []byte) (*pdfImage, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image (use PNG, JPEG or GIF): %w", err)
+	}
+
+	// CMYK JPEGs are converted, as viewers disagree on their colors
+	if format == "jpeg" && config.ColorModel != color.CMYKModel {
+		colorSpace := "DeviceRGB"
+		if config.ColorModel == color.GrayModel {
+			colorSpace = "DeviceGray"
+		}
+		return &pdfImage{width: config.Width, height: config.Height, colorSpace: colorSpace, filter: "DCTDecode", data: data}, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	bounds := img.Bounds()
+	raw := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			// Premultiplied components over a white background
+			r, g, b, a := img.At(x, y).RGBA()
+			white := 0xffff - a
+			raw = append(raw, byte((r+white)>>8), byte((g+white)>>8), byte((b+white)>>8))
+		}
+	}
+	compressed, err := pdfDeflate(raw)
+	if err != nil {
+		return nil, err
+	}
+	return &pdfImage{width: bounds.Dx(), height: bounds.Dy(), colorSpace: "DeviceRGB", filter: "FlateDecode", data: compressed}, nil
+}
+
+// pdfDeflate compresses a stream
+func pdfDeflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress PDF stream: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress PDF stream: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// pdfWinAnsi maps the characters of Windows-1252 outside Latin-1
+var pdfWinAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '‘': 0x91, '’': 0x92,
+	'“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '™': 0x99,
+}
+
+// pdfEncode converts text to the WinAnsi encoding of the fonts, replacing
+// characters it lacks
+func pdfEncode(s string) string {
+	var buf strings.Builder
+	for _, r := range s {
+		switch {
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			buf.WriteByte(byte(r))
+		case pdfWinAnsi[r] != 0:
+			buf.WriteByte(pdfWinAnsi[r])
+		case r == '→':
+			buf.WriteString("->")
+		default:
+			buf.WriteByte('?')
+		}
+	}
+	return buf.String()
+}
+
+// pdfEscape escapes a PDF string literal
+func pdfEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "(", `\(`, ")", `\)`, "\r", " ", "\n", " ").Replace(s)
+}
+
+// Widths of the printable ASCII characters in thousandths of the font size,
+// from the Helvetica font metrics
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+// pdfTextWidth measures text in points
+func pdfTextWidth(font string, size float64, s string) float64 {
+	widths := &helveticaWidths
+	if font == pdfBold {
+		widths = &helveticaBoldWidths
+	}
+	total := 0
+	for _, c := range []byte(pdfEncode(s)) {
+		switch {
+		case c >= 32 && c < 127:
+			total += widths[c-32]
+		case c == 0x85 || c == 0x97:
+			total += 1000
+		default:
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// pdfFit shortens text with an ellipsis to fit width
+func pdfFit(font string, size, width float64, s string) string {
+	if pdfTextWidth(font, size, s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdfTextWidth(font, size, string(runes)+"…") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "…"
+}
+
+// pdfWrap breaks text into lines fitting width at spaces
+func pdfWrap(font string, size, width float64, s string) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if line != "" && pdfTextWidth(font, size, candidate) > width {
+			lines = append(lines, line)
+			candidate = word
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// pdfColumn is a column of a table
+type pdfColumn struct {
+	title string
+	width float64
+	right bool
+}
+
+// table draws rows under a header row, repeating the header on every page it
+// spans
+func (d *pdfDocument) table(columns []pdfColumn, rows [][]string) {
+	const rowHeight, size = 18.0, 9.0
+
+	header := func() {
+		d.y -= rowHeight
+		x := pdfMargin
+		for _, column := range columns {
+			title := strings.ToUpper(column.title)
+			if column.right {
+				d.textRight(x+column.width-4, d.y+6, pdfBold, 7.5, pdfMuted, title)
+			} else {
+				d.text(x+4, d.y+6, pdfBold, 7.5, pdfMuted, title)
+			}
+			x += column.width
+		}
+		d.rule(d.y, pdfRule)
+	}
+
+	d.ensure(2 * rowHeight)
+	header()
+	for i, row := range rows {
+		if d.ensure(rowHeight) {
+			header()
+		}
+		d.y -= rowHeight
+		if i%2 == 1 {
+			d.fillRect(pdfMargin, d.y, pdfPageWidth-2*pdfMargin, rowHeight, pdfStripe)
+		}
+
+		x := pdfMargin
+		for j, column := range columns {
+			font := pdfRegular
+			if j == 0 {
+				font = pdfBold
+			}
+			cell := pdfFit(font, size, column.width-8, row[j])
+			if column.right {
+				d.textRight(x+column.width-4, d.y+6, font, size, pdfBlack, cell)
+			} else {
+				d.text(x+4, d.y+6, font, size, pdfBlack, cell)
+			}
+			x += column.width
+		}
+	}
+}
@@ -0,0 +1,420 @@
+package export
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// Report is the submission pipeline of a project, for a client report
+type Report struct {
+	Project     string
+	Submissions []models.TrackedSubmission
+
+	// Directories are the catalog entries of the submissions, by slug
+	Directories map[string]models.Directory
+
+	// EvidencePath locates the evidence files of a submission
+	EvidencePath func(*models.TrackedSubmission, models.Evidence) string
+
+	// Brand is the product a PDF report is prepared for, if any
+	Brand *ReportBrand
+}
+
+// ReportBrand names a PDF report after a product
+type ReportBrand struct {
+	Name    string
+	Tagline string
+
+	// Logo is a PNG, JPEG or GIF image, if any
+	Logo []byte
+}
+
+// ToReport writes a gzipped tar archive with a Markdown report of the
+// submissions, the submissions as JSON, their evidence files and a
+// metadata.json file
+func ToReport(report Report, outputPath string) error {
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close report file")
+		}
+	}()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+
+	now := time.Now().UTC()
+	prefix := bundlePrefix(outputPath)
+	meta := BundleMetadata{
+		CreatedAt: now,
+		Count:     len(report.Submissions),
+		Generator: "awesome-directories",
+	}
+
+	// Evidence files are stored as evidence/<project>/<directory>/<file>
+	for i := range report.Submissions {
+		submission := &report.Submissions[i]
+		for _, evidence := range submission.Evidence {
+			if evidence.File == "" {
+				continue
+			}
+			data, err := os.ReadFile(report.EvidencePath(submission, evidence))
+			if err != nil {
+				return fmt.Errorf("failed to read evidence of %s: %w", submission.Directory, err)
+			}
+			name := reportEvidenceName(submission, evidence)
+			if err := addTarFile(tw, prefix+name, data, now); err != nil {
+				return err
+			}
+			meta.Files = append(meta.Files, name)
+		}
+	}
+
+	submissionsData, err := json.MarshalIndent(report.Submissions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal submissions: %w", err)
+	}
+	files := []struct {
+		name string
+		data []byte
+	}{
+		{"report.md", reportMarkdown(report, now)},
+		{"submissions.json", append(submissionsData, '\n')},
+	}
+	for _, f := range files {
+		if err := addTarFile(tw, prefix+f.name, f.data, now); err != nil {
+			return err
+		}
+		meta.Files = append(meta.Files, f.name)
+	}
+
+	metaData, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	if err := addTarFile(tw, prefix+"metadata.json", append(metaData, '\n'), now); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("failed to finish compression: %w", err)
+	}
+
+	return nil
+}
+
+// reportEvidenceName returns the path of an evidence file inside a report
+func reportEvidenceName(submission *models.TrackedSubmission, evidence models.Evidence) string {
+	return path.Join("evidence", submission.Project, submission.Directory, evidence.File)
+}
+
+// reportMarkdown renders the submission pipeline as a Markdown document
+// linking to the evidence in the archive
+func reportMarkdown(report Report, now time.Time) []byte {
+	var buf bytes.Buffer
+
+	title := "Submission report"
+	if report.Project != "" {
+		title += ": " + report.Project
+	}
+	fmt.Fprintf(&buf, "# %s\n\n", title)
+	fmt.Fprintf(&buf, "Generated %s.\n\n", now.Format("January 2, 2006"))
+
+	counts := make(map[string]int)
+	for _, submission := range report.Submissions {
+		counts[submission.Status]++
+	}
+	buf.WriteString("| Status | Submissions |\n|---|---|\n")
+	for _, status := range models.SubmissionStatuses {
+		fmt.Fprintf(&buf, "| %s | %d |\n", status, counts[status])
+	}
+
+	buf.WriteString("\n## Submissions\n\n")
+	buf.WriteString("| Directory | DR | Project | Status | Updated | Listing | Evidence |\n|---|---|---|---|---|---|---|\n")
+	for i := range report.Submissions {
+		submission := &report.Submissions[i]
+
+		name, dr := submission.Directory, "-"
+		if dir, ok := report.Directories[submission.Directory]; ok {
+			name = fmt.Sprintf("[%s](%s)", markdownCell(dir.Name), dir.URL)
+			dr = fmt.Sprint(dir.DomainRating)
+		}
+
+		listing := "-"
+		if submission.ListingURL != "" {
+			listing = fmt.Sprintf("[live](%s)", submission.ListingURL)
+		}
+
+		var evidence []string
+		for _, e := range submission.Evidence {
+			if e.File != "" {
+				evidence = append(evidence, fmt.Sprintf("[%s](%s)", markdownCell(e.File), reportEvidenceName(submission, e)))
+			} else {
+				evidence = append(evidence, fmt.Sprintf("[link](%s)", e.URL))
+			}
+		}
+		if len(evidence) == 0 {
+			evidence = []string{"-"}
+		}
+
+		fmt.Fprintf(&buf, "| %s | %s | %s | %s | %s | %s | %s |\n",
+			name, dr, submission.Project, submission.Status,
+			submission.UpdatedAt.Format("2006-01-02"), listing, strings.Join(evidence, ", "))
+	}
+
+	return buf.Bytes()
+}
+
+// markdownCell escapes text for a Markdown table cell
+func markdownCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ", "[", `\[`, "]", `\]`).Replace(text)
+}
+
+// reportHighlights is how many of the highest-rated directories not yet
+// submitted a PDF report suggests
+const reportHighlights = 10
+
+// ToReportPDF writes a paginated PDF report of the submissions, branded with
+// the product's name and logo, with highlights of the catalog
+func ToReportPDF(report Report, outputPath string) error {
+	doc := &pdfDocument{}
+	if report.Brand != nil && len(report.Brand.Logo) > 0 {
+		image, err := newPDFImage(report.Brand.Logo)
+		if err != nil {
+			return fmt.Errorf("failed to use logo: %w", err)
+		}
+		doc.image = image
+	}
+
+	now := time.Now()
+	title := "Submission report"
+	if report.Project != "" {
+		title += ": " + report.Project
+	}
+	brand := "awesome-directories"
+	if report.Brand != nil {
+		brand = report.Brand.Name
+	}
+
+	doc.header = func() {
+		doc.text(pdfMargin, doc.y-8, pdfBold, 8, pdfMuted, brand)
+		doc.textRight(pdfPageWidth-pdfMargin, doc.y-8, pdfRegular, 8, pdfMuted, title)
+		doc.y -= 16
+		doc.rule(doc.y, pdfRule)
+		doc.y -= 8
+	}
+	doc.newPage()
+	reportPDFHeading(doc, report, brand, now)
+
+	doc.y -= 28
+	doc.text(pdfMargin, doc.y, pdfBold, 18, pdfBlack, title)
+	doc.y -= 6
+	doc.paragraph(pdfRegular, 10, pdfMuted, pdfPageWidth-2*pdfMargin, reportPDFSummary(report))
+
+	reportPDFStatuses(doc, report)
+
+	doc.y -= 34
+	doc.text(pdfMargin, doc.y, pdfBold, 13, pdfBlack, "Submission pipeline")
+	doc.y -= 4
+	doc.table(reportPDFColumns, reportPDFRows(report))
+
+	if len(report.Directories) > 0 {
+		doc.ensure(80)
+		doc.y -= 34
+		doc.text(pdfMargin, doc.y, pdfBold, 13, pdfBlack, "Catalog highlights")
+		doc.y -= 4
+		doc.paragraph(pdfRegular, 10, pdfMuted, pdfPageWidth-2*pdfMargin, reportPDFCatalog(report))
+
+		if rows := reportPDFHighlights(report); len(rows) > 0 {
+			doc.y -= 10
+			doc.ensure(60)
+			doc.y -= 12
+			doc.text(pdfMargin, doc.y, pdfBold, 10, pdfBlack, "Highest-rated directories not yet submitted")
+			doc.table(reportPDFHighlightColumns, rows)
+		}
+	}
+
+	doc.eachPage(func(number, total int) {
+		doc.text(pdfMargin, pdfMargin-20, pdfRegular, 8, pdfMuted, fmt.Sprintf("%s · %s", brand, now.Format("January 2, 2006")))
+		doc.textRight(pdfPageWidth-pdfMargin, pdfMargin-20, pdfRegular, 8, pdfMuted, fmt.Sprintf("Page %d of %d", number, total))
+	})
+
+	data, err := doc.bytes()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write report file: %w", err)
+	}
+	return nil
+}
+
+// reportPDFHeading draws the brand and the report date at the top of the
+// first page
+func reportPDFHeading(doc *pdfDocument, report Report, brand string, now time.Time) {
+	const logoSize = 40.0
+
+	top := doc.y
+	x := pdfMargin
+	if doc.image != nil {
+		x += doc.drawImage(pdfMargin, top-logoSize, logoSize) + 12
+	}
+	doc.text(x, top-18, pdfBold, 18, pdfAccent, brand)
+	if report.Brand != nil && report.Brand.Tagline != "" {
+		doc.text(x, top-34, pdfRegular, 9.5, pdfMuted, pdfFit(pdfRegular, 9.5, pdfPageWidth-pdfMargin-x-130, report.Brand.Tagline))
+	}
+
+	doc.textRight(pdfPageWidth-pdfMargin, top-14, pdfBold, 8, pdfMuted, "SUBMISSION REPORT")
+	doc.textRight(pdfPageWidth-pdfMargin, top-28, pdfRegular, 9.5, pdfBlack, now.Format("January 2, 2006"))
+
+	doc.y = top - logoSize - 10
+	doc.rule(doc.y, pdfRule)
+}
+
+// reportPDFSummary describes the submissions in a sentence
+func reportPDFSummary(report Report) string {
+	live, evidence := 0, 0
+	for _, submission := range report.Submissions {
+		if submission.ListingURL != "" {
+			live++
+		}
+		evidence += len(submission.Evidence)
+	}
+
+	scope := "across all projects"
+	if report.Project != "" {
+		scope = "in project " + report.Project
+	}
+	return fmt.Sprintf("%d directory submission(s) tracked %s, %d of them live with a listing link, backed by %d evidence item(s).",
+		len(report.Submissions), scope, live, evidence)
+}
+
+// reportPDFStatuses draws a box with the number of submissions of each status
+func reportPDFStatuses(doc *pdfDocument, report Report) {
+	const gap, height = 10.0, 50.0
+
+	counts := make(map[string]int)
+	for _, submission := range report.Submissions {
+		counts[submission.Status]++
+	}
+
+	doc.y -= 14 + height
+	width := (pdfPageWidth - 2*pdfMargin - gap*float64(len(models.SubmissionStatuses)-1)) / float64(len(models.SubmissionStatuses))
+	for i, status := range models.SubmissionStatuses {
+		x := pdfMargin + float64(i)*(width+gap)
+		doc.fillRect(x, doc.y, width, height, pdfStripe)
+		doc.fillRect(x, doc.y, 3, height, pdfAccent)
+		doc.text(x+14, doc.y+22, pdfBold, 20, pdfBlack, fmt.Sprint(counts[status]))
+		doc.text(x+14, doc.y+10, pdfRegular, 8, pdfMuted, strings.ToUpper(status))
+	}
+}
+
+// reportPDFColumns are the columns of the submission pipeline table
+var reportPDFColumns = []pdfColumn{
+	{title: "Directory", width: 165},
+	{title: "DR", width: 35, right: true},
+	{title: "Project", width: 75},
+	{title: "Status", width: 62},
+	{title: "Updated", width: 62},
+	{title: "Listing", width: 70},
+	{title: "Evidence", width: 30, right: true},
+}
+
+// reportPDFRows are the rows of the submission pipeline table
+func reportPDFRows(report Report) [][]string {
+	var rows [][]string
+	for _, submission := range report.Submissions {
+		name, dr := submission.Directory, "-"
+		if dir, ok := report.Directories[submission.Directory]; ok {
+			name, dr = dir.Name, fmt.Sprint(dir.DomainRating)
+		}
+
+		listing := "-"
+		if submission.ListingURL != "" {
+			listing = "live"
+		}
+
+		rows = append(rows, []string{
+			name, dr, submission.Project, submission.Status,
+			submission.UpdatedAt.Format("2006-01-02"), listing, fmt.Sprint(len(submission.Evidence)),
+		})
+	}
+	return rows
+}
+
+// reportPDFCatalog describes the catalog in a sentence
+func reportPDFCatalog(report Report) string {
+	free, dofollow, strong := 0, 0, 0
+	for _, dir := range report.Directories {
+		if dir.Pricing == "free" {
+			free++
+		}
+		if dir.LinkType == "dofollow" {
+			dofollow++
+		}
+		if dir.DomainRating >= 50 {
+			strong++
+		}
+	}
+	return fmt.Sprintf("The catalog lists %d directories: %d free to submit to, %d giving dofollow links and %d with a domain rating of 50 or more.",
+		len(report.Directories), free, dofollow, strong)
+}
+
+// reportPDFHighlightColumns are the columns of the catalog highlights table
+var reportPDFHighlightColumns = []pdfColumn{
+	{title: "Directory", width: 175},
+	{title: "DR", width: 35, right: true},
+	{title: "Pricing", width: 65},
+	{title: "Link", width: 65},
+	{title: "Category", width: 159},
+}
+
+// reportPDFHighlights are the highest-rated directories of the catalog that
+// no reported submission targets
+func reportPDFHighlights(report Report) [][]string {
+	submitted := make(map[string]bool)
+	for _, submission := range report.Submissions {
+		submitted[submission.Directory] = true
+	}
+
+	var candidates []models.Directory
+	for slug, dir := range report.Directories {
+		if !submitted[slug] {
+			candidates = append(candidates, dir)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].DomainRating != candidates[j].DomainRating {
+			return candidates[i].DomainRating > candidates[j].DomainRating
+		}
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	var rows [][]string
+	for _, dir := range candidates[:min(len(candidates), reportHighlights)] {
+		category := "-"
+		if len(dir.Categories) > 0 {
+			category = dir.Categories[0]
+		}
+		rows = append(rows, []string{dir.Name, fmt.Sprint(dir.DomainRating), dir.Pricing, dir.LinkType, category})
+	}
+	return rows
+}
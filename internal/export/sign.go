@@ -0,0 +1,84 @@
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// WriteChecksum writes a sha256sum-compatible checksum file next to path and
+// returns its location
+func WriteChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close file")
+		}
+	}()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+
+	checksumPath := path + ".sha256"
+	line := fmt.Sprintf("%s  %s\n", hex.EncodeToString(hash.Sum(nil)), filepath.Base(path))
+	if err := os.WriteFile(checksumPath, []byte(line), 0644); err != nil {
+		return "", fmt.Errorf("failed to write checksum file: %w", err)
+	}
+
+	return checksumPath, nil
+}
+
+// Sign signs path with minisign or cosign using the given private key and
+// returns the signature file location
+func Sign(ctx context.Context, tool, key, path string) (string, error) {
+	if key == "" {
+		return "", fmt.Errorf("no signing key configured (set signing_key in config or SIGNING_KEY)")
+	}
+
+	var (
+		args          []string
+		signaturePath string
+	)
+
+	switch strings.ToLower(tool) {
+	case "minisign":
+		signaturePath = path + ".minisig"
+		args = []string{"-S", "-s", key, "-m", path, "-x", signaturePath}
+	case "cosign":
+		signaturePath = path + ".sig"
+		args = []string{"sign-blob", "--key", key, "--output-signature", signaturePath, "--yes", path}
+	default:
+		return "", fmt.Errorf("unsupported signing tool: %s (use minisign or cosign)", tool)
+	}
+
+	binary, err := exec.LookPath(strings.ToLower(tool))
+	if err != nil {
+		return "", fmt.Errorf("%s not found in PATH: %w", tool, err)
+	}
+
+	cmd := exec.CommandContext(ctx, binary, args...)
+	// Both tools may prompt for the key password
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = os.Stderr
+
+	log.Debug().Str("tool", tool).Strs("args", args).Msg("Signing export")
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s failed: %w", tool, err)
+	}
+
+	return signaturePath, nil
+}
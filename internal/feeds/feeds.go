@@ -0,0 +1,151 @@
+package feeds
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// discoverWorkers is how many pages Discover fetches at once
+const discoverWorkers = 8
+
+// maxPageSize is how much of a page is searched for feed links
+const maxPageSize = 512 << 10
+
+var (
+	linkTagPattern = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+	attrPattern    = regexp.MustCompile(`(?is)([a-z-]+)\s*=\s*("[^"]*"|'[^']*'|[^\s>]+)`)
+)
+
+// feedTypes are the link types announcing a feed
+var feedTypes = []string{"application/rss+xml", "application/atom+xml", "application/feed+json"}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Discover fills in the feed URL of directories without one, from the feed
+// links announced on their homepages, and returns how many were found.
+// Directories whose page can't be fetched are left unchanged. onDone, when
+// set, is called once per directory.
+func Discover(ctx context.Context, directories []models.Directory, onDone func()) int {
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	found := 0
+
+	for range discoverWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				feedURL, err := FeedURL(ctx, directories[i].URL)
+				if err != nil {
+					log.Debug().Err(err).Str("directory", directories[i].Slug).Msg("No feed found")
+				}
+
+				mu.Lock()
+				if feedURL != "" {
+					directories[i].FeedURL = feedURL
+					found++
+				}
+				if onDone != nil {
+					onDone()
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range directories {
+		if directories[i].FeedURL == "" && directories[i].URL != "" {
+			jobs <- i
+		} else if onDone != nil {
+			mu.Lock()
+			onDone()
+			mu.Unlock()
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return found
+}
+
+// FeedURL returns the first RSS, Atom or JSON feed linked from the page at
+// pageURL, or an empty string when it links none
+func FeedURL(ctx context.Context, pageURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", pageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/html")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", pageURL, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to fetch %s: status %d", pageURL, resp.StatusCode)
+	}
+
+	page, err := io.ReadAll(io.LimitReader(resp.Body, maxPageSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", pageURL, err)
+	}
+
+	href := findFeedLink(string(page))
+	if href == "" {
+		return "", nil
+	}
+
+	// Feed links are often relative to the page
+	feed, err := resp.Request.URL.Parse(href)
+	if err != nil {
+		return "", fmt.Errorf("invalid feed link %q: %w", href, err)
+	}
+	return feed.String(), nil
+}
+
+// findFeedLink returns the href of the first <link rel="alternate"> to a
+// feed in page
+func findFeedLink(page string) string {
+	for _, tag := range linkTagPattern.FindAllString(page, -1) {
+		attrs := make(map[string]string)
+		for _, match := range attrPattern.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(match[1])] = strings.Trim(match[2], `"'`)
+		}
+
+		if !containsFold(strings.Fields(attrs["rel"]), "alternate") || attrs["href"] == "" {
+			continue
+		}
+		if containsFold(feedTypes, attrs["type"]) {
+			return html.UnescapeString(attrs["href"])
+		}
+	}
+	return ""
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,265 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+)
+
+// graphQLURL is the GitHub GraphQL API endpoint
+const graphQLURL = "https://api.github.com/graphql"
+
+// statusField is the single select field holding the board column of an item
+const statusField = "Status"
+
+// projectURLPattern matches the URL of a GitHub project (v2)
+var projectURLPattern = regexp.MustCompile(`^https://github\.com/(orgs|users)/([^/]+)/projects/(\d+)`)
+
+// Client talks to the GitHub GraphQL API
+type Client struct {
+	token  string
+	client *http.Client
+}
+
+// NewClient creates a GitHub client authenticated with token
+func NewClient(token string) *Client {
+	return &Client{
+		token:  token,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Project is a GitHub project (v2) board
+type Project struct {
+	ID    string
+	Title string
+
+	// StatusFieldID is the ID of the Status field, and StatusOptions maps
+	// its column names to option IDs
+	StatusFieldID string
+	StatusOptions map[string]string
+
+	// Items maps the titles of draft issues on the board to item IDs
+	Items map[string]string
+}
+
+// ParseProjectURL returns the owner login and number of a project from its
+// URL, such as https://github.com/orgs/acme/projects/3
+func ParseProjectURL(projectURL string) (string, int, error) {
+	match := projectURLPattern.FindStringSubmatch(projectURL)
+	if match == nil {
+		return "", 0, fmt.Errorf("invalid GitHub project URL: %s (expected https://github.com/orgs/<org>/projects/<number>)", projectURL)
+	}
+
+	number, err := strconv.Atoi(match[3])
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid GitHub project number: %s", match[3])
+	}
+	return match[2], number, nil
+}
+
+// Column returns the option ID of a Status column, ignoring case
+func (p *Project) Column(name string) (string, bool) {
+	for option, id := range p.StatusOptions {
+		if strings.EqualFold(option, name) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+const projectQuery = `query($owner: String!, $number: Int!, $after: String) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id
+        title
+        field(name: "` + statusField + `") {
+          ... on ProjectV2SingleSelectField { id options { id name } }
+        }
+        items(first: 100, after: $after) {
+          pageInfo { hasNextPage endCursor }
+          nodes { id content { ... on DraftIssue { title } } }
+        }
+      }
+    }
+  }
+}`
+
+// Project fetches a project of owner with its Status field and items
+func (c *Client) Project(ctx context.Context, owner string, number int) (*Project, error) {
+	project := &Project{
+		StatusOptions: make(map[string]string),
+		Items:         make(map[string]string),
+	}
+
+	var after *string
+	for {
+		var data struct {
+			RepositoryOwner *struct {
+				ProjectV2 *struct {
+					ID    string `json:"id"`
+					Title string `json:"title"`
+					Field *struct {
+						ID      string `json:"id"`
+						Options []struct {
+							ID   string `json:"id"`
+							Name string `json:"name"`
+						} `json:"options"`
+					} `json:"field"`
+					Items struct {
+						PageInfo struct {
+							HasNextPage bool   `json:"hasNextPage"`
+							EndCursor   string `json:"endCursor"`
+						} `json:"pageInfo"`
+						Nodes []struct {
+							ID      string `json:"id"`
+							Content *struct {
+								Title string `json:"title"`
+							} `json:"content"`
+						} `json:"nodes"`
+					} `json:"items"`
+				} `json:"projectV2"`
+			} `json:"repositoryOwner"`
+		}
+
+		vars := map[string]interface{}{"owner": owner, "number": number, "after": after}
+		if err := c.graphQL(ctx, projectQuery, vars, &data); err != nil {
+			return nil, fmt.Errorf("failed to fetch project: %w", err)
+		}
+
+		if data.RepositoryOwner == nil || data.RepositoryOwner.ProjectV2 == nil {
+			return nil, fmt.Errorf("project %d of %s not found", number, owner)
+		}
+		p := data.RepositoryOwner.ProjectV2
+
+		project.ID = p.ID
+		project.Title = p.Title
+		if p.Field == nil || p.Field.ID == "" {
+			return nil, fmt.Errorf("project %q has no single select %s field", p.Title, statusField)
+		}
+		project.StatusFieldID = p.Field.ID
+		for _, option := range p.Field.Options {
+			project.StatusOptions[option.Name] = option.ID
+		}
+
+		for _, node := range p.Items.Nodes {
+			if node.Content != nil && node.Content.Title != "" {
+				project.Items[node.Content.Title] = node.ID
+			}
+		}
+
+		if !p.Items.PageInfo.HasNextPage {
+			return project, nil
+		}
+		after = &p.Items.PageInfo.EndCursor
+	}
+}
+
+// AddDraftIssue adds a draft issue to a project and returns its item ID
+func (c *Client) AddDraftIssue(ctx context.Context, projectID, title, body string) (string, error) {
+	const mutation = `mutation($project: ID!, $title: String!, $body: String) {
+  addProjectV2DraftIssue(input: {projectId: $project, title: $title, body: $body}) {
+    projectItem { id }
+  }
+}`
+
+	var data struct {
+		AddProjectV2DraftIssue struct {
+			ProjectItem struct {
+				ID string `json:"id"`
+			} `json:"projectItem"`
+		} `json:"addProjectV2DraftIssue"`
+	}
+
+	vars := map[string]interface{}{"project": projectID, "title": title, "body": body}
+	if err := c.graphQL(ctx, mutation, vars, &data); err != nil {
+		return "", fmt.Errorf("failed to add draft issue: %w", err)
+	}
+	return data.AddProjectV2DraftIssue.ProjectItem.ID, nil
+}
+
+// SetStatus moves a project item to the Status column with optionID
+func (c *Client) SetStatus(ctx context.Context, project *Project, itemID, optionID string) error {
+	const mutation = `mutation($project: ID!, $item: ID!, $field: ID!, $option: String!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {singleSelectOptionId: $option}}) {
+    projectV2Item { id }
+  }
+}`
+
+	vars := map[string]interface{}{
+		"project": project.ID,
+		"item":    itemID,
+		"field":   project.StatusFieldID,
+		"option":  optionID,
+	}
+	if err := c.graphQL(ctx, mutation, vars, nil); err != nil {
+		return fmt.Errorf("failed to set item status: %w", err)
+	}
+	return nil
+}
+
+// graphQL runs a GraphQL query and decodes its data into out
+func (c *Client) graphQL(ctx context.Context, query string, vars map[string]interface{}, out interface{}) error {
+	payload, err := json.Marshal(map[string]interface{}{"query": query, "variables": vars})
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", graphQLURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call GitHub API: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("GitHub API error: %s", strings.Join(messages, "; "))
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
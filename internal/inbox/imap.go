@@ -0,0 +1,250 @@
+package inbox
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// imapTimeout bounds each IMAP command
+const imapTimeout = 30 * time.Second
+
+// imapLookback is how far back the first poll of a mailbox looks
+const imapLookback = 7 * 24 * time.Hour
+
+// maxFetch is how many messages one poll fetches at most
+const maxFetch = 50
+
+// IMAPConfig is the mailbox polled for confirmation emails
+type IMAPConfig struct {
+	Server   string // host or host:port, 993 by default
+	Username string
+	Password string // an app password with most providers
+	Mailbox  string // INBOX by default
+}
+
+// IMAPState remembers the messages already seen in a mailbox. Messages are
+// never marked as read; the highest UID seen is kept instead.
+type IMAPState struct {
+	UIDValidity uint32 `json:"uid_validity"`
+	LastUID     uint32 `json:"last_uid"`
+}
+
+// Poll fetches the messages that arrived in the mailbox since state was
+// saved, or in the last week on the first poll, and returns them with the
+// state to save
+func Poll(ctx context.Context, cfg IMAPConfig, state IMAPState) ([][]byte, IMAPState, error) {
+	client, err := dialIMAP(ctx, cfg.Server)
+	if err != nil {
+		return nil, state, err
+	}
+	defer client.close()
+
+	if _, err := client.command("LOGIN %s %s", imapQuote(cfg.Username), imapQuote(cfg.Password)); err != nil {
+		return nil, state, fmt.Errorf("IMAP login failed: %w", err)
+	}
+
+	mailbox := cfg.Mailbox
+	if mailbox == "" {
+		mailbox = "INBOX"
+	}
+	responses, err := client.command("EXAMINE %s", imapQuote(mailbox))
+	if err != nil {
+		return nil, state, fmt.Errorf("failed to open mailbox %s: %w", mailbox, err)
+	}
+	validity := uidValidity(responses)
+
+	// A new UIDVALIDITY means UIDs were reassigned; start over
+	criteria := "UID " + strconv.FormatUint(uint64(state.LastUID)+1, 10) + ":*"
+	if state.UIDValidity != validity || state.LastUID == 0 {
+		state = IMAPState{UIDValidity: validity}
+		criteria = "SINCE " + time.Now().Add(-imapLookback).Format("2-Jan-2006")
+	}
+
+	responses, err = client.command("UID SEARCH %s", criteria)
+	if err != nil {
+		return nil, state, fmt.Errorf("failed to search mailbox: %w", err)
+	}
+
+	var uids []uint32
+	for _, response := range responses {
+		fields := strings.Fields(response.line)
+		if len(fields) < 2 || !strings.EqualFold(fields[1], "SEARCH") {
+			continue
+		}
+		for _, field := range fields[2:] {
+			// UID n:* always matches the last message, even if seen
+			if uid, err := strconv.ParseUint(field, 10, 32); err == nil && uint32(uid) > state.LastUID {
+				uids = append(uids, uint32(uid))
+			}
+		}
+	}
+	if len(uids) > maxFetch {
+		uids = uids[len(uids)-maxFetch:]
+	}
+
+	var messages [][]byte
+	for _, uid := range uids {
+		responses, err := client.command("UID FETCH %d (BODY.PEEK[])", uid)
+		if err != nil {
+			return messages, state, fmt.Errorf("failed to fetch message %d: %w", uid, err)
+		}
+		for _, response := range responses {
+			if len(response.literals) > 0 {
+				messages = append(messages, response.literals[0])
+			}
+		}
+		state.LastUID = max(state.LastUID, uid)
+	}
+
+	if _, err := client.command("LOGOUT"); err != nil {
+		log.Debug().Err(err).Msg("IMAP logout failed")
+	}
+	return messages, state, nil
+}
+
+// imapClient is a minimal IMAP4rev1 client over TLS, enough to read a
+// mailbox
+type imapClient struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	tag    int
+}
+
+// imapResponse is an untagged server response with the literals it carries
+type imapResponse struct {
+	line     string
+	literals [][]byte
+}
+
+// dialIMAP connects to an IMAP server over TLS and reads its greeting
+func dialIMAP(ctx context.Context, server string) (*imapClient, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "993")
+	}
+
+	dialer := &tls.Dialer{NetDialer: &net.Dialer{Timeout: imapTimeout}}
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", server, err)
+	}
+
+	client := &imapClient{conn: conn, reader: bufio.NewReader(conn)}
+	if err := conn.SetDeadline(time.Now().Add(imapTimeout)); err != nil {
+		client.close()
+		return nil, fmt.Errorf("failed to connect to %s: %w", server, err)
+	}
+	greeting, err := client.readLine()
+	if err != nil || !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+		client.close()
+		return nil, fmt.Errorf("unexpected IMAP greeting from %s: %q", server, greeting)
+	}
+	return client, nil
+}
+
+// command sends a command and returns the untagged responses, failing
+// unless the server completes it with OK
+func (c *imapClient) command(format string, args ...interface{}) ([]imapResponse, error) {
+	c.tag++
+	tag := fmt.Sprintf("a%d", c.tag)
+
+	if err := c.conn.SetDeadline(time.Now().Add(imapTimeout)); err != nil {
+		return nil, err
+	}
+	if _, err := fmt.Fprintf(c.conn, "%s %s\r\n", tag, fmt.Sprintf(format, args...)); err != nil {
+		return nil, fmt.Errorf("failed to send IMAP command: %w", err)
+	}
+
+	var responses []imapResponse
+	for {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+
+		// A line ending in {n} announces a literal of n bytes, after which
+		// the response line continues
+		response := imapResponse{line: line}
+		for {
+			size, ok := literalSize(line)
+			if !ok {
+				break
+			}
+			literal := make([]byte, size)
+			if _, err := io.ReadFull(c.reader, literal); err != nil {
+				return nil, fmt.Errorf("failed to read IMAP literal: %w", err)
+			}
+			response.literals = append(response.literals, literal)
+
+			if line, err = c.readLine(); err != nil {
+				return nil, err
+			}
+			response.line += " " + line
+		}
+
+		if status, ok := strings.CutPrefix(response.line, tag+" "); ok {
+			if !strings.HasPrefix(strings.ToUpper(status), "OK") {
+				return nil, fmt.Errorf("%s", status)
+			}
+			return responses, nil
+		}
+		responses = append(responses, response)
+	}
+}
+
+// readLine reads a response line without its CRLF
+func (c *imapClient) readLine() (string, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read IMAP response: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// close closes the connection
+func (c *imapClient) close() {
+	if err := c.conn.Close(); err != nil {
+		log.Debug().Err(err).Msg("Failed to close IMAP connection")
+	}
+}
+
+// literalSize returns the size of the literal a response line announces
+func literalSize(line string) (int, bool) {
+	if !strings.HasSuffix(line, "}") {
+		return 0, false
+	}
+	start := strings.LastIndexByte(line, '{')
+	if start < 0 {
+		return 0, false
+	}
+	size, err := strconv.Atoi(line[start+1 : len(line)-1])
+	return size, err == nil && size >= 0
+}
+
+// uidValidity returns the UIDVALIDITY reported when opening a mailbox
+func uidValidity(responses []imapResponse) uint32 {
+	for _, response := range responses {
+		_, rest, ok := strings.Cut(strings.ToUpper(response.line), "[UIDVALIDITY ")
+		if !ok {
+			continue
+		}
+		value, _, _ := strings.Cut(rest, "]")
+		if validity, err := strconv.ParseUint(value, 10, 32); err == nil {
+			return uint32(validity)
+		}
+	}
+	return 0
+}
+
+// imapQuote quotes a string argument
+func imapQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,248 @@
+// Package inbox reads directory confirmation emails, such as "your listing
+// was approved", to update submission statuses automatically
+package inbox
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// maxMessageSize is how much of a message is read
+const maxMessageSize = 10 << 20
+
+// Message is the part of an email needed to recognize a confirmation
+type Message struct {
+	From    string // sender address
+	Subject string
+	Date    time.Time
+	Text    string // plain text body, or the text of the HTML body
+
+	// Raw is the message as received, for keeping it as evidence
+	Raw []byte
+}
+
+// Parse reads an RFC 5322 message, such as an .eml file
+func Parse(r io.Reader) (*Message, error) {
+	raw, err := io.ReadAll(io.LimitReader(r, maxMessageSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read message: %w", err)
+	}
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse message: %w", err)
+	}
+
+	decoder := new(mime.WordDecoder)
+	message := &Message{Raw: raw}
+	if from, err := mail.ParseAddress(msg.Header.Get("From")); err == nil {
+		message.From = strings.ToLower(from.Address)
+	}
+	if message.Subject, err = decoder.DecodeHeader(msg.Header.Get("Subject")); err != nil {
+		message.Subject = msg.Header.Get("Subject")
+	}
+	if date, err := msg.Header.Date(); err == nil {
+		message.Date = date
+	}
+
+	plain, htmlText, err := bodyText(msg.Header.Get("Content-Type"), msg.Header.Get("Content-Transfer-Encoding"), msg.Body)
+	if err != nil {
+		return nil, err
+	}
+	message.Text = plain
+	if strings.TrimSpace(plain) == "" {
+		message.Text = htmlToText(htmlText)
+	}
+
+	return message, nil
+}
+
+// bodyText returns the plain text and HTML parts of a message body
+func bodyText(contentType, encoding string, body io.Reader) (plain, htmlText string, err error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = "text/plain"
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return plain, htmlText, fmt.Errorf("failed to read message part: %w", err)
+			}
+
+			p, h, err := bodyText(part.Header.Get("Content-Type"), part.Header.Get("Content-Transfer-Encoding"), part)
+			if err != nil {
+				return plain, htmlText, err
+			}
+			plain += p
+			htmlText += h
+		}
+		return plain, htmlText, nil
+	}
+
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, &newlineStripper{r: body})
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode message body: %w", err)
+	}
+
+	switch mediaType {
+	case "text/plain":
+		return string(data), "", nil
+	case "text/html":
+		return "", string(data), nil
+	}
+	return "", "", nil
+}
+
+// newlineStripper drops line breaks, which the base64 decoder rejects
+type newlineStripper struct {
+	r io.Reader
+}
+
+func (n *newlineStripper) Read(p []byte) (int, error) {
+	count, err := n.r.Read(p)
+	kept := 0
+	for _, b := range p[:count] {
+		if b != '\r' && b != '\n' {
+			p[kept] = b
+			kept++
+		}
+	}
+	return kept, err
+}
+
+var (
+	blockPattern  = regexp.MustCompile(`(?is)<(script|style)\b.*?</(script|style)>`)
+	hrefPattern   = regexp.MustCompile(`(?is)<a\b[^>]*?href\s*=\s*["']([^"']+)["'][^>]*>`)
+	tagPattern    = regexp.MustCompile(`(?s)<[^>]*>`)
+	spacePattern  = regexp.MustCompile(`[ \t]+`)
+	linkPattern   = regexp.MustCompile(`https?://[^\s<>"')\]]+`)
+	phraseCleaner = strings.NewReplacer("’", "'")
+)
+
+// htmlToText returns the text of an HTML body, keeping link targets so
+// listing URLs can be found
+func htmlToText(body string) string {
+	body = blockPattern.ReplaceAllString(body, " ")
+	body = hrefPattern.ReplaceAllString(body, " $1 ")
+	body = tagPattern.ReplaceAllString(body, " ")
+	return strings.TrimSpace(spacePattern.ReplaceAllString(html.UnescapeString(body), " "))
+}
+
+// statusPhrases recognize the status a confirmation email announces. They
+// are checked in order, so negative phrases come first.
+var statusPhrases = []struct {
+	status  string
+	phrases []string
+}{
+	{"rejected", []string{
+		"not approved", "not been approved", "rejected", "declined", "unable to approve",
+		"unable to accept", "not a good fit", "can't accept", "cannot accept", "won't be listed", "will not be listed",
+	}},
+	{"approved", []string{
+		"approved", "is now live", "is live", "went live", "has been published", "is now listed",
+		"has been listed", "now featured", "has been accepted", "is now available on", "successfully listed",
+	}},
+	{"submitted", []string{
+		"received your submission", "thanks for submitting", "thank you for submitting", "thanks for your submission",
+		"thank you for your submission", "submission received", "under review", "pending review", "in the queue",
+		"in our queue", "we will review", "we'll review", "has been submitted", "successfully submitted",
+	}},
+}
+
+// Status returns the submission status a confirmation email announces, or
+// "" when it doesn't look like a confirmation
+func Status(msg *Message) string {
+	text := strings.ToLower(strings.Join(strings.Fields(phraseCleaner.Replace(msg.Subject+" "+msg.Text)), " "))
+	for _, group := range statusPhrases {
+		for _, phrase := range group.phrases {
+			if strings.Contains(text, phrase) {
+				return group.status
+			}
+		}
+	}
+	return ""
+}
+
+// MatchDirectory returns the directory a confirmation email comes from: the
+// one whose domain sent it or, failing that, whose name is in the subject
+func MatchDirectory(msg *Message, directories []models.Directory) *models.Directory {
+	_, sender, _ := strings.Cut(msg.From, "@")
+	subject := strings.ToLower(msg.Subject)
+
+	var byName *models.Directory
+	for i, dir := range directories {
+		for _, link := range []string{dir.URL, dir.SubmissionURL} {
+			if domain := siteDomain(link); domain != "" && sender != "" &&
+				(sender == domain || strings.HasSuffix(sender, "."+domain)) {
+				return &directories[i]
+			}
+		}
+
+		// Shorter names match too many unrelated subjects
+		if byName == nil && len(dir.Name) >= 4 {
+			pattern := `\b` + regexp.QuoteMeta(strings.ToLower(dir.Name)) + `\b`
+			if matched, _ := regexp.MatchString(pattern, subject); matched {
+				byName = &directories[i]
+			}
+		}
+	}
+	return byName
+}
+
+// ListingURL returns the first link in the email pointing to the directory's
+// site other than its homepage, which in approval emails is usually the new
+// listing
+func ListingURL(msg *Message, dir *models.Directory) string {
+	domain := siteDomain(dir.URL)
+	if domain == "" {
+		return ""
+	}
+
+	for _, link := range linkPattern.FindAllString(msg.Text, -1) {
+		link = strings.TrimRight(link, ".,;:!")
+		parsed, err := url.Parse(link)
+		if err != nil || siteDomain(link) != domain {
+			continue
+		}
+		if path := strings.Trim(parsed.Path, "/"); path == "" || link == dir.SubmissionURL ||
+			strings.Contains(path, "unsubscribe") || strings.Contains(path, "login") {
+			continue
+		}
+		return link
+	}
+	return ""
+}
+
+// siteDomain returns the host of a URL without a www. prefix
+func siteDomain(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(parsed.Hostname()), "www.")
+}
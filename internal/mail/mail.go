@@ -0,0 +1,289 @@
+// Package mail sends digests, alerts and reports by email through an SMTP
+// server.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// smtpTimeout bounds the whole exchange with the server
+const smtpTimeout = 30 * time.Second
+
+// Config is the SMTP server mail is sent through
+type Config struct {
+	Server   string // host or host:port; 587 with STARTTLS by default, 465 for implicit TLS
+	Username string
+	Password string // an app password with most providers
+	From     string // the username by default
+}
+
+// Configured reports whether a server is set
+func (c Config) Configured() bool {
+	return c.Server != ""
+}
+
+// Attachment is a file attached to a message
+type Attachment struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Message is an email to send
+type Message struct {
+	To          []string
+	Subject     string
+	Text        string
+	Attachments []Attachment
+}
+
+// ParseAddress checks an email address, returning it without any display
+// name
+func ParseAddress(address string) (string, error) {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return "", fmt.Errorf("invalid email address %q: %w", address, err)
+	}
+	return parsed.Address, nil
+}
+
+// Send sends msg through the SMTP server of cfg
+func Send(ctx context.Context, cfg Config, msg Message) error {
+	if !cfg.Configured() {
+		return fmt.Errorf("no SMTP server configured: set smtp.server in config.yaml or SMTP_SERVER")
+	}
+	if len(msg.To) == 0 {
+		return fmt.Errorf("no recipient")
+	}
+
+	from := cfg.From
+	if from == "" {
+		from = cfg.Username
+	}
+	sender, err := ParseAddress(from)
+	if err != nil {
+		return fmt.Errorf("invalid sender (set smtp.from): %w", err)
+	}
+	var recipients []string
+	for _, to := range msg.To {
+		address, err := ParseAddress(to)
+		if err != nil {
+			return err
+		}
+		recipients = append(recipients, address)
+	}
+
+	body, err := compose(from, msg)
+	if err != nil {
+		return err
+	}
+
+	client, host, err := dialSMTP(ctx, cfg.Server)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			log.Debug().Err(err).Msg("Failed to close SMTP connection")
+		}
+	}()
+
+	if cfg.Username != "" {
+		// PlainAuth refuses to send the password over an unencrypted
+		// connection to another host
+		if err := client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, host)); err != nil {
+			return fmt.Errorf("SMTP login failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(sender); err != nil {
+		return fmt.Errorf("SMTP server refused the sender: %w", err)
+	}
+	for _, recipient := range recipients {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("SMTP server refused %s: %w", recipient, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	log.Debug().Str("to", strings.Join(recipients, ", ")).Str("subject", msg.Subject).Msg("Email sent")
+	return client.Quit()
+}
+
+// dialSMTP connects to server, with implicit TLS on port 465 and STARTTLS
+// when the server offers it otherwise
+func dialSMTP(ctx context.Context, server string) (*smtp.Client, string, error) {
+	host, port, err := net.SplitHostPort(server)
+	if err != nil {
+		host, port = server, "587"
+	}
+	address := net.JoinHostPort(host, port)
+
+	ctx, cancel := context.WithTimeout(ctx, smtpTimeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	var conn net.Conn
+	if port == "465" {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host}}).DialContext(ctx, "tcp", address)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		_ = conn.Close()
+		return nil, "", fmt.Errorf("failed to start SMTP session with %s: %w", address, err)
+	}
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			_ = client.Close()
+			return nil, "", fmt.Errorf("failed to start TLS with %s: %w", address, err)
+		}
+	}
+
+	return client, host, nil
+}
+
+// compose builds the message: plain text, or multipart with attachments
+func compose(from string, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	header := textproto.MIMEHeader{}
+	header.Set("From", from)
+	header.Set("To", strings.Join(msg.To, ", "))
+	header.Set("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header.Set("Date", time.Now().Format(time.RFC1123Z))
+	header.Set("Message-ID", messageID(from))
+	header.Set("MIME-Version", "1.0")
+
+	if len(msg.Attachments) == 0 {
+		header.Set("Content-Type", "text/plain; charset=utf-8")
+		header.Set("Content-Transfer-Encoding", "quoted-printable")
+		writeHeader(&buf, header)
+		if err := writeQuotedPrintable(&buf, msg.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	parts := multipart.NewWriter(&buf)
+	header.Set("Content-Type", "multipart/mixed; boundary="+parts.Boundary())
+	writeHeader(&buf, header)
+
+	text, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to compose email: %w", err)
+	}
+	if err := writeQuotedPrintable(text, msg.Text); err != nil {
+		return nil, err
+	}
+
+	for _, attachment := range msg.Attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		part, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {contentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Name})},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to compose email: %w", err)
+		}
+		if err := writeBase64(part, attachment.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compose email: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeHeader(buf *bytes.Buffer, header textproto.MIMEHeader) {
+	for _, key := range []string{"From", "To", "Subject", "Date", "Message-ID", "MIME-Version", "Content-Type", "Content-Transfer-Encoding"} {
+		if value := header.Get(key); value != "" {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+	buf.WriteString("\r\n")
+}
+
+func writeQuotedPrintable(w io.Writer, text string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(text, "\n", "\r\n"))); err != nil {
+		return fmt.Errorf("failed to compose email: %w", err)
+	}
+	if err := qp.Close(); err != nil {
+		return fmt.Errorf("failed to compose email: %w", err)
+	}
+	return nil
+}
+
+// writeBase64 writes data base64-encoded in lines of 76 characters
+func writeBase64(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 0 {
+		line := encoded[:min(76, len(encoded))]
+		encoded = encoded[len(line):]
+		if _, err := w.Write([]byte(line + "\r\n")); err != nil {
+			return fmt.Errorf("failed to compose email: %w", err)
+		}
+	}
+	return nil
+}
+
+// messageID returns a unique Message-ID on the domain of the sender
+func messageID(from string) string {
+	domain := "localhost"
+	if address, err := mail.ParseAddress(from); err == nil {
+		if _, d, ok := strings.Cut(address.Address, "@"); ok {
+			domain = d
+		}
+	}
+
+	random := make([]byte, 12)
+	_, _ = rand.Read(random)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(random), domain)
+}
@@ -0,0 +1,339 @@
+package plan
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// Item is a directory considered for a submission plan
+type Item struct {
+	Directory models.Directory
+	Cost      float64
+	Score     float64
+}
+
+// Plan is an optimized selection of directories
+type Plan struct {
+	Items     []Item
+	TotalCost float64
+	Score     float64
+	Currency  string
+}
+
+// scoreFields are the directory metrics usable in a score
+var scoreFields = map[string]func(models.Directory) float64{
+	"dr":       func(d models.Directory) float64 { return float64(d.DomainRating) },
+	"helpful":  func(d models.Directory) float64 { return float64(d.HelpfulCount) },
+	"traffic":  func(d models.Directory) float64 { return float64(d.OrganicTraffic) },
+	"keywords": func(d models.Directory) float64 { return float64(d.OrganicKeywords) },
+	"views":    func(d models.Directory) float64 { return float64(d.ViewCount) },
+}
+
+// Scorer computes the value of including a directory in a plan
+type Scorer func(models.Directory) float64
+
+// ParseScore parses a score definition: a metric name such as "dr", or a
+// weighted sum such as "dr=1,helpful=0.5"
+func ParseScore(spec string) (Scorer, error) {
+	type term struct {
+		field  func(models.Directory) float64
+		weight float64
+	}
+
+	var terms []term
+	for _, part := range strings.Split(spec, ",") {
+		name, weightStr, hasWeight := strings.Cut(strings.TrimSpace(part), "=")
+
+		field, ok := scoreFields[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown score metric: %s (use %s)", name, strings.Join(ScoreMetrics(), ", "))
+		}
+
+		weight := 1.0
+		if hasWeight {
+			w, err := strconv.ParseFloat(weightStr, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid weight for %s: %s", name, weightStr)
+			}
+			weight = w
+		}
+
+		terms = append(terms, term{field: field, weight: weight})
+	}
+
+	return func(d models.Directory) float64 {
+		var score float64
+		for _, t := range terms {
+			score += t.weight * t.field(d)
+		}
+		return score
+	}, nil
+}
+
+// difficultyWeights scale the score of directories by how hard their
+// submission form is to fill in
+var difficultyWeights = map[string]float64{
+	"easy":   1,
+	"medium": 0.8,
+	"hard":   0.5,
+}
+
+// Difficulties orders the known difficulty ratings, easiest first
+var Difficulties = []string{"easy", "medium", "hard"}
+
+// WithDifficulty scales score by the difficulty rating of each directory,
+// keyed by slug, so easier submissions are preferred. Directories without a
+// rating keep their score.
+func WithDifficulty(score Scorer, difficulties map[string]string) Scorer {
+	if len(difficulties) == 0 {
+		return score
+	}
+	return func(d models.Directory) float64 {
+		if weight, ok := difficultyWeights[difficulties[d.Slug]]; ok {
+			return weight * score(d)
+		}
+		return score(d)
+	}
+}
+
+// ScoreMetrics returns the metric names usable in a score
+func ScoreMetrics() []string {
+	names := make([]string, 0, len(scoreFields))
+	for name := range scoreFields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Cost returns the listing fee of a directory and whether it is known. Free
+// directories and free tiers cost nothing.
+func Cost(dir models.Directory) (float64, bool) {
+	if dir.PriceAmount > 0 {
+		return dir.PriceAmount, true
+	}
+	if strings.EqualFold(dir.Pricing, "paid") {
+		return 0, false
+	}
+	return 0, true
+}
+
+// Build selects the directories maximizing the total score with a total cost
+// within budget and at most maxCount directories. A budget or maxCount of 0
+// means no limit. Paid directories with an unknown price, or priced in another
+// currency than currency, are skipped when a budget is set.
+func Build(directories []models.Directory, score Scorer, budget float64, maxCount int, currency string) *Plan {
+	var items []Item
+	for _, dir := range directories {
+		cost, known := Cost(dir)
+		if budget > 0 {
+			if !known || cost > budget {
+				continue
+			}
+			if cost > 0 && currency != "" && !strings.EqualFold(dir.PriceCurrency, currency) {
+				continue
+			}
+		}
+		items = append(items, Item{Directory: dir, Cost: cost, Score: math.Max(score(dir), 0)})
+	}
+
+	var selected []Item
+	if budget > 0 {
+		selected = knapsack(items, budget, maxCount)
+	} else {
+		selected = topN(items, maxCount)
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].Score > selected[j].Score })
+
+	p := &Plan{Items: selected, Currency: currency}
+	for _, item := range selected {
+		p.TotalCost += item.Cost
+		p.Score += item.Score
+	}
+	return p
+}
+
+// topN returns the n highest scoring items, or all items when n is 0
+func topN(items []Item, n int) []Item {
+	sorted := append([]Item(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Score > sorted[j].Score })
+	if n > 0 && len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted
+}
+
+// knapsackMaxCells bounds the table knapsack fills, items × budget steps ×
+// count slots, so budgets in currencies with small units, such as JPY or
+// INR, don't take gigabytes and minutes to plan
+const knapsackMaxCells = 50_000_000
+
+// knapsackMinSteps is the coarsest budget resolution knapsack is worth
+// using at; with fewer steps the selection is made greedily
+const knapsackMinSteps = 100
+
+// knapsack solves the 0/1 knapsack problem, optionally limiting the number
+// of items. Costs are rounded up to whole currency units so the result never
+// exceeds the budget. Budgets too large to solve in whole units are solved
+// in coarser steps, with costs rounded up to them, and what is left of the
+// budget then filled with the best items still fitting.
+func knapsack(items []Item, budget float64, maxCount int) []Item {
+	capacity := int64(math.Floor(budget))
+
+	// Without a count limit a single count slot is enough
+	limited := maxCount > 0 && maxCount < len(items)
+	slots := 1
+	if limited {
+		slots = maxCount + 1
+	}
+
+	// Costs sharing a factor, such as prices in hundreds of yen, are
+	// solved in steps of it at no loss
+	costs := make([]int64, len(items))
+	var unit int64
+	for i, item := range items {
+		costs[i] = int64(math.Ceil(item.Cost))
+		unit = gcd(unit, costs[i])
+	}
+	if unit == 0 {
+		unit = 1
+	}
+
+	exact := true
+	if maxSteps := int64(knapsackMaxCells / (max(len(items), 1) * slots)); capacity/unit > maxSteps {
+		if maxSteps < knapsackMinSteps {
+			return fill(items, costs, make([]bool, len(items)), capacity, maxCount, byValue)
+		}
+		unit = (capacity + maxSteps - 1) / maxSteps
+		exact = false
+	}
+
+	steps := int(capacity / unit)
+	stepCosts := make([]int, len(items))
+	for i, cost := range costs {
+		stepCosts[i] = int((cost + unit - 1) / unit)
+	}
+
+	// best[b*slots+k] is the best score within budget b using at most k items
+	best := make([]float64, (steps+1)*slots)
+
+	// taken[i] records, as a bitset, the (b, k) cells item i improved
+	taken := make([][]uint64, len(items))
+
+	for i, item := range items {
+		taken[i] = make([]uint64, (len(best)+63)/64)
+		for b := steps; b >= stepCosts[i]; b-- {
+			for k := slots - 1; k >= 0; k-- {
+				prev := k
+				if limited {
+					if k == 0 {
+						break
+					}
+					prev = k - 1
+				}
+
+				cell := b*slots + k
+				if candidate := best[(b-stepCosts[i])*slots+prev] + item.Score; candidate > best[cell] {
+					best[cell] = candidate
+					taken[i][cell/64] |= 1 << (cell % 64)
+				}
+			}
+		}
+	}
+
+	// Walk back through the decisions to recover the selection
+	chosen := make([]bool, len(items))
+	b, k := steps, slots-1
+	for i := len(items) - 1; i >= 0; i-- {
+		cell := b*slots + k
+		if taken[i][cell/64]&(1<<(cell%64)) != 0 {
+			chosen[i] = true
+			b -= stepCosts[i]
+			if limited {
+				k--
+			}
+		}
+	}
+
+	if !exact {
+		return fill(items, costs, chosen, capacity, maxCount, byScore)
+	}
+	var selected []Item
+	for i := len(items) - 1; i >= 0; i-- {
+		if chosen[i] {
+			selected = append(selected, items[i])
+		}
+	}
+	return selected
+}
+
+// fillOrder is the order fill adds items in
+type fillOrder int
+
+const (
+	byScore fillOrder = iota
+	byValue
+)
+
+// fill adds to the chosen items those still fitting within capacity and
+// maxCount, best first: by score, or by value for the cost, free items
+// first. It returns the chosen items.
+func fill(items []Item, costs []int64, chosen []bool, capacity int64, maxCount int, order fillOrder) []Item {
+	var spent int64
+	count := 0
+	var rest []int
+	for i := range items {
+		if chosen[i] {
+			spent += costs[i]
+			count++
+		} else {
+			rest = append(rest, i)
+		}
+	}
+
+	value := func(i int) float64 {
+		if order == byScore || costs[i] == 0 {
+			return items[i].Score
+		}
+		return items[i].Score / float64(costs[i])
+	}
+	sort.SliceStable(rest, func(a, b int) bool {
+		if order == byValue && (costs[rest[a]] == 0) != (costs[rest[b]] == 0) {
+			return costs[rest[a]] == 0
+		}
+		return value(rest[a]) > value(rest[b])
+	})
+
+	for _, i := range rest {
+		if maxCount > 0 && count >= maxCount {
+			break
+		}
+		if spent+costs[i] <= capacity {
+			chosen[i] = true
+			spent += costs[i]
+			count++
+		}
+	}
+
+	var selected []Item
+	for i, item := range items {
+		if chosen[i] {
+			selected = append(selected, item)
+		}
+	}
+	return selected
+}
+
+// gcd returns the greatest common divisor of a and b, b when a is 0
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
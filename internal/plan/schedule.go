@@ -0,0 +1,99 @@
+package plan
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/awesome-directories/cli/internal/dates"
+)
+
+// Pacing limits how fast the submissions of a plan are scheduled, so a
+// launch looks natural rather than a burst of listings on one day. A limit
+// of 0 means no limit.
+type Pacing struct {
+	// PerDay and PerWeek cap the submissions scheduled a day and a week
+	PerDay  int
+	PerWeek int
+
+	// CategoryPerWeek caps the submissions a week to directories sharing a
+	// category, spreading same-category directories across weeks
+	CategoryPerWeek int
+
+	// SkipWeekends schedules nothing on Saturdays and Sundays
+	SkipWeekends bool
+}
+
+// DefaultPacing is used when a schedule is asked for without pacing rules
+var DefaultPacing = Pacing{PerDay: 5, CategoryPerWeek: 3}
+
+// IsZero reports whether no pacing rule is set
+func (p Pacing) IsZero() bool {
+	return p == Pacing{}
+}
+
+// Scheduled is a plan item with the day to submit it
+type Scheduled struct {
+	Item
+	Date time.Time
+}
+
+// Schedule assigns each item, in order, the earliest day from start that
+// keeps within the pacing rules. Weeks are counted from start, so a plan
+// started on a Thursday has Thursday-to-Wednesday weeks.
+func Schedule(items []Item, pacing Pacing, start time.Time) []Scheduled {
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+
+	perDay := make(map[int]int)
+	perWeek := make(map[int]int)
+	perCategory := make(map[int]map[string]int)
+
+	fits := func(day int, categories []string) bool {
+		if pacing.SkipWeekends && dates.IsWeekend(start.AddDate(0, 0, day)) {
+			return false
+		}
+		if pacing.PerDay > 0 && perDay[day] >= pacing.PerDay {
+			return false
+		}
+		if pacing.PerWeek > 0 && perWeek[day/7] >= pacing.PerWeek {
+			return false
+		}
+		if pacing.CategoryPerWeek > 0 {
+			for _, category := range categories {
+				if perCategory[day/7][category] >= pacing.CategoryPerWeek {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	scheduled := make([]Scheduled, 0, len(items))
+	for _, item := range items {
+		categories := make([]string, len(item.Directory.Categories))
+		for i, category := range item.Directory.Categories {
+			categories[i] = strings.ToLower(category)
+		}
+
+		// Every limit is reset by a later day or week, so a day always fits
+		day := 0
+		for !fits(day, categories) {
+			day++
+		}
+
+		perDay[day]++
+		perWeek[day/7]++
+		if perCategory[day/7] == nil {
+			perCategory[day/7] = make(map[string]int)
+		}
+		for _, category := range categories {
+			perCategory[day/7][category]++
+		}
+
+		scheduled = append(scheduled, Scheduled{Item: item, Date: start.AddDate(0, 0, day)})
+	}
+
+	// Keep the plan order within a day
+	sort.SliceStable(scheduled, func(i, j int) bool { return scheduled[i].Date.Before(scheduled[j].Date) })
+	return scheduled
+}
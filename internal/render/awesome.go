@@ -0,0 +1,151 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+func init() {
+	Register("awesome-md", RendererFunc(AwesomeMarkdown), "awesome")
+}
+
+// awesomeOther is the section of directories without a category
+const awesomeOther = "Other"
+
+// anchorUnsafe matches what GitHub strips from headings to build anchors
+var anchorUnsafe = regexp.MustCompile(`[^\p{L}\p{N} _-]`)
+
+// AwesomeMarkdown renders directories as an awesome-list README: a title
+// with the awesome badge, a table of contents and one section per category,
+// with badges for domain rating and pricing. Each directory is listed under
+// its first category.
+func AwesomeMarkdown(w io.Writer, directories []models.Directory, opts Options) error {
+	title := opts.Title
+	if title == "" {
+		title = "Awesome Directories"
+	}
+
+	sections := make(map[string][]models.Directory)
+	for _, dir := range directories {
+		category := awesomeOther
+		if len(dir.Categories) > 0 && strings.TrimSpace(dir.Categories[0]) != "" {
+			category = strings.TrimSpace(dir.Categories[0])
+		}
+		sections[category] = append(sections[category], dir)
+	}
+
+	categories := make([]string, 0, len(sections))
+	for category := range sections {
+		if category != awesomeOther {
+			categories = append(categories, category)
+		}
+	}
+	sort.Strings(categories)
+	if _, ok := sections[awesomeOther]; ok {
+		categories = append(categories, awesomeOther)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s [![Awesome](https://awesome.re/badge.svg)](https://awesome.re)\n\n", title)
+	if opts.Description != "" {
+		fmt.Fprintf(&b, "> %s\n\n", strings.TrimSpace(opts.Description))
+	}
+
+	b.WriteString("## Contents\n\n")
+	for _, category := range categories {
+		fmt.Fprintf(&b, "- [%s](#%s)\n", category, headingAnchor(category))
+	}
+
+	for _, category := range categories {
+		fmt.Fprintf(&b, "\n## %s\n\n", category)
+		for _, dir := range sections[category] {
+			b.WriteString(awesomeEntry(dir, opts))
+			b.WriteString("\n")
+		}
+	}
+
+	b.WriteString("\n---\n\nGenerated with [awesome-directories](https://awesome-directories.com).\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write awesome list: %w", err)
+	}
+	for range directories {
+		opts.row()
+	}
+	return nil
+}
+
+// awesomeEntry returns the list item of a directory
+func awesomeEntry(dir models.Directory, opts Options) string {
+	entry := fmt.Sprintf("- [%s](%s)", markdownText(dir.Name), dir.URL)
+
+	if !opts.missing("domain_rating") && dir.DomainRating > 0 {
+		entry += " " + shieldsBadge("DR", fmt.Sprintf("%d", dir.DomainRating), drColor(dir.DomainRating))
+	}
+	if !opts.missing("pricing") && dir.Pricing != "" {
+		entry += " " + shieldsBadge("pricing", dir.Pricing, pricingColor(dir.Pricing))
+	}
+
+	if description := strings.Join(strings.Fields(dir.Description), " "); description != "" {
+		// awesome-lint expects descriptions to end with punctuation
+		if !strings.ContainsAny(description[len(description)-1:], ".!?") {
+			description += "."
+		}
+		entry += " - " + markdownText(description)
+	}
+	return entry
+}
+
+// shieldsBadge returns the Markdown image of a shields.io static badge
+func shieldsBadge(label, message, color string) string {
+	escape := func(s string) string {
+		s = strings.ReplaceAll(s, "-", "--")
+		s = strings.ReplaceAll(s, "_", "__")
+		return url.PathEscape(s)
+	}
+	return fmt.Sprintf("![%s: %s](https://img.shields.io/badge/%s-%s-%s)", label, message, escape(label), escape(message), color)
+}
+
+// drColor returns the badge color of a domain rating
+func drColor(dr int) string {
+	switch {
+	case dr >= 70:
+		return "brightgreen"
+	case dr >= 40:
+		return "yellow"
+	default:
+		return "lightgrey"
+	}
+}
+
+// pricingColor returns the badge color of a pricing model
+func pricingColor(pricing string) string {
+	switch strings.ToLower(pricing) {
+	case "free":
+		return "brightgreen"
+	case "freemium":
+		return "blue"
+	case "paid":
+		return "orange"
+	default:
+		return "lightgrey"
+	}
+}
+
+// headingAnchor returns the anchor GitHub gives a Markdown heading
+func headingAnchor(heading string) string {
+	anchor := anchorUnsafe.ReplaceAllString(strings.ToLower(heading), "")
+	return strings.ReplaceAll(anchor, " ", "-")
+}
+
+// markdownText escapes characters that would end link text or start
+// formatting
+func markdownText(s string) string {
+	return strings.NewReplacer(`[`, `\[`, `]`, `\]`, `*`, `\*`, `_`, `\_`).Replace(s)
+}
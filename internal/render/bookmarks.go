@@ -0,0 +1,90 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// bookmarksRootFolder is the folder holding the exported bookmarks
+const bookmarksRootFolder = "Awesome Directories"
+
+// uncategorizedFolder holds directories without a category
+const uncategorizedFolder = "Uncategorized"
+
+func init() {
+	Register("bookmarks", RendererFunc(Bookmarks), "html")
+}
+
+// Bookmarks renders directories as a Netscape bookmarks file, importable into
+// Chrome and Firefox, with a folder per category linking to each directory's
+// submission page
+func Bookmarks(w io.Writer, directories []models.Directory, opts Options) error {
+	now := time.Now().Unix()
+
+	// Group by category
+	folders := make(map[string][]models.Directory)
+	for _, dir := range directories {
+		if len(dir.Categories) == 0 {
+			folders[uncategorizedFolder] = append(folders[uncategorizedFolder], dir)
+			continue
+		}
+		for _, cat := range dir.Categories {
+			folders[cat] = append(folders[cat], dir)
+		}
+	}
+
+	names := make([]string, 0, len(folders))
+	for name := range folders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE NETSCAPE-Bookmark-file-1>\n")
+	b.WriteString("<META HTTP-EQUIV=\"Content-Type\" CONTENT=\"text/html; charset=UTF-8\">\n")
+	b.WriteString("<TITLE>Bookmarks</TITLE>\n")
+	b.WriteString("<H1>Bookmarks</H1>\n")
+	b.WriteString("<DL><p>\n")
+	fmt.Fprintf(&b, "    <DT><H3 ADD_DATE=\"%d\">%s</H3>\n", now, bookmarksRootFolder)
+	b.WriteString("    <DL><p>\n")
+
+	written := make(map[string]bool, len(directories))
+	for _, name := range names {
+		fmt.Fprintf(&b, "        <DT><H3 ADD_DATE=\"%d\">%s</H3>\n", now, html.EscapeString(name))
+		b.WriteString("        <DL><p>\n")
+
+		for _, dir := range folders[name] {
+			link := dir.SubmissionURL
+			if link == "" {
+				link = dir.URL
+			}
+			fmt.Fprintf(&b, "            <DT><A HREF=\"%s\" ADD_DATE=\"%d\">%s</A>\n",
+				html.EscapeString(link), now, html.EscapeString(dir.Name))
+			if dir.Description != "" {
+				fmt.Fprintf(&b, "            <DD>%s\n", html.EscapeString(dir.Description))
+			}
+
+			if !written[dir.ID] {
+				written[dir.ID] = true
+				opts.row()
+			}
+		}
+
+		b.WriteString("        </DL><p>\n")
+	}
+
+	b.WriteString("    </DL><p>\n")
+	b.WriteString("</DL><p>\n")
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write bookmarks: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,379 @@
+package render
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/goccy/go-json"
+	"gopkg.in/yaml.v3"
+
+	"github.com/awesome-directories/cli/internal/ui"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// column is a column of a tabular format
+type column struct {
+	header string
+	// field is the directory field the column shows, "" when the column
+	// doesn't depend on a single field
+	field string
+	value func(dir models.Directory) string
+}
+
+// csvColumns are the columns written by the CSV format
+var csvColumns = []column{
+	{"Name", "name", func(d models.Directory) string { return d.Name }},
+	{"URL", "url", func(d models.Directory) string { return d.URL }},
+	{"Description", "description", func(d models.Directory) string { return d.Description }},
+	{"Categories", "categories", func(d models.Directory) string { return strings.Join(d.Categories, ", ") }},
+	{"Pricing", "pricing", func(d models.Directory) string { return d.Pricing }},
+	{"Price", "price_amount", func(d models.Directory) string { return formatAmount(d.PriceAmount) }},
+	{"Currency", "price_currency", func(d models.Directory) string { return d.PriceCurrency }},
+	{"Link Type", "link_type", func(d models.Directory) string { return d.LinkType }},
+	{"Domain Rating", "domain_rating", func(d models.Directory) string { return strconv.Itoa(d.DomainRating) }},
+	{"Organic Traffic", "organic_traffic", func(d models.Directory) string { return strconv.Itoa(d.OrganicTraffic) }},
+	{"Organic Keywords", "organic_keywords", func(d models.Directory) string { return strconv.Itoa(d.OrganicKeywords) }},
+	{"Helpful Votes", "helpful_count", func(d models.Directory) string { return strconv.Itoa(d.HelpfulCount) }},
+	{"Submission URL", "submission_url", func(d models.Directory) string { return d.SubmissionURL }},
+}
+
+// tableColumns are the columns written by the table format
+var tableColumns = []column{
+	{"Name", "name", func(d models.Directory) string { return ui.TruncateString(d.Name, 40) }},
+	{"DR", "domain_rating", func(d models.Directory) string { return ui.FormatDR(&d.DomainRating) }},
+	{"Category", "categories", func(d models.Directory) string {
+		category := strings.Join(d.Categories, ", ")
+		if len(category) > 30 {
+			category = ui.TruncateString(category, 30)
+		}
+		return category
+	}},
+	{"Pricing", "pricing", func(d models.Directory) string { return ui.FormatPricing(d.Pricing) }},
+	{"Price", "price_amount", func(d models.Directory) string { return ui.FormatPrice(d.PriceAmount, d.PriceCurrency) }},
+	{"Link", "link_type", func(d models.Directory) string { return ui.FormatLinkType(d.LinkType) }},
+	{"Votes", "helpful_count", func(d models.Directory) string { return strconv.Itoa(d.HelpfulCount) }},
+}
+
+// CSVColumns is the header row written by the CSV format
+var CSVColumns = headers(csvColumns)
+
+// headers returns the headers of columns
+func headers(columns []column) []string {
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = col.header
+	}
+	return names
+}
+
+// visibleColumns returns the columns whose field the backend provides
+func visibleColumns(columns []column, opts Options) []column {
+	visible := make([]column, 0, len(columns))
+	for _, col := range columns {
+		if !opts.missing(col.field) {
+			visible = append(visible, col)
+		}
+	}
+	return visible
+}
+
+// rowValues returns the cells of a directory's row
+func rowValues(columns []column, dir models.Directory) []string {
+	values := make([]string, len(columns))
+	for i, col := range columns {
+		values[i] = col.value(dir)
+	}
+	return values
+}
+
+func init() {
+	Register("table", RendererFunc(Table))
+	Register("json", RendererFunc(JSON))
+	Register("yaml", RendererFunc(YAML), "yml")
+	Register("csv", RendererFunc(CSV))
+	Register("markdown", RendererFunc(Markdown), "md")
+	Register("template", RendererFunc(Template), "tpl")
+}
+
+// Table renders directories as an aligned terminal table
+func Table(w io.Writer, directories []models.Directory, opts Options) error {
+	columns := visibleColumns(tableColumns, opts)
+	if opts.Badges != nil && slices.ContainsFunc(directories, func(d models.Directory) bool { return len(opts.Badges(d)) > 0 }) {
+		columns = append(columns, column{"Badges", "", func(d models.Directory) string {
+			return strings.Join(opts.Badges(d), " ")
+		}})
+	}
+	table := ui.New(nil, w, io.Discard).CreateTable(headers(columns))
+
+	for _, dir := range directories {
+		table.Row(rowValues(columns, dir)...)
+		opts.row()
+	}
+
+	_, err := fmt.Fprintln(w, table)
+	return err
+}
+
+// JSON renders directories as an indented JSON array. Elements are written
+// one at a time so large exports are streamed.
+func JSON(w io.Writer, directories []models.Directory, opts Options) error {
+	if len(directories) == 0 {
+		_, err := fmt.Fprintln(w, "[]")
+		return err
+	}
+
+	if _, err := fmt.Fprint(w, "[\n  "); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+
+	if opts.CanonicalJSON {
+		directories = canonicalize(directories)
+	}
+
+	for i, dir := range directories {
+		data, err := marshalDirectory(dir, opts.CanonicalJSON)
+		if err != nil {
+			return fmt.Errorf("failed to marshal directory: %w", err)
+		}
+
+		if i > 0 {
+			if _, err := fmt.Fprint(w, ",\n  "); err != nil {
+				return fmt.Errorf("failed to write JSON: %w", err)
+			}
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("failed to write JSON: %w", err)
+		}
+		opts.row()
+	}
+
+	if _, err := fmt.Fprint(w, "\n]\n"); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+
+	return nil
+}
+
+// marshalDirectory marshals an element of the JSON array, with its keys
+// sorted when canonical
+func marshalDirectory(dir models.Directory, canonical bool) ([]byte, error) {
+	if !canonical {
+		return json.MarshalIndent(dir, "  ", "  ")
+	}
+
+	data, err := json.Marshal(dir)
+	if err != nil {
+		return nil, err
+	}
+	// Maps are marshaled with their keys sorted
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(fields, "  ", "  ")
+}
+
+// canonicalize returns a copy of directories ordered by slug, with their
+// lists sorted and timestamps in UTC to the second, so exports of the same
+// catalog are identical whatever order it was fetched in
+func canonicalize(directories []models.Directory) []models.Directory {
+	sorted := make([]models.Directory, len(directories))
+	for i, dir := range directories {
+		dir.Categories = sortedCopy(dir.Categories)
+		dir.Countries = sortedCopy(dir.Countries)
+		dir.Languages = sortedCopy(dir.Languages)
+		dir.CreatedAt = dir.CreatedAt.UTC().Truncate(time.Second)
+		dir.UpdatedAt = dir.UpdatedAt.UTC().Truncate(time.Second)
+		sorted[i] = dir
+	}
+	slices.SortStableFunc(sorted, func(a, b models.Directory) int {
+		if c := strings.Compare(a.Slug, b.Slug); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return sorted
+}
+
+// sortedCopy returns a sorted copy of values, keeping nil as is
+func sortedCopy(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	values = slices.Clone(values)
+	slices.Sort(values)
+	return values
+}
+
+// YAML renders directories as a YAML sequence
+func YAML(w io.Writer, directories []models.Directory, opts Options) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(directories); err != nil {
+		return fmt.Errorf("failed to write YAML: %w", err)
+	}
+
+	for range directories {
+		opts.row()
+	}
+
+	return encoder.Close()
+}
+
+// CSV renders directories as CSV with a header row
+func CSV(w io.Writer, directories []models.Directory, opts Options) error {
+	writer := csv.NewWriter(w)
+
+	columns := visibleColumns(csvColumns, opts)
+
+	// Write header
+	if err := writer.Write(headers(columns)); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	// Write rows
+	for _, dir := range directories {
+		row := rowValues(columns, dir)
+
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+		opts.row()
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// formatAmount formats a price for machine-readable output, empty when unknown
+func formatAmount(amount float64) string {
+	if amount <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+// Markdown renders directories as a Markdown document grouped by category
+func Markdown(w io.Writer, directories []models.Directory, opts Options) error {
+	if _, err := fmt.Fprintf(w, "# Awesome Directories Export\n\n"); err != nil {
+		return fmt.Errorf("failed to write header: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "Total directories: %d\n\n", len(directories)); err != nil {
+		return fmt.Errorf("failed to write content: %w", err)
+	}
+	if _, err := fmt.Fprintf(w, "---\n\n"); err != nil {
+		return fmt.Errorf("failed to write separator: %w", err)
+	}
+
+	// Group by category, in the order categories first appear so the
+	// output is the same on every run
+	var categories []string
+	categoryMap := make(map[string][]models.Directory)
+	for _, dir := range directories {
+		for _, cat := range dir.Categories {
+			if _, ok := categoryMap[cat]; !ok {
+				categories = append(categories, cat)
+			}
+			categoryMap[cat] = append(categoryMap[cat], dir)
+		}
+	}
+
+	// Write by category
+	written := make(map[string]bool, len(directories))
+	for _, category := range categories {
+		dirs := categoryMap[category]
+		if _, err := fmt.Fprintf(w, "## %s\n\n", category); err != nil {
+			return fmt.Errorf("failed to write category: %w", err)
+		}
+
+		for _, dir := range dirs {
+			if _, err := fmt.Fprintf(w, "### [%s](%s)\n\n", dir.Name, dir.URL); err != nil {
+				return fmt.Errorf("failed to write directory name: %w", err)
+			}
+			if _, err := fmt.Fprintf(w, "%s\n\n", dir.Description); err != nil {
+				return fmt.Errorf("failed to write description: %w", err)
+			}
+
+			if !opts.missing("pricing") {
+				if _, err := fmt.Fprintf(w, "- **Pricing:** %s\n", dir.Pricing); err != nil {
+					return fmt.Errorf("failed to write pricing: %w", err)
+				}
+			}
+			if dir.PriceAmount > 0 {
+				if _, err := fmt.Fprintf(w, "- **Price:** %s\n", ui.FormatPrice(dir.PriceAmount, dir.PriceCurrency)); err != nil {
+					return fmt.Errorf("failed to write price: %w", err)
+				}
+			}
+			if !opts.missing("link_type") {
+				if _, err := fmt.Fprintf(w, "- **Link Type:** %s\n", dir.LinkType); err != nil {
+					return fmt.Errorf("failed to write link type: %w", err)
+				}
+			}
+
+			if dir.DomainRating > 0 {
+				if _, err := fmt.Fprintf(w, "- **Domain Rating:** %d\n", dir.DomainRating); err != nil {
+					return fmt.Errorf("failed to write domain rating: %w", err)
+				}
+			}
+
+			if dir.HelpfulCount > 0 {
+				if _, err := fmt.Fprintf(w, "- **Helpful Votes:** %d\n", dir.HelpfulCount); err != nil {
+					return fmt.Errorf("failed to write helpful votes: %w", err)
+				}
+			}
+
+			if dir.SubmissionURL != "" {
+				if _, err := fmt.Fprintf(w, "- **Submission URL:** %s\n", dir.SubmissionURL); err != nil {
+					return fmt.Errorf("failed to write submission URL: %w", err)
+				}
+			}
+
+			if _, err := fmt.Fprintf(w, "\n"); err != nil {
+				return fmt.Errorf("failed to write newline: %w", err)
+			}
+
+			if !written[dir.ID] {
+				written[dir.ID] = true
+				opts.row()
+			}
+		}
+	}
+
+	return nil
+}
+
+// Template renders each directory with a user-supplied text/template
+func Template(w io.Writer, directories []models.Directory, opts Options) error {
+	if opts.Template == "" {
+		return fmt.Errorf("template format requires a template (use --template)")
+	}
+
+	text := opts.Template
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	tmpl, err := template.New("directory").Funcs(template.FuncMap{
+		"join": strings.Join,
+	}).Parse(text)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+
+	for _, dir := range directories {
+		if err := tmpl.Execute(w, dir); err != nil {
+			return fmt.Errorf("failed to execute template: %w", err)
+		}
+		opts.row()
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/goccy/go-json"
+
+	"github.com/awesome-directories/cli/internal/api"
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+func init() {
+	Register("alfred", RendererFunc(Alfred))
+	Register("raycast", RendererFunc(Raycast))
+}
+
+// alfredItem is a result of an Alfred Script Filter
+type alfredItem struct {
+	UID          string                   `json:"uid"`
+	Title        string                   `json:"title"`
+	Subtitle     string                   `json:"subtitle"`
+	Arg          string                   `json:"arg"`
+	Autocomplete string                   `json:"autocomplete"`
+	QuicklookURL string                   `json:"quicklookurl"`
+	Text         map[string]string        `json:"text"`
+	Mods         map[string]alfredModItem `json:"mods,omitempty"`
+}
+
+// alfredModItem is what an Alfred result does with a modifier key held
+type alfredModItem struct {
+	Arg      string `json:"arg"`
+	Subtitle string `json:"subtitle"`
+}
+
+// Alfred renders directories as Alfred Script Filter JSON. Actioning a
+// result opens the directory; with ⌘ held, its submission page.
+func Alfred(w io.Writer, directories []models.Directory, opts Options) error {
+	items := make([]alfredItem, 0, len(directories))
+	for _, dir := range directories {
+		item := alfredItem{
+			UID:          dir.Slug,
+			Title:        dir.Name,
+			Subtitle:     launcherSubtitle(dir),
+			Arg:          dir.URL,
+			Autocomplete: dir.Name,
+			QuicklookURL: dir.URL,
+			Text:         map[string]string{"copy": dir.URL, "largetype": dir.Name},
+		}
+		if dir.SubmissionURL != "" {
+			item.Mods = map[string]alfredModItem{
+				"cmd": {Arg: dir.SubmissionURL, Subtitle: "Open the submission page"},
+			}
+		}
+		items = append(items, item)
+		opts.row()
+	}
+
+	return writeLauncherJSON(w, map[string]interface{}{"items": items})
+}
+
+// raycastItem is a result of a Raycast script filter: a list item with an
+// icon, accessories shown on the right and the URLs its actions open
+type raycastItem struct {
+	ID            string              `json:"id"`
+	Title         string              `json:"title"`
+	Subtitle      string              `json:"subtitle"`
+	Arg           string              `json:"arg"`
+	Icon          string              `json:"icon,omitempty"`
+	Accessories   []map[string]string `json:"accessories"`
+	URL           string              `json:"url"`
+	SubmissionURL string              `json:"submissionUrl,omitempty"`
+}
+
+// Raycast renders directories as the items of a Raycast list, with their
+// site icons
+func Raycast(w io.Writer, directories []models.Directory, opts Options) error {
+	items := make([]raycastItem, 0, len(directories))
+	for _, dir := range directories {
+		icon, _ := api.LogoURL(dir.URL)
+		items = append(items, raycastItem{
+			ID:            dir.Slug,
+			Title:         dir.Name,
+			Subtitle:      strings.Join(dir.Categories, ", "),
+			Arg:           dir.URL,
+			Icon:          icon,
+			Accessories:   []map[string]string{{"text": fmt.Sprintf("DR %d", dir.DomainRating)}, {"text": dir.Pricing}},
+			URL:           dir.URL,
+			SubmissionURL: dir.SubmissionURL,
+		})
+		opts.row()
+	}
+
+	return writeLauncherJSON(w, map[string]interface{}{"items": items})
+}
+
+// launcherSubtitle summarizes a directory on one line
+func launcherSubtitle(dir models.Directory) string {
+	parts := []string{fmt.Sprintf("DR %d", dir.DomainRating)}
+	for _, part := range []string{dir.Pricing, dir.LinkType, strings.Join(dir.Categories, ", ")} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// writeLauncherJSON writes a launcher result document
+func writeLauncherJSON(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal results: %w", err)
+	}
+	if _, err := fmt.Fprintln(w, string(data)); err != nil {
+		return fmt.Errorf("failed to write results: %w", err)
+	}
+	return nil
+}
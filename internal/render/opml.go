@@ -0,0 +1,81 @@
+package render
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+func init() {
+	Register("opml", RendererFunc(OPML))
+}
+
+// opmlDocument is an OPML 2.0 subscription list
+type opmlDocument struct {
+	XMLName xml.Name      `xml:"opml"`
+	Version string        `xml:"version,attr"`
+	Title   string        `xml:"head>title"`
+	Created string        `xml:"head>dateCreated"`
+	Body    []opmlOutline `xml:"body>outline"`
+}
+
+// opmlOutline is a folder, or a feed when XMLURL is set
+type opmlOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Outlines []opmlOutline `xml:"outline"`
+}
+
+// OPML renders the blog and changelog feeds of directories as an OPML file,
+// importable into RSS readers, with a folder per category. Directories
+// without a feed are left out.
+func OPML(w io.Writer, directories []models.Directory, opts Options) error {
+	folders := make(map[string][]opmlOutline)
+	for _, dir := range directories {
+		if dir.FeedURL != "" {
+			feed := opmlOutline{Text: dir.Name, Title: dir.Name, Type: "rss", XMLURL: dir.FeedURL, HTMLURL: dir.URL}
+
+			categories := dir.Categories
+			if len(categories) == 0 {
+				categories = []string{uncategorizedFolder}
+			}
+			// Readers merge duplicate subscriptions, so list the feed under
+			// its first category only
+			folders[categories[0]] = append(folders[categories[0]], feed)
+		}
+		opts.row()
+	}
+
+	names := make([]string, 0, len(folders))
+	for name := range folders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	doc := opmlDocument{
+		Version: "2.0",
+		Title:   bookmarksRootFolder,
+		Created: time.Now().UTC().Format(time.RFC1123Z),
+	}
+	for _, name := range names {
+		doc.Body = append(doc.Body, opmlOutline{Text: name, Title: name, Outlines: folders[name]})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("failed to write OPML: %w", err)
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to write OPML: %w", err)
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,166 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// Options holds format-specific rendering options
+type Options struct {
+	// Template is the text/template used by the template format
+	Template string
+
+	// OnRow, when set, is called after each directory has been written
+	OnRow func()
+
+	// Title and Description head document formats, such as awesome-md
+	Title       string
+	Description string
+
+	// Missing are directory fields the backend doesn't provide. Tabular
+	// formats leave out their columns rather than showing empty values.
+	Missing []string
+
+	// Badges, when set, labels directories in an extra table column
+	Badges func(dir models.Directory) []string
+
+	// CanonicalJSON makes JSON output diff-friendly: directories ordered by
+	// slug, keys and lists sorted and timestamps in UTC to the second
+	CanonicalJSON bool
+}
+
+// missing reports whether the backend doesn't provide a field
+func (o Options) missing(field string) bool {
+	return field != "" && slices.Contains(o.Missing, field)
+}
+
+// row calls the OnRow hook if one is set
+func (o Options) row() {
+	if o.OnRow != nil {
+		o.OnRow()
+	}
+}
+
+// Renderer renders directories in an output format
+type Renderer interface {
+	Render(w io.Writer, directories []models.Directory, opts Options) error
+}
+
+// RendererFunc adapts a function to the Renderer interface
+type RendererFunc func(w io.Writer, directories []models.Directory, opts Options) error
+
+// Render calls f(w, directories, opts)
+func (f RendererFunc) Render(w io.Writer, directories []models.Directory, opts Options) error {
+	return f(w, directories, opts)
+}
+
+var (
+	mu        sync.RWMutex
+	renderers = make(map[string]Renderer)
+	aliases   = make(map[string]string)
+)
+
+// Register makes a renderer available under name and any aliases
+func Register(name string, r Renderer, alias ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	renderers[name] = r
+	for _, a := range alias {
+		aliases[a] = name
+	}
+}
+
+// Get returns the renderer registered under name or one of its aliases
+func Get(name string) (Renderer, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	name = strings.ToLower(name)
+	if canonical, ok := aliases[name]; ok {
+		name = canonical
+	}
+
+	r, ok := renderers[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported format: %s (use %s)", name, strings.Join(namesLocked(), ", "))
+	}
+
+	return r, nil
+}
+
+// Canonical returns the name a format is registered under, resolving
+// aliases such as md for markdown
+func Canonical(name string) (string, error) {
+	if _, err := Get(name); err != nil {
+		return "", err
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	name = strings.ToLower(name)
+	if canonical, ok := aliases[name]; ok {
+		return canonical, nil
+	}
+	return name, nil
+}
+
+// Names returns the names of all registered renderers
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	return namesLocked()
+}
+
+func namesLocked() []string {
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Columns returns the columns or fields written by a format, or nil when the
+// output is free-form
+func Columns(format string) []string {
+	mu.RLock()
+	name := strings.ToLower(format)
+	if canonical, ok := aliases[name]; ok {
+		name = canonical
+	}
+	mu.RUnlock()
+
+	switch name {
+	case "csv":
+		return CSVColumns
+	case "table":
+		return headers(tableColumns)
+	case "json", "yaml":
+		return directoryFields()
+	default:
+		return nil
+	}
+}
+
+// directoryFields returns the serialized field names of a directory
+func directoryFields() []string {
+	t := reflect.TypeOf(models.Directory{})
+	fields := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,133 @@
+package sample
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strings"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// Stratum is the group of directories sharing a stratification key, and how
+// many of them were sampled
+type Stratum struct {
+	Key       string
+	Size      int
+	Allocated int
+}
+
+// Strata maps a directory to the stratum it belongs to
+type Strata func(models.Directory) string
+
+// strataKeys are the supported --stratify-by values
+var strataKeys = map[string]Strata{
+	"dr-band":   DRBand,
+	"pricing":   func(d models.Directory) string { return valueOr(d.Pricing, "unknown") },
+	"link-type": func(d models.Directory) string { return valueOr(d.LinkType, "unknown") },
+	"category": func(d models.Directory) string {
+		if len(d.Categories) == 0 {
+			return "uncategorized"
+		}
+		return d.Categories[0]
+	},
+}
+
+// ParseStrata returns the stratification for a --stratify-by value. An empty
+// value puts all directories in a single stratum.
+func ParseStrata(name string) (Strata, error) {
+	if name == "" {
+		return func(models.Directory) string { return "all" }, nil
+	}
+
+	strata, ok := strataKeys[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown stratification: %s (use %s)", name, strings.Join(StrataNames(), ", "))
+	}
+	return strata, nil
+}
+
+// StrataNames returns the supported stratification names
+func StrataNames() []string {
+	names := make([]string, 0, len(strataKeys))
+	for name := range strataKeys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DRBand returns the domain rating band of a directory, such as "DR 50-69"
+func DRBand(d models.Directory) string {
+	switch {
+	case d.DomainRating <= 0:
+		return "DR unknown"
+	case d.DomainRating < 30:
+		return "DR 1-29"
+	case d.DomainRating < 50:
+		return "DR 30-49"
+	case d.DomainRating < 70:
+		return "DR 50-69"
+	default:
+		return "DR 70+"
+	}
+}
+
+// valueOr returns value, or fallback when value is empty
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// Stratified draws count directories at random, allocating the sample to
+// strata in proportion to their size (largest remainder method) so each
+// group is represented. The same seed always yields the same sample.
+func Stratified(directories []models.Directory, count int, strata Strata, seed uint64) ([]models.Directory, []Stratum) {
+	groups := make(map[string][]models.Directory)
+	for _, dir := range directories {
+		key := strata(dir)
+		groups[key] = append(groups[key], dir)
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	count = min(count, len(directories))
+	summary := make([]Stratum, len(keys))
+	remainders := make([]float64, len(keys))
+	allocated := 0
+	for i, key := range keys {
+		exact := float64(count) * float64(len(groups[key])) / float64(len(directories))
+		summary[i] = Stratum{Key: key, Size: len(groups[key]), Allocated: int(exact)}
+		remainders[i] = exact - float64(int(exact))
+		allocated += summary[i].Allocated
+	}
+
+	// Hand out the rest to the strata with the largest remainders
+	order := make([]int, len(keys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for i := 0; allocated < count; i = (i + 1) % len(order) {
+		if s := &summary[order[i]]; s.Allocated < s.Size {
+			s.Allocated++
+			allocated++
+		}
+	}
+
+	rng := rand.New(rand.NewPCG(seed, seed))
+	var sampled []models.Directory
+	for i, key := range keys {
+		group := append([]models.Directory(nil), groups[key]...)
+		rng.Shuffle(len(group), func(a, b int) { group[a], group[b] = group[b], group[a] })
+		sampled = append(sampled, group[:summary[i].Allocated]...)
+	}
+
+	return sampled, summary
+}
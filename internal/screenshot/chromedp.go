@@ -0,0 +1,52 @@
+//go:build chromedp
+
+package screenshot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Supported reports whether this build can capture screenshots
+const Supported = true
+
+// Capture loads each of urls in one headless browser session and returns a
+// PNG screenshot of each, in order
+func Capture(ctx context.Context, opts Options, urls ...string) ([][]byte, error) {
+	allocOptions := append(chromedp.DefaultExecAllocatorOptions[:], chromedp.WindowSize(opts.Width, opts.Height))
+	if opts.BrowserPath != "" {
+		allocOptions = append(allocOptions, chromedp.ExecPath(opts.BrowserPath))
+	}
+
+	allocCtx, cancelAlloc := chromedp.NewExecAllocator(ctx, allocOptions...)
+	defer cancelAlloc()
+	browserCtx, cancelBrowser := chromedp.NewContext(allocCtx)
+	defer cancelBrowser()
+
+	// Start the browser outside the per-page timeouts
+	if err := chromedp.Run(browserCtx); err != nil {
+		return nil, fmt.Errorf("failed to start browser: %w", err)
+	}
+
+	shots := make([][]byte, len(urls))
+	for i, pageURL := range urls {
+		var capture chromedp.Action
+		if opts.FullPage {
+			// Quality 100 keeps the PNG encoding
+			capture = chromedp.FullScreenshot(&shots[i], 100)
+		} else {
+			capture = chromedp.CaptureScreenshot(&shots[i])
+		}
+
+		pageCtx, cancel := context.WithTimeout(browserCtx, opts.Timeout)
+		err := chromedp.Run(pageCtx, chromedp.Navigate(pageURL), chromedp.Sleep(opts.Wait), capture)
+		cancel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to capture %s: %w", pageURL, err)
+		}
+	}
+
+	return shots, nil
+}
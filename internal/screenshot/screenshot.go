@@ -0,0 +1,41 @@
+// Package screenshot captures pages with a headless browser. Browser support
+// is optional: build with -tags chromedp to include it.
+package screenshot
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUnsupported is returned by Capture in builds without browser support
+var ErrUnsupported = errors.New("screenshots need a build with browser support: go build -tags chromedp ./cmd/awesome-directories")
+
+// Options controls how pages are captured
+type Options struct {
+	Width  int
+	Height int
+
+	// FullPage captures the whole page instead of the viewport
+	FullPage bool
+
+	// Wait is how long to let a page settle after it loaded, for content
+	// rendered by JavaScript
+	Wait time.Duration
+
+	// Timeout limits the capture of each page
+	Timeout time.Duration
+
+	// BrowserPath is the Chrome or Chromium executable, found on the PATH
+	// when empty
+	BrowserPath string
+}
+
+// DefaultOptions returns the options used unless flags override them
+func DefaultOptions() Options {
+	return Options{
+		Width:   1280,
+		Height:  800,
+		Wait:    2 * time.Second,
+		Timeout: 45 * time.Second,
+	}
+}
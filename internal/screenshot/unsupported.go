@@ -0,0 +1,14 @@
+//go:build !chromedp
+
+package screenshot
+
+import "context"
+
+// Supported reports whether this build can capture screenshots
+const Supported = false
+
+// Capture returns ErrUnsupported; browser support is included with -tags
+// chromedp
+func Capture(ctx context.Context, opts Options, urls ...string) ([][]byte, error) {
+	return nil, ErrUnsupported
+}
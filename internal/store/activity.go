@@ -0,0 +1,25 @@
+package store
+
+import (
+	"time"
+)
+
+const activityFile = "activity.jsonl"
+
+// Activity records work done on a directory submission
+type Activity struct {
+	Time      time.Time `json:"time"`
+	Directory string    `json:"directory"`
+	Product   string    `json:"product,omitempty"`
+	Action    string    `json:"action"`
+	Detail    string    `json:"detail,omitempty"`
+}
+
+// LogActivity appends an entry to the activity log
+func (s *Store) LogActivity(activity Activity) error {
+	if activity.Time.IsZero() {
+		activity.Time = time.Now().UTC()
+	}
+
+	return s.appendJSONL(activityFile, activity)
+}
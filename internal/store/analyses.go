@@ -0,0 +1,58 @@
+package store
+
+import "time"
+
+const analysesFile = "analyses.json"
+
+// Analysis is the stored result of analyzing the submission form of a
+// directory
+type Analysis struct {
+	URL              string    `json:"url"`
+	Difficulty       string    `json:"difficulty"`
+	RobotsDisallowed bool      `json:"robots_disallowed,omitempty"`
+	LoginWall        bool      `json:"login_wall,omitempty"`
+	Captcha          bool      `json:"captcha,omitempty"`
+	ExternalForm     bool      `json:"external_form,omitempty"`
+	Fields           int       `json:"fields"`
+	RequiredFields   int       `json:"required_fields"`
+	AnalyzedAt       time.Time `json:"analyzed_at"`
+}
+
+// Analyses returns the stored form analyses keyed by directory slug
+func (s *Store) Analyses() (map[string]Analysis, error) {
+	analyses := make(map[string]Analysis)
+	if err := s.readJSON(analysesFile, &analyses); err != nil {
+		return nil, err
+	}
+	return analyses, nil
+}
+
+// SaveAnalyses stores form analyses, replacing earlier analyses of the same
+// directories
+func (s *Store) SaveAnalyses(analyses map[string]Analysis) error {
+	return s.Transaction(func() error {
+		stored, err := s.Analyses()
+		if err != nil {
+			return err
+		}
+
+		for slug, analysis := range analyses {
+			stored[slug] = analysis
+		}
+		return s.writeJSON(analysesFile, stored)
+	})
+}
+
+// Difficulties returns the difficulty rating of each analyzed directory
+func (s *Store) Difficulties() (map[string]string, error) {
+	analyses, err := s.Analyses()
+	if err != nil {
+		return nil, err
+	}
+
+	difficulties := make(map[string]string, len(analyses))
+	for slug, analysis := range analyses {
+		difficulties[slug] = analysis.Difficulty
+	}
+	return difficulties, nil
+}
@@ -0,0 +1,121 @@
+package store
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+)
+
+const auditFile = "audit.jsonl"
+
+// run identifies this invocation of the CLI in the audit log
+var run = strconv.FormatInt(time.Now().UnixNano(), 36)
+
+// AuditEntry records a change to local data
+type AuditEntry struct {
+	Time   time.Time `json:"time"`
+	User   string    `json:"user"`
+	Action string    `json:"action"`
+	Target string    `json:"target,omitempty"`
+	Detail string    `json:"detail,omitempty"`
+
+	// Run groups the entries of one invocation, such as a bulk change
+	Run string `json:"run,omitempty"`
+
+	// Before is the changed record as it was, and Created marks a change
+	// that added the record; either makes the change undoable
+	Before  json.RawMessage `json:"before,omitempty"`
+	Created bool            `json:"created,omitempty"`
+}
+
+// Undoable reports whether the entry holds what it takes to revert it
+func (e AuditEntry) Undoable() bool {
+	return e.Created || len(e.Before) > 0
+}
+
+// Audit appends an entry to the append-only audit log, recording the current
+// OS user
+func (s *Store) Audit(action, target, detail string) error {
+	return s.appendJSONL(auditFile, AuditEntry{
+		Time:   time.Now().UTC(),
+		User:   currentUser(),
+		Action: action,
+		Target: target,
+		Detail: detail,
+		Run:    run,
+	})
+}
+
+// AuditChange appends an entry like Audit, keeping the record as it was
+// before the change so it can be undone. before is nil for a record the
+// change created.
+func (s *Store) AuditChange(action, target, detail string, before interface{}) error {
+	entry := AuditEntry{
+		Time:    time.Now().UTC(),
+		User:    currentUser(),
+		Action:  action,
+		Target:  target,
+		Detail:  detail,
+		Run:     run,
+		Created: before == nil,
+	}
+	if before != nil {
+		data, err := json.Marshal(before)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", target, err)
+		}
+		entry.Before = data
+	}
+	return s.appendJSONL(auditFile, entry)
+}
+
+// AuditLog returns the audit entries recorded since the given time, oldest
+// first
+func (s *Store) AuditLog(since time.Time) ([]AuditEntry, error) {
+	file, err := os.Open(s.path(auditFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close audit log")
+		}
+	}()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if !entry.Time.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	return entries, nil
+}
+
+// currentUser returns the name of the OS user running the CLI
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
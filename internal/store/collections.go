@@ -0,0 +1,96 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+const collectionsFile = "collections.json"
+
+// Collections returns all collections sorted by name
+func (s *Store) Collections() ([]models.Collection, error) {
+	collections, err := s.loadCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.Collection, 0, len(collections))
+	for _, collection := range collections {
+		list = append(list, collection)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list, nil
+}
+
+// Collection returns the collection with the given name
+func (s *Store) Collection(name string) (*models.Collection, error) {
+	collections, err := s.loadCollections()
+	if err != nil {
+		return nil, err
+	}
+
+	collection, ok := collections[name]
+	if !ok {
+		return nil, fmt.Errorf("collection not found: %s", name)
+	}
+	return &collection, nil
+}
+
+// SaveCollection creates or replaces a collection
+func (s *Store) SaveCollection(collection *models.Collection) error {
+	if !slugPattern.MatchString(collection.Name) {
+		return fmt.Errorf("invalid collection name %q: use lowercase letters, digits and '-'", collection.Name)
+	}
+
+	now := time.Now().UTC()
+	if collection.CreatedAt.IsZero() {
+		collection.CreatedAt = now
+	}
+	collection.UpdatedAt = now
+
+	return s.PutCollection(collection)
+}
+
+// PutCollection writes a collection as is, such as one synced from the
+// server
+func (s *Store) PutCollection(collection *models.Collection) error {
+	return s.Transaction(func() error {
+		collections, err := s.loadCollections()
+		if err != nil {
+			return err
+		}
+
+		collections[collection.Name] = *collection
+		return s.writeJSON(collectionsFile, collections)
+	})
+}
+
+// DeleteCollection removes a collection
+func (s *Store) DeleteCollection(name string) error {
+	return s.Transaction(func() error {
+		collections, err := s.loadCollections()
+		if err != nil {
+			return err
+		}
+
+		if _, ok := collections[name]; !ok {
+			return fmt.Errorf("collection not found: %s", name)
+		}
+
+		delete(collections, name)
+		return s.writeJSON(collectionsFile, collections)
+	})
+}
+
+// loadCollections reads all collections keyed by name
+func (s *Store) loadCollections() (map[string]models.Collection, error) {
+	collections := make(map[string]models.Collection)
+	if err := s.readJSON(collectionsFile, &collections); err != nil {
+		return nil, err
+	}
+	return collections, nil
+}
@@ -0,0 +1,165 @@
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// Resolution is how a conflict between the local and remote version of a
+// shared state file is resolved
+type Resolution string
+
+// Supported resolutions. Merge keeps the newer of both versions, and for
+// submissions combines their notes and todos.
+const (
+	ResolveLocal  Resolution = "local"
+	ResolveRemote Resolution = "remote"
+	ResolveMerge  Resolution = "merge"
+)
+
+// ParseResolution parses a --prefer value
+func ParseResolution(value string) (Resolution, error) {
+	switch r := Resolution(strings.ToLower(value)); r {
+	case ResolveLocal, ResolveRemote, ResolveMerge:
+		return r, nil
+	}
+	return "", fmt.Errorf("invalid resolution: %s (use local, remote or merge)", value)
+}
+
+// ResolveConflict returns the content resolving a conflicted shared state
+// file at path, relative to the state dir. A nil side means the file was
+// deleted there; a nil result means the file should be deleted.
+func ResolveConflict(path string, local, remote []byte, resolution Resolution) ([]byte, error) {
+	switch {
+	case resolution == ResolveLocal:
+		return local, nil
+	case resolution == ResolveRemote:
+		return remote, nil
+	case local == nil:
+		return remote, nil
+	case remote == nil:
+		return local, nil
+	}
+
+	switch strings.Split(filepath.ToSlash(path), "/")[0] {
+	case sharedSubmissionsDir:
+		var l, r models.TrackedSubmission
+		if err := unmarshalSides(local, remote, &l, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return marshalYAML(MergeSubmissions(l, r))
+	case sharedProductsDir:
+		var l, r models.Product
+		if err := unmarshalSides(local, remote, &l, &r); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if r.UpdatedAt.After(l.UpdatedAt) {
+			return marshalYAML(r)
+		}
+		return marshalYAML(l)
+	}
+	return nil, fmt.Errorf("cannot merge %s: not a submission or product profile", path)
+}
+
+// MergeSubmissions merges two versions of a submission: fields come from
+// the newer one, notes, todos and evidence from both
+func MergeSubmissions(local, remote models.TrackedSubmission) models.TrackedSubmission {
+	merged, older := local, remote
+	if remote.UpdatedAt.After(local.UpdatedAt) {
+		merged, older = remote, local
+	}
+
+	merged.Notes = mergeNotes(merged.Notes, older.Notes)
+
+	merged.Todos = append([]models.Todo(nil), merged.Todos...)
+	for _, todo := range older.Todos {
+		found := false
+		for i, existing := range merged.Todos {
+			if existing.Text == todo.Text {
+				// A todo done on either side stays done
+				if todo.Done && !existing.Done {
+					merged.Todos[i] = todo
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged.Todos = append(merged.Todos, todo)
+		}
+	}
+
+	merged.Evidence = append([]models.Evidence(nil), merged.Evidence...)
+	for _, evidence := range older.Evidence {
+		found := false
+		for _, existing := range merged.Evidence {
+			if existing.Name() == evidence.Name() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged.Evidence = append(merged.Evidence, evidence)
+		}
+	}
+
+	for tracker, key := range older.Issues {
+		if _, ok := merged.Issues[tracker]; !ok {
+			if merged.Issues == nil {
+				merged.Issues = make(map[string]string)
+			}
+			merged.Issues[tracker] = key
+		}
+	}
+
+	if older.CreatedAt.Before(merged.CreatedAt) {
+		merged.CreatedAt = older.CreatedAt
+	}
+	return merged
+}
+
+// mergeNotes appends the lines of other missing from notes
+func mergeNotes(notes, other string) string {
+	lines := strings.Split(notes, "\n")
+	for _, line := range strings.Split(other, "\n") {
+		missing := true
+		for _, existing := range lines {
+			if existing == line {
+				missing = false
+				break
+			}
+		}
+		if missing {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
+
+// unmarshalSides decodes both versions of a conflicted file
+func unmarshalSides(local, remote []byte, l, r interface{}) error {
+	if err := yaml.Unmarshal(local, l); err != nil {
+		return err
+	}
+	return yaml.Unmarshal(remote, r)
+}
+
+// marshalYAML encodes v the same way records are written to the state dir
+func marshalYAML(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(v); err != nil {
+		return nil, fmt.Errorf("failed to marshal record: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to marshal record: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"sort"
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+const eventsFile = "events.json"
+
+// Events returns the known directory events sorted by directory and title
+func (s *Store) Events() ([]models.DirectoryEvent, error) {
+	var events []models.DirectoryEvent
+	if err := s.readJSON(eventsFile, &events); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if events[i].Directory != events[j].Directory {
+			return events[i].Directory < events[j].Directory
+		}
+		return events[i].Title < events[j].Title
+	})
+	return events, nil
+}
+
+// SaveEvent creates an event or replaces the one of the directory with the
+// same title
+func (s *Store) SaveEvent(event *models.DirectoryEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+
+	return s.Transaction(func() error {
+		events, err := s.Events()
+		if err != nil {
+			return err
+		}
+
+		replaced := false
+		for i, existing := range events {
+			if existing.Directory == event.Directory && existing.Title == event.Title {
+				events[i] = *event
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			events = append(events, *event)
+		}
+		return s.writeJSON(eventsFile, events)
+	})
+}
+
+// DeleteEvents removes the events of a directory with the given title, or
+// all its events when title is empty, returning how many were removed
+func (s *Store) DeleteEvents(directory, title string) (int, error) {
+	removed := 0
+	err := s.Transaction(func() error {
+		events, err := s.Events()
+		if err != nil {
+			return err
+		}
+
+		kept := events[:0]
+		for _, event := range events {
+			if event.Directory == directory && (title == "" || event.Title == title) {
+				removed++
+				continue
+			}
+			kept = append(kept, event)
+		}
+		if removed == 0 {
+			return nil
+		}
+		return s.writeJSON(eventsFile, kept)
+	})
+	return removed, err
+}
@@ -0,0 +1,123 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+// evidenceDir holds the evidence files of submissions, by project and
+// directory, next to the submissions: in the state dir when one is
+// configured, so teammates get the files too
+const evidenceDir = "evidence"
+
+// EvidenceDir returns the directory holding the evidence files of a
+// submission
+func (s *Store) EvidenceDir(project, directory string) string {
+	base := s.dir
+	if s.Shared() {
+		base = s.stateDir
+	}
+	return filepath.Join(base, evidenceDir, project, directory)
+}
+
+// EvidencePath returns the path of an evidence file of a submission
+func (s *Store) EvidencePath(submission *models.TrackedSubmission, evidence models.Evidence) string {
+	return filepath.Join(s.EvidenceDir(submission.Project, submission.Directory), evidence.File)
+}
+
+// StoreEvidence copies the file at src into the evidence dir of a submission
+// and returns its name there. A file of the same name is kept; the copy is
+// numbered instead.
+func (s *Store) StoreEvidence(project, directory, src string) (string, error) {
+	name, err := s.evidenceName(project, directory, filepath.Base(src))
+	if err != nil {
+		return "", err
+	}
+
+	if err := copyFile(src, filepath.Join(s.EvidenceDir(project, directory), name)); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// SaveEvidence writes data as a file named name into the evidence dir of a
+// submission, and returns its name there, numbered like StoreEvidence
+func (s *Store) SaveEvidence(project, directory, name string, data []byte) (string, error) {
+	name, err := s.evidenceName(project, directory, name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.WriteFile(filepath.Join(s.EvidenceDir(project, directory), name), data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write evidence: %w", err)
+	}
+	return name, nil
+}
+
+// evidenceName creates the evidence dir of a submission and returns a name
+// based on base that no file there has yet
+func (s *Store) evidenceName(project, directory, base string) (string, error) {
+	dir := s.EvidenceDir(project, directory)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create evidence directory: %w", err)
+	}
+
+	ext := filepath.Ext(base)
+	name := base
+	for i := 2; ; i++ {
+		if _, err := os.Stat(filepath.Join(dir, name)); os.IsNotExist(err) {
+			return name, nil
+		}
+		name = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(base, ext), i, ext)
+	}
+}
+
+// copyEvidence copies the evidence files of a submission from s to dst
+func (s *Store) copyEvidence(dst *Store, submission *models.TrackedSubmission) error {
+	for _, evidence := range submission.Evidence {
+		if evidence.File == "" {
+			continue
+		}
+		target := dst.EvidencePath(submission, evidence)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("failed to create evidence directory: %w", err)
+		}
+		if err := copyFile(s.EvidencePath(submission, evidence), target); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFile copies the file at src to dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer func() {
+		if err := in.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close evidence file")
+		}
+	}()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", dst, err)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package store
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+)
+
+// lockFile guards read-modify-write cycles across processes, such as a watch
+// daemon and an interactive command updating submissions at the same time
+const lockFile = "store.lock"
+
+// Transaction runs fn holding an exclusive lock on the store, so changes
+// read and written within fn cannot interleave with another process. Store
+// methods called from fn join the transaction.
+func (s *Store) Transaction(fn func() error) error {
+	if s.held > 0 {
+		s.held++
+		defer func() { s.held-- }()
+		return fn()
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	file, err := os.OpenFile(s.path(lockFile), os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open store lock: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close store lock")
+		}
+	}()
+
+	log.Debug().Str("file", file.Name()).Msg("Waiting for store lock")
+	if err := lock(file); err != nil {
+		return fmt.Errorf("failed to lock store: %w", err)
+	}
+	defer func() {
+		if err := unlock(file); err != nil {
+			log.Error().Err(err).Msg("Failed to unlock store")
+		}
+	}()
+
+	s.held++
+	defer func() { s.held-- }()
+	return fn()
+}
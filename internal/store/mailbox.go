@@ -0,0 +1,22 @@
+package store
+
+const mailboxFile = "mailbox.json"
+
+// MailboxState remembers the messages of the confirmation mailbox already
+// read by watch
+type MailboxState struct {
+	UIDValidity uint32 `json:"uid_validity"`
+	LastUID     uint32 `json:"last_uid"`
+}
+
+// MailboxState returns the saved state of the confirmation mailbox
+func (s *Store) MailboxState() (MailboxState, error) {
+	var state MailboxState
+	err := s.readJSON(mailboxFile, &state)
+	return state, err
+}
+
+// SaveMailboxState saves the state of the confirmation mailbox
+func (s *Store) SaveMailboxState(state MailboxState) error {
+	return s.writeJSON(mailboxFile, state)
+}
@@ -0,0 +1,60 @@
+package store
+
+import (
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+const overridesFile = "overrides.json"
+
+// Override replaces catalog fields of a directory locally, until the catalog
+// itself is corrected
+type Override struct {
+	SubmissionURL string    `json:"submission_url,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Overrides returns the local overrides keyed by directory slug
+func (s *Store) Overrides() (map[string]Override, error) {
+	overrides := make(map[string]Override)
+	if err := s.readJSON(overridesFile, &overrides); err != nil {
+		return nil, err
+	}
+	return overrides, nil
+}
+
+// UpdateOverride applies fn to the override of a directory and saves it
+func (s *Store) UpdateOverride(slug string, fn func(*Override)) error {
+	return s.Transaction(func() error {
+		overrides, err := s.Overrides()
+		if err != nil {
+			return err
+		}
+
+		override := overrides[slug]
+		fn(&override)
+		override.UpdatedAt = time.Now().UTC()
+		overrides[slug] = override
+
+		return s.writeJSON(overridesFile, overrides)
+	})
+}
+
+// ApplyOverrides replaces the catalog fields of directories with their local
+// overrides
+func ApplyOverrides(directories []models.Directory, overrides map[string]Override) {
+	if len(overrides) == 0 {
+		return
+	}
+
+	for i := range directories {
+		override, ok := overrides[directories[i].Slug]
+		if !ok {
+			continue
+		}
+		if override.SubmissionURL != "" {
+			directories[i].SubmissionURL = override.SubmissionURL
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+const productsFile = "products.json"
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Products returns all product profiles sorted by slug
+func (s *Store) Products() ([]models.Product, error) {
+	products, err := s.loadProducts()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.Product, 0, len(products))
+	for _, product := range products {
+		list = append(list, product)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Slug < list[j].Slug })
+
+	return list, nil
+}
+
+// Product returns the profile with the given slug
+func (s *Store) Product(slug string) (*models.Product, error) {
+	products, err := s.loadProducts()
+	if err != nil {
+		return nil, err
+	}
+
+	product, ok := products[slug]
+	if !ok {
+		return nil, fmt.Errorf("product not found: %s", slug)
+	}
+	return &product, nil
+}
+
+// SaveProduct creates or replaces a product profile
+func (s *Store) SaveProduct(product *models.Product) error {
+	if !slugPattern.MatchString(product.Slug) {
+		return fmt.Errorf("invalid product slug %q: use lowercase letters, digits and '-'", product.Slug)
+	}
+
+	now := time.Now().UTC()
+	if product.CreatedAt.IsZero() {
+		product.CreatedAt = now
+	}
+	product.UpdatedAt = now
+
+	return s.putProduct(product)
+}
+
+// putProduct writes a product profile as is
+func (s *Store) putProduct(product *models.Product) error {
+	if s.Shared() {
+		return writeYAML(s.sharedProductPath(product.Slug), product)
+	}
+
+	return s.Transaction(func() error {
+		products, err := s.loadProducts()
+		if err != nil {
+			return err
+		}
+
+		products[product.Slug] = *product
+		return s.writeJSON(productsFile, products)
+	})
+}
+
+// DeleteProduct removes a product profile
+func (s *Store) DeleteProduct(slug string) error {
+	return s.Transaction(func() error {
+		products, err := s.loadProducts()
+		if err != nil {
+			return err
+		}
+
+		if _, ok := products[slug]; !ok {
+			return fmt.Errorf("product not found: %s", slug)
+		}
+
+		if s.Shared() {
+			if err := os.Remove(s.sharedProductPath(slug)); err != nil {
+				return fmt.Errorf("failed to delete product: %w", err)
+			}
+			return nil
+		}
+
+		delete(products, slug)
+		return s.writeJSON(productsFile, products)
+	})
+}
+
+// loadProducts reads all product profiles keyed by slug
+func (s *Store) loadProducts() (map[string]models.Product, error) {
+	products := make(map[string]models.Product)
+	if s.Shared() {
+		list, err := readYAMLFiles[models.Product](filepath.Join(s.stateDir, sharedProductsDir))
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range list {
+			products[product.Slug] = product
+		}
+		return products, nil
+	}
+
+	if err := s.readJSON(productsFile, &products); err != nil {
+		return nil, err
+	}
+	return products, nil
+}
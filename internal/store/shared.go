@@ -0,0 +1,137 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Layout of the shared state dir. Each record is a file of its own, so
+// teammates editing different submissions never conflict.
+const (
+	sharedProductsDir    = "products"
+	sharedSubmissionsDir = "submissions"
+)
+
+// Shared reports whether submissions and product profiles are kept in the
+// shared state dir
+func (s *Store) Shared() bool {
+	return s.stateDir != ""
+}
+
+// StateDir returns the shared state dir, empty when not configured
+func (s *Store) StateDir() string {
+	return s.stateDir
+}
+
+// sharedProductPath returns the file of a product profile in the state dir
+func (s *Store) sharedProductPath(slug string) string {
+	return filepath.Join(s.stateDir, sharedProductsDir, slug+".yaml")
+}
+
+// sharedSubmissionPath returns the file of a submission in the state dir
+func (s *Store) sharedSubmissionPath(project, directory string) string {
+	return filepath.Join(s.stateDir, sharedSubmissionsDir, project, directory+".yaml")
+}
+
+// readYAMLFiles decodes every .yaml file below dir, in path order
+func readYAMLFiles[T any](dir string) ([]T, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && strings.HasSuffix(path, ".yaml") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	records := make([]T, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		var record T
+		if err := yaml.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// writeYAML atomically replaces path with v. Fields are written in struct
+// order and map keys sorted, so unchanged records produce identical files.
+func writeYAML(path string, v interface{}) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+
+	data, err := marshalYAML(v)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// CopyTo copies the submissions, with their evidence files, and product
+// profiles of s into dst, keeping their timestamps, and returns how many of each were copied
+func (s *Store) CopyTo(dst *Store) (int, int, error) {
+	products, err := s.Products()
+	if err != nil {
+		return 0, 0, err
+	}
+	for i := range products {
+		if err := dst.putProduct(&products[i]); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	submissions, err := s.Submissions()
+	if err != nil {
+		return 0, 0, err
+	}
+	for i := range submissions {
+		if err := dst.putSubmission(&submissions[i]); err != nil {
+			return 0, 0, err
+		}
+		if err := s.copyEvidence(dst, &submissions[i]); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	return len(products), len(submissions), nil
+}
+
+// Local returns a store reading the data dir only, ignoring the state dir
+func (s *Store) Local() *Store {
+	return &Store{dir: s.dir}
+}
+
+// WithStateDir returns a store keeping submissions and product profiles in
+// stateDir
+func (s *Store) WithStateDir(stateDir string) *Store {
+	return &Store{dir: s.dir, stateDir: stateDir}
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+
+	"github.com/awesome-directories/cli/internal/config"
+)
+
+// Store keeps user-owned data, such as product profiles, in the data dir.
+// Submissions and product profiles live in the shared state dir instead
+// when one is configured. A Store is not safe for concurrent use by
+// multiple goroutines; see Transaction for concurrent processes.
+type Store struct {
+	dir      string
+	stateDir string
+
+	// held counts the nested transactions holding the store lock
+	held int
+}
+
+// New creates a store in the data dir of cfg
+func New(cfg *config.Config) *Store {
+	return &Store{dir: cfg.DataDir, stateDir: cfg.StateDir}
+}
+
+// path returns the path of a store file
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name)
+}
+
+// readJSON decodes a store file into v. A missing file leaves v untouched.
+func (s *Store) readJSON(name string, v interface{}) error {
+	data, err := os.ReadFile(s.path(name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeJSON atomically replaces a store file with v
+func (s *Store) writeJSON(name string, v interface{}) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", name, err)
+	}
+
+	tmp := s.path(name + ".tmp")
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	if err := os.Rename(tmp, s.path(name)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// appendJSONL appends v as a line to a JSON Lines store file
+func (s *Store) appendJSONL(name string, v interface{}) error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s entry: %w", name, err)
+	}
+
+	file, err := os.OpenFile(s.path(name), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Error().Err(err).Str("file", name).Msg("Failed to close store file")
+		}
+	}()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package store
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+const submissionsFile = "submissions.json"
+
+// Submissions returns all tracked submissions sorted by project and directory
+func (s *Store) Submissions() ([]models.TrackedSubmission, error) {
+	submissions, err := s.loadSubmissions()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(submissions, func(i, j int) bool {
+		if submissions[i].Project != submissions[j].Project {
+			return submissions[i].Project < submissions[j].Project
+		}
+		return submissions[i].Directory < submissions[j].Directory
+	})
+
+	return submissions, nil
+}
+
+// Submission returns the submission to a directory tracked in a project, or
+// nil when it is not tracked
+func (s *Store) Submission(project, directory string) (*models.TrackedSubmission, error) {
+	submissions, err := s.Submissions()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, submission := range submissions {
+		if submission.Project == project && submission.Directory == directory {
+			return &submissions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// SaveSubmission creates or replaces the submission to a directory in a
+// project
+func (s *Store) SaveSubmission(submission *models.TrackedSubmission) error {
+	if !slugPattern.MatchString(submission.Project) {
+		return fmt.Errorf("invalid project name %q: use lowercase letters, digits and '-'", submission.Project)
+	}
+
+	now := time.Now().UTC()
+	if submission.CreatedAt.IsZero() {
+		submission.CreatedAt = now
+	}
+	submission.UpdatedAt = now
+
+	return s.putSubmission(submission)
+}
+
+// putSubmission writes a submission as is
+func (s *Store) putSubmission(submission *models.TrackedSubmission) error {
+	if s.Shared() {
+		return writeYAML(s.sharedSubmissionPath(submission.Project, submission.Directory), submission)
+	}
+
+	return s.Transaction(func() error {
+		submissions, err := s.loadSubmissions()
+		if err != nil {
+			return err
+		}
+
+		replaced := false
+		for i, existing := range submissions {
+			if existing.Project == submission.Project && existing.Directory == submission.Directory {
+				submissions[i] = *submission
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			submissions = append(submissions, *submission)
+		}
+
+		return s.writeJSON(submissionsFile, submissions)
+	})
+}
+
+// DeleteSubmission stops tracking the submission to a directory in a project
+func (s *Store) DeleteSubmission(project, directory string) error {
+	if s.Shared() {
+		err := os.Remove(s.sharedSubmissionPath(project, directory))
+		if os.IsNotExist(err) {
+			return fmt.Errorf("submission not found: %s/%s", project, directory)
+		}
+		return err
+	}
+
+	return s.Transaction(func() error {
+		submissions, err := s.loadSubmissions()
+		if err != nil {
+			return err
+		}
+
+		for i, existing := range submissions {
+			if existing.Project == project && existing.Directory == directory {
+				return s.writeJSON(submissionsFile, append(submissions[:i], submissions[i+1:]...))
+			}
+		}
+		return fmt.Errorf("submission not found: %s/%s", project, directory)
+	})
+}
+
+// loadSubmissions reads all tracked submissions in storage order
+func (s *Store) loadSubmissions() ([]models.TrackedSubmission, error) {
+	if s.Shared() {
+		return readYAMLFiles[models.TrackedSubmission](filepath.Join(s.stateDir, sharedSubmissionsDir))
+	}
+
+	var submissions []models.TrackedSubmission
+	if err := s.readJSON(submissionsFile, &submissions); err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+// SubmittedElsewhere returns the submissions of a directory marked submitted
+// or approved in projects other than project
+func SubmittedElsewhere(submissions []models.TrackedSubmission, project, directory string) []models.TrackedSubmission {
+	var found []models.TrackedSubmission
+	for _, submission := range submissions {
+		if submission.Directory == directory && submission.Project != project && submission.IsSubmitted() {
+			found = append(found, submission)
+		}
+	}
+	return found
+}
+
+// Overlaps groups the submissions of directories tracked in more than one
+// project, keyed by directory slug
+func Overlaps(submissions []models.TrackedSubmission) map[string][]models.TrackedSubmission {
+	byDirectory := make(map[string][]models.TrackedSubmission)
+	for _, submission := range submissions {
+		byDirectory[submission.Directory] = append(byDirectory[submission.Directory], submission)
+	}
+
+	for directory, tracked := range byDirectory {
+		if len(tracked) < 2 {
+			delete(byDirectory, directory)
+		}
+	}
+	return byDirectory
+}
@@ -0,0 +1,87 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/awesome-directories/cli/pkg/models"
+)
+
+const subscriptionsFile = "subscriptions.json"
+
+// Subscriptions returns all subscriptions sorted by name
+func (s *Store) Subscriptions() ([]models.Subscription, error) {
+	subscriptions, err := s.loadSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]models.Subscription, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		list = append(list, subscription)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list, nil
+}
+
+// Subscription returns the subscription with the given name
+func (s *Store) Subscription(name string) (*models.Subscription, error) {
+	subscriptions, err := s.loadSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+
+	subscription, ok := subscriptions[name]
+	if !ok {
+		return nil, fmt.Errorf("subscription not found: %s", name)
+	}
+	return &subscription, nil
+}
+
+// SaveSubscription creates or replaces a subscription
+func (s *Store) SaveSubscription(subscription *models.Subscription) error {
+	if !slugPattern.MatchString(subscription.Name) {
+		return fmt.Errorf("invalid subscription name %q: use lowercase letters, digits and '-'", subscription.Name)
+	}
+	if subscription.CreatedAt.IsZero() {
+		subscription.CreatedAt = time.Now().UTC()
+	}
+
+	return s.Transaction(func() error {
+		subscriptions, err := s.loadSubscriptions()
+		if err != nil {
+			return err
+		}
+
+		subscriptions[subscription.Name] = *subscription
+		return s.writeJSON(subscriptionsFile, subscriptions)
+	})
+}
+
+// DeleteSubscription removes a subscription
+func (s *Store) DeleteSubscription(name string) error {
+	return s.Transaction(func() error {
+		subscriptions, err := s.loadSubscriptions()
+		if err != nil {
+			return err
+		}
+
+		if _, ok := subscriptions[name]; !ok {
+			return fmt.Errorf("subscription not found: %s", name)
+		}
+
+		delete(subscriptions, name)
+		return s.writeJSON(subscriptionsFile, subscriptions)
+	})
+}
+
+// loadSubscriptions reads all subscriptions keyed by name
+func (s *Store) loadSubscriptions() (map[string]models.Subscription, error) {
+	subscriptions := make(map[string]models.Subscription)
+	if err := s.readJSON(subscriptionsFile, &subscriptions); err != nil {
+		return nil, err
+	}
+	return subscriptions, nil
+}
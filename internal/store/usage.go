@@ -0,0 +1,66 @@
+package store
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+)
+
+const usageFile = "usage.jsonl"
+
+// UsageEntry records a command run, for insights into one's own use of the
+// CLI. It holds the filters used but not free-text queries, and never
+// leaves the data dir.
+type UsageEntry struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+
+	// Filters are the filter flags set, as name=value
+	Filters []string `json:"filters,omitempty"`
+}
+
+// LogUsage appends an entry to the usage log
+func (s *Store) LogUsage(entry UsageEntry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now().UTC()
+	}
+	return s.appendJSONL(usageFile, entry)
+}
+
+// UsageLog returns the usage entries recorded since the given time, oldest
+// first
+func (s *Store) UsageLog(since time.Time) ([]UsageEntry, error) {
+	file, err := os.Open(s.path(usageFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open usage log: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close usage log")
+		}
+	}()
+
+	var entries []UsageEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry UsageEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if !entry.Time.Before(since) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read usage log: %w", err)
+	}
+
+	return entries, nil
+}
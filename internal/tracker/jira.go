@@ -0,0 +1,87 @@
+package tracker
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// jiraIssueType is the type of the issues created in Jira
+const jiraIssueType = "Task"
+
+// jiraLabelPattern matches the characters not allowed in Jira labels
+var jiraLabelPattern = regexp.MustCompile(`[^a-z0-9+_.-]+`)
+
+// Jira pushes issues to a Jira Cloud project
+type Jira struct {
+	baseURL    string
+	email      string
+	token      string
+	projectKey string
+}
+
+// NewJira creates a Jira tracker for the project with the given key on the
+// site at baseURL, such as https://acme.atlassian.net
+func NewJira(baseURL, email, token, projectKey string) *Jira {
+	return &Jira{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		email:      email,
+		token:      token,
+		projectKey: projectKey,
+	}
+}
+
+// Name identifies the tracker
+func (j *Jira) Name() string {
+	return "jira"
+}
+
+// Push creates or updates a Jira issue
+func (j *Jira) Push(ctx context.Context, key string, issue Issue) (string, string, error) {
+	labels := make([]string, len(issue.Labels))
+	for i, label := range issue.Labels {
+		labels[i] = jiraLabel(label)
+	}
+
+	fields := map[string]interface{}{
+		"summary":     issue.Title,
+		"description": issue.Description,
+		"labels":      labels,
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(j.email+":"+j.token)))
+
+	if key != "" {
+		endpoint := j.baseURL + "/rest/api/2/issue/" + key
+		if err := doJSON(ctx, "PUT", endpoint, header, map[string]interface{}{"fields": fields}, nil); err != nil {
+			return "", "", fmt.Errorf("failed to update Jira issue %s: %w", key, err)
+		}
+		return key, j.browseURL(key), nil
+	}
+
+	fields["project"] = map[string]string{"key": j.projectKey}
+	fields["issuetype"] = map[string]string{"name": jiraIssueType}
+
+	var created struct {
+		Key string `json:"key"`
+	}
+	endpoint := j.baseURL + "/rest/api/2/issue"
+	if err := doJSON(ctx, "POST", endpoint, header, map[string]interface{}{"fields": fields}, &created); err != nil {
+		return "", "", fmt.Errorf("failed to create Jira issue: %w", err)
+	}
+	return created.Key, j.browseURL(created.Key), nil
+}
+
+// browseURL returns the web URL of an issue
+func (j *Jira) browseURL(key string) string {
+	return j.baseURL + "/browse/" + key
+}
+
+// jiraLabel turns a label into a Jira label, which cannot contain spaces
+func jiraLabel(label string) string {
+	return strings.Trim(jiraLabelPattern.ReplaceAllString(strings.ToLower(label), "-"), "-")
+}
@@ -0,0 +1,185 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/goccy/go-json"
+)
+
+// linearURL is the Linear GraphQL API endpoint
+const linearURL = "https://api.linear.app/graphql"
+
+// Linear pushes issues to a Linear team
+type Linear struct {
+	apiKey  string
+	teamKey string
+
+	teamID string
+	labels map[string]string
+}
+
+// NewLinear creates a Linear tracker for the team with the given key, such
+// as "ENG"
+func NewLinear(apiKey, teamKey string) *Linear {
+	return &Linear{apiKey: apiKey, teamKey: teamKey}
+}
+
+// Name identifies the tracker
+func (l *Linear) Name() string {
+	return "linear"
+}
+
+// Push creates or updates a Linear issue, creating missing labels
+func (l *Linear) Push(ctx context.Context, key string, issue Issue) (string, string, error) {
+	if err := l.loadTeam(ctx); err != nil {
+		return "", "", err
+	}
+
+	labelIDs := make([]string, 0, len(issue.Labels))
+	for _, name := range issue.Labels {
+		id, err := l.labelID(ctx, name)
+		if err != nil {
+			return "", "", err
+		}
+		labelIDs = append(labelIDs, id)
+	}
+
+	input := map[string]interface{}{
+		"title":       issue.Title,
+		"description": issue.Description,
+		"labelIds":    labelIDs,
+	}
+
+	var result struct {
+		Issue struct {
+			Success bool `json:"success"`
+			Issue   struct {
+				Identifier string `json:"identifier"`
+				URL        string `json:"url"`
+			} `json:"issue"`
+		} `json:"result"`
+	}
+
+	if key == "" {
+		input["teamId"] = l.teamID
+		const mutation = `mutation($input: IssueCreateInput!) {
+  result: issueCreate(input: $input) { success issue { identifier url } }
+}`
+		if err := l.graphQL(ctx, mutation, map[string]interface{}{"input": input}, &result); err != nil {
+			return "", "", fmt.Errorf("failed to create Linear issue: %w", err)
+		}
+	} else {
+		const mutation = `mutation($id: String!, $input: IssueUpdateInput!) {
+  result: issueUpdate(id: $id, input: $input) { success issue { identifier url } }
+}`
+		if err := l.graphQL(ctx, mutation, map[string]interface{}{"id": key, "input": input}, &result); err != nil {
+			return "", "", fmt.Errorf("failed to update Linear issue %s: %w", key, err)
+		}
+	}
+
+	if !result.Issue.Success {
+		return "", "", fmt.Errorf("linear did not save issue %q", issue.Title)
+	}
+	return result.Issue.Issue.Identifier, result.Issue.Issue.URL, nil
+}
+
+// loadTeam resolves the team ID and its labels once
+func (l *Linear) loadTeam(ctx context.Context) error {
+	if l.teamID != "" {
+		return nil
+	}
+
+	const query = `query($key: String!) {
+  teams(filter: {key: {eq: $key}}) {
+    nodes { id labels(first: 250) { nodes { id name } } }
+  }
+}`
+
+	var data struct {
+		Teams struct {
+			Nodes []struct {
+				ID     string `json:"id"`
+				Labels struct {
+					Nodes []struct {
+						ID   string `json:"id"`
+						Name string `json:"name"`
+					} `json:"nodes"`
+				} `json:"labels"`
+			} `json:"nodes"`
+		} `json:"teams"`
+	}
+	if err := l.graphQL(ctx, query, map[string]interface{}{"key": l.teamKey}, &data); err != nil {
+		return fmt.Errorf("failed to fetch Linear team: %w", err)
+	}
+	if len(data.Teams.Nodes) == 0 {
+		return fmt.Errorf("linear team not found: %s", l.teamKey)
+	}
+
+	team := data.Teams.Nodes[0]
+	l.teamID = team.ID
+	l.labels = make(map[string]string, len(team.Labels.Nodes))
+	for _, label := range team.Labels.Nodes {
+		l.labels[strings.ToLower(label.Name)] = label.ID
+	}
+	return nil
+}
+
+// labelID returns the ID of a team label, creating it if needed
+func (l *Linear) labelID(ctx context.Context, name string) (string, error) {
+	if id, ok := l.labels[strings.ToLower(name)]; ok {
+		return id, nil
+	}
+
+	const mutation = `mutation($input: IssueLabelCreateInput!) {
+  issueLabelCreate(input: $input) { issueLabel { id } }
+}`
+
+	var data struct {
+		IssueLabelCreate struct {
+			IssueLabel struct {
+				ID string `json:"id"`
+			} `json:"issueLabel"`
+		} `json:"issueLabelCreate"`
+	}
+	input := map[string]interface{}{"name": name, "teamId": l.teamID}
+	if err := l.graphQL(ctx, mutation, map[string]interface{}{"input": input}, &data); err != nil {
+		return "", fmt.Errorf("failed to create Linear label %q: %w", name, err)
+	}
+
+	id := data.IssueLabelCreate.IssueLabel.ID
+	l.labels[strings.ToLower(name)] = id
+	return id, nil
+}
+
+// graphQL runs a Linear GraphQL query and decodes its data into out
+func (l *Linear) graphQL(ctx context.Context, query string, vars map[string]interface{}, out interface{}) error {
+	header := http.Header{}
+	header.Set("Authorization", l.apiKey)
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	body := map[string]interface{}{"query": query, "variables": vars}
+	if err := doJSON(ctx, "POST", linearURL, header, body, &result); err != nil {
+		return err
+	}
+
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("linear API error: %s", strings.Join(messages, "; "))
+	}
+
+	if err := json.Unmarshal(result.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package tracker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+)
+
+// Issue is a submission as pushed to an issue tracker
+type Issue struct {
+	Title       string
+	Description string
+	Labels      []string
+}
+
+// Tracker creates and updates issues in an issue tracker
+type Tracker interface {
+	// Name identifies the tracker, such as "linear" or "jira"
+	Name() string
+
+	// Push creates the issue, or updates the one with key when key is not
+	// empty, and returns its key and URL
+	Push(ctx context.Context, key string, issue Issue) (string, string, error)
+}
+
+// httpClient is shared by the tracker clients
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// doJSON sends body as JSON and decodes the JSON response into out
+func doJSON(ctx context.Context, method, url string, header http.Header, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(data))
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
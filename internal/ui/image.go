@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"os"
+	"strings"
+
+	// Register decoders for logos served as GIF or JPEG
+	_ "image/gif"
+	_ "image/jpeg"
+
+	"golang.org/x/term"
+)
+
+// ImageProtocol is a terminal inline image protocol
+type ImageProtocol string
+
+// Supported inline image protocols
+const (
+	ImageNone   ImageProtocol = "none"
+	ImageKitty  ImageProtocol = "kitty"
+	ImageITerm2 ImageProtocol = "iterm2"
+	ImageSixel  ImageProtocol = "sixel"
+)
+
+// kittyChunkSize is the maximum payload size of a kitty graphics escape
+const kittyChunkSize = 4096
+
+// ParseImageProtocol parses a protocol name. "auto" detects the protocol
+// supported by the terminal.
+func ParseImageProtocol(name string) (ImageProtocol, error) {
+	switch p := ImageProtocol(strings.ToLower(name)); p {
+	case "", "auto":
+		return DetectImageProtocol(), nil
+	case ImageNone, ImageKitty, ImageITerm2, ImageSixel:
+		return p, nil
+	default:
+		return ImageNone, fmt.Errorf("unknown image protocol: %s (use auto, kitty, iterm2, sixel or none)", name)
+	}
+}
+
+// DetectImageProtocol guesses the inline image protocol supported by the
+// terminal from its environment
+func DetectImageProtocol() ImageProtocol {
+	termName := os.Getenv("TERM")
+
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "" || termName == "xterm-kitty" || termName == "xterm-ghostty":
+		return ImageKitty
+	case os.Getenv("TERM_PROGRAM") == "iTerm.app" || os.Getenv("TERM_PROGRAM") == "WezTerm":
+		return ImageITerm2
+	case strings.Contains(termName, "sixel") || termName == "foot" || termName == "mlterm":
+		return ImageSixel
+	default:
+		return ImageNone
+	}
+}
+
+// CanShowImages reports whether Out is a terminal that can display inline images
+func (u *UI) CanShowImages() bool {
+	file, ok := u.Out.(*os.File)
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// Image writes an image to Out using protocol, scaled to the given width in
+// terminal cells where the protocol allows it
+func (u *UI) Image(data []byte, protocol ImageProtocol, cells int) error {
+	var seq string
+
+	switch protocol {
+	case ImageNone:
+		return nil
+	case ImageITerm2:
+		seq = fmt.Sprintf("\033]1337;File=inline=1;size=%d;width=%d;preserveAspectRatio=1:%s\a",
+			len(data), cells, base64.StdEncoding.EncodeToString(data))
+	case ImageKitty:
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to decode image: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return fmt.Errorf("failed to encode image: %w", err)
+		}
+		seq = kittyImage(buf.Bytes(), cells)
+	case ImageSixel:
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to decode image: %w", err)
+		}
+		seq = sixelImage(img)
+	default:
+		return fmt.Errorf("unknown image protocol: %s", protocol)
+	}
+
+	u.Printf("%s\n", seq)
+	return nil
+}
+
+// kittyImage encodes PNG data as kitty graphics protocol escapes
+func kittyImage(pngData []byte, cells int) string {
+	payload := base64.StdEncoding.EncodeToString(pngData)
+
+	var b strings.Builder
+	for first := true; len(payload) > 0; first = false {
+		chunk := payload[:min(kittyChunkSize, len(payload))]
+		payload = payload[len(chunk):]
+
+		more := 0
+		if len(payload) > 0 {
+			more = 1
+		}
+
+		if first {
+			fmt.Fprintf(&b, "\033_Ga=T,f=100,c=%d,m=%d;%s\033\\", cells, more, chunk)
+		} else {
+			fmt.Fprintf(&b, "\033_Gm=%d;%s\033\\", more, chunk)
+		}
+	}
+
+	return b.String()
+}
+
+// sixelImage encodes an image as sixel graphics, quantized to a 256 color
+// palette. Transparent pixels are left untouched.
+func sixelImage(img image.Image) string {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	paletted := image.NewPaletted(image.Rect(0, 0, width, height), palette.Plan9)
+	draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), img, bounds.Min)
+
+	opaque := func(x, y int) bool {
+		_, _, _, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+		return a >= 0x8000
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "\033P0;1;0q\"1;1;%d;%d", width, height)
+
+	for i, c := range paletted.Palette {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(&b, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+
+	for top := 0; top < height; top += 6 {
+		// Collect the colors used in this band of six rows
+		var used []uint8
+		seen := make(map[uint8]bool)
+		for y := top; y < min(top+6, height); y++ {
+			for x := 0; x < width; x++ {
+				if idx := paletted.ColorIndexAt(x, y); opaque(x, y) && !seen[idx] {
+					seen[idx] = true
+					used = append(used, idx)
+				}
+			}
+		}
+
+		for _, idx := range used {
+			fmt.Fprintf(&b, "#%d", idx)
+
+			var run byte
+			count := 0
+			flush := func() {
+				switch {
+				case count == 0:
+				case count > 3:
+					fmt.Fprintf(&b, "!%d%c", count, run)
+				default:
+					b.WriteString(strings.Repeat(string(run), count))
+				}
+			}
+
+			for x := 0; x < width; x++ {
+				var bits byte
+				for dy := 0; dy < 6 && top+dy < height; dy++ {
+					if paletted.ColorIndexAt(x, top+dy) == idx && opaque(x, top+dy) {
+						bits |= 1 << dy
+					}
+				}
+
+				char := '?' + bits
+				if char == run {
+					count++
+					continue
+				}
+				flush()
+				run, count = char, 1
+			}
+			flush()
+
+			b.WriteByte('$')
+		}
+
+		b.WriteByte('-')
+	}
+
+	b.WriteString("\033\\")
+	return b.String()
+}
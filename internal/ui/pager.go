@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/term"
+)
+
+const defaultPager = "less -R"
+
+// Page writes text to Out, through $PAGER when Out is a terminal too small to
+// show it at once
+func (u *UI) Page(text string) {
+	file, ok := u.Out.(*os.File)
+	if !ok || !term.IsTerminal(int(file.Fd())) {
+		u.Printf("%s", text)
+		return
+	}
+
+	_, height, err := term.GetSize(int(file.Fd()))
+	if err != nil || strings.Count(text, "\n") < height {
+		u.Printf("%s", text)
+		return
+	}
+
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = defaultPager
+	}
+
+	args := strings.Fields(pager)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout = file
+	cmd.Stderr = u.Err
+
+	if err := cmd.Run(); err != nil {
+		log.Debug().Err(err).Str("pager", pager).Msg("Pager failed, printing directly")
+		u.Printf("%s", text)
+	}
+}
+
+// Interactive reports whether both In and Out are terminals, so output can
+// wait on key presses
+func (u *UI) Interactive() bool {
+	in, ok := u.In.(*os.File)
+	if !ok || !term.IsTerminal(int(in.Fd())) {
+		return false
+	}
+	out, ok := u.Out.(*os.File)
+	return ok && term.IsTerminal(int(out.Fd()))
+}
+
+// PageRows writes head, such as table headers, then rows a page at a time.
+// After each page but the last it waits for space or enter to show the next
+// one, or q to stop. It returns how many rows were written.
+func (u *UI) PageRows(head, rows []string, pageSize int) int {
+	for _, line := range head {
+		u.Println(line)
+	}
+
+	shown := 0
+	for shown < len(rows) {
+		end := min(shown+pageSize, len(rows))
+		for _, line := range rows[shown:end] {
+			u.Println(line)
+		}
+		shown = end
+		if shown == len(rows) {
+			break
+		}
+
+		fmt.Fprint(u.Out, MutedColor.Sprintf("Showing 1–%s of %s — press space for more, q to quit",
+			formatCount(shown), formatCount(len(rows))))
+		more := u.readMore()
+		fmt.Fprint(u.Out, "\r\033[K")
+		if !more {
+			break
+		}
+	}
+	return shown
+}
+
+// readMore waits for a key press on In, reporting whether it asks for more
+func (u *UI) readMore() bool {
+	file, ok := u.In.(*os.File)
+	if !ok {
+		return false
+	}
+	state, err := term.MakeRaw(int(file.Fd()))
+	if err != nil {
+		return false
+	}
+	defer func() { _ = term.Restore(int(file.Fd()), state) }()
+
+	key := make([]byte, 1)
+	for {
+		if _, err := file.Read(key); err != nil {
+			return false
+		}
+		switch key[0] {
+		case ' ', '\r', '\n', 'j':
+			return true
+		case 'q', 'Q', 0x03, 0x1b: // Ctrl-C and Esc stop too
+			return false
+		}
+	}
+}
+
+// formatCount formats a count with thousands separators, such as 1,234
+func formatCount(n int) string {
+	digits := strconv.Itoa(n)
+	var b strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String()
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/term"
+)
+
+const progressWidth = 30
+
+// Progress renders a progress bar on the error writer. Nothing is drawn when
+// the error writer is not a terminal.
+type Progress struct {
+	u       *UI
+	label   string
+	total   int
+	current int
+	drawn   int
+	enabled bool
+	last    time.Time
+}
+
+// NewProgress creates a progress bar for total steps
+func (u *UI) NewProgress(label string, total int) *Progress {
+	enabled := false
+	if file, ok := u.Err.(*os.File); ok {
+		enabled = term.IsTerminal(int(file.Fd()))
+	}
+
+	return &Progress{
+		u:       u,
+		label:   label,
+		total:   total,
+		enabled: enabled && total > 0,
+	}
+}
+
+// Increment advances the progress bar by one step
+func (p *Progress) Increment() {
+	p.current++
+	if !p.enabled {
+		return
+	}
+
+	// Redraw at most every 50ms, and always on the last step
+	if p.current < p.total && time.Since(p.last) < 50*time.Millisecond {
+		return
+	}
+	p.last = time.Now()
+	p.draw()
+}
+
+// Done completes the progress bar and moves to a new line
+func (p *Progress) Done() {
+	if !p.enabled || p.drawn == 0 {
+		return
+	}
+
+	p.current = p.total
+	p.draw()
+	fmt.Fprintln(p.u.Err)
+}
+
+func (p *Progress) draw() {
+	filled := progressWidth * p.current / p.total
+	if filled > progressWidth {
+		filled = progressWidth
+	}
+
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressWidth-filled)
+	fmt.Fprintf(p.u.Err, "\r%s %s %d/%d", p.label, bar, p.current, p.total)
+	p.drawn++
+}
+
+// FormatBytes formats a byte count as a human-readable size
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
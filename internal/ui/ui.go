@@ -1,13 +1,18 @@
 package ui
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/fatih/color"
+	"golang.org/x/term"
 )
 
 var (
@@ -27,6 +32,50 @@ var (
 	LowDRColor    = color.New(color.FgRed)
 )
 
+// UI writes user-facing output to injected writers. Results go to Out,
+// diagnostics and prompts go to Err.
+type UI struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+
+	// lines buffers In when it isn't a terminal. Every prompt reads from
+	// it, so lines buffered while reading one aren't lost to the next.
+	lines *bufio.Reader
+}
+
+type contextKey struct{}
+
+var defaultUI = New(os.Stdin, os.Stdout, os.Stderr)
+
+// New creates a UI reading from in and writing to out and errOut
+func New(in io.Reader, out, errOut io.Writer) *UI {
+	return &UI{In: in, Out: out, Err: errOut}
+}
+
+// Default returns the UI used by the package-level helpers
+func Default() *UI {
+	return defaultUI
+}
+
+// SetDefault replaces the UI used by the package-level helpers
+func SetDefault(u *UI) {
+	defaultUI = u
+}
+
+// WithContext returns a copy of ctx carrying u
+func WithContext(ctx context.Context, u *UI) context.Context {
+	return context.WithValue(ctx, contextKey{}, u)
+}
+
+// FromContext returns the UI carried by ctx, or the default UI
+func FromContext(ctx context.Context) *UI {
+	if u, ok := ctx.Value(contextKey{}).(*UI); ok && u != nil {
+		return u
+	}
+	return defaultUI
+}
+
 // DisableColors disables colored output
 func DisableColors() {
 	colorsEnabled = false
@@ -39,69 +88,120 @@ func EnableColors() {
 	color.NoColor = false
 }
 
+// ColorsEnabled reports whether output is colored, which it isn't when
+// disabled, with NO_COLOR set or when stdout is not a terminal
+func ColorsEnabled() bool {
+	return colorsEnabled && !color.NoColor
+}
+
+// PauseColors disables colored output until the returned function restores
+// the previous setting
+func PauseColors() (restore func()) {
+	enabled, noColor := colorsEnabled, color.NoColor
+	DisableColors()
+	return func() {
+		colorsEnabled, color.NoColor = enabled, noColor
+	}
+}
+
+// Printf writes formatted output to Out
+func (u *UI) Printf(format string, args ...interface{}) {
+	if _, err := fmt.Fprintf(u.Out, format, args...); err != nil {
+		fmt.Fprintf(u.Err, "Failed to write output: %v\n", err)
+	}
+}
+
+// Println writes a line to Out
+func (u *UI) Println(args ...interface{}) {
+	if _, err := fmt.Fprintln(u.Out, args...); err != nil {
+		fmt.Fprintf(u.Err, "Failed to write output: %v\n", err)
+	}
+}
+
 // Success prints a success message
-func Success(format string, args ...interface{}) {
+func (u *UI) Success(format string, args ...interface{}) {
+	u.message(u.Out, SuccessColor, "✓ ", format, args...)
+}
+
+// Error prints an error message
+func (u *UI) Error(format string, args ...interface{}) {
+	u.message(u.Err, ErrorColor, "✗ ", format, args...)
+}
+
+// Warning prints a warning message
+func (u *UI) Warning(format string, args ...interface{}) {
+	u.message(u.Out, WarningColor, "⚠ ", format, args...)
+}
+
+// Info prints an info message
+func (u *UI) Info(format string, args ...interface{}) {
+	u.message(u.Out, InfoColor, "ℹ ", format, args...)
+}
+
+// Muted prints a muted message
+func (u *UI) Muted(format string, args ...interface{}) {
+	u.message(u.Out, MutedColor, "", format, args...)
+}
+
+// Bold prints a bold message
+func (u *UI) Bold(format string, args ...interface{}) {
+	u.message(u.Out, BoldColor, "", format, args...)
+}
+
+// message prints a colored message with an icon prefix to w
+func (u *UI) message(w io.Writer, c *color.Color, icon string, format string, args ...interface{}) {
+	var err error
 	if colorsEnabled {
-		if _, err := SuccessColor.Printf("✓ "+format+"\n", args...); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to print success message: %v\n", err)
-		}
+		_, err = c.Fprintf(w, icon+format+"\n", args...)
 	} else {
-		fmt.Printf(format+"\n", args...)
+		_, err = fmt.Fprintf(w, format+"\n", args...)
+	}
+	if err != nil {
+		fmt.Fprintf(u.Err, "Failed to print message: %v\n", err)
 	}
 }
 
+// Success prints a success message
+func Success(format string, args ...interface{}) {
+	defaultUI.Success(format, args...)
+}
+
 // Error prints an error message
 func Error(format string, args ...interface{}) {
-	if colorsEnabled {
-		if _, err := ErrorColor.Fprintf(os.Stderr, "✗ "+format+"\n", args...); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to print error message: %v\n", err)
-		}
-	} else {
-		fmt.Fprintf(os.Stderr, format+"\n", args...)
-	}
+	defaultUI.Error(format, args...)
 }
 
 // Warning prints a warning message
 func Warning(format string, args ...interface{}) {
-	if colorsEnabled {
-		if _, err := WarningColor.Printf("⚠ "+format+"\n", args...); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to print warning message: %v\n", err)
-		}
-	} else {
-		fmt.Printf(format+"\n", args...)
-	}
+	defaultUI.Warning(format, args...)
 }
 
 // Info prints an info message
 func Info(format string, args ...interface{}) {
-	if colorsEnabled {
-		if _, err := InfoColor.Printf("ℹ "+format+"\n", args...); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to print info message: %v\n", err)
-		}
-	} else {
-		fmt.Printf(format+"\n", args...)
-	}
+	defaultUI.Info(format, args...)
 }
 
 // Muted prints a muted message
 func Muted(format string, args ...interface{}) {
-	if colorsEnabled {
-		if _, err := MutedColor.Printf(format+"\n", args...); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to print muted message: %v\n", err)
-		}
-	} else {
-		fmt.Printf(format+"\n", args...)
-	}
+	defaultUI.Muted(format, args...)
 }
 
 // Bold prints a bold message
 func Bold(format string, args ...interface{}) {
-	if colorsEnabled {
-		if _, err := BoldColor.Printf(format+"\n", args...); err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to print bold message: %v\n", err)
-		}
-	} else {
-		fmt.Printf(format+"\n", args...)
+	defaultUI.Bold(format, args...)
+}
+
+// FormatAge formats how long ago something happened, such as "6h ago"
+func FormatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
 	}
 }
 
@@ -147,6 +247,37 @@ func FormatPricing(pricing string) string {
 	}
 }
 
+// currencySymbols maps ISO currency codes to their symbol
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"INR": "₹",
+}
+
+// FormatPrice formats a listing fee such as $49 or 120 CHF. It returns "-"
+// when the price is unknown.
+func FormatPrice(amount float64, currency string) string {
+	if amount <= 0 {
+		return "-"
+	}
+
+	value := strconv.FormatFloat(amount, 'f', -1, 64)
+	if amount != float64(int64(amount)) {
+		value = strconv.FormatFloat(amount, 'f', 2, 64)
+	}
+
+	currency = strings.ToUpper(currency)
+	if symbol, ok := currencySymbols[currency]; ok {
+		return symbol + value
+	}
+	if currency == "" {
+		return value
+	}
+	return value + " " + currency
+}
+
 // FormatLinkType formats link type with color
 func FormatLinkType(linkType string) string {
 	if !colorsEnabled {
@@ -166,20 +297,27 @@ func FormatLinkType(linkType string) string {
 // Table represents a simple table
 type Table struct {
 	writer  *tabwriter.Writer
+	errOut  io.Writer
 	headers []string
 	rows    [][]string
 }
 
-// CreateTable creates a formatted table
-func CreateTable(headers []string) *Table {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+// CreateTable creates a formatted table writing to Out
+func (u *UI) CreateTable(headers []string) *Table {
+	w := tabwriter.NewWriter(u.Out, 0, 0, 2, ' ', 0)
 	return &Table{
 		writer:  w,
+		errOut:  u.Err,
 		headers: headers,
 		rows:    [][]string{},
 	}
 }
 
+// CreateTable creates a formatted table writing to the default UI
+func CreateTable(headers []string) *Table {
+	return defaultUI.CreateTable(headers)
+}
+
 // Row adds a row to the table
 func (t *Table) Row(cols ...string) {
 	t.rows = append(t.rows, cols)
@@ -191,31 +329,31 @@ func (t *Table) String() string {
 		for i, h := range t.headers {
 			if i > 0 {
 				if _, err := fmt.Fprint(t.writer, "\t"); err != nil {
-					fmt.Fprintf(os.Stderr, "Failed to write tab: %v\n", err)
+					fmt.Fprintf(t.errOut, "Failed to write tab: %v\n", err)
 				}
 			}
 			if _, err := fmt.Fprint(t.writer, BoldColor.Sprint(h)); err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to write header: %v\n", err)
+				fmt.Fprintf(t.errOut, "Failed to write header: %v\n", err)
 			}
 		}
 		_, err := fmt.Fprintln(t.writer)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to write newline: %v\n", err)
+			fmt.Fprintf(t.errOut, "Failed to write newline: %v\n", err)
 		}
 
 		for i := range t.headers {
 			if i > 0 {
 				if _, err := fmt.Fprint(t.writer, "\t"); err != nil {
-					fmt.Fprintf(os.Stderr, "Failed to write tab: %v\n", err)
+					fmt.Fprintf(t.errOut, "Failed to write tab: %v\n", err)
 				}
 			}
 			if _, err := fmt.Fprint(t.writer, strings.Repeat("-", len(t.headers[i])+2)); err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to write separator: %v\n", err)
+				fmt.Fprintf(t.errOut, "Failed to write separator: %v\n", err)
 			}
 		}
 		_, err = fmt.Fprintln(t.writer)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to write newline: %v\n", err)
+			fmt.Fprintf(t.errOut, "Failed to write newline: %v\n", err)
 		}
 	}
 
@@ -223,21 +361,21 @@ func (t *Table) String() string {
 		for i, col := range row {
 			if i > 0 {
 				if _, err := fmt.Fprint(t.writer, "\t"); err != nil {
-					fmt.Fprintf(os.Stderr, "Failed to write tab: %v\n", err)
+					fmt.Fprintf(t.errOut, "Failed to write tab: %v\n", err)
 				}
 			}
 			if _, err := fmt.Fprint(t.writer, col); err != nil {
-				fmt.Fprintf(os.Stderr, "Failed to write column: %v\n", err)
+				fmt.Fprintf(t.errOut, "Failed to write column: %v\n", err)
 			}
 		}
 		_, err := fmt.Fprintln(t.writer)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Failed to write newline: %v\n", err)
+			fmt.Fprintf(t.errOut, "Failed to write newline: %v\n", err)
 		}
 	}
 
 	if err := t.writer.Flush(); err != nil {
-		fmt.Fprintf(os.Stderr, "Failed to flush table writer: %v\n", err)
+		fmt.Fprintf(t.errOut, "Failed to flush table writer: %v\n", err)
 	}
 	return ""
 }
@@ -252,3 +390,56 @@ func TruncateString(s string, maxLen int) string {
 	}
 	return s[:maxLen-3] + "..."
 }
+
+// ReadPassword prompts for a secret without echoing it. When input is not a
+// terminal the first line of input is used.
+func (u *UI) ReadPassword(prompt string) (string, error) {
+	fmt.Fprint(u.Err, prompt)
+
+	file, ok := u.In.(*os.File)
+	if !ok || !term.IsTerminal(int(file.Fd())) {
+		line, err := u.readLine()
+		if err != nil && line == "" {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	password, err := term.ReadPassword(int(file.Fd()))
+	fmt.Fprintln(u.Err)
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+
+	return string(password), nil
+}
+
+// ReadPassword prompts for a secret using the default UI
+func ReadPassword(prompt string) (string, error) {
+	return defaultUI.ReadPassword(prompt)
+}
+
+// Prompt asks for a line of input
+func (u *UI) Prompt(prompt string) (string, error) {
+	fmt.Fprint(u.Err, prompt)
+
+	line, err := u.readLine()
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+
+	return strings.TrimSpace(line), nil
+}
+
+// readLine reads a line of input, including its newline
+func (u *UI) readLine() (string, error) {
+	if u.lines == nil {
+		u.lines = bufio.NewReader(u.In)
+	}
+	return u.lines.ReadString('\n')
+}
+
+// Prompt asks for a line of input using the default UI
+func Prompt(prompt string) (string, error) {
+	return defaultUI.Prompt(prompt)
+}
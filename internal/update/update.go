@@ -0,0 +1,105 @@
+package update
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+)
+
+// latestReleaseURL is the GitHub API endpoint for the latest CLI release
+const latestReleaseURL = "https://api.github.com/repos/awesome-directories/cli/releases/latest"
+
+// Release describes a published CLI release
+type Release struct {
+	Version     string    `json:"version"`
+	URL         string    `json:"url"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// LatestRelease fetches the latest published release
+func LatestRelease(ctx context.Context) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", latestReleaseURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check latest release: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("GitHub API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var release struct {
+		TagName     string    `json:"tag_name"`
+		HTMLURL     string    `json:"html_url"`
+		PublishedAt time.Time `json:"published_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("failed to decode release: %w", err)
+	}
+
+	return &Release{
+		Version:     strings.TrimPrefix(release.TagName, "v"),
+		URL:         release.HTMLURL,
+		PublishedAt: release.PublishedAt,
+	}, nil
+}
+
+// IsNewer reports whether version latest is newer than current. Development
+// builds are never considered outdated.
+func IsNewer(latest, current string) bool {
+	a, okA := parseVersion(latest)
+	b, okB := parseVersion(current)
+	if !okA || !okB {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] > b[i]
+		}
+	}
+	return false
+}
+
+// parseVersion parses the major, minor and patch numbers of a version such
+// as v1.2.3 or 1.2.3-rc1
+func parseVersion(version string) ([3]int, bool) {
+	var parts [3]int
+
+	version = strings.TrimPrefix(version, "v")
+	version, _, _ = strings.Cut(version, "-")
+
+	fields := strings.Split(version, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+
+	for i, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return parts, false
+		}
+		parts[i] = n
+	}
+
+	return parts, true
+}
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+	"github.com/rs/zerolog/log"
+)
+
+// SignatureHeader carries the HMAC-SHA256 of the payload when a secret is
+// configured, in the same format as GitHub webhooks
+const SignatureHeader = "X-Signature-256"
+
+// StatusChanged is the event sent when a submission changes status
+type StatusChanged struct {
+	Event     string    `json:"event"`
+	Directory string    `json:"directory"`
+	Project   string    `json:"project"`
+	Product   string    `json:"product,omitempty"`
+	OldStatus string    `json:"old_status"`
+	NewStatus string    `json:"new_status"`
+	Notes     string    `json:"notes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+// StatusChangedEvent is the event name of StatusChanged
+const StatusChangedEvent = "submission.status_changed"
+
+// Send posts payload as JSON to url, signing it with secret if one is set
+func Send(ctx context.Context, url, secret string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		req.Header.Set(SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Error().Err(err).Msg("Failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(data))
+	}
+
+	log.Debug().Str("url", url).Int("status", resp.StatusCode).Msg("Webhook delivered")
+	return nil
+}
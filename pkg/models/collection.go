@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Collection is a named, user-curated list of directories, such as the
+// directories picked for a launch week
+type Collection struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Preset names the preset from config.yaml whose filters and sort apply
+	// by default to commands scoped to the collection
+	Preset string `json:"preset,omitempty" yaml:"preset,omitempty"`
+	// Directories are directory slugs, in the order they were added
+	Directories []string  `json:"directories" yaml:"directories"`
+	CreatedAt   time.Time `json:"created_at" yaml:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" yaml:"updated_at"`
+}
+
+// Contains reports whether the collection holds the directory with slug
+func (c Collection) Contains(slug string) bool {
+	for _, s := range c.Directories {
+		if s == slug {
+			return true
+		}
+	}
+	return false
+}
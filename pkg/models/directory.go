@@ -6,25 +6,32 @@ import (
 
 // Directory represents a single directory listing
 type Directory struct {
-	ID              string    `json:"id"`
-	Slug            string    `json:"slug"`
-	Name            string    `json:"name"`
-	URL             string    `json:"url"`
-	Description     string    `json:"description"`
-	Categories      []string  `json:"categories"`
-	Pricing         string    `json:"pricing"`
-	LinkType        string    `json:"link_type"`
-	DomainRating    int       `json:"domain_rating"`
-	OrganicTraffic  int       `json:"organic_traffic"`
-	OrganicKeywords int       `json:"organic_keywords"`
-	HelpfulCount    int       `json:"helpful_count"`
-	ViewCount       int       `json:"view_count"`
-	SubmissionURL   string    `json:"submission_url"`
-	IsAffiliate     bool      `json:"is_affiliate"`
-	AffiliateURL    string    `json:"affiliate_url"`
-	IsActive        bool      `json:"is_active"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              string    `json:"id" yaml:"id"`
+	Slug            string    `json:"slug" yaml:"slug"`
+	Name            string    `json:"name" yaml:"name"`
+	URL             string    `json:"url" yaml:"url"`
+	Description     string    `json:"description" yaml:"description"`
+	Categories      []string  `json:"categories" yaml:"categories"`
+	Pricing         string    `json:"pricing" yaml:"pricing"`
+	LinkType        string    `json:"link_type" yaml:"link_type"`
+	PriceAmount     float64   `json:"price_amount,omitempty" yaml:"price_amount,omitempty"`
+	PriceCurrency   string    `json:"price_currency,omitempty" yaml:"price_currency,omitempty"`
+	DomainRating    int       `json:"domain_rating" yaml:"domain_rating"`
+	OrganicTraffic  int       `json:"organic_traffic" yaml:"organic_traffic"`
+	OrganicKeywords int       `json:"organic_keywords" yaml:"organic_keywords"`
+	HelpfulCount    int       `json:"helpful_count" yaml:"helpful_count"`
+	ViewCount       int       `json:"view_count" yaml:"view_count"`
+	SubmissionURL   string    `json:"submission_url" yaml:"submission_url"`
+	ReviewDays      int       `json:"review_days,omitempty" yaml:"review_days,omitempty"` // typical days until a submission is reviewed
+	IsAffiliate     bool      `json:"is_affiliate" yaml:"is_affiliate"`
+	AffiliateURL    string    `json:"affiliate_url" yaml:"affiliate_url"`
+	IsActive        bool      `json:"is_active" yaml:"is_active"`
+	Countries       []string  `json:"countries,omitempty" yaml:"countries,omitempty"`
+	Languages       []string  `json:"languages,omitempty" yaml:"languages,omitempty"`
+	Audience        string    `json:"audience,omitempty" yaml:"audience,omitempty"` // b2b, b2c or both
+	FeedURL         string    `json:"feed_url,omitempty" yaml:"feed_url,omitempty"` // RSS or Atom feed of the directory's blog or changelog
+	CreatedAt       time.Time `json:"created_at" yaml:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at" yaml:"updated_at"`
 }
 
 // DirectoriesResponse represents the response from the API
@@ -53,6 +60,36 @@ type Submission struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// Vote represents a user's helpful vote on a directory
+type Vote struct {
+	ID          int       `json:"id"`
+	UserID      string    `json:"user_id"`
+	DirectoryID string    `json:"directory_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AccountData represents all server-side data of a user
+type AccountData struct {
+	ExportedAt  time.Time    `json:"exported_at"`
+	User        User         `json:"user"`
+	Favorites   []Favorite   `json:"favorites"`
+	Submissions []Submission `json:"submissions"`
+	Votes       []Vote       `json:"votes"`
+}
+
+// RateLimit represents the API quota reported by the backend
+type RateLimit struct {
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	Reset     time.Time `json:"reset,omitempty"`
+}
+
+// AccountStats represents usage statistics of the authenticated user
+type AccountStats struct {
+	FavoritesCount int        `json:"favorites_count"`
+	RateLimit      *RateLimit `json:"rate_limit,omitempty"`
+}
+
 // User represents an authenticated user
 type User struct {
 	ID        string    `json:"id"`
@@ -68,6 +105,11 @@ type FilterOptions struct {
 	LinkType   []string
 	DRMin      int
 	DRMax      int
+	Countries  []string
+	Languages  []string
+	Audience   string
+	MaxPrice   float64
+	Currency   string
 	SortBy     string
 	Limit      int
 	Offset     int
@@ -91,3 +133,14 @@ const (
 	SortNewest      SortOption = "newest"
 	SortAlpha       SortOption = "alpha"
 )
+
+// CommandEvent is the anonymous telemetry reported for a command run
+type CommandEvent struct {
+	Command    string `json:"command"`
+	DurationMS int64  `json:"duration_ms"`
+	ExitStatus int    `json:"exit_status"`
+	ErrorClass string `json:"error_class,omitempty"`
+	Version    string `json:"version"`
+	OS         string `json:"os"`
+	Arch       string `json:"arch"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// DirectoryEvent is a known day of a directory worth timing submissions by,
+// such as the weekly launch day of a Product Hunt-style site
+type DirectoryEvent struct {
+	// Directory is the directory slug
+	Directory string `json:"directory"`
+	Title     string `json:"title"`
+	// Weekday is the day of weekly events, such as "tuesday"
+	Weekday string `json:"weekday,omitempty"`
+	// Date is the day of one-off events, as YYYY-MM-DD
+	Date      string    `json:"date,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// On reports whether the event falls on day
+func (e DirectoryEvent) On(day time.Time) bool {
+	if e.Weekday != "" {
+		return strings.EqualFold(day.Weekday().String(), e.Weekday)
+	}
+	return e.Date == day.Format("2006-01-02")
+}
+
+// When describes when the event falls, such as "every Tuesday"
+func (e DirectoryEvent) When() string {
+	if e.Weekday != "" {
+		return "every " + strings.ToUpper(e.Weekday[:1]) + e.Weekday[1:]
+	}
+	return e.Date
+}
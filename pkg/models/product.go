@@ -0,0 +1,63 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Product is the profile of a product submitted to directories
+type Product struct {
+	Slug         string                 `json:"slug" yaml:"slug"`
+	Name         string                 `json:"name" yaml:"name"`
+	URL          string                 `json:"url" yaml:"url"`
+	Tagline      string                 `json:"tagline,omitempty" yaml:"tagline,omitempty"`
+	Description  string                 `json:"description,omitempty" yaml:"description,omitempty"`
+	Categories   []string               `json:"categories,omitempty" yaml:"categories,omitempty"`
+	ContactEmail string                 `json:"contact_email,omitempty" yaml:"contact_email,omitempty"`
+	Logo         string                 `json:"logo,omitempty" yaml:"logo,omitempty"`
+	Variants     map[string]ProductCopy `json:"variants,omitempty" yaml:"variants,omitempty"`
+	CreatedAt    time.Time              `json:"created_at" yaml:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at" yaml:"updated_at"`
+}
+
+// ProductCopy is an alternative tagline and description of a product, used
+// to compare how listing copy performs
+type ProductCopy struct {
+	Tagline     string `json:"tagline,omitempty" yaml:"tagline,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+// DefaultVariant names the copy stored directly on the product
+const DefaultVariant = "a"
+
+// WithVariant returns a copy of the product using the tagline and description
+// of the named variant. Fields the variant leaves empty fall back to the
+// default copy.
+func (p Product) WithVariant(name string) (Product, bool) {
+	if name == "" || name == DefaultVariant {
+		return p, true
+	}
+
+	variant, ok := p.Variants[name]
+	if !ok {
+		return p, false
+	}
+
+	if variant.Tagline != "" {
+		p.Tagline = variant.Tagline
+	}
+	if variant.Description != "" {
+		p.Description = variant.Description
+	}
+	return p, true
+}
+
+// VariantNames returns the names of all copy variants, including the default
+func (p Product) VariantNames() []string {
+	names := []string{DefaultVariant}
+	for name := range p.Variants {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+	return names
+}
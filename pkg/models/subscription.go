@@ -0,0 +1,59 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Digest periods of subscriptions
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// Subscription is a saved search whose new, changed and removed directories
+// are sent as a periodic digest
+type Subscription struct {
+	Name string `json:"name"`
+	// Preset names the preset from config.yaml holding the search
+	Preset string `json:"preset"`
+	// Digest is how often the digest is sent: daily or weekly
+	Digest string `json:"digest"`
+	// Deliver is where the digest goes: the terminal, the webhook, an email
+	// address, or a Markdown file path
+	Deliver   string    `json:"deliver"`
+	CreatedAt time.Time `json:"created_at"`
+	// LastDigest is when the last digest was sent; zero before the first
+	LastDigest time.Time `json:"last_digest,omitempty"`
+	// Seen are the directories matching the search at the last digest, which
+	// the next one reports changes against
+	Seen []Directory `json:"seen"`
+}
+
+// DigestPeriod returns the time between two digests of a period name
+func DigestPeriod(digest string) (time.Duration, error) {
+	switch digest {
+	case DigestDaily:
+		return 24 * time.Hour, nil
+	case DigestWeekly:
+		return 7 * 24 * time.Hour, nil
+	}
+	return 0, fmt.Errorf("invalid digest period: %s (use %s or %s)", digest, DigestDaily, DigestWeekly)
+}
+
+// Since returns the start of the period the next digest covers
+func (s Subscription) Since() time.Time {
+	if s.LastDigest.IsZero() {
+		return s.CreatedAt
+	}
+	return s.LastDigest
+}
+
+// NextDigest returns when the next digest is due
+func (s Subscription) NextDigest() time.Time {
+	period, err := DigestPeriod(s.Digest)
+	if err != nil {
+		period = 7 * 24 * time.Hour
+	}
+	return s.Since().Add(period)
+}
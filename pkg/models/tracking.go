@@ -0,0 +1,103 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultProject is the project submissions are tracked in unless another is
+// given
+const DefaultProject = "default"
+
+// SubmissionStatuses are the statuses of a tracked submission, in pipeline
+// order
+var SubmissionStatuses = []string{"pending", "submitted", "approved", "rejected"}
+
+// TrackedSubmission is a directory submission tracked locally, within a
+// project
+type TrackedSubmission struct {
+	Directory  string            `json:"directory" yaml:"directory"`
+	Project    string            `json:"project" yaml:"project"`
+	Product    string            `json:"product,omitempty" yaml:"product,omitempty"`
+	Variant    string            `json:"variant,omitempty" yaml:"variant,omitempty"`
+	Status     string            `json:"status" yaml:"status"`
+	Notes      string            `json:"notes,omitempty" yaml:"notes,omitempty"`
+	ListingURL string            `json:"listing_url,omitempty" yaml:"listing_url,omitempty"` // the live listing page, once published
+	Todos      []Todo            `json:"todos,omitempty" yaml:"todos,omitempty"`
+	Evidence   []Evidence        `json:"evidence,omitempty" yaml:"evidence,omitempty"`
+	Issues     map[string]string `json:"issues,omitempty" yaml:"issues,omitempty"` // issue key per tracker, such as "linear"
+	CreatedAt  time.Time         `json:"created_at" yaml:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at" yaml:"updated_at"`
+}
+
+// Todo is a preparation step of a submission, such as resizing a logo
+type Todo struct {
+	Text   string     `json:"text" yaml:"text"`
+	Done   bool       `json:"done" yaml:"done"`
+	DoneAt *time.Time `json:"done_at,omitempty" yaml:"done_at,omitempty"`
+}
+
+// Evidence is proof of a submission: a file kept with the submission, such
+// as a screenshot or a confirmation email saved as .eml, or a URL
+type Evidence struct {
+	File    string    `json:"file,omitempty" yaml:"file,omitempty"`
+	URL     string    `json:"url,omitempty" yaml:"url,omitempty"`
+	AddedAt time.Time `json:"added_at" yaml:"added_at"`
+}
+
+// Name returns the file name or URL of the evidence
+func (e Evidence) Name() string {
+	if e.File != "" {
+		return e.File
+	}
+	return e.URL
+}
+
+// ValidSubmissionStatus reports whether status is a known submission status
+func ValidSubmissionStatus(status string) bool {
+	return slices.Contains(SubmissionStatuses, strings.ToLower(status))
+}
+
+// Advances reports whether moving a submission from status to next moves it
+// forward in the pipeline. Approved and rejected are both final.
+func Advances(status, next string) bool {
+	rank := func(s string) int {
+		switch s {
+		case "rejected":
+			return 2
+		default:
+			return slices.Index(SubmissionStatuses, s)
+		}
+	}
+	return rank(next) > rank(status)
+}
+
+// CanTransition reports whether a submission may move from status to next:
+// forward in the pipeline, or from rejected back to pending or submitted to
+// try again. An empty status is a submission not tracked yet.
+func CanTransition(status, next string) bool {
+	if status == "" {
+		return true
+	}
+	if status == "rejected" {
+		return next == "pending" || next == "submitted"
+	}
+	return Advances(status, next)
+}
+
+// IsSubmitted reports whether the submission has been sent to the directory
+func (s TrackedSubmission) IsSubmitted() bool {
+	return s.Status == "submitted" || s.Status == "approved"
+}
+
+// OpenTodos returns the number of todos not done yet
+func (s TrackedSubmission) OpenTodos() int {
+	open := 0
+	for _, todo := range s.Todos {
+		if !todo.Done {
+			open++
+		}
+	}
+	return open
+}